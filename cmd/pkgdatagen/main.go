@@ -17,9 +17,8 @@
 package main
 
 import (
-	"archive/zip"
 	"bytes"
-	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	goast "go/ast"
@@ -27,12 +26,14 @@ import (
 	goparser "go/parser"
 	gotoken "go/token"
 	gotypes "go/types"
+	"io/fs"
 	"os"
 	"os/exec"
 	"path"
-	"path/filepath"
 	"slices"
+	"strings"
 
+	"github.com/goplus/xgolsw/internal/pkgdata"
 	"github.com/goplus/xgolsw/pkgdoc"
 	"golang.org/x/mod/module"
 	"golang.org/x/tools/go/gcexportdata"
@@ -154,19 +155,88 @@ var stdPkgPaths = []string{
 	"github.com/goplus/spx/v2/pkg/gdspx/pkg/engine",
 }
 
-// generate generates the package data file containing the exported symbols of
-// the given packages.
-func generate(pkgPaths []string, outputFile string) error {
+// newBuildContext returns the build context for the given target.
+func newBuildContext(goos, goarch string) build.Context {
 	buildCtx := build.Default
-	buildCtx.GOOS = "js"
-	buildCtx.GOARCH = "wasm"
+	buildCtx.GOOS = goos
+	buildCtx.GOARCH = goarch
 	buildCtx.CgoEnabled = false
+	return buildCtx
+}
 
-	var zipBuf bytes.Buffer
-	zw := zip.NewWriter(&zipBuf)
+// filterPkgsForTarget returns the packages in pkgPaths that can be built for
+// the given target, e.g., "syscall/js" is only kept for js/wasm. It returns an
+// error if any package fails to import for a reason other than being excluded
+// from the target by build constraints.
+func filterPkgsForTarget(pkgPaths []string, goos, goarch string) ([]string, error) {
+	buildCtx := newBuildContext(goos, goarch)
+	filtered := make([]string, 0, len(pkgPaths))
 	for _, pkgPath := range pkgPaths {
-		buildPkg, err := buildCtx.Import(pkgPath, "", build.ImportComment)
+		buildPkg, err := importPkg(buildCtx, pkgPath)
 		if err != nil {
+			return nil, err
+		}
+		if buildPkg == nil {
+			continue
+		}
+		filtered = append(filtered, pkgPath)
+	}
+	return filtered, nil
+}
+
+// importPkg imports the package at pkgPath with buildCtx. It returns a nil
+// package without any error if the package is excluded from the target of
+// buildCtx by build constraints. XGo packages without any Go files are kept.
+func importPkg(buildCtx build.Context, pkgPath string) (*build.Package, error) {
+	buildPkg, err := buildCtx.Import(pkgPath, "", build.ImportComment)
+	if err != nil {
+		var (
+			noGoErr        *build.NoGoError
+			multiplePkgErr *build.MultiplePackageError
+		)
+		switch {
+		case errors.As(err, &noGoErr):
+			if hasXGoFiles(buildPkg.Dir) {
+				return buildPkg, nil
+			}
+			return nil, nil
+		case errors.As(err, &multiplePkgErr):
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to import package %q: %w", pkgPath, err)
+	}
+	return buildPkg, nil
+}
+
+// hasXGoFiles reports whether dir contains any XGo source files, in which case
+// the package in dir must be exported with xgo instead of go.
+func hasXGoFiles(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	return slices.ContainsFunc(entries, isXGoFile)
+}
+
+// isXGoFile reports whether the given directory entry is an XGo source file.
+func isXGoFile(entry fs.DirEntry) bool {
+	return !entry.IsDir() && path.Ext(entry.Name()) == ".xgo"
+}
+
+// generate generates the package data file containing the exported symbols of
+// the given packages for the given target. Packages containing XGo source
+// files, or all packages if useXGo is true, are exported with xgo instead of
+// go.
+func generate(pkgPaths []string, outputFile, goos, goarch string, useXGo bool) error {
+	buildCtx := newBuildContext(goos, goarch)
+
+	var entries []pkgdata.PkgDataEntry
+	for _, pkgPath := range pkgPaths {
+		buildPkg, err := importPkg(buildCtx, pkgPath)
+		if err != nil {
+			return err
+		}
+		if buildPkg == nil {
 			continue
 		}
 
@@ -177,7 +247,10 @@ func generate(pkgPaths []string, outputFile string) error {
 			pkgName = path.Base(buildPkg.ImportPath)
 		}
 
-		var pkgDoc *pkgdoc.PkgDoc
+		var (
+			pkgDoc     *pkgdoc.PkgDoc
+			exportData []byte
+		)
 		if pkgPath == "builtin" {
 			astFile, err := goparser.ParseFile(gotoken.NewFileSet(), path.Join(buildPkg.Dir, "builtin.go"), nil, goparser.ParseComments)
 			if err != nil {
@@ -252,16 +325,19 @@ func generate(pkgPaths []string, outputFile string) error {
 				}
 			}
 		} else {
-			exportFile, err := execGo("list", "-trimpath", "-export", "-f", "{{.Export}}", pkgPath)
+			hasXGo := hasXGoFiles(buildPkg.Dir)
+			isXGoPkg := useXGo || hasXGo
+
+			output, err := execTool(exportCommand(goos, goarch, pkgPath, isXGoPkg))
 			if err != nil {
 				return err
 			}
-			exportFile = bytes.TrimSpace(exportFile)
-			if len(exportFile) == 0 {
+			exportFile := exportFileFromOutput(output)
+			if exportFile == "" {
 				continue
 			}
 
-			f, err := os.Open(string(exportFile))
+			f, err := os.Open(exportFile)
 			if err != nil {
 				return err
 			}
@@ -277,58 +353,88 @@ func generate(pkgPaths []string, outputFile string) error {
 			if err != nil {
 				return fmt.Errorf("failed to read package export data: %w", err)
 			}
-			if zf, err := zw.Create(pkgPath + ".pkgexport"); err != nil {
-				return err
-			} else if err := gcexportdata.Write(zf, exportFSet, typesPkg); err != nil {
+			var exportBuf bytes.Buffer
+			if err := gcexportdata.Write(&exportBuf, exportFSet, typesPkg); err != nil {
 				return fmt.Errorf("failed to write optimized package export data: %w", err)
 			}
+			exportData = exportBuf.Bytes()
 
-			parseFSet := gotoken.NewFileSet()
-			astFiles := make(map[string]*goast.File, len(buildPkg.GoFiles)+len(buildPkg.CgoFiles))
-			for _, fileName := range slices.Concat(buildPkg.GoFiles, buildPkg.CgoFiles) {
-				fullPath := filepath.Join(buildPkg.Dir, fileName)
-				astFile, err := goparser.ParseFile(parseFSet, fullPath, nil, goparser.ParseComments)
-				if err != nil {
-					return fmt.Errorf("failed to parse %q: %w", fileName, err)
-				}
-				if astFile.Name == nil || astFile.Name.Name != pkgName {
-					continue
-				}
-				astFiles[fullPath] = astFile
+			if hasXGo {
+				// XGo sources are not understood by go/doc, so the symbols
+				// are documented from the export data without any docs.
+				pkgDoc, err = pkgdoc.NewGoFromExportData(pkgPath, bytes.NewReader(exportData))
+			} else {
+				fileNames := slices.Concat(buildPkg.GoFiles, buildPkg.CgoFiles, buildPkg.TestGoFiles, buildPkg.XTestGoFiles)
+				pkgDoc, err = pkgdoc.NewGoFromDir(pkgPath, buildPkg.Dir, func(fi fs.FileInfo) bool {
+					return slices.Contains(fileNames, fi.Name())
+				})
+			}
+			if err != nil {
+				return err
 			}
-			if len(astFiles) == 0 {
+			if pkgDoc == nil {
+				// Keep the export data so that the package stays importable.
+				entries = append(entries, pkgdata.PkgDataEntry{PkgPath: pkgPath, ExportData: exportData})
 				continue
 			}
-
-			astPkg := &goast.Package{
-				Files: astFiles,
-				Name:  pkgName,
+			for _, verr := range pkgdoc.Validate(pkgDoc, typesPkg) {
+				fmt.Fprintf(os.Stderr, "warning: %s: %v\n", pkgPath, verr)
 			}
-
-			pkgDoc = pkgdoc.NewGo(pkgPath, astPkg)
-		}
-		if zf, err := zw.Create(pkgPath + ".pkgdoc"); err != nil {
-			return err
-		} else if err := json.NewEncoder(zf).Encode(pkgDoc); err != nil {
-			return fmt.Errorf("failed to encode package doc: %w", err)
 		}
+		entries = append(entries, pkgdata.PkgDataEntry{
+			PkgPath:    pkgPath,
+			Doc:        pkgDoc,
+			ExportData: exportData,
+		})
 	}
-	if err := zw.Close(); err != nil {
+
+	var zipBuf bytes.Buffer
+	if err := pkgdata.WriteZip(&zipBuf, entries); err != nil {
 		return err
 	}
 	return os.WriteFile(outputFile, zipBuf.Bytes(), 0o644)
 }
 
-// execGo executes the given go command.
-func execGo(args ...string) ([]byte, error) {
-	cmd := exec.Command("go", args...)
-	cmd.Env = append(os.Environ(), "GOOS=js", "GOARCH=wasm")
+// exportCommand returns the command that prints the export file of pkgPath
+// for the given target. The command uses xgo if useXGo is true, or go
+// otherwise. The xgo binary is taken from the XGO environment variable,
+// defaulting to "xgo" in PATH.
+func exportCommand(goos, goarch, pkgPath string, useXGo bool) *exec.Cmd {
+	if useXGo {
+		xgo := os.Getenv("XGO")
+		if xgo == "" {
+			xgo = "xgo"
+		}
+		return toolCommand(xgo, goos, goarch, "list", "-export", "-f", "{{.Export}}", pkgPath)
+	}
+	return toolCommand("go", goos, goarch, "list", "-trimpath", "-export", "-f", "{{.Export}}", pkgPath)
+}
+
+// toolCommand returns the given command of a Go-compatible toolchain for the
+// given target.
+func toolCommand(name, goos, goarch string, args ...string) *exec.Cmd {
+	cmd := exec.Command(name, args...)
+	cmd.Env = append(os.Environ(), "GOOS="+goos, "GOARCH="+goarch)
+	return cmd
+}
+
+// exportFileFromOutput returns the export file path in the output of a
+// command returned by [exportCommand], or "" if the package has no export
+// file. Since xgo may print progress messages before the path, only the last
+// non-empty line of output is used.
+func exportFileFromOutput(output []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// execTool executes cmd and returns its standard output.
+func execTool(cmd *exec.Cmd) ([]byte, error) {
 	output, err := cmd.Output()
 	if err != nil {
 		if ee, ok := err.(*exec.ExitError); ok {
 			err = fmt.Errorf("%w: %s", err, ee.Stderr)
 		}
-		return nil, fmt.Errorf("failed to execute go command: %w", err)
+		return nil, fmt.Errorf("failed to execute %s command: %w", path.Base(cmd.Path), err)
 	}
 	return output, nil
 }
@@ -336,8 +442,16 @@ func execGo(args ...string) ([]byte, error) {
 func main() {
 	outputFile := flag.String("o", "pkgdata.zip", "output file")
 	noStd := flag.Bool("no-std", false, "do not generate standard packages")
+	target := flag.String("target", "js/wasm", "target platform in the form GOOS/GOARCH")
+	useXGo := flag.Bool("xgo", false, "export all packages with xgo instead of go")
 	flag.Parse()
 
+	goos, goarch, ok := strings.Cut(*target, "/")
+	if !ok || goos == "" || goarch == "" {
+		fmt.Fprintf(os.Stderr, "invalid target %q: want GOOS/GOARCH\n", *target)
+		os.Exit(2)
+	}
+
 	var pkgPaths []string
 	if !*noStd {
 		pkgPaths = stdPkgPaths
@@ -348,7 +462,13 @@ func main() {
 		}
 	}
 
-	if err := generate(pkgPaths, *outputFile); err != nil {
+	pkgPaths, err := filterPkgsForTarget(pkgPaths, goos, goarch)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to filter packages: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := generate(pkgPaths, *outputFile, goos, goarch, *useXGo); err != nil {
 		fmt.Fprintf(os.Stderr, "failed to generate package data: %v\n", err)
 		os.Exit(1)
 	}
@@ -0,0 +1,111 @@
+/*
+ * Copyright (c) 2025 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterPkgsForTarget(t *testing.T) {
+	t.Run("JSWasm", func(t *testing.T) {
+		pkgPaths, err := filterPkgsForTarget([]string{"fmt", "syscall/js"}, "js", "wasm")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"fmt", "syscall/js"}, pkgPaths)
+	})
+
+	t.Run("ExcludedByBuildConstraints", func(t *testing.T) {
+		pkgPaths, err := filterPkgsForTarget([]string{"fmt", "syscall/js"}, "linux", "amd64")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"fmt"}, pkgPaths)
+	})
+
+	t.Run("NonExistentPackage", func(t *testing.T) {
+		pkgPaths, err := filterPkgsForTarget([]string{"fmt", "nonexistent/pkg"}, "js", "wasm")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `"nonexistent/pkg"`)
+		assert.Nil(t, pkgPaths)
+	})
+}
+
+func TestExportCommand(t *testing.T) {
+	t.Run("Go", func(t *testing.T) {
+		cmd := exportCommand("js", "wasm", "fmt", false)
+		assert.Equal(t, "go", path.Base(cmd.Path))
+		assert.Equal(t, []string{"go", "list", "-trimpath", "-export", "-f", "{{.Export}}", "fmt"}, cmd.Args)
+		assert.Equal(t, []string{"GOOS=js", "GOARCH=wasm"}, cmd.Env[len(cmd.Env)-2:])
+	})
+
+	t.Run("XGo", func(t *testing.T) {
+		t.Setenv("XGO", "/path/to/xgo")
+		cmd := exportCommand("linux", "amd64", "example.com/foo", true)
+		assert.Equal(t, "/path/to/xgo", cmd.Path)
+		assert.Equal(t, []string{"/path/to/xgo", "list", "-export", "-f", "{{.Export}}", "example.com/foo"}, cmd.Args)
+		assert.Equal(t, []string{"GOOS=linux", "GOARCH=amd64"}, cmd.Env[len(cmd.Env)-2:])
+	})
+
+	t.Run("XGoDefault", func(t *testing.T) {
+		t.Setenv("XGO", "")
+		cmd := exportCommand("js", "wasm", "example.com/foo", true)
+		assert.Equal(t, "xgo", cmd.Args[0])
+	})
+}
+
+func TestExportFileFromOutput(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{
+			name:   "Path",
+			output: "/cache/fmt.a\n",
+			want:   "/cache/fmt.a",
+		},
+		{
+			name:   "Empty",
+			output: "\n",
+			want:   "",
+		},
+		{
+			name:   "XGoProgressMessages",
+			output: "GenGo ./... ...\n\n/cache/foo.a\n",
+			want:   "/cache/foo.a",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, exportFileFromOutput([]byte(tt.output)))
+		})
+	}
+}
+
+func TestExecTool(t *testing.T) {
+	t.Run("TargetEnv", func(t *testing.T) {
+		output, err := execTool(toolCommand("go", "js", "wasm", "env", "GOOS", "GOARCH"))
+		require.NoError(t, err)
+		assert.Equal(t, "js\nwasm\n", string(output))
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		_, err := execTool(toolCommand("go", "js", "wasm", "list", "nonexistent/pkg"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to execute go command")
+	})
+}
@@ -23,6 +23,15 @@ declare global {
    */
   function NewXGoLanguageServer(filesProvider: () => Files, messageReplier: (message: ResponseMessage | NotificationMessage) => void): XGoLanguageServer | Error
 
+  /**
+   * Replaces the project served by the most recently created language server with a new one, e.g., after the
+   * classfile configuration changed. Requests still running on the old project are cancelled.
+   *
+   * @param filesProvider - Function that provides access to the workspace files of the new project, with the same
+   *                       semantics as the one passed to `NewXGoLanguageServer`.
+   */
+  function ReplaceProject(filesProvider: () => Files): Error | null
+
   /**
    * Sets custom package data that will be used with higher priority than the embedded package data.
    *
@@ -31,12 +40,162 @@ declare global {
   function SetCustomPkgdataZip(data: Uint8Array): Error | null
 
   /**
-   * Sets the auto-imported packages for the classfile specified by id.
+   * Sets the auto-imported packages for the classfile specified by id. It returns an error if the id is unknown.
    *
    * @param id - The identifier of the classfile.
    * @param packages - A map where keys are package names and values are the full import paths.
    */
   function SetClassfileAutoImportedPackages(id: string, packages: Record<string, string>): Error | null
+
+  /**
+   * Gets the auto-imported packages for the classfile specified by id.
+   *
+   * @param id - The identifier of the classfile.
+   * @returns A map where keys are package names and values are the full import paths, or null if none are set.
+   */
+  function GetClassfileAutoImportedPackages(id: string): Record<string, string> | null | Error
+
+  /**
+   * Removes the auto-imported packages for the classfile specified by id.
+   *
+   * @param id - The identifier of the classfile.
+   */
+  function RemoveClassfileAutoImportedPackages(id: string): Error | null
+
+  /**
+   * Lists the identifiers of the classfiles that have auto-imported packages set.
+   */
+  function ListClassfileAutoImportedPackageIDs(): string[] | Error
+
+  /**
+   * Removes the auto-imported packages for all classfiles. It is mainly intended for testing.
+   */
+  function ClearAllClassfileAutoImportedPackages(): Error | null
+
+  /**
+   * Sets the completion sort config used by all subsequent completion requests.
+   *
+   * @param config - Per-kind sort weights. Kinds omitted from the config keep their default weights.
+   */
+  function SetCompletionSortConfig(config: CompletionSortConfig): Error | null
+
+  /**
+   * Returns all spx definitions, including those of the spx package, the math package, the builtins, and the members
+   * of `Game` and `Sprite`. It is intended for pre-loading definitions at startup.
+   */
+  function GetSpxDefinitions(): SpxDefinition[] | Error
+
+  /**
+   * Loads all package data into the cache in the background, so that the first requests after startup do not have to
+   * load it lazily.
+   *
+   * @returns A promise that resolves once the package data has been loaded, or rejects with the loading error.
+   */
+  function PreloadPkgdata(): Promise<void> | Error
+
+  /**
+   * Searches all packages in the package data for package-level symbols whose names contain the given string, ignoring
+   * case.
+   *
+   * @param name - The string to search for.
+   */
+  function SearchSymbol(name: string): SymbolSearchResult[] | Error
+
+  /**
+   * Sets the policy used by the language server to yield to the JavaScript event loop for a bounded duration.
+   *
+   * @param policy - `setTimeout` always uses `setTimeout`, `requestAnimationFrame` always waits for the next frame, and
+   *                 `auto` (the default) waits for the next frame only if the duration allows a full frame (16ms).
+   */
+  function SetSchedulerPolicy(policy: 'setTimeout' | 'requestAnimationFrame' | 'auto'): Error | null
+
+  /**
+   * Sets how long file changes must settle before the language server publishes diagnostics for them, so that rapid
+   * successive changes (e.g., while typing) trigger a single recompilation.
+   *
+   * @param ms - Delay in milliseconds. Defaults to 300. `0` disables debouncing.
+   */
+  function SetDiagnosticDebounceMs(ms: number): Error | null
+
+  /**
+   * Returns the build information of the language server.
+   */
+  function GetSpxlsVersion(): SpxlsVersion | Error
+}
+
+/**
+ * The build information of the language server. Fields that are unknown are empty strings.
+ */
+export type SpxlsVersion = {
+  version: string
+  goVersion: string
+  xgoVersion: string
+  spxVersion: string
+  buildTime: string
+}
+
+/**
+ * An spx definition.
+ */
+export type SpxDefinition = {
+  id: {
+    package?: string
+    name?: string
+    overloadId?: string
+  }
+  overview: string
+  detail: string
+  deprecated?: string
+  parameterDocs?: {
+    name: string
+    type: string
+    doc: string
+  }[]
+  signature?: {
+    params: SpxParam[]
+    results: SpxParam[]
+  }
+  completionItemLabel: string
+  completionItemKind: number
+  completionItemInsertText: string
+  completionItemInsertTextFormat: number
+}
+
+/**
+ * A parameter or result of a function in an {@link SpxDefinition}. For a variadic parameter, `typeString` is the type
+ * of each of its arguments.
+ */
+export type SpxParam = {
+  name: string
+  typeString: string
+  isVariadic?: boolean
+}
+
+/**
+ * A package-level symbol found by `SearchSymbol`.
+ */
+export type SymbolSearchResult = {
+  pkgPath: string
+  name: string
+  symbolKind: 'func' | 'type' | 'var' | 'const'
+  doc: string
+}
+
+/**
+ * Per-kind weights used to sort completion items. Items whose kind has a lower weight are sorted first.
+ */
+export type CompletionSortConfig = {
+  variable?: number
+  field?: number
+  property?: number
+  method?: number
+  function?: number
+  constant?: number
+  unit?: number
+  class?: number
+  interface?: number
+  module?: number
+  keyword?: number
 }
 
 /**
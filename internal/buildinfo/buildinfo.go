@@ -0,0 +1,73 @@
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Build information injected at build time, e.g.,
+//
+//	go build -ldflags "-X github.com/goplus/xgolsw/internal/buildinfo.version=v1.0.0"
+var (
+	version    string
+	xgoVersion string
+	spxVersion string
+	buildTime  string
+)
+
+const (
+	xgoModulePath = "github.com/goplus/xgo"
+	spxModulePath = "github.com/goplus/spx/v2"
+)
+
+// Info describes the build of the language server.
+type Info struct {
+	Version    string `json:"version"`
+	GoVersion  string `json:"goVersion"`
+	XGoVersion string `json:"xgoVersion"`
+	SpxVersion string `json:"spxVersion"`
+	BuildTime  string `json:"buildTime"`
+}
+
+// Get returns the build information of the running binary. Values not
+// injected at build time fall back to [runtime.Version] for the Go version
+// and [debug.ReadBuildInfo] for module versions, and are empty if unknown.
+func Get() Info {
+	info := Info{
+		Version:    version,
+		GoVersion:  runtime.Version(),
+		XGoVersion: xgoVersion,
+		SpxVersion: spxVersion,
+		BuildTime:  buildTime,
+	}
+
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	if info.Version == "" {
+		info.Version = bi.Main.Version
+	}
+	if info.XGoVersion == "" {
+		info.XGoVersion = depVersion(bi, xgoModulePath)
+	}
+	if info.SpxVersion == "" {
+		info.SpxVersion = depVersion(bi, spxModulePath)
+	}
+	return info
+}
+
+// depVersion returns the version of the dependency module at path in bi, or
+// "" if the module is not linked into the binary.
+func depVersion(bi *debug.BuildInfo, path string) string {
+	for _, dep := range bi.Deps {
+		if dep.Path != path {
+			continue
+		}
+		if dep.Replace != nil {
+			dep = dep.Replace
+		}
+		return dep.Version
+	}
+	return ""
+}
@@ -0,0 +1,35 @@
+package buildinfo
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGet(t *testing.T) {
+	t.Run("Normal", func(t *testing.T) {
+		info := Get()
+		assert.NotEmpty(t, info.GoVersion)
+		assert.Equal(t, runtime.Version(), info.GoVersion)
+	})
+
+	t.Run("InjectedValues", func(t *testing.T) {
+		oldVersion, oldXGoVersion, oldSpxVersion, oldBuildTime := version, xgoVersion, spxVersion, buildTime
+		t.Cleanup(func() {
+			version, xgoVersion, spxVersion, buildTime = oldVersion, oldXGoVersion, oldSpxVersion, oldBuildTime
+		})
+		version = "v1.2.3"
+		xgoVersion = "v1.7.2"
+		spxVersion = "v2.0.4"
+		buildTime = "2025-01-01T00:00:00Z"
+
+		assert.Equal(t, Info{
+			Version:    "v1.2.3",
+			GoVersion:  runtime.Version(),
+			XGoVersion: "v1.7.2",
+			SpxVersion: "v2.0.4",
+			BuildTime:  "2025-01-01T00:00:00Z",
+		}, Get())
+	})
+}
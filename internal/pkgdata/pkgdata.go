@@ -3,6 +3,7 @@ package pkgdata
 import (
 	"archive/zip"
 	"bytes"
+	"container/list"
 	_ "embed"
 	"encoding/json"
 	"errors"
@@ -12,8 +13,10 @@ import (
 	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/goplus/xgolsw/pkgdoc"
+	"golang.org/x/sync/singleflight"
 )
 
 //go:generate sh -c "GOTOOLCHAIN=\"go$(go list -m -f '{{.GoVersion}}')\" go tool pkgdatagen"
@@ -27,9 +30,16 @@ var (
 	customPkgdataZip []byte
 )
 
-// SetCustomPkgdataZip sets the customPkgdataZip.
+// SetCustomPkgdataZip sets the customPkgdataZip. It also flushes the package
+// documentation cache.
 func SetCustomPkgdataZip(data []byte) {
+	pkgDocCache.mu.Lock()
+	defer pkgDocCache.mu.Unlock()
 	customPkgdataZip = data
+	pkgDocCache.gen++
+	pkgDocCache.entries = make(map[pkgDocCacheKey]*list.Element)
+	pkgDocCache.lru.Init()
+	preloaded.Store(false)
 }
 
 const (
@@ -98,22 +108,123 @@ func openExport(zipData []byte, pkgPath string) (io.ReadCloser, error) {
 	return nil, fmt.Errorf("failed to find export file for package %q: %w", pkgPath, fs.ErrNotExist)
 }
 
-// pkgDocCache is a cache for package documentation.
-var pkgDocCache sync.Map // map[string]*pkgdoc.PkgDoc
+// pkgDocCacheCapacity is the maximum number of entries in [pkgDocCache].
+const pkgDocCacheCapacity = 256
+
+// pkgDocCacheKey is the key of a [pkgDocCache] entry. The gen field identifies
+// the zip sources the entry was loaded from, so results loaded before a call
+// to [SetCustomPkgdataZip] are never mixed with those loaded after it.
+type pkgDocCacheKey struct {
+	gen     uint64
+	pkgPath string
+}
+
+// pkgDocCacheEntry is a [pkgDocCache] entry.
+type pkgDocCacheEntry struct {
+	key    pkgDocCacheKey
+	pkgDoc *pkgdoc.PkgDoc
+}
+
+// pkgDocCache is an LRU cache for package documentation.
+var pkgDocCache = struct {
+	mu      sync.Mutex
+	gen     uint64
+	entries map[pkgDocCacheKey]*list.Element
+	lru     list.List // of *pkgDocCacheEntry, most recently used first
+
+	sfg    singleflight.Group
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}{
+	entries: make(map[pkgDocCacheKey]*list.Element),
+}
+
+// CacheStats returns the number of hits and misses of the package
+// documentation cache.
+func CacheStats() (hits, misses uint64) {
+	return pkgDocCache.hits.Load(), pkgDocCache.misses.Load()
+}
 
 // GetPkgDoc gets the documentation for a package.
-func GetPkgDoc(pkgPath string) (pkgDoc *pkgdoc.PkgDoc, err error) {
-	if pkgDocIface, ok := pkgDocCache.Load(pkgPath); ok {
-		return pkgDocIface.(*pkgdoc.PkgDoc), nil
+func GetPkgDoc(pkgPath string) (*pkgdoc.PkgDoc, error) {
+	pkgDocCache.mu.Lock()
+	key := pkgDocCacheKey{gen: pkgDocCache.gen, pkgPath: pkgPath}
+	customZip := customPkgdataZip
+	if elem, ok := pkgDocCache.entries[key]; ok {
+		pkgDocCache.lru.MoveToFront(elem)
+		pkgDocCache.mu.Unlock()
+		pkgDocCache.hits.Add(1)
+		return elem.Value.(*pkgDocCacheEntry).pkgDoc, nil
 	}
-	defer func() {
-		if err == nil {
-			pkgDocCache.Store(pkgPath, pkgDoc)
+	pkgDocCache.mu.Unlock()
+	pkgDocCache.misses.Add(1)
+
+	v, err, _ := pkgDocCache.sfg.Do(fmt.Sprintf("%d:%s", key.gen, key.pkgPath), func() (any, error) {
+		// A previous call may have finished loading after the lookup above.
+		pkgDocCache.mu.Lock()
+		elem, ok := pkgDocCache.entries[key]
+		pkgDocCache.mu.Unlock()
+		if ok {
+			return elem.Value.(*pkgDocCacheEntry).pkgDoc, nil
 		}
-	}()
 
-	if len(customPkgdataZip) > 0 {
-		pkgDoc, err = getPkgDoc(customPkgdataZip, pkgPath)
+		pkgDoc, err := loadPkgDoc(customZip, pkgPath)
+		if err != nil {
+			return nil, err
+		}
+
+		pkgDocCache.mu.Lock()
+		defer pkgDocCache.mu.Unlock()
+		if key.gen == pkgDocCache.gen {
+			if elem, ok := pkgDocCache.entries[key]; ok {
+				return elem.Value.(*pkgDocCacheEntry).pkgDoc, nil
+			}
+			pkgDocCache.entries[key] = pkgDocCache.lru.PushFront(&pkgDocCacheEntry{key: key, pkgDoc: pkgDoc})
+			if pkgDocCache.lru.Len() > pkgDocCacheCapacity {
+				oldest := pkgDocCache.lru.Back()
+				pkgDocCache.lru.Remove(oldest)
+				delete(pkgDocCache.entries, oldest.Value.(*pkgDocCacheEntry).key)
+			}
+		}
+		return pkgDoc, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*pkgdoc.PkgDoc), nil
+}
+
+// preloaded reports whether [PreloadAll] has completed successfully since the
+// last call to [SetCustomPkgdataZip].
+var preloaded atomic.Bool
+
+// PreloadAll loads the documentation of all packages listed by [ListPkgs]
+// into the package documentation cache.
+func PreloadAll() error {
+	pkgs, err := ListPkgs()
+	if err != nil {
+		return err
+	}
+	for _, pkg := range pkgs {
+		if _, err := GetPkgDoc(pkg); err != nil {
+			return fmt.Errorf("failed to preload package doc for %q: %w", pkg, err)
+		}
+	}
+	preloaded.Store(true)
+	return nil
+}
+
+// IsPreloaded reports whether [PreloadAll] has completed successfully since
+// the package data was last changed.
+func IsPreloaded() bool {
+	return preloaded.Load()
+}
+
+// loadPkgDoc loads the documentation for a package from customZip, falling
+// back to the embedded package data.
+func loadPkgDoc(customZip []byte, pkgPath string) (*pkgdoc.PkgDoc, error) {
+	if len(customZip) > 0 {
+		pkgDoc, err := getPkgDoc(customZip, pkgPath)
 		if err == nil {
 			return pkgDoc, nil
 		} else if !errors.Is(err, fs.ErrNotExist) {
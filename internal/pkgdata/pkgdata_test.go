@@ -0,0 +1,143 @@
+package pkgdata
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/goplus/xgolsw/pkgdoc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPkgDataEntries returns n package data entries for packages named
+// example.com/pkg0, example.com/pkg1, and so on.
+func testPkgDataEntries(n int) []PkgDataEntry {
+	entries := make([]PkgDataEntry, n)
+	for i := range entries {
+		pkgPath := fmt.Sprintf("example.com/pkg%d", i)
+		entries[i] = PkgDataEntry{
+			PkgPath:    pkgPath,
+			Doc:        &pkgdoc.PkgDoc{Path: pkgPath, Name: fmt.Sprintf("pkg%d", i)},
+			ExportData: []byte("export data"),
+		}
+	}
+	return entries
+}
+
+func TestGetPkgDoc(t *testing.T) {
+	t.Run("Embedded", func(t *testing.T) {
+		pkgDoc, err := GetPkgDoc("fmt")
+		require.NoError(t, err)
+		assert.Equal(t, "fmt", pkgDoc.Name)
+		assert.Contains(t, pkgDoc.Funcs, "Println")
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := GetPkgDoc("example.com/nonexistent")
+		assert.ErrorContains(t, err, `failed to find doc file for package "example.com/nonexistent"`)
+	})
+
+	t.Run("CacheHit", func(t *testing.T) {
+		setCustomPkgdataEntries(t, testPkgDataEntries(1)...)
+
+		hits, misses := CacheStats()
+		first, err := GetPkgDoc("example.com/pkg0")
+		require.NoError(t, err)
+		second, err := GetPkgDoc("example.com/pkg0")
+		require.NoError(t, err)
+		assert.Same(t, first, second)
+
+		newHits, newMisses := CacheStats()
+		assert.Equal(t, hits+1, newHits)
+		assert.Equal(t, misses+1, newMisses)
+	})
+
+	t.Run("LRUBound", func(t *testing.T) {
+		entries := testPkgDataEntries(pkgDocCacheCapacity + 1)
+		setCustomPkgdataEntries(t, entries...)
+
+		for _, entry := range entries {
+			_, err := GetPkgDoc(entry.PkgPath)
+			require.NoError(t, err)
+		}
+
+		pkgDocCache.mu.Lock()
+		gen := pkgDocCache.gen
+		assert.Equal(t, pkgDocCacheCapacity, pkgDocCache.lru.Len())
+		assert.Len(t, pkgDocCache.entries, pkgDocCacheCapacity)
+		assert.NotContains(t, pkgDocCache.entries, pkgDocCacheKey{gen: gen, pkgPath: entries[0].PkgPath})
+		assert.Contains(t, pkgDocCache.entries, pkgDocCacheKey{gen: gen, pkgPath: entries[1].PkgPath})
+		assert.Contains(t, pkgDocCache.entries, pkgDocCacheKey{gen: gen, pkgPath: entries[pkgDocCacheCapacity].PkgPath})
+		pkgDocCache.mu.Unlock()
+
+		// The evicted package is loaded again on the next call.
+		_, misses := CacheStats()
+		_, err := GetPkgDoc(entries[0].PkgPath)
+		require.NoError(t, err)
+		_, newMisses := CacheStats()
+		assert.Equal(t, misses+1, newMisses)
+	})
+
+	t.Run("ConcurrentCallsShareResult", func(t *testing.T) {
+		setCustomPkgdataEntries(t, testPkgDataEntries(1)...)
+
+		const n = 16
+		var (
+			wg      sync.WaitGroup
+			start   = make(chan struct{})
+			pkgDocs = make([]*pkgdoc.PkgDoc, n)
+			errs    = make([]error, n)
+		)
+		for i := range n {
+			wg.Go(func() {
+				<-start
+				pkgDocs[i], errs[i] = GetPkgDoc("example.com/pkg0")
+			})
+		}
+		close(start)
+		wg.Wait()
+
+		for i := range n {
+			require.NoError(t, errs[i])
+			assert.Same(t, pkgDocs[0], pkgDocs[i])
+		}
+	})
+
+	t.Run("InvalidatedByCustomPkgdata", func(t *testing.T) {
+		setCustomPkgdataEntries(t, testPkgDataEntries(1)...)
+		before, err := GetPkgDoc("example.com/pkg0")
+		require.NoError(t, err)
+
+		setCustomPkgdataEntries(t, testPkgDataEntries(1)...)
+		after, err := GetPkgDoc("example.com/pkg0")
+		require.NoError(t, err)
+		assert.NotSame(t, before, after)
+		assert.Equal(t, before, after)
+	})
+}
+
+func TestPreloadAll(t *testing.T) {
+	setCustomPkgdataEntries(t, testPkgDataEntries(3)...)
+	require.False(t, IsPreloaded())
+
+	require.NoError(t, PreloadAll())
+	assert.True(t, IsPreloaded())
+
+	pkgs, err := ListPkgs()
+	require.NoError(t, err)
+	require.Contains(t, pkgs, "fmt")
+	require.Contains(t, pkgs, "example.com/pkg0")
+
+	hits, misses := CacheStats()
+	for _, pkg := range pkgs {
+		_, err := GetPkgDoc(pkg)
+		require.NoError(t, err)
+	}
+	newHits, newMisses := CacheStats()
+	assert.Equal(t, hits+uint64(len(pkgs)), newHits)
+	assert.Equal(t, misses, newMisses)
+
+	SetCustomPkgdataZip(nil)
+	assert.False(t, IsPreloaded())
+}
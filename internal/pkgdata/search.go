@@ -0,0 +1,155 @@
+package pkgdata
+
+import (
+	"cmp"
+	"container/list"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/goplus/xgolsw/pkgdoc"
+)
+
+// Symbol kinds of [PkgDocSearchResult].
+const (
+	SymbolKindFunc  = "func"
+	SymbolKindType  = "type"
+	SymbolKindVar   = "var"
+	SymbolKindConst = "const"
+)
+
+// PkgDocSearchResult is a package-level symbol found by [SearchPkgDoc].
+type PkgDocSearchResult struct {
+	PkgPath    string `json:"pkgPath"`
+	Name       string `json:"name"`
+	SymbolKind string `json:"symbolKind"`
+	Doc        string `json:"doc"`
+}
+
+// SearchPkgDoc searches the documentation of all packages for package-level
+// symbols whose names contain name, ignoring case. The results are sorted by
+// package path, symbol kind, and symbol name.
+func SearchPkgDoc(name string) []PkgDocSearchResult {
+	term := strings.ToLower(name)
+	if term == "" {
+		return nil
+	}
+
+	pkgDocCache.mu.Lock()
+	key := searchCacheKey{gen: pkgDocCache.gen, term: term}
+	pkgDocCache.mu.Unlock()
+	if results, ok := searchCache.get(key); ok {
+		return slices.Clone(results)
+	}
+
+	pkgs, err := ListPkgs()
+	if err != nil {
+		return nil
+	}
+	var results []PkgDocSearchResult
+	for _, pkgPath := range pkgs {
+		pkgDoc, err := GetPkgDoc(pkgPath)
+		if err != nil {
+			continue
+		}
+		results = appendPkgDocSearchResults(results, pkgPath, pkgDoc, term)
+	}
+	slices.SortFunc(results, func(a, b PkgDocSearchResult) int {
+		return cmp.Or(
+			cmp.Compare(a.PkgPath, b.PkgPath),
+			cmp.Compare(a.SymbolKind, b.SymbolKind),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+
+	searchCache.put(key, results)
+	return slices.Clone(results)
+}
+
+// appendPkgDocSearchResults appends the package-level symbols of pkgDoc whose
+// lowercased names contain term to results.
+func appendPkgDocSearchResults(results []PkgDocSearchResult, pkgPath string, pkgDoc *pkgdoc.PkgDoc, term string) []PkgDocSearchResult {
+	appendMatches := func(kind string, docs map[string]string) {
+		for name, doc := range docs {
+			if strings.Contains(strings.ToLower(name), term) {
+				results = append(results, PkgDocSearchResult{
+					PkgPath:    pkgPath,
+					Name:       name,
+					SymbolKind: kind,
+					Doc:        doc,
+				})
+			}
+		}
+	}
+	appendMatches(SymbolKindFunc, pkgDoc.Funcs)
+	appendMatches(SymbolKindVar, pkgDoc.Vars)
+	appendMatches(SymbolKindConst, pkgDoc.Consts)
+	for name, typeDoc := range pkgDoc.Types {
+		if strings.Contains(strings.ToLower(name), term) {
+			results = append(results, PkgDocSearchResult{
+				PkgPath:    pkgPath,
+				Name:       name,
+				SymbolKind: SymbolKindType,
+				Doc:        typeDoc.Doc,
+			})
+		}
+	}
+	return results
+}
+
+// searchCacheCapacity is the maximum number of entries in [searchCache].
+const searchCacheCapacity = 64
+
+// searchCacheKey is the key of a [searchCache] entry. Like [pkgDocCacheKey],
+// the gen field keeps results from before a call to [SetCustomPkgdataZip]
+// from being reused after it.
+type searchCacheKey struct {
+	gen  uint64
+	term string
+}
+
+// searchCacheEntry is a [searchCache] entry.
+type searchCacheEntry struct {
+	key     searchCacheKey
+	results []PkgDocSearchResult
+}
+
+// searchCache is an LRU cache for [SearchPkgDoc] results.
+var searchCache = &searchLRU{
+	entries: make(map[searchCacheKey]*list.Element),
+}
+
+// searchLRU is the type of [searchCache].
+type searchLRU struct {
+	mu      sync.Mutex
+	entries map[searchCacheKey]*list.Element
+	lru     list.List // of *searchCacheEntry, most recently used first
+}
+
+// get returns the cached results for key.
+func (c *searchLRU) get(key searchCacheKey) ([]PkgDocSearchResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.lru.MoveToFront(elem)
+	return elem.Value.(*searchCacheEntry).results, true
+}
+
+// put caches results for key, evicting the least recently used entry if the
+// cache is full.
+func (c *searchLRU) put(key searchCacheKey, results []PkgDocSearchResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; ok {
+		return
+	}
+	c.entries[key] = c.lru.PushFront(&searchCacheEntry{key: key, results: results})
+	if c.lru.Len() > searchCacheCapacity {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*searchCacheEntry).key)
+	}
+}
@@ -0,0 +1,102 @@
+package pkgdata
+
+import (
+	"bytes"
+	"container/list"
+	"fmt"
+	"testing"
+
+	"github.com/goplus/xgolsw/pkgdoc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setCustomPkgdataEntries sets the custom package data to a zip containing
+// entries and restores it when t finishes.
+func setCustomPkgdataEntries(t *testing.T, entries ...PkgDataEntry) {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, WriteZip(&buf, entries))
+	SetCustomPkgdataZip(buf.Bytes())
+	t.Cleanup(func() { SetCustomPkgdataZip(nil) })
+}
+
+func TestSearchPkgDoc(t *testing.T) {
+	t.Run("Println", func(t *testing.T) {
+		results := SearchPkgDoc("Println")
+		assert.Contains(t, results, PkgDocSearchResult{
+			PkgPath:    "fmt",
+			Name:       "Println",
+			SymbolKind: SymbolKindFunc,
+			Doc:        mustGetPkgDoc(t, "fmt").Funcs["Println"],
+		})
+		for _, result := range results {
+			assert.Contains(t, result.Name, "rintln")
+		}
+	})
+
+	t.Run("IgnoreCase", func(t *testing.T) {
+		assert.Equal(t, SearchPkgDoc("Println"), SearchPkgDoc("pRINTLN"))
+	})
+
+	t.Run("EmptyName", func(t *testing.T) {
+		assert.Nil(t, SearchPkgDoc(""))
+	})
+
+	t.Run("InvalidatedByCustomPkgdata", func(t *testing.T) {
+		const name = "XGolswSearchTestFunc"
+		assert.Empty(t, SearchPkgDoc(name))
+
+		setCustomPkgdataEntries(t, PkgDataEntry{
+			PkgPath: "example.com/foo",
+			Doc: &pkgdoc.PkgDoc{
+				Path:  "example.com/foo",
+				Name:  "foo",
+				Funcs: map[string]string{name: "doc"},
+			},
+			ExportData: []byte("export data"),
+		})
+		assert.Equal(t, []PkgDocSearchResult{{
+			PkgPath:    "example.com/foo",
+			Name:       name,
+			SymbolKind: SymbolKindFunc,
+			Doc:        "doc",
+		}}, SearchPkgDoc(name))
+
+		SetCustomPkgdataZip(nil)
+		assert.Empty(t, SearchPkgDoc(name))
+	})
+}
+
+func TestSearchLRU(t *testing.T) {
+	c := &searchLRU{entries: make(map[searchCacheKey]*list.Element)}
+	keyOf := func(i int) searchCacheKey {
+		return searchCacheKey{term: fmt.Sprint(i)}
+	}
+	for i := range searchCacheCapacity {
+		c.put(keyOf(i), []PkgDocSearchResult{{Name: fmt.Sprint(i)}})
+	}
+
+	// Touch the oldest entry so that the second oldest is evicted next.
+	_, ok := c.get(keyOf(0))
+	require.True(t, ok)
+	c.put(keyOf(searchCacheCapacity), nil)
+
+	assert.Equal(t, searchCacheCapacity, c.lru.Len())
+	assert.Len(t, c.entries, searchCacheCapacity)
+	_, ok = c.get(keyOf(1))
+	assert.False(t, ok)
+	results, ok := c.get(keyOf(0))
+	require.True(t, ok)
+	assert.Equal(t, []PkgDocSearchResult{{Name: "0"}}, results)
+	_, ok = c.get(keyOf(searchCacheCapacity))
+	assert.True(t, ok)
+}
+
+// mustGetPkgDoc returns the documentation for pkgPath.
+func mustGetPkgDoc(t *testing.T, pkgPath string) *pkgdoc.PkgDoc {
+	t.Helper()
+	pkgDoc, err := GetPkgDoc(pkgPath)
+	require.NoError(t, err)
+	return pkgDoc
+}
@@ -0,0 +1,144 @@
+package pkgdata
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/goplus/xgolsw/pkgdoc"
+)
+
+// PkgDataEntry is the package data of a single package.
+type PkgDataEntry struct {
+	// PkgPath is the package path.
+	PkgPath string
+
+	// Doc is the package documentation. It is omitted from the zip if nil.
+	Doc *pkgdoc.PkgDoc
+
+	// ExportData is the package export data in the format produced by
+	// golang.org/x/tools/go/gcexportdata. It is omitted from the zip if nil.
+	ExportData []byte
+}
+
+// WriteZip writes entries to w in the same zip layout as the embedded package
+// data, so the result can be used with [SetCustomPkgdataZip].
+func WriteZip(w io.Writer, entries []PkgDataEntry) error {
+	zw := zip.NewWriter(w)
+	for _, entry := range entries {
+		if entry.ExportData != nil {
+			zf, err := zw.Create(entry.PkgPath + pkgExportSuffix)
+			if err != nil {
+				return fmt.Errorf("failed to create export file for package %q: %w", entry.PkgPath, err)
+			}
+			if _, err := zf.Write(entry.ExportData); err != nil {
+				return fmt.Errorf("failed to write export data for package %q: %w", entry.PkgPath, err)
+			}
+		}
+		if entry.Doc != nil {
+			zf, err := zw.Create(entry.PkgPath + pkgDocSuffix)
+			if err != nil {
+				return fmt.Errorf("failed to create doc file for package %q: %w", entry.PkgPath, err)
+			}
+			if err := json.NewEncoder(zf).Encode(entry.Doc); err != nil {
+				return fmt.Errorf("failed to encode doc for package %q: %w", entry.PkgPath, err)
+			}
+		}
+	}
+	return zw.Close()
+}
+
+// ReadZip reads the package data entries from the zip in r, which has the
+// given size. Entries are returned in the order their packages first appear
+// in the zip. Files other than package export and doc files are ignored.
+func ReadZip(r io.ReaderAt, size int64) ([]PkgDataEntry, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create zip reader: %w", err)
+	}
+
+	var (
+		entries      []PkgDataEntry
+		entryIndexes = make(map[string]int)
+	)
+	entryFor := func(pkgPath string) *PkgDataEntry {
+		i, ok := entryIndexes[pkgPath]
+		if !ok {
+			i = len(entries)
+			entryIndexes[pkgPath] = i
+			entries = append(entries, PkgDataEntry{PkgPath: pkgPath})
+		}
+		return &entries[i]
+	}
+	for _, f := range zr.File {
+		if pkgPath, ok := strings.CutSuffix(f.Name, pkgExportSuffix); ok {
+			exportData, err := readZipFile(f)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read export file for package %q: %w", pkgPath, err)
+			}
+			entryFor(pkgPath).ExportData = exportData
+		} else if pkgPath, ok := strings.CutSuffix(f.Name, pkgDocSuffix); ok {
+			rc, err := f.Open()
+			if err != nil {
+				return nil, fmt.Errorf("failed to open doc file for package %q: %w", pkgPath, err)
+			}
+			var pkgDoc pkgdoc.PkgDoc
+			err = json.NewDecoder(rc).Decode(&pkgDoc)
+			rc.Close()
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode doc for package %q: %w", pkgPath, err)
+			}
+			entryFor(pkgPath).Doc = &pkgDoc
+		}
+	}
+	return entries, nil
+}
+
+// ExportPackageDoc returns a zip in the same layout as the embedded package
+// data that contains the doc and, if not nil, the export data of the single
+// package pkgPath. It is the reverse of [ImportPackageDoc].
+func ExportPackageDoc(pkgPath string, doc *pkgdoc.PkgDoc, exportData []byte) ([]byte, error) {
+	if doc == nil {
+		return nil, errors.New("doc must not be nil")
+	}
+	var buf bytes.Buffer
+	if err := WriteZip(&buf, []PkgDataEntry{{
+		PkgPath:    pkgPath,
+		Doc:        doc,
+		ExportData: exportData,
+	}}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ImportPackageDoc reads the single package data entry from the zip in data,
+// as produced by [ExportPackageDoc].
+func ImportPackageDoc(data []byte) (*PkgDataEntry, error) {
+	entries, err := ReadZip(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) != 1 {
+		return nil, fmt.Errorf("expected 1 package, got %d", len(entries))
+	}
+	entry := entries[0]
+	if entry.Doc == nil {
+		return nil, fmt.Errorf("missing doc for package %q", entry.PkgPath)
+	}
+	return &entry, nil
+}
+
+// readZipFile reads the whole content of the zip file f.
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
@@ -0,0 +1,143 @@
+package pkgdata
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/goplus/xgolsw/pkgdoc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteZipReadZip(t *testing.T) {
+	t.Run("RoundTrip", func(t *testing.T) {
+		entries := []PkgDataEntry{
+			{
+				PkgPath: "example.com/foo",
+				Doc: &pkgdoc.PkgDoc{
+					Doc:    "Package foo does things.",
+					Path:   "example.com/foo",
+					Name:   "foo",
+					Vars:   map[string]string{"V": "V is a variable."},
+					Consts: map[string]string{"C": "C is a constant."},
+					Types: map[string]*pkgdoc.TypeDoc{
+						"T": {
+							Doc:     "T is a type.",
+							Fields:  map[string]string{"F": "F is a field."},
+							Methods: map[string]string{"M": "M is a method."},
+						},
+					},
+					Funcs: map[string]string{"Fn": "Fn is a function."},
+				},
+				ExportData: []byte("foo export data"),
+			},
+			{
+				PkgPath:    "example.com/bar",
+				ExportData: []byte("bar export data"),
+			},
+			{
+				PkgPath: "example.com/baz",
+				Doc:     &pkgdoc.PkgDoc{Path: "example.com/baz", Name: "baz"},
+			},
+		}
+
+		var buf bytes.Buffer
+		require.NoError(t, WriteZip(&buf, entries))
+		got, err := ReadZip(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		require.NoError(t, err)
+		assert.Equal(t, entries, got)
+	})
+
+	t.Run("IgnoreOtherFiles", func(t *testing.T) {
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		zf, err := zw.Create("README")
+		require.NoError(t, err)
+		_, err = zf.Write([]byte("not package data"))
+		require.NoError(t, err)
+		zf, err = zw.Create("example.com/foo" + pkgExportSuffix)
+		require.NoError(t, err)
+		_, err = zf.Write([]byte("foo export data"))
+		require.NoError(t, err)
+		require.NoError(t, zw.Close())
+
+		got, err := ReadZip(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		require.NoError(t, err)
+		assert.Equal(t, []PkgDataEntry{{
+			PkgPath:    "example.com/foo",
+			ExportData: []byte("foo export data"),
+		}}, got)
+	})
+
+	t.Run("InvalidZip", func(t *testing.T) {
+		data := []byte("not a zip")
+		_, err := ReadZip(bytes.NewReader(data), int64(len(data)))
+		assert.Error(t, err)
+	})
+}
+
+func TestExportImportPackageDoc(t *testing.T) {
+	doc := &pkgdoc.PkgDoc{
+		Doc:    "Package foo does things.",
+		Path:   "example.com/foo",
+		Name:   "foo",
+		Vars:   map[string]string{},
+		Consts: map[string]string{},
+		Types: map[string]*pkgdoc.TypeDoc{
+			"T": {
+				Doc:        "T is a type.",
+				Fields:     map[string]string{},
+				Methods:    map[string]string{"M": "M is a method."},
+				TypeParams: []string{"E"},
+			},
+		},
+		Funcs:    map[string]string{"Fn": "Fn is a function."},
+		Examples: map[string]string{"Fn": "foo.Fn()"},
+	}
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		data, err := ExportPackageDoc("example.com/foo", doc, []byte("export data"))
+		require.NoError(t, err)
+		entry, err := ImportPackageDoc(data)
+		require.NoError(t, err)
+		assert.Equal(t, &PkgDataEntry{
+			PkgPath:    "example.com/foo",
+			Doc:        doc,
+			ExportData: []byte("export data"),
+		}, entry)
+	})
+
+	t.Run("WithoutExportData", func(t *testing.T) {
+		data, err := ExportPackageDoc("example.com/foo", doc, nil)
+		require.NoError(t, err)
+		entry, err := ImportPackageDoc(data)
+		require.NoError(t, err)
+		assert.Equal(t, doc, entry.Doc)
+		assert.Nil(t, entry.ExportData)
+	})
+
+	t.Run("NilDoc", func(t *testing.T) {
+		_, err := ExportPackageDoc("example.com/foo", nil, nil)
+		assert.EqualError(t, err, "doc must not be nil")
+	})
+
+	t.Run("MultiplePackages", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, WriteZip(&buf, []PkgDataEntry{
+			{PkgPath: "example.com/foo", Doc: doc},
+			{PkgPath: "example.com/bar", Doc: doc},
+		}))
+		_, err := ImportPackageDoc(buf.Bytes())
+		assert.EqualError(t, err, "expected 1 package, got 2")
+	})
+
+	t.Run("MissingDoc", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, WriteZip(&buf, []PkgDataEntry{
+			{PkgPath: "example.com/foo", ExportData: []byte("export data")},
+		}))
+		_, err := ImportPackageDoc(buf.Bytes())
+		assert.EqualError(t, err, `missing doc for package "example.com/foo"`)
+	})
+}
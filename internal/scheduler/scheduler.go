@@ -0,0 +1,53 @@
+// Package scheduler implements the policies used to yield the processor to
+// the JavaScript event loop in browsers.
+package scheduler
+
+import (
+	"fmt"
+	"time"
+)
+
+// Policy is a policy of yielding the processor for a bounded duration.
+type Policy string
+
+// Supported policies.
+const (
+	// PolicySetTimeout always yields with `setTimeout`.
+	PolicySetTimeout Policy = "setTimeout"
+
+	// PolicyRequestAnimationFrame always yields until the next frame with
+	// `requestAnimationFrame`.
+	PolicyRequestAnimationFrame Policy = "requestAnimationFrame"
+
+	// PolicyAuto yields until the next frame only if the duration allows a
+	// full frame, and with `setTimeout` otherwise.
+	PolicyAuto Policy = "auto"
+)
+
+// FrameDuration is the duration of a display frame at 60 FPS.
+const FrameDuration = 16 * time.Millisecond
+
+// ParsePolicy parses the policy with the given name.
+func ParsePolicy(name string) (Policy, error) {
+	switch policy := Policy(name); policy {
+	case PolicySetTimeout, PolicyRequestAnimationFrame, PolicyAuto:
+		return policy, nil
+	}
+	return "", fmt.Errorf("unknown policy %q", name)
+}
+
+// UseRequestAnimationFrame reports whether `requestAnimationFrame` should be
+// used to yield for at most maxWait under the policy. It always reports false
+// if `requestAnimationFrame` is not available, e.g., in some workers.
+func (p Policy) UseRequestAnimationFrame(maxWait time.Duration, available bool) bool {
+	if !available {
+		return false
+	}
+	switch p {
+	case PolicyRequestAnimationFrame:
+		return true
+	case PolicyAuto:
+		return maxWait >= FrameDuration
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePolicy(t *testing.T) {
+	for _, name := range []string{"setTimeout", "requestAnimationFrame", "auto"} {
+		policy, err := ParsePolicy(name)
+		require.NoError(t, err)
+		assert.Equal(t, Policy(name), policy)
+	}
+
+	_, err := ParsePolicy("idle")
+	assert.EqualError(t, err, `unknown policy "idle"`)
+}
+
+func TestPolicyUseRequestAnimationFrame(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		policy    Policy
+		maxWait   time.Duration
+		available bool
+		want      bool
+	}{
+		{"AutoFullFrame", PolicyAuto, FrameDuration, true, true},
+		{"AutoLongerThanFrame", PolicyAuto, 100 * time.Millisecond, true, true},
+		{"AutoShorterThanFrame", PolicyAuto, FrameDuration - time.Millisecond, true, false},
+		{"AutoZero", PolicyAuto, 0, true, false},
+		{"AutoUnavailable", PolicyAuto, FrameDuration, false, false},
+		{"SetTimeout", PolicySetTimeout, 100 * time.Millisecond, true, false},
+		{"RequestAnimationFrame", PolicyRequestAnimationFrame, 0, true, true},
+		{"RequestAnimationFrameUnavailable", PolicyRequestAnimationFrame, 0, false, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.policy.UseRequestAnimationFrame(tt.maxWait, tt.available))
+		})
+	}
+}
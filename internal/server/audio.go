@@ -0,0 +1,207 @@
+package server
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// audioInfo is the metadata parsed from the header of an audio file.
+type audioInfo struct {
+	duration   int64 // In milliseconds.
+	sampleRate int
+	channels   int
+}
+
+// parseAudioInfo parses the metadata of the audio file in data of the given
+// format, which is one of "wav", "mp3", and "ogg". It reports false if the
+// format is not supported or data is not a valid file of the format.
+func parseAudioInfo(format string, data []byte) (audioInfo, bool) {
+	switch format {
+	case "wav":
+		return parseWAVInfo(data)
+	case "mp3":
+		return parseMP3Info(data)
+	case "ogg":
+		return parseOggVorbisInfo(data)
+	}
+	return audioInfo{}, false
+}
+
+// parseWAVInfo parses the metadata of the RIFF WAVE file in data.
+func parseWAVInfo(data []byte) (audioInfo, bool) {
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		return audioInfo{}, false
+	}
+
+	var (
+		info     audioInfo
+		byteRate uint32
+		hasFmt   bool
+	)
+	for chunks := data[12:]; len(chunks) >= 8; {
+		id := string(chunks[:4])
+		size := int(binary.LittleEndian.Uint32(chunks[4:8]))
+		body := chunks[8:]
+		switch id {
+		case "fmt ":
+			if len(body) < 16 {
+				return audioInfo{}, false
+			}
+			info.channels = int(binary.LittleEndian.Uint16(body[2:4]))
+			info.sampleRate = int(binary.LittleEndian.Uint32(body[4:8]))
+			byteRate = binary.LittleEndian.Uint32(body[8:12])
+			hasFmt = true
+		case "data":
+			if !hasFmt || byteRate == 0 {
+				return audioInfo{}, false
+			}
+			// The size of the data chunk may be larger than the actual data
+			// of a truncated or streamed file.
+			size = min(size, len(body))
+			info.duration = int64(size) * 1000 / int64(byteRate)
+			return info, true
+		}
+
+		size += size & 1 // Chunks are padded to an even size.
+		if size > len(body) {
+			break
+		}
+		chunks = body[size:]
+	}
+	return audioInfo{}, false
+}
+
+// mp3 bitrates in kbps, indexed by [mpeg1?0:1][layer-1][bitrate index].
+var mp3Bitrates = [2][3][15]int{
+	{
+		{0, 32, 64, 96, 128, 160, 192, 224, 256, 288, 320, 352, 384, 416, 448},
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 384},
+		{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320},
+	},
+	{
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 144, 160, 176, 192, 224, 256},
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160},
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160},
+	},
+}
+
+// mp3SampleRates are the MPEG audio sample rates in Hz, indexed by
+// [version bits][sample rate index]. Version bits 1 are reserved.
+var mp3SampleRates = [4][3]int{
+	{11025, 12000, 8000},  // MPEG 2.5
+	{},                    // Reserved
+	{22050, 24000, 16000}, // MPEG 2
+	{44100, 48000, 32000}, // MPEG 1
+}
+
+// parseMP3Info parses the metadata of the MP3 file in data. The duration is
+// computed from the frame count of a Xing or Info header if present, and from
+// the bitrate of the first frame otherwise.
+func parseMP3Info(data []byte) (audioInfo, bool) {
+	// Skip the ID3v2 tag, if any.
+	start := 0
+	if len(data) >= 10 && string(data[:3]) == "ID3" {
+		tagSize := int(data[6]&0x7f)<<21 | int(data[7]&0x7f)<<14 | int(data[8]&0x7f)<<7 | int(data[9]&0x7f)
+		start = 10 + tagSize
+		if data[5]&0x10 != 0 {
+			start += 10 // Footer.
+		}
+	}
+	end := len(data)
+	if end-128 >= start && string(data[end-128:end-125]) == "TAG" {
+		end -= 128 // ID3v1 tag.
+	}
+
+	for i := start; i+4 <= end; i++ {
+		if data[i] != 0xff || data[i+1]&0xe0 != 0xe0 {
+			continue
+		}
+		version := int(data[i+1]>>3) & 3
+		layer := 4 - int(data[i+1]>>1)&3
+		bitrateIndex := int(data[i+2] >> 4)
+		sampleRateIndex := int(data[i+2]>>2) & 3
+		if version == 1 || layer == 4 || bitrateIndex == 0 || bitrateIndex == 15 || sampleRateIndex == 3 {
+			continue
+		}
+
+		isMPEG1 := version == 3
+		table := 1
+		if isMPEG1 {
+			table = 0
+		}
+		bitrate := mp3Bitrates[table][layer-1][bitrateIndex]
+		info := audioInfo{
+			sampleRate: mp3SampleRates[version][sampleRateIndex],
+			channels:   2,
+		}
+		mono := data[i+3]>>6 == 3
+		if mono {
+			info.channels = 1
+		}
+
+		samplesPerFrame := 1152
+		switch {
+		case layer == 1:
+			samplesPerFrame = 384
+		case layer == 3 && !isMPEG1:
+			samplesPerFrame = 576
+		}
+
+		// Look for a Xing or Info header after the side information of the
+		// first frame.
+		sideInfoSize := 32
+		switch {
+		case isMPEG1 && mono, !isMPEG1 && !mono:
+			sideInfoSize = 17
+		case !isMPEG1 && mono:
+			sideInfoSize = 9
+		}
+		if xing := i + 4 + sideInfoSize; layer == 3 && xing+12 <= end {
+			tag := string(data[xing : xing+4])
+			flags := binary.BigEndian.Uint32(data[xing+4 : xing+8])
+			if (tag == "Xing" || tag == "Info") && flags&1 != 0 {
+				frames := int64(binary.BigEndian.Uint32(data[xing+8 : xing+12]))
+				info.duration = frames * int64(samplesPerFrame) * 1000 / int64(info.sampleRate)
+				return info, true
+			}
+		}
+
+		info.duration = int64(end-i) * 8 / int64(bitrate)
+		return info, true
+	}
+	return audioInfo{}, false
+}
+
+// parseOggVorbisInfo parses the metadata of the Ogg Vorbis file in data. The
+// duration is computed from the granule position of the last page.
+func parseOggVorbisInfo(data []byte) (audioInfo, bool) {
+	const (
+		pageHeaderSize = 27
+		idHeaderSize   = 16 // Up to and including the sample rate.
+	)
+	if len(data) < pageHeaderSize || string(data[:4]) != "OggS" {
+		return audioInfo{}, false
+	}
+	segmentCount := int(data[26])
+	idHeader := pageHeaderSize + segmentCount
+	if len(data) < idHeader+idHeaderSize || string(data[idHeader:idHeader+7]) != "\x01vorbis" {
+		return audioInfo{}, false
+	}
+	info := audioInfo{
+		channels:   int(data[idHeader+11]),
+		sampleRate: int(binary.LittleEndian.Uint32(data[idHeader+12 : idHeader+16])),
+	}
+	if info.sampleRate == 0 {
+		return audioInfo{}, false
+	}
+
+	lastPage := bytes.LastIndex(data, []byte("OggS"))
+	if lastPage+pageHeaderSize > len(data) {
+		return info, true
+	}
+	granule := int64(binary.LittleEndian.Uint64(data[lastPage+6 : lastPage+14]))
+	if granule > 0 {
+		info.duration = granule * 1000 / int64(info.sampleRate)
+	}
+	return info, true
+}
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestWAV returns a PCM WAV file with the given number of samples of
+// silence.
+func newTestWAV(sampleRate, channels, bitsPerSample, samples int) []byte {
+	blockAlign := channels * bitsPerSample / 8
+	dataSize := samples * blockAlign
+
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(4+8+16+8+dataSize))
+	buf.WriteString("WAVE")
+	buf.WriteString("LIST")
+	binary.Write(&buf, binary.LittleEndian, uint32(3))
+	buf.WriteString("abc\x00") // Odd-sized chunk with padding.
+	buf.WriteString("fmt ")
+	binary.Write(&buf, binary.LittleEndian, uint32(16))
+	binary.Write(&buf, binary.LittleEndian, uint16(1)) // PCM.
+	binary.Write(&buf, binary.LittleEndian, uint16(channels))
+	binary.Write(&buf, binary.LittleEndian, uint32(sampleRate))
+	binary.Write(&buf, binary.LittleEndian, uint32(sampleRate*blockAlign))
+	binary.Write(&buf, binary.LittleEndian, uint16(blockAlign))
+	binary.Write(&buf, binary.LittleEndian, uint16(bitsPerSample))
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, uint32(dataSize))
+	buf.Write(make([]byte, dataSize))
+	return buf.Bytes()
+}
+
+func TestParseWAVInfo(t *testing.T) {
+	t.Run("Normal", func(t *testing.T) {
+		info, ok := parseWAVInfo(newTestWAV(44100, 2, 16, 22050))
+		assert.True(t, ok)
+		assert.Equal(t, audioInfo{duration: 500, sampleRate: 44100, channels: 2}, info)
+	})
+
+	t.Run("Truncated", func(t *testing.T) {
+		data := newTestWAV(8000, 1, 8, 8000)
+		info, ok := parseWAVInfo(data[:len(data)-4000])
+		assert.True(t, ok)
+		assert.Equal(t, int64(500), info.duration)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, ok := parseWAVInfo([]byte("not a wav file"))
+		assert.False(t, ok)
+	})
+}
+
+func TestParseMP3Info(t *testing.T) {
+	// MPEG 1 Layer III, 128 kbps, 44100 Hz, joint stereo.
+	frameHeader := []byte{0xff, 0xfb, 0x90, 0x44}
+	const frameSize = 144 * 128000 / 44100 // 417 bytes.
+
+	t.Run("CBR", func(t *testing.T) {
+		var data []byte
+		data = append(data, "ID3\x03\x00\x00\x00\x00\x00\x0a"...)
+		data = append(data, make([]byte, 10)...)
+		for range 160 {
+			frame := make([]byte, frameSize)
+			copy(frame, frameHeader)
+			data = append(data, frame...)
+		}
+		info, ok := parseMP3Info(data)
+		assert.True(t, ok)
+		assert.Equal(t, audioInfo{duration: 160 * frameSize * 8 / 128, sampleRate: 44100, channels: 2}, info)
+	})
+
+	t.Run("Xing", func(t *testing.T) {
+		frame := make([]byte, frameSize)
+		copy(frame, frameHeader)
+		xing := 4 + 32
+		copy(frame[xing:], "Xing")
+		binary.BigEndian.PutUint32(frame[xing+4:], 1)
+		binary.BigEndian.PutUint32(frame[xing+8:], 1000)
+		info, ok := parseMP3Info(frame)
+		assert.True(t, ok)
+		assert.Equal(t, int64(1000*1152*1000/44100), info.duration)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, ok := parseMP3Info([]byte("not an mp3 file"))
+		assert.False(t, ok)
+	})
+}
+
+func TestParseOggVorbisInfo(t *testing.T) {
+	newPage := func(granule uint64, packet []byte) []byte {
+		page := []byte("OggS\x00\x02")
+		page = binary.LittleEndian.AppendUint64(page, granule)
+		page = append(page, make([]byte, 12)...) // Serial, sequence, and checksum.
+		page = append(page, 1, byte(len(packet)))
+		return append(page, packet...)
+	}
+	idHeader := []byte("\x01vorbis\x00\x00\x00\x00\x02")
+	idHeader = binary.LittleEndian.AppendUint32(idHeader, 48000)
+	idHeader = append(idHeader, make([]byte, 14)...)
+
+	t.Run("Normal", func(t *testing.T) {
+		data := append(newPage(0, idHeader), newPage(72000, []byte{0})...)
+		info, ok := parseOggVorbisInfo(data)
+		assert.True(t, ok)
+		assert.Equal(t, audioInfo{duration: 1500, sampleRate: 48000, channels: 2}, info)
+	})
+
+	t.Run("NotVorbis", func(t *testing.T) {
+		_, ok := parseOggVorbisInfo(newPage(0, []byte("OpusHead\x01\x02")))
+		assert.False(t, ok)
+	})
+}
+
+func TestParseAudioInfo(t *testing.T) {
+	info, ok := parseAudioInfo("wav", newTestWAV(8000, 1, 16, 16000))
+	assert.True(t, ok)
+	assert.Equal(t, int64(2000), info.duration)
+
+	_, ok = parseAudioInfo("flac", []byte("fLaC"))
+	assert.False(t, ok)
+}
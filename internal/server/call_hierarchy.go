@@ -0,0 +1,187 @@
+package server
+
+import (
+	"context"
+	gotypes "go/types"
+	"path"
+	"strings"
+
+	"github.com/goplus/xgo/ast"
+	"github.com/goplus/xgolsw/xgo"
+	"github.com/goplus/xgolsw/xgo/types"
+	"github.com/goplus/xgolsw/xgo/xgoutil"
+)
+
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.18/specification/#textDocument_prepareCallHierarchy
+func (s *Server) textDocumentPrepareCallHierarchy(ctx context.Context, params *CallHierarchyPrepareParams) ([]CallHierarchyItem, error) {
+	result, err := s.compileAndGetASTFileForDocumentURI(ctx, params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	if !result.IsUsable() {
+		return nil, nil
+	}
+	typeInfo, _ := result.proj.TypeInfo()
+	if typeInfo == nil {
+		return nil, nil
+	}
+	position := ToPosition(result.proj, result.astFile, params.Position)
+	_, obj, _ := objectAtPosition(result.proj, typeInfo, result.astFile, position)
+	fun, ok := obj.(*gotypes.Func)
+	if !ok || !xgoutil.IsInMainPkg(fun) {
+		return nil, nil
+	}
+
+	item := s.callHierarchyItemForFunc(result.proj, typeInfo, fun)
+	if item == nil {
+		return nil, nil
+	}
+	return []CallHierarchyItem{*item}, nil
+}
+
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.18/specification/#callHierarchy_incomingCalls
+func (s *Server) callHierarchyIncomingCalls(ctx context.Context, params *CallHierarchyIncomingCallsParams) ([]CallHierarchyIncomingCall, error) {
+	result, err := s.compileAndGetASTFileForDocumentURI(ctx, params.Item.URI)
+	if err != nil {
+		return nil, err
+	}
+	if !result.IsUsable() {
+		return nil, nil
+	}
+	typeInfo, _ := result.proj.TypeInfo()
+	if typeInfo == nil {
+		return nil, nil
+	}
+	position := ToPosition(result.proj, result.astFile, params.Item.SelectionRange.Start)
+	_, obj, _ := objectAtPosition(result.proj, typeInfo, result.astFile, position)
+	fun, ok := obj.(*gotypes.Func)
+	if !ok || !xgoutil.IsInMainPkg(fun) {
+		return nil, nil
+	}
+
+	var (
+		calls       []CallHierarchyIncomingCall
+		callIndexes = make(map[ast.Node]int)
+	)
+	xgoutil.WalkAllFiles(result.proj, func(spxFile string, astFile *ast.File) error {
+		ast.Inspect(astFile, func(node ast.Node) bool {
+			callExpr, ok := node.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			calleeIdent := callExprCalleeIdent(callExpr)
+			if calleeIdent == nil || typeInfo.Uses[calleeIdent] != fun {
+				return true
+			}
+
+			caller, callerItem := s.callHierarchyCallerAt(result.proj, typeInfo, spxFile, astFile, callExpr)
+			if callerItem == nil {
+				return true
+			}
+			fromRange := RangeForNode(result.proj, callExpr)
+			if i, ok := callIndexes[caller]; ok {
+				calls[i].FromRanges = append(calls[i].FromRanges, fromRange)
+				return true
+			}
+			callIndexes[caller] = len(calls)
+			calls = append(calls, CallHierarchyIncomingCall{
+				From:       *callerItem,
+				FromRanges: []Range{fromRange},
+			})
+			return true
+		})
+		return nil
+	})
+	return calls, nil
+}
+
+// callExprCalleeIdent returns the identifier that names the callee of the
+// given call expression, or nil if the callee is not an identifier or a
+// selector.
+func callExprCalleeIdent(callExpr *ast.CallExpr) *ast.Ident {
+	switch fun := callExpr.Fun.(type) {
+	case *ast.Ident:
+		return fun
+	case *ast.SelectorExpr:
+		return fun.Sel
+	}
+	return nil
+}
+
+// callHierarchyItemForFunc returns the [CallHierarchyItem] for the given
+// function declared in the main package.
+func (s *Server) callHierarchyItemForFunc(proj *xgo.Project, typeInfo *types.Info, fun *gotypes.Func) *CallHierarchyItem {
+	defIdent := typeInfo.ObjToDef[fun]
+	if defIdent == nil {
+		return nil
+	}
+	astPkg, _ := proj.ASTPackage()
+	astFile := xgoutil.NodeASTFile(proj.Fset, astPkg, defIdent)
+	if astFile == nil {
+		return nil
+	}
+
+	var declNode ast.Node = defIdent
+	for _, decl := range astFile.Decls {
+		if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Name == defIdent {
+			declNode = funcDecl
+			break
+		}
+	}
+
+	kind := Function
+	if fun.Signature().Recv() != nil {
+		kind = Method
+	}
+	parsedName, _ := xgoutil.ParseXGoFuncName(fun.Name())
+	return &CallHierarchyItem{
+		Name:           parsedName,
+		Kind:           kind,
+		Detail:         GetSimplifiedTypeString(fun.Type()),
+		URI:            s.nodeDocumentURI(proj, defIdent),
+		Range:          RangeForNode(proj, declNode),
+		SelectionRange: RangeForNode(proj, defIdent),
+	}
+}
+
+// callHierarchyCallerAt returns the innermost caller enclosing the given call
+// expression along with its [CallHierarchyItem]. The caller is either an spx
+// event handler, a function declaration, or the top-level statements of the
+// spx file.
+func (s *Server) callHierarchyCallerAt(proj *xgo.Project, typeInfo *types.Info, spxFile string, astFile *ast.File, callExpr *ast.CallExpr) (ast.Node, *CallHierarchyItem) {
+	for node := range xgoutil.PathEnclosingIntervalNodes(astFile, callExpr.Pos(), callExpr.End(), false) {
+		switch node := node.(type) {
+		case *ast.CallExpr:
+			if node == callExpr {
+				continue
+			}
+			funIdent, ok := node.Fun.(*ast.Ident)
+			if !ok || !IsSpxEventHandlerFuncName(funIdent.Name) || !IsInSpxPkg(typeInfo.ObjectOf(funIdent)) {
+				continue
+			}
+			return node, &CallHierarchyItem{
+				Name:           funIdent.Name,
+				Kind:           Event,
+				URI:            s.toDocumentURI(spxFile),
+				Range:          RangeForNode(proj, node),
+				SelectionRange: RangeForNode(proj, funIdent),
+			}
+		case *ast.FuncDecl:
+			if node.Shadow {
+				return node, &CallHierarchyItem{
+					Name:           strings.TrimSuffix(path.Base(spxFile), path.Ext(spxFile)),
+					Kind:           Class,
+					URI:            s.toDocumentURI(spxFile),
+					Range:          RangeForNode(proj, node),
+					SelectionRange: RangeForPos(proj, node.Pos()),
+				}
+			}
+			fun, ok := typeInfo.Defs[node.Name].(*gotypes.Func)
+			if !ok {
+				return nil, nil
+			}
+			return node, s.callHierarchyItemForFunc(proj, typeInfo, fun)
+		}
+	}
+	return nil, nil
+}
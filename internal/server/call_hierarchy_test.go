@@ -0,0 +1,101 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerCallHierarchy(t *testing.T) {
+	m := map[string][]byte{
+		"main.spx": []byte(`
+func greet(name string) {
+	echo "Hi, " + name
+}
+
+onStart => {
+	greet "Alice"
+}
+
+onClick => {
+	greet "Bob"
+	greet "Carol"
+}
+`),
+		"MySprite.spx": []byte(`
+onStart => {
+	say "Hi"
+}
+`),
+		"assets/index.json":                  []byte(`{}`),
+		"assets/sprites/MySprite/index.json": []byte(`{}`),
+	}
+	s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+	wantItem := CallHierarchyItem{
+		Name:   "greet",
+		Kind:   Method,
+		Detail: "func(name string)",
+		URI:    "file:///main.spx",
+		Range: Range{
+			Start: Position{Line: 1, Character: 0},
+			End:   Position{Line: 3, Character: 1},
+		},
+		SelectionRange: Range{
+			Start: Position{Line: 1, Character: 5},
+			End:   Position{Line: 1, Character: 10},
+		},
+	}
+
+	t.Run("PrepareAtCallSite", func(t *testing.T) {
+		items, err := s.textDocumentPrepareCallHierarchy(t.Context(), &CallHierarchyPrepareParams{
+			TextDocumentPositionParams: TextDocumentPositionParams{
+				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+				Position:     Position{Line: 6, Character: 2},
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []CallHierarchyItem{wantItem}, items)
+	})
+
+	t.Run("PrepareAtSpxFunc", func(t *testing.T) {
+		items, err := s.textDocumentPrepareCallHierarchy(t.Context(), &CallHierarchyPrepareParams{
+			TextDocumentPositionParams: TextDocumentPositionParams{
+				TextDocument: TextDocumentIdentifier{URI: "file:///MySprite.spx"},
+				Position:     Position{Line: 2, Character: 1},
+			},
+		})
+		require.NoError(t, err)
+		assert.Nil(t, items)
+	})
+
+	t.Run("IncomingCalls", func(t *testing.T) {
+		calls, err := s.callHierarchyIncomingCalls(t.Context(), &CallHierarchyIncomingCallsParams{Item: wantItem})
+		require.NoError(t, err)
+		require.Len(t, calls, 2)
+
+		assert.Equal(t, "onStart", calls[0].From.Name)
+		assert.Equal(t, Event, calls[0].From.Kind)
+		assert.Equal(t, DocumentURI("file:///main.spx"), calls[0].From.URI)
+		assert.Equal(t, []Range{
+			{
+				Start: Position{Line: 6, Character: 1},
+				End:   Position{Line: 6, Character: 14},
+			},
+		}, calls[0].FromRanges)
+
+		assert.Equal(t, "onClick", calls[1].From.Name)
+		assert.Equal(t, Event, calls[1].From.Kind)
+		assert.Equal(t, []Range{
+			{
+				Start: Position{Line: 10, Character: 1},
+				End:   Position{Line: 10, Character: 12},
+			},
+			{
+				Start: Position{Line: 11, Character: 1},
+				End:   Position{Line: 11, Character: 14},
+			},
+		}, calls[1].FromRanges)
+	})
+}
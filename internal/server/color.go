@@ -0,0 +1,142 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.18/specification/#textDocument_documentColor
+func (s *Server) textDocumentDocumentColor(ctx context.Context, params *DocumentColorParams) ([]ColorInformation, error) {
+	result, err := s.compileAndGetASTFileForDocumentURI(ctx, params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	if !result.IsUsable() {
+		return nil, nil
+	}
+
+	var colorInfos []ColorInformation
+	for _, slot := range findInputSlots(result.compileResult, result.astFile) {
+		if slot.Kind != XGoInputSlotKindValue || slot.Input.Kind != XGoInputKindInPlace {
+			continue
+		}
+		colorValue, ok := slot.Input.Value.(XGoInputSpxColorValue)
+		if !ok {
+			continue
+		}
+		color, ok := spxColorValueToColor(colorValue)
+		if !ok {
+			continue
+		}
+		colorInfos = append(colorInfos, ColorInformation{
+			Range: slot.Range,
+			Color: color,
+		})
+	}
+	return colorInfos, nil
+}
+
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.18/specification/#textDocument_colorPresentation
+func (s *Server) textDocumentColorPresentation(params *ColorPresentationParams) ([]ColorPresentation, error) {
+	h, sat, b := rgbToSpxHSB(params.Color.Red, params.Color.Green, params.Color.Blue)
+	hsb := fmt.Sprintf("HSB(%s, %s, %s)", formatSpxColorArg(h), formatSpxColorArg(sat), formatSpxColorArg(b))
+	hsba := fmt.Sprintf("HSBA(%s, %s, %s, %s)", formatSpxColorArg(h), formatSpxColorArg(sat), formatSpxColorArg(b), formatSpxColorArg(params.Color.Alpha*100))
+
+	presentations := []ColorPresentation{
+		{Label: hsb, TextEdit: &TextEdit{Range: params.Range, NewText: hsb}},
+		{Label: hsba, TextEdit: &TextEdit{Range: params.Range, NewText: hsba}},
+	}
+	if params.Color.Alpha < 1 {
+		// HSB cannot represent transparency, so prefer HSBA.
+		presentations[0], presentations[1] = presentations[1], presentations[0]
+	}
+	return presentations, nil
+}
+
+// spxColorValueToColor converts the given spx color input value to a [Color].
+// It reports false if the value is not a valid HSB or HSBA color.
+func spxColorValueToColor(v XGoInputSpxColorValue) (Color, bool) {
+	var alpha float64
+	switch {
+	case v.Constructor == XGoInputTypeSpxColorConstructorHSB && len(v.Args) == 3:
+		alpha = 1
+	case v.Constructor == XGoInputTypeSpxColorConstructorHSBA && len(v.Args) == 4:
+		alpha = clamp01(v.Args[3] / 100)
+	default:
+		return Color{}, false
+	}
+	r, g, b := spxHSBToRGB(v.Args[0], v.Args[1], v.Args[2])
+	return Color{Red: r, Green: g, Blue: b, Alpha: alpha}, true
+}
+
+// spxHSBToRGB converts an spx HSB color, whose components are all in the
+// range [0, 100] like in Scratch, to RGB components in the range [0, 1].
+func spxHSBToRGB(h, s, b float64) (red, green, blue float64) {
+	hue := math.Mod(h*3.6, 360)
+	if hue < 0 {
+		hue += 360
+	}
+	sat, val := clamp01(s/100), clamp01(b/100)
+
+	chroma := val * sat
+	x := chroma * (1 - math.Abs(math.Mod(hue/60, 2)-1))
+	m := val - chroma
+	switch {
+	case hue < 60:
+		red, green, blue = chroma, x, 0
+	case hue < 120:
+		red, green, blue = x, chroma, 0
+	case hue < 180:
+		red, green, blue = 0, chroma, x
+	case hue < 240:
+		red, green, blue = 0, x, chroma
+	case hue < 300:
+		red, green, blue = x, 0, chroma
+	default:
+		red, green, blue = chroma, 0, x
+	}
+	return red + m, green + m, blue + m
+}
+
+// rgbToSpxHSB converts RGB components in the range [0, 1] to an spx HSB
+// color, whose components are all in the range [0, 100].
+func rgbToSpxHSB(red, green, blue float64) (h, s, b float64) {
+	red, green, blue = clamp01(red), clamp01(green), clamp01(blue)
+	maxC := max(red, green, blue)
+	minC := min(red, green, blue)
+	delta := maxC - minC
+
+	var hue float64
+	switch {
+	case delta == 0:
+		hue = 0
+	case maxC == red:
+		hue = 60 * math.Mod((green-blue)/delta, 6)
+	case maxC == green:
+		hue = 60 * ((blue-red)/delta + 2)
+	default:
+		hue = 60 * ((red-green)/delta + 4)
+	}
+	if hue < 0 {
+		hue += 360
+	}
+
+	var sat float64
+	if maxC > 0 {
+		sat = delta / maxC
+	}
+	return hue / 3.6, sat * 100, maxC * 100
+}
+
+// formatSpxColorArg formats an spx color argument with at most two decimal
+// places.
+func formatSpxColorArg(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
+
+// clamp01 clamps v to the range [0, 1].
+func clamp01(v float64) float64 {
+	return min(max(v, 0), 1)
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerTextDocumentDocumentColor(t *testing.T) {
+	m := map[string][]byte{
+		"main.spx": []byte(`
+onStart => {
+	red := HSB(0, 255, 255)
+	translucentBlue := HSBA(66.67, 100, 100, 50)
+	dynamic := HSB(red.X_0, 100, 100)
+	echo red, translucentBlue, dynamic
+}
+`),
+		"assets/index.json": []byte(`{}`),
+	}
+	s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+	colorInfos, err := s.textDocumentDocumentColor(t.Context(), &DocumentColorParams{
+		TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+	})
+	require.NoError(t, err)
+	require.Len(t, colorInfos, 2)
+
+	assert.Equal(t, Range{
+		Start: Position{Line: 2, Character: 8},
+		End:   Position{Line: 2, Character: 24},
+	}, colorInfos[0].Range)
+	assert.Equal(t, Color{Red: 1, Green: 0, Blue: 0, Alpha: 1}, colorInfos[0].Color)
+	h, sat, b := rgbToSpxHSB(colorInfos[0].Color.Red, colorInfos[0].Color.Green, colorInfos[0].Color.Blue)
+	assert.Zero(t, h)
+	assert.Equal(t, 100.0, sat)
+	assert.Equal(t, 100.0, b)
+
+	assert.InDelta(t, 0, colorInfos[1].Color.Red, 1e-3)
+	assert.InDelta(t, 0, colorInfos[1].Color.Green, 1e-3)
+	assert.InDelta(t, 1, colorInfos[1].Color.Blue, 1e-3)
+	assert.Equal(t, 0.5, colorInfos[1].Color.Alpha)
+}
+
+func TestServerTextDocumentColorPresentation(t *testing.T) {
+	m := map[string][]byte{
+		"main.spx":          []byte(`echo HSB(0, 100, 100)`),
+		"assets/index.json": []byte(`{}`),
+	}
+	s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+	colorRange := Range{
+		Start: Position{Line: 0, Character: 5},
+		End:   Position{Line: 0, Character: 21},
+	}
+
+	t.Run("Opaque", func(t *testing.T) {
+		presentations, err := s.textDocumentColorPresentation(&ColorPresentationParams{
+			TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+			Color:        Color{Red: 0, Green: 1, Blue: 0, Alpha: 1},
+			Range:        colorRange,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []ColorPresentation{
+			{Label: "HSB(33.33, 100, 100)", TextEdit: &TextEdit{Range: colorRange, NewText: "HSB(33.33, 100, 100)"}},
+			{Label: "HSBA(33.33, 100, 100, 100)", TextEdit: &TextEdit{Range: colorRange, NewText: "HSBA(33.33, 100, 100, 100)"}},
+		}, presentations)
+	})
+
+	t.Run("Translucent", func(t *testing.T) {
+		presentations, err := s.textDocumentColorPresentation(&ColorPresentationParams{
+			TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+			Color:        Color{Red: 0.5, Green: 0.5, Blue: 0.5, Alpha: 0.25},
+			Range:        colorRange,
+		})
+		require.NoError(t, err)
+		require.Len(t, presentations, 2)
+		assert.Equal(t, "HSBA(0, 0, 50, 25)", presentations[0].Label)
+		assert.Equal(t, "HSB(0, 0, 50)", presentations[1].Label)
+	})
+}
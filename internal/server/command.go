@@ -2,19 +2,29 @@ package server
 
 import (
 	"cmp"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"go/constant"
 	gotypes "go/types"
 	"iter"
+	"maps"
+	"math"
+	"path"
 	"slices"
 	"strconv"
 	"strings"
 	"unicode"
 
 	"github.com/goplus/xgo/ast"
+	"github.com/goplus/xgo/parser"
+	"github.com/goplus/xgo/scanner"
 	"github.com/goplus/xgo/token"
 	"github.com/goplus/xgolsw/internal/pkgdata"
 	"github.com/goplus/xgolsw/pkgdoc"
+	"github.com/goplus/xgolsw/xgo"
+	"github.com/goplus/xgolsw/xgo/types"
 	"github.com/goplus/xgolsw/xgo/xgoutil"
 )
 
@@ -23,7 +33,19 @@ const (
 	CommandSpxRenameResources = "spx.renameResources"
 	CommandXGoGetInputSlots   = "xgo.getInputSlots"
 	CommandSpxGetInputSlots   = "spx.getInputSlots"
+	CommandXGoSetInputSlot    = "xgo.setInputSlot"
+	CommandSpxSetInputSlot    = "spx.setInputSlot"
 	CommandXGoGetProperties   = "xgo.getProperties"
+	CommandSpxGetSpriteInfo   = "spx.getSpriteInfo"
+	CommandSpxGetBackdropInfo = "spx.getBackdropInfo"
+	CommandSpxGetDefinitionAt = "spx.getDefinitionAt"
+
+	CommandSpxGetAnimationFrames = "spx.getAnimationFrames"
+	CommandSpxGetSoundInfo       = "spx.getSoundInfo"
+	CommandSpxCheckCode          = "spx.checkCode"
+
+	CommandSpxGetProjectStructure      = "spx.getProjectStructure"
+	CommandSpxGetEventHandlerPositions = "spx.getEventHandlerPositions"
 )
 
 // xgoPropertyKindPriority defines the presentation order for XGo properties.
@@ -33,7 +55,7 @@ var xgoPropertyKindPriority = map[XGoPropertyKind]int{
 }
 
 // See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.18/specification/#workspace_executeCommand
-func (s *Server) workspaceExecuteCommand(params *ExecuteCommandParams) (any, error) {
+func (s *Server) workspaceExecuteCommand(ctx context.Context, params *ExecuteCommandParams) (any, error) {
 	switch params.Command {
 	case CommandXGoRenameResources, CommandSpxRenameResources:
 		var cmdParams []XGoRenameResourceParams
@@ -44,7 +66,7 @@ func (s *Server) workspaceExecuteCommand(params *ExecuteCommandParams) (any, err
 			}
 			cmdParams = append(cmdParams, cmdParam)
 		}
-		return s.spxRenameResources(cmdParams)
+		return s.spxRenameResources(ctx, cmdParams)
 	case CommandXGoGetInputSlots, CommandSpxGetInputSlots:
 		var cmdParams []XGoGetInputSlotsParams
 		for _, arg := range params.Arguments {
@@ -54,7 +76,19 @@ func (s *Server) workspaceExecuteCommand(params *ExecuteCommandParams) (any, err
 			}
 			cmdParams = append(cmdParams, cmdParam)
 		}
-		return s.spxGetInputSlots(cmdParams)
+		if len(cmdParams) > 1 {
+			return s.spxGetInputSlotsBatch(ctx, cmdParams)
+		}
+		return s.spxGetInputSlots(ctx, cmdParams)
+	case CommandXGoSetInputSlot, CommandSpxSetInputSlot:
+		var cmdParams XGoSetInputSlotParams
+		if len(params.Arguments) != 1 {
+			return nil, fmt.Errorf("expected exactly one argument for command %s", params.Command)
+		}
+		if err := json.Unmarshal(params.Arguments[0], &cmdParams); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal command argument as XGoSetInputSlotParams: %w", err)
+		}
+		return s.xgoSetInputSlot(cmdParams)
 	case CommandXGoGetProperties:
 		var cmdParams XGoGetPropertiesParams
 		if len(params.Arguments) != 1 {
@@ -64,13 +98,78 @@ func (s *Server) workspaceExecuteCommand(params *ExecuteCommandParams) (any, err
 			return nil, fmt.Errorf("failed to unmarshal command argument as XGoGetPropertiesParams: %w", err)
 		}
 		return s.xgoGetProperties(cmdParams)
+	case CommandSpxGetSpriteInfo:
+		var cmdParams SpxGetSpriteInfoParams
+		if len(params.Arguments) != 1 {
+			return nil, fmt.Errorf("expected exactly one argument for command %s", CommandSpxGetSpriteInfo)
+		}
+		if err := json.Unmarshal(params.Arguments[0], &cmdParams); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal command argument as SpxGetSpriteInfoParams: %w", err)
+		}
+		return s.spxGetSpriteInfo(ctx, cmdParams)
+	case CommandSpxGetBackdropInfo:
+		var cmdParams SpxGetBackdropInfoParams
+		if len(params.Arguments) != 1 {
+			return nil, fmt.Errorf("expected exactly one argument for command %s", CommandSpxGetBackdropInfo)
+		}
+		if err := json.Unmarshal(params.Arguments[0], &cmdParams); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal command argument as SpxGetBackdropInfoParams: %w", err)
+		}
+		return s.spxGetBackdropInfo(ctx, cmdParams)
+	case CommandSpxGetDefinitionAt:
+		var cmdParams TextDocumentPositionParams
+		if len(params.Arguments) != 1 {
+			return nil, fmt.Errorf("expected exactly one argument for command %s", CommandSpxGetDefinitionAt)
+		}
+		if err := json.Unmarshal(params.Arguments[0], &cmdParams); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal command argument as TextDocumentPositionParams: %w", err)
+		}
+		return s.spxGetDefinitionAt(ctx, cmdParams)
+	case CommandSpxGetAnimationFrames:
+		var cmdParams SpxGetAnimationFramesParams
+		if len(params.Arguments) != 1 {
+			return nil, fmt.Errorf("expected exactly one argument for command %s", CommandSpxGetAnimationFrames)
+		}
+		if err := json.Unmarshal(params.Arguments[0], &cmdParams); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal command argument as SpxGetAnimationFramesParams: %w", err)
+		}
+		return s.spxGetAnimationFrames(ctx, cmdParams)
+	case CommandSpxGetSoundInfo:
+		var cmdParams SpxGetSoundInfoParams
+		if len(params.Arguments) != 1 {
+			return nil, fmt.Errorf("expected exactly one argument for command %s", CommandSpxGetSoundInfo)
+		}
+		if err := json.Unmarshal(params.Arguments[0], &cmdParams); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal command argument as SpxGetSoundInfoParams: %w", err)
+		}
+		return s.spxGetSoundInfo(ctx, cmdParams)
+	case CommandSpxCheckCode:
+		var cmdParams SpxCheckCodeParams
+		if len(params.Arguments) != 1 {
+			return nil, fmt.Errorf("expected exactly one argument for command %s", CommandSpxCheckCode)
+		}
+		if err := json.Unmarshal(params.Arguments[0], &cmdParams); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal command argument as SpxCheckCodeParams: %w", err)
+		}
+		return s.spxCheckCode(cmdParams)
+	case CommandSpxGetProjectStructure:
+		return s.spxGetProjectStructure()
+	case CommandSpxGetEventHandlerPositions:
+		var cmdParams SpxGetEventHandlerPositionsParams
+		if len(params.Arguments) != 1 {
+			return nil, fmt.Errorf("expected exactly one argument for command %s", CommandSpxGetEventHandlerPositions)
+		}
+		if err := json.Unmarshal(params.Arguments[0], &cmdParams); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal command argument as SpxGetEventHandlerPositionsParams: %w", err)
+		}
+		return s.spxGetEventHandlerPositions(ctx, cmdParams)
 	}
 	return nil, fmt.Errorf("unknown command: %s", params.Command)
 }
 
 // spxRenameResources renames spx resources in the workspace.
-func (s *Server) spxRenameResources(params []XGoRenameResourceParams) (*WorkspaceEdit, error) {
-	result, err := s.compile()
+func (s *Server) spxRenameResources(ctx context.Context, params []XGoRenameResourceParams) (*WorkspaceEdit, error) {
+	result, err := s.compile(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -125,24 +224,229 @@ func (s *Server) spxRenameResourcesWithCompileResult(result *compileResult, para
 	return &workspaceEdit, nil
 }
 
-// spxGetInputSlots gets input slots in a document.
-func (s *Server) spxGetInputSlots(params []XGoGetInputSlotsParams) ([]XGoInputSlot, error) {
+// spxGetInputSlots gets input slots in a document. Use
+// [Server.spxGetInputSlotsBatch] for multiple documents.
+func (s *Server) spxGetInputSlots(ctx context.Context, params []XGoGetInputSlotsParams) ([]XGoInputSlot, error) {
 	if l := len(params); l == 0 {
 		return nil, nil
 	} else if l > 1 {
 		return nil, fmt.Errorf("%s only supports one document at a time", CommandXGoGetInputSlots)
 	}
-	param := params[0]
-
-	result, _, astFile, err := s.compileAndGetASTFileForDocumentURI(param.TextDocument.URI)
+	batchResult, err := s.spxGetInputSlotsBatch(ctx, params)
 	if err != nil {
 		return nil, err
 	}
-	if astFile == nil {
+	return batchResult[params[0].TextDocument.URI], nil
+}
+
+// spxGetInputSlotsBatch gets input slots in multiple documents. The project is
+// compiled only once for all of them.
+func (s *Server) spxGetInputSlotsBatch(ctx context.Context, params []XGoGetInputSlotsParams) (XGoGetInputSlotsBatchResult, error) {
+	if len(params) == 0 {
 		return nil, nil
 	}
 
-	return findInputSlots(result, astFile), nil
+	spxFiles := make([]string, 0, len(params))
+	for _, param := range params {
+		spxFile, err := s.spxFileForDocumentURI(param.TextDocument.URI)
+		if err != nil {
+			return nil, err
+		}
+		spxFiles = append(spxFiles, spxFile)
+	}
+	result, err := s.compile(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile: %w", err)
+	}
+
+	batchResult := make(XGoGetInputSlotsBatchResult, len(params))
+	for i, param := range params {
+		docResult := newDocumentCompileResult(result, spxFiles[i])
+		if !docResult.IsUsable() {
+			batchResult[param.TextDocument.URI] = nil
+			continue
+		}
+		batchResult[param.TextDocument.URI] = findInputSlots(result, docResult.astFile)
+	}
+	return batchResult, nil
+}
+
+// xgoSetInputSlot replaces the code of an input slot with the textual
+// representation of a new input value.
+func (s *Server) xgoSetInputSlot(params XGoSetInputSlotParams) (*WorkspaceEdit, error) {
+	spxFile, err := s.spxFileForDocumentURI(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	file, ok := s.getProjWithFile().File(spxFile)
+	if !ok {
+		return nil, fmt.Errorf("file %q not found", spxFile)
+	}
+	start := PositionOffset(file.Content, params.Range.Start)
+	end := PositionOffset(file.Content, params.Range.End)
+	if start > end {
+		return nil, fmt.Errorf("invalid range %v", params.Range)
+	}
+
+	newText, err := formatXGoInput(params.Input, string(file.Content[start:end]))
+	if err != nil {
+		return nil, err
+	}
+	return &WorkspaceEdit{
+		Changes: map[DocumentURI][]TextEdit{
+			params.TextDocument.URI: {{
+				Range:   params.Range,
+				NewText: newText,
+			}},
+		},
+	}, nil
+}
+
+// formatXGoInput returns the code representing input. The old code of the slot
+// is used to keep the type of a struct literal.
+func formatXGoInput(input XGoInput, oldCode string) (string, error) {
+	switch input.Kind {
+	case XGoInputKindPredefined:
+		if !token.IsIdentifier(input.Name) {
+			return "", fmt.Errorf("invalid predefined name %q", input.Name)
+		}
+		return input.Name, nil
+	case XGoInputKindInPlace:
+	default:
+		return "", fmt.Errorf("unsupported input kind %q", input.Kind)
+	}
+
+	switch input.Type {
+	case XGoInputTypeString, XGoInputTypeSpxPropertyName:
+		if v, ok := input.Value.(string); ok {
+			return strconv.Quote(v), nil
+		}
+	case XGoInputTypeInteger:
+		if v, ok := xgoInputNumberValue(input.Value); ok && v == math.Trunc(v) {
+			return strconv.FormatInt(int64(v), 10), nil
+		}
+	case XGoInputTypeDecimal, XGoInputTypeSpxDirection:
+		if v, ok := xgoInputNumberValue(input.Value); ok {
+			return strconv.FormatFloat(v, 'g', -1, 64), nil
+		}
+	case XGoInputTypeBoolean:
+		if v, ok := input.Value.(bool); ok {
+			return strconv.FormatBool(v), nil
+		}
+	case XGoInputTypeSpxResourceName, XGoInputTypeSpxSpriteInstance:
+		var uri XGoResourceURI
+		switch v := input.Value.(type) {
+		case XGoResourceURI:
+			uri = v
+		case string:
+			uri = XGoResourceURI(v)
+		default:
+			return "", fmt.Errorf("invalid value %v for input type %q", input.Value, input.Type)
+		}
+		id, err := ParseSpxResourceURI(uri)
+		if err != nil {
+			return "", err
+		}
+		if input.Type == XGoInputTypeSpxSpriteInstance {
+			if _, ok := id.(SpxSpriteResourceID); !ok || !token.IsIdentifier(id.Name()) {
+				return "", fmt.Errorf("invalid sprite instance %q", uri)
+			}
+			return id.Name(), nil
+		}
+		return strconv.Quote(id.Name()), nil
+	case XGoInputTypeSpxLayerAction,
+		XGoInputTypeSpxDirAction,
+		XGoInputTypeSpxEffectKind,
+		XGoInputTypeSpxKey,
+		XGoInputTypeSpxSpecialObj,
+		XGoInputTypeSpxRotationStyle:
+		if v, ok := input.Value.(string); ok && token.IsIdentifier(v) {
+			return v, nil
+		}
+	case XGoInputTypeSpxColor:
+		var v XGoInputSpxColorValue
+		if !convertXGoInputValue(input.Value, &v) {
+			break
+		}
+		wantArgs := 3
+		if v.Constructor == XGoInputTypeSpxColorConstructorHSBA {
+			wantArgs = 4
+		} else if v.Constructor != XGoInputTypeSpxColorConstructorHSB {
+			return "", fmt.Errorf("unsupported color constructor %q", v.Constructor)
+		}
+		if len(v.Args) != wantArgs {
+			return "", fmt.Errorf("color constructor %s expects %d arguments, got %d", v.Constructor, wantArgs, len(v.Args))
+		}
+		args := make([]string, len(v.Args))
+		for i, arg := range v.Args {
+			args[i] = strconv.FormatFloat(arg, 'g', -1, 64)
+		}
+		return string(v.Constructor) + "(" + strings.Join(args, ", ") + ")", nil
+	case XGoInputTypeStruct:
+		fields, ok := input.Value.(map[string]any)
+		if !ok {
+			break
+		}
+		typeExpr, _, ok := strings.Cut(oldCode, "{")
+		if !ok {
+			return "", fmt.Errorf("cannot infer struct type from %q", oldCode)
+		}
+		elts := make([]string, 0, len(fields))
+		for _, name := range slices.Sorted(maps.Keys(fields)) {
+			if !token.IsIdentifier(name) {
+				return "", fmt.Errorf("invalid struct field name %q", name)
+			}
+			var value string
+			switch v := fields[name].(type) {
+			case string:
+				value = strconv.Quote(v)
+			case bool:
+				value = strconv.FormatBool(v)
+			default:
+				n, ok := xgoInputNumberValue(v)
+				if !ok {
+					return "", fmt.Errorf("unsupported value %v for struct field %s", v, name)
+				}
+				value = strconv.FormatFloat(n, 'g', -1, 64)
+			}
+			elts = append(elts, name+": "+value)
+		}
+		return strings.TrimSpace(typeExpr) + "{" + strings.Join(elts, ", ") + "}", nil
+	default:
+		return "", fmt.Errorf("unsupported input type %q", input.Type)
+	}
+	return "", fmt.Errorf("invalid value %v for input type %q", input.Value, input.Type)
+}
+
+// xgoInputNumberValue returns the numeric value of an input value, which is a
+// float64 when decoded from JSON.
+func xgoInputNumberValue(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int64:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// convertXGoInputValue converts an input value, which is a map when decoded
+// from JSON, to v. It reports whether the conversion succeeded.
+func convertXGoInputValue[T any](value any, v *T) bool {
+	if tv, ok := value.(T); ok {
+		*v = tv
+		return true
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, v) == nil
 }
 
 // xgoGetProperties gets properties for a specific target (e.g., "Game" or a sprite name).
@@ -204,6 +508,422 @@ func (s *Server) xgoGetProperties(params XGoGetPropertiesParams) ([]XGoProperty,
 	return properties, nil
 }
 
+// spxGetSpriteInfo gets information about the spx sprite with the given name,
+// including its source file, its resource, and the definitions available on
+// it.
+func (s *Server) spxGetSpriteInfo(ctx context.Context, params SpxGetSpriteInfoParams) (*SpxSpriteInfo, error) {
+	result, err := s.compile(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	spriteResource := result.spxResourceSet.Sprite(params.Sprite)
+	if spriteResource == nil {
+		return nil, fmt.Errorf("sprite %q not found", params.Sprite)
+	}
+	info := &SpxSpriteInfo{Resource: spriteResource}
+
+	spriteFile := params.Sprite + ".spx"
+	if _, ok := result.proj.File(spriteFile); ok {
+		info.File = s.toDocumentURI(spriteFile)
+	}
+
+	for obj := range result.spxSpriteResourceAutoBindings {
+		if obj.Name() == params.Sprite {
+			info.AutoBinding = true
+			break
+		}
+	}
+
+	spriteType := GetSpxSpriteImplType()
+	if typeInfo, _ := result.proj.TypeInfo(); typeInfo != nil && typeInfo.Pkg != nil {
+		if obj, ok := typeInfo.Pkg.Scope().Lookup(params.Sprite).(*gotypes.TypeName); ok {
+			if named, ok := xgoutil.DerefType(obj.Type()).(*gotypes.Named); ok && result.hasSpxSpriteType(named) {
+				spriteType = named
+			}
+		}
+	}
+	info.Definitions = result.spxDefinitionsForNamedStruct(spriteType)
+
+	return info, nil
+}
+
+// spxGetBackdropInfo gets information about the spx backdrop with the given
+// name, including its resource and the definitions of the game that accept a
+// backdrop, e.g., Game.setBackdrop.
+func (s *Server) spxGetBackdropInfo(ctx context.Context, params SpxGetBackdropInfoParams) (*SpxBackdropInfo, error) {
+	result, err := s.compile(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	backdropResource := result.spxResourceSet.Backdrop(params.Backdrop)
+	if backdropResource == nil {
+		return nil, fmt.Errorf("backdrop %q not found", params.Backdrop)
+	}
+	info := &SpxBackdropInfo{Resource: backdropResource}
+
+	for structMember := range xgoutil.StructMembers(GetSpxGameType()) {
+		fun, ok := structMember.Member.(*gotypes.Func)
+		if !ok || !acceptsSpxBackdropName(fun) {
+			continue
+		}
+		info.Definitions = append(info.Definitions, result.spxDefinitionsFor(structMember.Member, structMember.Selector.Obj().Name())...)
+	}
+
+	return info, nil
+}
+
+// acceptsSpxBackdropName reports whether fun, or any of its overloads, has a
+// parameter of the [spx.BackdropName] type.
+func acceptsSpxBackdropName(fun *gotypes.Func) bool {
+	funcOverloads := xgoutil.ExpandXGoOverloadableFunc(fun)
+	if funcOverloads == nil {
+		funcOverloads = []*gotypes.Func{fun}
+	}
+	for _, funcOverload := range funcOverloads {
+		for param := range funcOverload.Signature().Params().Variables() {
+			if canonicalSpxResourceNameType(spxResourceNameValueType(param.Type())) == GetSpxBackdropNameType() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// spxGetSoundInfo gets information about the spx sound with the given name.
+// The duration, sample rate, and channels are parsed from the header of the
+// sound file if it is accessible in the project.
+func (s *Server) spxGetSoundInfo(ctx context.Context, params SpxGetSoundInfoParams) (*SpxSoundInfo, error) {
+	result, err := s.compile(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	soundResource := result.spxResourceSet.Sound(params.Sound)
+	if soundResource == nil {
+		return nil, fmt.Errorf("sound %q not found", params.Sound)
+	}
+	info := &SpxSoundInfo{
+		Path: path.Join(spxResourceRootDir, "sounds", params.Sound, soundResource.Path),
+	}
+	switch ext := strings.ToLower(path.Ext(soundResource.Path)); ext {
+	case ".mp3", ".wav", ".ogg":
+		info.Format = ext[1:]
+	}
+
+	if file, ok := result.proj.File(info.Path); ok {
+		if audio, ok := parseAudioInfo(info.Format, file.Content); ok {
+			info.Duration = audio.duration
+			info.SampleRate = audio.sampleRate
+			info.Channels = audio.channels
+		}
+	}
+	return info, nil
+}
+
+// spxGetAnimationFrames gets the frames of the spx sprite animation with the
+// given name. If no such animation exists, the costume with the given name is
+// returned as a single frame. The size of each frame is parsed from its image
+// file if it is accessible in the project.
+func (s *Server) spxGetAnimationFrames(ctx context.Context, params SpxGetAnimationFramesParams) ([]SpxAnimationFrame, error) {
+	result, err := s.compile(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	spriteResource := result.spxResourceSet.Sprite(params.Sprite)
+	if spriteResource == nil {
+		return nil, fmt.Errorf("sprite %q not found", params.Sprite)
+	}
+
+	newFrame := func(costume SpxSpriteCostumeResource, frameRate float64) SpxAnimationFrame {
+		frame := SpxAnimationFrame{
+			Path:      path.Join(spxResourceRootDir, "sprites", params.Sprite, costume.Path),
+			FrameRate: frameRate,
+		}
+		if file, ok := result.proj.File(frame.Path); ok {
+			if width, height, ok := parseImageSize(file.Content); ok {
+				frame.Width = width
+				frame.Height = height
+			}
+		}
+		return frame
+	}
+	if animation := spriteResource.Animation(params.Animation); animation != nil {
+		if animation.FromIndex == nil || animation.ToIndex == nil || *animation.ToIndex < *animation.FromIndex {
+			return nil, fmt.Errorf("animation %q of sprite %q has no valid frames", params.Animation, params.Sprite)
+		}
+		frames := make([]SpxAnimationFrame, 0, *animation.ToIndex-*animation.FromIndex+1)
+		for i := *animation.FromIndex; i <= *animation.ToIndex; i++ {
+			if i >= 0 && i < len(spriteResource.Costumes) {
+				frames = append(frames, newFrame(spriteResource.Costumes[i], animation.FrameRate))
+			}
+		}
+		return frames, nil
+	}
+	if costume := spriteResource.Costume(params.Animation); costume != nil {
+		return []SpxAnimationFrame{newFrame(*costume, 0)}, nil
+	}
+	return nil, fmt.Errorf("animation %q of sprite %q not found", params.Animation, params.Sprite)
+}
+
+// spxProjectStructureCacheKind is the cache kind for [SpxProjectStructure].
+type spxProjectStructureCacheKind struct{}
+
+// spxGetProjectStructure gets an overview of the spx project. The result is
+// cached in the project until any file changes.
+func (s *Server) spxGetProjectStructure() (*SpxProjectStructure, error) {
+	structure, err := s.getProjWithFile().Cache(spxProjectStructureCacheKind{})
+	if err != nil {
+		return nil, err
+	}
+	return structure.(*SpxProjectStructure), nil
+}
+
+// buildSpxProjectStructureCache implements [xgo.CacheBuilder] to build the
+// [SpxProjectStructure] of the given project.
+func (s *Server) buildSpxProjectStructureCache(proj *xgo.Project) (any, error) {
+	// The structure is cached in the project and shared by later requests, so
+	// building it is not tied to the context of any single request.
+	result, err := s.compileAt(context.Background(), proj)
+	if err != nil {
+		return nil, err
+	}
+	resourceSet := &result.spxResourceSet
+
+	structure := &SpxProjectStructure{
+		Game: SpxGameStructure{
+			File:          s.toDocumentURI(result.mainSpxFile),
+			BackdropCount: len(resourceSet.backdrops),
+		},
+		Sprites:    make([]SpxSpriteStructure, 0, len(resourceSet.sprites)),
+		SoundCount: len(resourceSet.sounds),
+		Widgets:    slices.Sorted(maps.Keys(resourceSet.widgets)),
+	}
+
+	autoBindings := make(map[string]struct{}, len(result.spxSpriteResourceAutoBindings))
+	for obj := range result.spxSpriteResourceAutoBindings {
+		autoBindings[obj.Name()] = struct{}{}
+	}
+	for _, spriteName := range slices.Sorted(maps.Keys(resourceSet.sprites)) {
+		sprite := resourceSet.sprites[spriteName]
+		spriteStructure := SpxSpriteStructure{
+			Name:           spriteName,
+			CostumeCount:   len(sprite.Costumes),
+			AnimationCount: len(sprite.Animations),
+		}
+		spriteFile := spriteName + ".spx"
+		if _, ok := proj.File(spriteFile); ok {
+			spriteStructure.File = s.toDocumentURI(spriteFile)
+		}
+		_, spriteStructure.AutoBinding = autoBindings[spriteName]
+		structure.Sprites = append(structure.Sprites, spriteStructure)
+	}
+	return structure, nil
+}
+
+// spxGetEventHandlerPositions gets the positions of the spx event handlers
+// in the given document, sorted by position. An event handler is either a
+// top-level call to an spx event handler function like `onStart => { ... }`
+// or a function declaration named like one.
+func (s *Server) spxGetEventHandlerPositions(ctx context.Context, params SpxGetEventHandlerPositionsParams) ([]SpxEventHandlerPosition, error) {
+	result, err := s.compileAndGetASTFileForDocumentURI(ctx, params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	positions := []SpxEventHandlerPosition{}
+	if !result.IsUsable() {
+		return positions, nil
+	}
+	typeInfo, _ := result.proj.TypeInfo()
+	if typeInfo == nil {
+		return positions, nil
+	}
+
+	addPosition := func(nameIdent *ast.Ident, node ast.Node) {
+		def := firstSpxDefinition(result.spxDefinitionsForIdent(nameIdent))
+		if def == nil {
+			return
+		}
+		positions = append(positions, SpxEventHandlerPosition{
+			EventType:  nameIdent.Name,
+			Definition: def.ID,
+			Range:      RangeForNode(result.proj, node),
+		})
+	}
+	for _, decl := range result.astFile.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		if !funcDecl.Shadow {
+			if IsSpxEventHandlerFuncName(funcDecl.Name.Name) {
+				addPosition(funcDecl.Name, funcDecl)
+			}
+			continue
+		}
+		if funcDecl.Body == nil {
+			continue
+		}
+		for _, stmt := range funcDecl.Body.List {
+			exprStmt, ok := stmt.(*ast.ExprStmt)
+			if !ok {
+				continue
+			}
+			callExpr, ok := exprStmt.X.(*ast.CallExpr)
+			if !ok {
+				continue
+			}
+			funIdent, ok := callExpr.Fun.(*ast.Ident)
+			if !ok || !IsSpxEventHandlerFuncName(funIdent.Name) || !IsInSpxPkg(typeInfo.ObjectOf(funIdent)) {
+				continue
+			}
+			addPosition(funIdent, callExpr)
+		}
+	}
+	slices.SortFunc(positions, func(a, b SpxEventHandlerPosition) int {
+		if a.Range.Start.Line != b.Range.Start.Line {
+			return cmp.Compare(a.Range.Start.Line, b.Range.Start.Line)
+		}
+		return cmp.Compare(a.Range.Start.Character, b.Range.Start.Character)
+	})
+	return positions, nil
+}
+
+// spxCheckCode checks the syntax of the given XGo source code and returns the
+// diagnostics for any parse errors. The code is parsed as an spx source file
+// but not type-checked, so it is fast enough to run on every block change.
+func (s *Server) spxCheckCode(params SpxCheckCodeParams) ([]Diagnostic, error) {
+	var header strings.Builder
+	if params.Package != "" {
+		header.WriteString("package " + params.Package + "\n")
+	}
+	for _, importPath := range params.Imports {
+		header.WriteString("import " + strconv.Quote(importPath) + "\n")
+	}
+	headerLineCount := strings.Count(header.String(), "\n")
+
+	fset := token.NewFileSet()
+	src := header.String() + params.Code
+	_, err := parser.ParseFile(fset, "check.spx", src, parser.AllErrors|parser.ParseXGoClass)
+	if err == nil {
+		return []Diagnostic{}, nil
+	}
+
+	var errorList scanner.ErrorList
+	if !errors.As(err, &errorList) {
+		return []Diagnostic{{
+			Severity: SeverityError,
+			Message:  s.translate(fmt.Sprintf("failed to parse code: %v", err)),
+		}}, nil
+	}
+	codeLines := strings.Split(params.Code, "\n")
+	diagnostics := make([]Diagnostic, 0, len(errorList))
+	for _, e := range errorList {
+		position := Position{}
+		if line := e.Pos.Line - 1 - headerLineCount; line >= 0 && line < len(codeLines) {
+			lineText := codeLines[line]
+			column := min(max(e.Pos.Column-1, 0), len(lineText))
+			position = Position{Line: uint32(line), Character: uint32(UTF16Len(lineText[:column]))}
+		}
+		diagnostics = append(diagnostics, Diagnostic{
+			Severity: SeverityError,
+			Range:    Range{Start: position, End: position},
+			Message:  s.translate(e.Msg),
+		})
+	}
+	return diagnostics, nil
+}
+
+// spxGetDefinitionAt gets the spx definition associated with the innermost
+// block at the given position. Calls take precedence over identifiers, so the
+// definition of the called function is returned for any position within a
+// call, except for spx resource references, which resolve to the definition of
+// their resource name type. It returns nil if no definition is found.
+func (s *Server) spxGetDefinitionAt(ctx context.Context, params TextDocumentPositionParams) (*SpxDefinition, error) {
+	result, err := s.compileAndGetASTFileForDocumentURI(ctx, params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	if !result.IsUsable() || !result.astFile.Pos().IsValid() {
+		return nil, nil
+	}
+
+	position := ToPosition(result.proj, result.astFile, params.Position)
+	if spxResourceRef := result.spxResourceRefAtPosition(position); spxResourceRef != nil {
+		nameType := spxResourceNameTypeForID(spxResourceRef.ID)
+		if nameType == nil {
+			return nil, nil
+		}
+		return firstSpxDefinition(result.spxDefinitionsFor(nameType.Obj(), "")), nil
+	}
+
+	pos := PosAt(result.proj, result.astFile, params.Position)
+	path, _ := xgoutil.PathEnclosingInterval(result.astFile, pos, pos)
+	var innermostIdent *ast.Ident
+findInStmt:
+	for _, node := range path {
+		switch node := node.(type) {
+		case ast.Stmt:
+			// Stop at the innermost statement to avoid resolving to enclosing
+			// calls like `onStart => { ... }`.
+			break findInStmt
+		case *ast.CallExpr:
+			var funIdent *ast.Ident
+			switch fun := node.Fun.(type) {
+			case *ast.Ident:
+				funIdent = fun
+			case *ast.SelectorExpr:
+				funIdent = fun.Sel
+			}
+			if funIdent == nil {
+				continue
+			}
+			if def := firstSpxDefinition(result.spxDefinitionsForIdent(funIdent)); def != nil {
+				return def, nil
+			}
+		case *ast.Ident:
+			if innermostIdent == nil {
+				innermostIdent = node
+			}
+		}
+	}
+	if innermostIdent == nil {
+		return nil, nil
+	}
+	return firstSpxDefinition(result.spxDefinitionsForIdent(innermostIdent)), nil
+}
+
+// spxResourceNameTypeForID returns the resource name type for the given spx
+// resource ID, such as [spx.SoundName] for a sound resource ID.
+func spxResourceNameTypeForID(id SpxResourceID) *gotypes.Alias {
+	switch id.(type) {
+	case SpxBackdropResourceID:
+		return GetSpxBackdropNameType()
+	case SpxSoundResourceID:
+		return GetSpxSoundNameType()
+	case SpxSpriteResourceID:
+		return GetSpxSpriteNameType()
+	case SpxSpriteCostumeResourceID:
+		return GetSpxSpriteCostumeNameType()
+	case SpxSpriteAnimationResourceID:
+		return GetSpxSpriteAnimationNameType()
+	case SpxWidgetResourceID:
+		return GetSpxWidgetNameType()
+	}
+	return nil
+}
+
+// firstSpxDefinition returns the first of the given spx definitions, or nil
+// if there is none.
+func firstSpxDefinition(defs []SpxDefinition) *SpxDefinition {
+	if len(defs) == 0 {
+		return nil
+	}
+	return &defs[0]
+}
+
 // propertyMember holds the resolved information for a single property member
 // (field or method) discovered during a type traversal.
 type propertyMember struct {
@@ -619,12 +1339,19 @@ func findInputSlots(result *compileResult, astFile *ast.File) []XGoInputSlot {
 				addInputSlot(checkValueInputSlot(result, expr, nil))
 			}
 		case *ast.RangeStmt:
-			if node.Key != nil && !isBlank(node.Key) {
-				addInputSlot(checkAddressInputSlot(result, node.Key))
-			}
-
-			if node.Value != nil && !isBlank(node.Value) {
-				addInputSlot(checkAddressInputSlot(result, node.Value))
+			for _, expr := range []ast.Expr{node.Key, node.Value} {
+				if expr == nil || isBlank(expr) {
+					continue
+				}
+				slot := checkAddressInputSlot(result, expr)
+				if slot != nil && node.Tok == token.ASSIGN {
+					// The range statement reuses an existing variable, so
+					// its type is known.
+					if typ := typeInfo.TypeOf(expr); typ != nil {
+						slot.Input.Type = inferSpxInputTypeFromTypeInProject(result, xgoutil.DerefType(typ))
+					}
+				}
+				addInputSlot(slot)
 			}
 
 			addInputSlot(checkValueInputSlot(result, node.X, nil))
@@ -656,6 +1383,13 @@ func findInputSlotsFromCallExpr(result *compileResult, callExpr *ast.CallExpr) [
 			declaredType = xgoutil.DerefType(sliceType.Elem())
 		}
 
+		if lit, ok := resolvedArg.Arg.(*ast.CompositeLit); ok {
+			if slots, ok := findInputSlotsFromArrayCompositeLit(result, lit); ok {
+				inputSlots = append(inputSlots, slots...)
+				continue
+			}
+		}
+
 		var slot *SpxInputSlot
 		if lit, ok := resolvedArg.Arg.(*ast.NumberUnitLit); ok {
 			unitExpectedType := xgoUnitExpectedTypeForResolvedArg(resolvedArg)
@@ -668,30 +1402,110 @@ func findInputSlotsFromCallExpr(result *compileResult, callExpr *ast.CallExpr) [
 			slot = checkValueInputSlot(result, resolvedArg.Arg, declaredType)
 		}
 		if slot != nil {
+			slot.CanDelete = canDeleteCallExprArg(result.proj, typeInfo, callExpr, resolvedArg)
 			inputSlots = append(inputSlots, *slot)
 		}
 	}
 	return inputSlots
 }
 
-// collectPredefinedNames collects all predefined names for the given expression.
-func collectPredefinedNames(result *compileResult, expr ast.Expr, declaredType gotypes.Type) []string {
+// canDeleteCallExprArg reports whether resolvedArg can be removed from
+// callExpr without breaking the call. This is the case for keyword arguments,
+// arguments passed to a variadic or optional parameter, and trailing
+// positional arguments that an overload of the callee can do without.
+func canDeleteCallExprArg(proj *xgo.Project, typeInfo *types.Info, callExpr *ast.CallExpr, resolvedArg xgoutil.ResolvedCallExprArg) bool {
+	if resolvedArg.Kind == xgoutil.ResolvedCallExprArgKeyword {
+		return true
+	}
+	if resolvedArg.Fun == nil || resolvedArg.Params == nil || resolvedArg.Param == nil {
+		return false
+	}
+	if resolvedArg.Fun.Signature().Variadic() && resolvedArg.ParamIndex == resolvedArg.Params.Len()-1 {
+		return true
+	}
+	if xgoutil.IsOptionalParam(typeInfo, resolvedArg.Param) {
+		return true
+	}
+
+	if len(callExpr.Kwargs) > 0 || resolvedArg.ArgIndex != len(callExpr.Args)-1 {
+		return false
+	}
+	argCount := len(callExpr.Args) - 1
+	for _, overload := range callExprFuncOverloads(proj, typeInfo, callExpr) {
+		sig := overload.Signature()
+		paramCount := sig.Params().Len()
+		if sig.Variadic() && argCount >= paramCount-1 || !sig.Variadic() && argCount == paramCount {
+			return true
+		}
+	}
+	return false
+}
+
+// findInputSlotsFromArrayCompositeLit finds input slots from the elements of
+// an array composite literal, e.g., `[3]int{1, 2, 3}`, each accepting the
+// element type of the array. It reports false if lit is not an array literal.
+func findInputSlotsFromArrayCompositeLit(result *compileResult, lit *ast.CompositeLit) ([]SpxInputSlot, bool) {
+	typeInfo, _ := result.proj.TypeInfo()
+	if typeInfo == nil {
+		return nil, false
+	}
+	typ := typeInfo.TypeOf(lit)
+	if !xgoutil.IsValidType(typ) {
+		return nil, false
+	}
+	arrayType, ok := typ.Underlying().(*gotypes.Array)
+	if !ok {
+		return nil, false
+	}
+
+	elemType := xgoutil.DerefType(arrayType.Elem())
+	var inputSlots []SpxInputSlot
+	for _, elt := range lit.Elts {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			elt = kv.Value
+		}
+		if slot := checkValueInputSlot(result, elt, elemType); slot != nil {
+			inputSlots = append(inputSlots, *slot)
+		}
+	}
+	return inputSlots, true
+}
+
+// Priorities of predefined names.
+const (
+	predefinedNamePriorityResource = 100 // Resource names of the accepted resource type.
+	predefinedNamePriorityMatching = 50  // In-scope names assignable to the declared type.
+	predefinedNamePriorityOther    = 10  // Other names.
+)
+
+// collectPredefinedNames collects all predefined names for the given
+// expression, sorted by priority in descending order.
+func collectPredefinedNames(result *compileResult, expr ast.Expr, declaredType gotypes.Type) []SpxPredefinedName {
 	typeInfo, _ := result.proj.TypeInfo()
 	astPkg, _ := result.proj.ASTPackage()
 	astFile := xgoutil.NodeASTFile(result.proj.Fset, astPkg, expr)
 	innermostScope := xgoutil.InnermostScopeAt(result.proj.Fset, typeInfo, astPkg, expr.Pos())
 
-	var names []string
+	var names []SpxPredefinedName
 	growNames := func(n int) {
 		names = slices.Grow(names, n)
 	}
-	seenNames := make(map[string]struct{})
+	seenNames := make(map[string]int) // Name to index in names.
+	addName := func(name string, priority int) {
+		if i, ok := seenNames[name]; ok {
+			names[i].Priority = max(names[i].Priority, priority)
+			return
+		}
+		seenNames[name] = len(names)
+		names = append(names, SpxPredefinedName{Name: name, Priority: priority})
+	}
 	addNameOf := func(obj gotypes.Object) {
 		name := obj.Name()
+		priority := predefinedNamePriorityOther
 		switch obj := obj.(type) {
 		case *gotypes.Var, *gotypes.Const:
-			if typ := obj.Type(); typ != nil && declaredType != nil && !gotypes.AssignableTo(typ, declaredType) {
-				return
+			if typ := obj.Type(); typ != nil && declaredType != nil && gotypes.AssignableTo(typ, declaredType) {
+				priority = predefinedNamePriorityMatching
 			}
 
 			switch {
@@ -711,17 +1525,22 @@ func collectPredefinedNames(result *compileResult, expr ast.Expr, declaredType g
 				if !gotypes.AssignableTo(funcReturnType, declaredType) {
 					return
 				}
+				priority = predefinedNamePriorityMatching
 			}
 
 			name = xgoutil.ToLowerCamelCase(name)
 		default:
 			return
 		}
-		if _, ok := seenNames[name]; ok {
-			return
+		addName(name, priority)
+	}
+
+	if declaredType != nil {
+		resourceNames := spxResourceNamesForType(result, declaredType)
+		growNames(len(resourceNames))
+		for _, name := range resourceNames {
+			addName(name, predefinedNamePriorityResource)
 		}
-		seenNames[name] = struct{}{}
-		names = append(names, name)
 	}
 
 	for scope := innermostScope; scope != nil && scope != gotypes.Universe; scope = scope.Parent() {
@@ -783,9 +1602,38 @@ func collectPredefinedNames(result *compileResult, expr ast.Expr, declaredType g
 		}
 	}
 
+	if declaredType != nil {
+		enumValues := GetSpxEnumValues(declaredType)
+		growNames(len(enumValues))
+		for _, name := range enumValues {
+			addName(name, predefinedNamePriorityMatching)
+		}
+	}
+
+	slices.SortStableFunc(names, func(a, b SpxPredefinedName) int {
+		return cmp.Compare(b.Priority, a.Priority)
+	})
 	return names
 }
 
+// spxResourceNamesForType returns the sorted names of the spx resources
+// accepted by the given resource name type. It returns nil if typ is not a
+// resource name type or the resources depend on a sprite context.
+func spxResourceNamesForType(result *compileResult, typ gotypes.Type) []string {
+	set := &result.spxResourceSet
+	switch canonicalSpxResourceNameType(typ) {
+	case GetSpxBackdropNameType():
+		return slices.Sorted(maps.Keys(set.backdrops))
+	case GetSpxSoundNameType():
+		return slices.Sorted(maps.Keys(set.sounds))
+	case GetSpxSpriteNameType():
+		return slices.Sorted(maps.Keys(set.sprites))
+	case GetSpxWidgetNameType():
+		return slices.Sorted(maps.Keys(set.widgets))
+	}
+	return nil
+}
+
 // checkValueInputSlot checks if the expression is a value input slot.
 func checkValueInputSlot(result *compileResult, expr ast.Expr, declaredType gotypes.Type) *SpxInputSlot {
 	switch expr := expr.(type) {
@@ -795,7 +1643,12 @@ func checkValueInputSlot(result *compileResult, expr ast.Expr, declaredType goty
 		return createValueInputSlotFromIdent(result, expr, declaredType)
 	case *ast.UnaryExpr:
 		return createValueInputSlotFromUnaryExpr(result, expr, declaredType)
+	case *ast.BinaryExpr:
+		return createValueInputSlotFromConstExpr(result, expr, declaredType)
 	case *ast.CallExpr:
+		if slot := createValueInputSlotFromTypeConversion(result, expr, declaredType); slot != nil {
+			return slot
+		}
 		return createValueInputSlotFromColorFuncCall(result, expr, declaredType)
 	}
 	return nil
@@ -814,6 +1667,7 @@ func checkAddressInputSlot(result *compileResult, expr ast.Expr) *SpxInputSlot {
 			},
 			PredefinedNames: collectPredefinedNames(result, expr, nil),
 			Range:           RangeForNode(result.proj, ident),
+			EditRange:       RangeForNode(result.proj, ident),
 		}
 	}
 	return nil
@@ -872,6 +1726,7 @@ func createValueInputSlotFromBasicLit(result *compileResult, lit *ast.BasicLit,
 		Input:           input,
 		PredefinedNames: collectPredefinedNames(result, lit, declaredType),
 		Range:           RangeForNode(result.proj, lit),
+		EditRange:       RangeForNode(result.proj, lit),
 	}
 }
 
@@ -911,6 +1766,7 @@ func createValueInputSlotFromNumberUnitLit(result *compileResult, lit *ast.Numbe
 		Input:           input,
 		PredefinedNames: collectPredefinedNames(result, lit, declaredType),
 		Range:           RangeForPosEnd(result.proj, lit.ValuePos, xgoUnitStart(lit)),
+		EditRange:       RangeForPosEnd(result.proj, lit.ValuePos, xgoUnitStart(lit)),
 	}
 }
 
@@ -1008,6 +1864,7 @@ func createValueInputSlotFromIdent(result *compileResult, ident *ast.Ident, decl
 		Input:           input,
 		PredefinedNames: collectPredefinedNames(result, ident, declaredType),
 		Range:           RangeForNode(result.proj, ident),
+		EditRange:       RangeForNode(result.proj, ident),
 	}
 }
 
@@ -1096,13 +1953,227 @@ func createValueInputSlotFromUnaryExpr(result *compileResult, expr *ast.UnaryExp
 				return nil
 			}
 		}
+	case *ast.CompositeLit:
+		if expr.Op != token.AND {
+			return nil
+		}
+		inputSlot = createValueInputSlotFromStructLit(result, x, declaredType)
+		if inputSlot == nil {
+			return nil
+		}
 	default:
 		return nil
 	}
-	inputSlot.Range = RangeForNode(result.proj, expr) // Update the range to include the entire unary expression.
+	// Update the ranges to include the entire unary expression.
+	inputSlot.Range = RangeForNode(result.proj, expr)
+	inputSlot.EditRange = inputSlot.Range
 	return inputSlot
 }
 
+// createValueInputSlotFromConstExpr creates a value input slot from an
+// expression that has been folded into a constant by the type checker, such
+// as `90 + 45` or `"sprite" + "1"`.
+func createValueInputSlotFromConstExpr(result *compileResult, expr ast.Expr, declaredType gotypes.Type) *SpxInputSlot {
+	typeInfo, _ := result.proj.TypeInfo()
+	if typeInfo == nil {
+		return nil
+	}
+	tv, ok := typeInfo.Types[expr]
+	if !ok || tv.Value == nil {
+		return nil
+	}
+
+	input := SpxInput{Kind: SpxInputKindInPlace}
+	switch val := tv.Value; val.Kind() {
+	case constant.String:
+		input.Type = SpxInputTypeString
+		input.Value = constant.StringVal(val)
+	case constant.Int:
+		v, exact := constant.Int64Val(val)
+		if !exact {
+			return nil
+		}
+		input.Type = SpxInputTypeInteger
+		input.Value = v
+	case constant.Float:
+		v, _ := constant.Float64Val(val)
+		input.Type = SpxInputTypeDecimal
+		input.Value = v
+	case constant.Bool:
+		input.Type = SpxInputTypeBoolean
+		input.Value = constant.BoolVal(val)
+	default:
+		return nil
+	}
+
+	accept := SpxInputSlotAccept{Type: input.Type}
+	if declaredType != nil {
+		accept.Type = inferSpxInputTypeFromType(declaredType)
+	}
+	switch accept.Type {
+	case SpxInputTypeResourceName:
+		return nil // Folded resource names cannot be edited in place.
+	case SpxInputTypeDirection:
+		v, _ := constant.Float64Val(constant.ToFloat(tv.Value))
+		input.Type = SpxInputTypeDirection
+		input.Value = v
+	}
+
+	return &SpxInputSlot{
+		Kind:            SpxInputSlotKindValue,
+		Accept:          accept,
+		Input:           input,
+		PredefinedNames: collectPredefinedNames(result, expr, declaredType),
+		Range:           RangeForNode(result.proj, expr),
+		EditRange:       RangeForNode(result.proj, expr),
+	}
+}
+
+// createValueInputSlotFromTypeConversion creates a value input slot from a
+// type conversion like `int(3.7)` or `float64(count)`. A constant argument is
+// folded into an in-place input, while an identifier argument results in a
+// predefined input typed by the conversion target.
+func createValueInputSlotFromTypeConversion(result *compileResult, callExpr *ast.CallExpr, declaredType gotypes.Type) *SpxInputSlot {
+	typeInfo, _ := result.proj.TypeInfo()
+	if typeInfo == nil || len(callExpr.Args) != 1 {
+		return nil
+	}
+	funTV, ok := typeInfo.Types[callExpr.Fun]
+	if !ok || !funTV.IsType() {
+		return nil
+	}
+	targetType := funTV.Type
+	inputType := inferSpxInputTypeFromType(xgoutil.DerefType(targetType))
+	switch inputType {
+	case SpxInputTypeString, SpxInputTypeInteger, SpxInputTypeDecimal, SpxInputTypeBoolean:
+	default:
+		return nil
+	}
+
+	arg := callExpr.Args[0]
+	input := SpxInput{Type: inputType}
+	if argTV, ok := typeInfo.Types[arg]; ok && argTV.Value != nil {
+		value := convertConstToSpxInputValue(argTV.Value, inputType)
+		if value == nil {
+			return nil
+		}
+		input.Kind = SpxInputKindInPlace
+		input.Value = value
+	} else if ident, ok := arg.(*ast.Ident); ok {
+		input.Kind = SpxInputKindPredefined
+		input.Name = ident.Name
+	} else {
+		return nil
+	}
+
+	accept := SpxInputSlotAccept{Type: inputType}
+	if declaredType != nil {
+		accept.Type = inferSpxInputTypeFromType(declaredType)
+	}
+	if accept.Type == SpxInputTypeResourceName {
+		return nil
+	}
+
+	return &SpxInputSlot{
+		Kind:            SpxInputSlotKindValue,
+		Accept:          accept,
+		Input:           input,
+		PredefinedNames: collectPredefinedNames(result, callExpr, targetType),
+		Range:           RangeForNode(result.proj, callExpr),
+		EditRange:       RangeForNode(result.proj, arg),
+	}
+}
+
+// convertConstToSpxInputValue converts the constant value to an input value
+// of the given input type. Numeric constants are truncated towards zero when
+// converted to integers. It returns nil if the conversion is not possible.
+func convertConstToSpxInputValue(val constant.Value, inputType SpxInputType) any {
+	switch inputType {
+	case SpxInputTypeString:
+		if val.Kind() == constant.String {
+			return constant.StringVal(val)
+		}
+	case SpxInputTypeInteger:
+		switch val.Kind() {
+		case constant.Int:
+			if v, exact := constant.Int64Val(val); exact {
+				return v
+			}
+		case constant.Float:
+			v, _ := constant.Float64Val(val)
+			return int64(v)
+		}
+	case SpxInputTypeDecimal:
+		switch val.Kind() {
+		case constant.Int, constant.Float:
+			v, _ := constant.Float64Val(constant.ToFloat(val))
+			return v
+		}
+	case SpxInputTypeBoolean:
+		if val.Kind() == constant.Bool {
+			return constant.BoolVal(val)
+		}
+	}
+	return nil
+}
+
+// createValueInputSlotFromStructLit creates a value input slot from a struct
+// composite literal whose elements are all keyed constant values.
+func createValueInputSlotFromStructLit(result *compileResult, lit *ast.CompositeLit, declaredType gotypes.Type) *SpxInputSlot {
+	typeInfo, _ := result.proj.TypeInfo()
+	if typeInfo == nil {
+		return nil
+	}
+	typ := typeInfo.TypeOf(lit)
+	if typ == nil {
+		return nil
+	}
+	if _, ok := typ.Underlying().(*gotypes.Struct); !ok {
+		return nil
+	}
+
+	fields := make(map[string]any, len(lit.Elts))
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			return nil
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			return nil
+		}
+		tv, ok := typeInfo.Types[kv.Value]
+		if !ok || tv.Value == nil {
+			return nil
+		}
+		value := convertConstToSpxInputValue(tv.Value, inferSpxInputTypeFromType(xgoutil.DerefType(tv.Type)))
+		if value == nil {
+			return nil
+		}
+		fields[key.Name] = value
+	}
+
+	accept := SpxInputSlotAccept{Type: SpxInputTypeStruct}
+	if declaredType != nil {
+		if _, ok := xgoutil.DerefType(declaredType).Underlying().(*gotypes.Struct); !ok {
+			accept.Type = inferSpxInputTypeFromType(declaredType)
+		}
+	}
+
+	return &SpxInputSlot{
+		Kind:   SpxInputSlotKindValue,
+		Accept: accept,
+		Input: SpxInput{
+			Kind:  SpxInputKindInPlace,
+			Type:  SpxInputTypeStruct,
+			Value: fields,
+		},
+		PredefinedNames: collectPredefinedNames(result, lit, declaredType),
+		Range:           RangeForNode(result.proj, lit),
+		EditRange:       RangeForNode(result.proj, lit),
+	}
+}
+
 // createValueInputSlotFromColorFuncCall creates a value input slot from an spx
 // color function call.
 func createValueInputSlotFromColorFuncCall(result *compileResult, callExpr *ast.CallExpr, declaredType gotypes.Type) *SpxInputSlot {
@@ -1172,6 +2243,7 @@ func createValueInputSlotFromColorFuncCall(result *compileResult, callExpr *ast.
 		},
 		PredefinedNames: collectPredefinedNames(result, callExpr, declaredType),
 		Range:           RangeForNode(result.proj, callExpr),
+		EditRange:       RangeForNode(result.proj, callExpr),
 	}
 }
 
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"encoding/json"
 	gotypes "go/types"
 	"reflect"
 	"slices"
@@ -47,10 +48,10 @@ onStart => {
 			"assets/sprites/MySprite/index.json":    []byte(`{}`),
 			"assets/sprites/OtherSprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		params := []SpxGetInputSlotsParams{{TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"}}}
-		inputSlots, err := s.spxGetInputSlots(params)
+		inputSlots, err := s.spxGetInputSlots(t.Context(), params)
 		require.NoError(t, err)
 		require.NotNil(t, inputSlots)
 		assert.Greater(t, len(inputSlots), 10)
@@ -187,7 +188,7 @@ onStart => {
 			)
 			require.NotNil(t, slot)
 			assert.Equal(t, ToPtr(SpxSpriteResourceContextURI), slot.Accept.ResourceContext)
-			assert.Contains(t, slot.PredefinedNames, "OtherSprite")
+			assert.Contains(t, predefinedNameStrings(slot.PredefinedNames), "OtherSprite")
 		})
 
 		t.Run("PredefinedValues", func(t *testing.T) {
@@ -255,10 +256,10 @@ var (
 	message   string
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		params := []SpxGetInputSlotsParams{{TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"}}}
-		inputSlots, err := s.spxGetInputSlots(params)
+		inputSlots, err := s.spxGetInputSlots(t.Context(), params)
 		require.NoError(t, err)
 		assert.Nil(t, inputSlots)
 	})
@@ -267,20 +268,20 @@ var (
 		m := map[string][]byte{
 			"main.spx": []byte(``),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		params := []SpxGetInputSlotsParams{{TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"}}}
-		inputSlots, err := s.spxGetInputSlots(params)
+		inputSlots, err := s.spxGetInputSlots(t.Context(), params)
 		require.NoError(t, err)
 		assert.Empty(t, inputSlots)
 	})
 
 	t.Run("NonExistentFile", func(t *testing.T) {
 		m := map[string][]byte{}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		params := []SpxGetInputSlotsParams{{TextDocument: TextDocumentIdentifier{URI: "file:///nonexistent.spx"}}}
-		inputSlots, err := s.spxGetInputSlots(params)
+		inputSlots, err := s.spxGetInputSlots(t.Context(), params)
 		require.Error(t, err)
 		assert.Nil(t, inputSlots)
 	})
@@ -289,18 +290,108 @@ var (
 		m := map[string][]byte{
 			"main.spx": []byte(`var a = 1`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		params := []SpxGetInputSlotsParams{
 			{TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"}},
 			{TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"}},
 		}
-		inputSlots, err := s.spxGetInputSlots(params)
+		inputSlots, err := s.spxGetInputSlots(t.Context(), params)
 		require.Error(t, err)
 		assert.Nil(t, inputSlots)
 		assert.ErrorContains(t, err, "only supports one document")
 	})
 
+	t.Run("CanDelete", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.spx": []byte(`
+func add(a, b int) int {
+	return a + b
+}
+
+onStart => {
+	println 42, "text"
+	echo add(10, 20)
+}
+`),
+			"assets/index.json": []byte(`{}`),
+		}
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+		params := []SpxGetInputSlotsParams{{TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"}}}
+		inputSlots, err := s.spxGetInputSlots(t.Context(), params)
+		require.NoError(t, err)
+
+		variadicSlot := findInputSlot(inputSlots, "text", "", SpxInputTypeString, SpxInputKindInPlace)
+		require.NotNil(t, variadicSlot)
+		assert.True(t, variadicSlot.CanDelete)
+
+		requiredSlot := findInputSlot(inputSlots, int64(10), "", SpxInputTypeInteger, SpxInputKindInPlace)
+		require.NotNil(t, requiredSlot)
+		assert.False(t, requiredSlot.CanDelete)
+	})
+
+	t.Run("Batch", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.spx": []byte(`
+onStart => {
+	println 42
+}
+`),
+			"MySprite.spx": []byte(`
+onStart => {
+	say "Hello"
+}
+`),
+			"assets/index.json":                  []byte(`{}`),
+			"assets/sprites/MySprite/index.json": []byte(`{}`),
+		}
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+		params := []SpxGetInputSlotsParams{
+			{TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"}},
+			{TextDocument: TextDocumentIdentifier{URI: "file:///MySprite.spx"}},
+		}
+		batchResult, err := s.spxGetInputSlotsBatch(t.Context(), params)
+		require.NoError(t, err)
+		require.Len(t, batchResult, 2)
+
+		mainSlots := batchResult["file:///main.spx"]
+		require.NotNil(t, findInputSlot(mainSlots, int64(42), "", SpxInputTypeInteger, SpxInputKindInPlace))
+		spriteSlots := batchResult["file:///MySprite.spx"]
+		require.NotNil(t, findInputSlot(spriteSlots, "Hello", "", SpxInputTypeString, SpxInputKindInPlace))
+
+		for _, param := range params {
+			inputSlots, err := s.spxGetInputSlots(t.Context(), []SpxGetInputSlotsParams{param})
+			require.NoError(t, err)
+			assert.Equal(t, batchResult[param.TextDocument.URI], inputSlots)
+		}
+
+		cmdResult, err := s.workspaceExecuteCommand(t.Context(), &ExecuteCommandParams{
+			Command: CommandXGoGetInputSlots,
+			Arguments: []json.RawMessage{
+				json.RawMessage(`{"textDocument":{"uri":"file:///main.spx"}}`),
+				json.RawMessage(`{"textDocument":{"uri":"file:///MySprite.spx"}}`),
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, batchResult, cmdResult)
+	})
+
+	t.Run("BatchWithInvalidDocument", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.spx": []byte(`var a = 1`),
+		}
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+		batchResult, err := s.spxGetInputSlotsBatch(t.Context(), []SpxGetInputSlotsParams{
+			{TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"}},
+			{TextDocument: TextDocumentIdentifier{URI: "file:///main.xgo"}},
+		})
+		require.Error(t, err)
+		assert.Nil(t, batchResult)
+	})
+
 	t.Run("SpxSpriteInstanceVariable", func(t *testing.T) {
 		m := map[string][]byte{
 			"main.spx": []byte(`
@@ -317,9 +408,9 @@ onStart => {
 			"assets/sprites/MySprite/index.json":    []byte(`{}`),
 			"assets/sprites/OtherSprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		inputSlots, err := s.spxGetInputSlots([]SpxGetInputSlotsParams{
+		inputSlots, err := s.spxGetInputSlots(t.Context(), []SpxGetInputSlotsParams{
 			{TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"}},
 		})
 		require.NoError(t, err)
@@ -328,8 +419,8 @@ onStart => {
 		require.NotNil(t, slot)
 		assert.Equal(t, SpxInputTypeSpriteInstance, slot.Accept.Type)
 		assert.Equal(t, ToPtr(SpxSpriteResourceContextURI), slot.Accept.ResourceContext)
-		assert.Contains(t, slot.PredefinedNames, "target")
-		assert.Contains(t, slot.PredefinedNames, "OtherSprite")
+		assert.Contains(t, predefinedNameStrings(slot.PredefinedNames), "target")
+		assert.Contains(t, predefinedNameStrings(slot.PredefinedNames), "OtherSprite")
 		assert.Equal(t, Range{
 			Start: Position{Line: 5, Character: 17},
 			End:   Position{Line: 5, Character: 23},
@@ -340,10 +431,10 @@ onStart => {
 		m := map[string][]byte{
 			"main.spx": []byte(`var a = 1`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		params := []SpxGetInputSlotsParams{}
-		inputSlots, err := s.spxGetInputSlots(params)
+		inputSlots, err := s.spxGetInputSlots(t.Context(), params)
 		require.NoError(t, err)
 		assert.Nil(t, inputSlots)
 	})
@@ -357,7 +448,7 @@ type Foo struct {
 
 func (Foo) Bar`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		params := []SpxGetInputSlotsParams{{TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"}}}
 
@@ -366,7 +457,7 @@ func (Foo) Bar`),
 			err        error
 		)
 		assert.NotPanics(t, func() {
-			inputSlots, err = s.spxGetInputSlots(params)
+			inputSlots, err = s.spxGetInputSlots(t.Context(), params)
 		})
 		require.NoError(t, err)
 		assert.Nil(t, inputSlots)
@@ -386,10 +477,10 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		params := []SpxGetInputSlotsParams{{TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"}}}
-		inputSlots, err := s.spxGetInputSlots(params)
+		inputSlots, err := s.spxGetInputSlots(t.Context(), params)
 		require.NoError(t, err)
 		require.NotNil(t, inputSlots)
 
@@ -420,10 +511,10 @@ onStart => {
 			"assets/sprites/MySprite/index.json":    []byte(`{}`),
 			"assets/sprites/OtherSprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		params := []SpxGetInputSlotsParams{{TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"}}}
-		inputSlots, err := s.spxGetInputSlots(params)
+		inputSlots, err := s.spxGetInputSlots(t.Context(), params)
 		require.NoError(t, err)
 		require.NotNil(t, inputSlots)
 
@@ -438,7 +529,7 @@ onStart => {
 		assert.Equal(t, SpxInputSlotKindValue, slot.Kind)
 		assert.Equal(t, SpxInputTypeSpriteInstance, slot.Accept.Type)
 		assert.Equal(t, ToPtr(SpxSpriteResourceContextURI), slot.Accept.ResourceContext)
-		assert.Contains(t, slot.PredefinedNames, "OtherSprite")
+		assert.Contains(t, predefinedNameStrings(slot.PredefinedNames), "OtherSprite")
 	})
 
 	t.Run("OverloadKwargValue", func(t *testing.T) {
@@ -463,10 +554,10 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		params := []SpxGetInputSlotsParams{{TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"}}}
-		inputSlots, err := s.spxGetInputSlots(params)
+		inputSlots, err := s.spxGetInputSlots(t.Context(), params)
 		require.NoError(t, err)
 		require.NotNil(t, inputSlots)
 
@@ -493,10 +584,10 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		params := []SpxGetInputSlotsParams{{TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"}}}
-		inputSlots, err := s.spxGetInputSlots(params)
+		inputSlots, err := s.spxGetInputSlots(t.Context(), params)
 		require.NoError(t, err)
 		require.NotNil(t, inputSlots)
 
@@ -519,7 +610,7 @@ onStart => {
 `)
 
 		params := []SpxGetInputSlotsParams{{TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"}}}
-		inputSlots, err := s.spxGetInputSlots(params)
+		inputSlots, err := s.spxGetInputSlots(t.Context(), params)
 		require.NoError(t, err)
 		require.NotNil(t, inputSlots)
 
@@ -546,7 +637,7 @@ onStart => {
 `)
 
 		params := []SpxGetInputSlotsParams{{TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"}}}
-		inputSlots, err := s.spxGetInputSlots(params)
+		inputSlots, err := s.spxGetInputSlots(t.Context(), params)
 		require.NoError(t, err)
 		require.NotNil(t, inputSlots)
 
@@ -582,7 +673,7 @@ onStart => {
 `)
 
 		params := []SpxGetInputSlotsParams{{TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"}}}
-		inputSlots, err := s.spxGetInputSlots(params)
+		inputSlots, err := s.spxGetInputSlots(t.Context(), params)
 		require.NoError(t, err)
 		require.NotNil(t, inputSlots)
 
@@ -621,7 +712,7 @@ onStart => {
 `)
 
 		params := []SpxGetInputSlotsParams{{TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"}}}
-		inputSlots, err := s.spxGetInputSlots(params)
+		inputSlots, err := s.spxGetInputSlots(t.Context(), params)
 		require.NoError(t, err)
 
 		assert.Nil(t, findInputSlotByRange(inputSlots, Range{
@@ -727,14 +818,14 @@ onStart => {
 		"assets/sprites/MySprite/index.json":    []byte(`{}`),
 		"assets/sprites/OtherSprite/index.json": []byte(`{}`),
 	}
-	s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+	s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-	result, _, astFile, err := s.compileAndGetASTFileForDocumentURI("file:///main.spx")
+	result, err := s.compileAndGetASTFileForDocumentURI(t.Context(), "file:///main.spx")
 	require.NoError(t, err)
 	require.False(t, result.hasErrorSeverityDiagnostic)
-	require.NotNil(t, astFile)
+	require.NotNil(t, result.astFile)
 
-	inputSlots := findInputSlots(result, astFile)
+	inputSlots := findInputSlots(result.compileResult, result.astFile)
 	require.NotNil(t, inputSlots)
 	assert.NotEmpty(t, inputSlots)
 
@@ -839,7 +930,7 @@ onStart => {
 		)
 		require.NotNil(t, slot)
 		assert.Equal(t, ToPtr(SpxSpriteResourceContextURI), slot.Accept.ResourceContext)
-		assert.Contains(t, slot.PredefinedNames, "OtherSprite")
+		assert.Contains(t, predefinedNameStrings(slot.PredefinedNames), "OtherSprite")
 	})
 
 	t.Run("AddressSlots", func(t *testing.T) {
@@ -881,19 +972,19 @@ onStart => {
 				assert.Equal(t, SpxInputKindPredefined, slot.Input.Kind)
 				assert.Equal(t, SpxInputTypeUnknown, slot.Input.Type)
 				assert.Equal(t, tt.wantInputName, slot.Input.Name)
-				assert.Contains(t, slot.PredefinedNames, "backdropName")
+				assert.Contains(t, predefinedNameStrings(slot.PredefinedNames), "backdropName")
 				assert.NotEmpty(t, slot.Range)
 			})
 		}
 	})
 
 	t.Run("SpxSpriteStepTo", func(t *testing.T) {
-		result, _, astFile, err := s.compileAndGetASTFileForDocumentURI("file:///MySprite.spx")
+		result, err := s.compileAndGetASTFileForDocumentURI(t.Context(), "file:///MySprite.spx")
 		require.NoError(t, err)
 		require.False(t, result.hasErrorSeverityDiagnostic)
-		require.NotNil(t, astFile)
+		require.NotNil(t, result.astFile)
 
-		inputSlots := findInputSlots(result, astFile)
+		inputSlots := findInputSlots(result.compileResult, result.astFile)
 		require.NotNil(t, inputSlots)
 		assert.NotEmpty(t, inputSlots)
 
@@ -903,7 +994,7 @@ onStart => {
 		assert.Equal(t, SpxInputKindPredefined, slot.Input.Kind)
 		assert.Equal(t, SpxInputTypeString, slot.Input.Type)
 		assert.Equal(t, "name", slot.Input.Name)
-		assert.Contains(t, slot.PredefinedNames, "backdropName")
+		assert.Contains(t, predefinedNameStrings(slot.PredefinedNames), "backdropName")
 		assert.Equal(t, slot.Range, Range{
 			Start: Position{Line: 3, Character: 8},
 			End:   Position{Line: 3, Character: 12},
@@ -911,12 +1002,12 @@ onStart => {
 	})
 
 	t.Run("SpxSpriteClone", func(t *testing.T) {
-		result, _, astFile, err := s.compileAndGetASTFileForDocumentURI("file:///MySprite.spx")
+		result, err := s.compileAndGetASTFileForDocumentURI(t.Context(), "file:///MySprite.spx")
 		require.NoError(t, err)
 		require.False(t, result.hasErrorSeverityDiagnostic)
-		require.NotNil(t, astFile)
+		require.NotNil(t, result.astFile)
 
-		inputSlots := findInputSlots(result, astFile)
+		inputSlots := findInputSlots(result.compileResult, result.astFile)
 		require.NotNil(t, inputSlots)
 		assert.NotEmpty(t, inputSlots)
 
@@ -926,7 +1017,7 @@ onStart => {
 		assert.Equal(t, SpxInputKindPredefined, slot.Input.Kind)
 		assert.Equal(t, SpxInputTypeString, slot.Input.Type)
 		assert.Equal(t, "data", slot.Input.Name)
-		assert.Contains(t, slot.PredefinedNames, "backdropName")
+		assert.Contains(t, predefinedNameStrings(slot.PredefinedNames), "backdropName")
 		assert.Equal(t, slot.Range, Range{
 			Start: Position{Line: 5, Character: 7},
 			End:   Position{Line: 5, Character: 11},
@@ -956,12 +1047,12 @@ onStart => {
 `),
 		"assets/index.json": []byte(`{}`),
 	}
-	s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+	s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-	result, _, astFile, err := s.compileAndGetASTFileForDocumentURI("file:///main.spx")
+	result, err := s.compileAndGetASTFileForDocumentURI(t.Context(), "file:///main.spx")
 	require.NoError(t, err)
 	require.False(t, result.hasErrorSeverityDiagnostic)
-	require.NotNil(t, astFile)
+	require.NotNil(t, result.astFile)
 
 	for _, tt := range []struct {
 		name           string
@@ -1046,11 +1137,11 @@ onStart => {
 		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
-			pos := PosAt(result.proj, astFile, tt.exprPosition)
+			pos := PosAt(result.proj, result.astFile, tt.exprPosition)
 			require.True(t, pos.IsValid())
 
 			var expr ast.Expr
-			for node := range xgoutil.PathEnclosingIntervalNodes(astFile, pos, pos, false) {
+			for node := range xgoutil.PathEnclosingIntervalNodes(result.astFile, pos, pos, false) {
 				if node, ok := node.(ast.Expr); ok && tt.exprFilter(node) {
 					expr = node
 					break
@@ -1058,7 +1149,7 @@ onStart => {
 			}
 			require.NotNil(t, expr)
 
-			got := checkValueInputSlot(result, expr, nil)
+			got := checkValueInputSlot(result.compileResult, expr, nil)
 			if tt.wantNil {
 				assert.Nil(t, got)
 			} else {
@@ -1075,6 +1166,410 @@ onStart => {
 	}
 }
 
+func TestFindInputSlotsForRangeStmt(t *testing.T) {
+	m := map[string][]byte{
+		"main.spx": []byte(`
+onStart => {
+	arr := []string{"a", "b"}
+	var i int
+	var v string
+	for i, v = range arr {
+		println i, v
+	}
+	for j, w := range arr {
+		println j, w
+	}
+	for _, v = range arr {
+	}
+}
+`),
+		"assets/index.json": []byte(`{}`),
+	}
+	s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+	result, err := s.compileAndGetASTFileForDocumentURI(t.Context(), "file:///main.spx")
+	require.NoError(t, err)
+	require.False(t, result.hasErrorSeverityDiagnostic)
+	require.NotNil(t, result.astFile)
+
+	inputSlots := findInputSlots(result.compileResult, result.astFile)
+
+	for _, tt := range []struct {
+		name          string
+		inputRange    Range
+		wantNil       bool
+		wantInputName string
+		wantInputType SpxInputType
+	}{
+		{
+			name: "AssignKey",
+			inputRange: Range{
+				Start: Position{Line: 5, Character: 5},
+				End:   Position{Line: 5, Character: 6},
+			},
+			wantInputName: "i",
+			wantInputType: SpxInputTypeInteger,
+		},
+		{
+			name: "AssignValue",
+			inputRange: Range{
+				Start: Position{Line: 5, Character: 8},
+				End:   Position{Line: 5, Character: 9},
+			},
+			wantInputName: "v",
+			wantInputType: SpxInputTypeString,
+		},
+		{
+			name: "DefineKey",
+			inputRange: Range{
+				Start: Position{Line: 8, Character: 5},
+				End:   Position{Line: 8, Character: 6},
+			},
+			wantInputName: "j",
+			wantInputType: SpxInputTypeUnknown,
+		},
+		{
+			name: "BlankKey",
+			inputRange: Range{
+				Start: Position{Line: 11, Character: 5},
+				End:   Position{Line: 11, Character: 6},
+			},
+			wantNil: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			slot := findInputSlotByRange(inputSlots, tt.inputRange)
+			if tt.wantNil {
+				assert.Nil(t, slot)
+				return
+			}
+			require.NotNil(t, slot)
+			assert.Equal(t, SpxInputSlotKindAddress, slot.Kind)
+			assert.Equal(t, SpxInputKindPredefined, slot.Input.Kind)
+			assert.Equal(t, tt.wantInputName, slot.Input.Name)
+			assert.Equal(t, tt.wantInputType, slot.Input.Type)
+		})
+	}
+}
+
+func TestFindInputSlotsFromArrayCompositeLit(t *testing.T) {
+	m := map[string][]byte{
+		"main.spx": []byte(`
+func myFunc(nums [3]int) {}
+
+onStart => {
+	myFunc [3]int{1, 2, 3}
+}
+`),
+		"assets/index.json": []byte(`{}`),
+	}
+	s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+	result, err := s.compileAndGetASTFileForDocumentURI(t.Context(), "file:///main.spx")
+	require.NoError(t, err)
+	require.False(t, result.hasErrorSeverityDiagnostic)
+	require.NotNil(t, result.astFile)
+
+	inputSlots := findInputSlots(result.compileResult, result.astFile)
+	for i, wantValue := range []int64{1, 2, 3} {
+		inputRange := Range{
+			Start: Position{Line: 4, Character: uint32(15 + 3*i)},
+			End:   Position{Line: 4, Character: uint32(16 + 3*i)},
+		}
+		slot := findInputSlotByRange(inputSlots, inputRange)
+		require.NotNil(t, slot, "no input slot at %v", inputRange)
+		assert.Equal(t, SpxInputSlotKindValue, slot.Kind)
+		assert.Equal(t, SpxInputTypeInteger, slot.Accept.Type)
+		assert.Equal(t, SpxInputKindInPlace, slot.Input.Kind)
+		assert.Equal(t, SpxInputTypeInteger, slot.Input.Type)
+		assert.Equal(t, wantValue, slot.Input.Value)
+	}
+}
+
+func TestCreateValueInputSlotFromConstExpr(t *testing.T) {
+	m := map[string][]byte{
+		"main.spx": []byte(`
+onStart => {
+	MySprite.show
+}
+`),
+		"MySprite.spx": []byte(`
+onStart => {
+	turn 90 + 45
+	name := "sprite" + "1"
+	visible := 1 < 2
+	count := 10
+	total := count + 1
+	println name, visible, total
+}
+`),
+		"assets/index.json":                  []byte(`{}`),
+		"assets/sprites/MySprite/index.json": []byte(`{}`),
+	}
+	s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+	result, err := s.compileAndGetASTFileForDocumentURI(t.Context(), "file:///MySprite.spx")
+	require.NoError(t, err)
+	require.False(t, result.hasErrorSeverityDiagnostic)
+	require.NotNil(t, result.astFile)
+
+	inputSlots := findInputSlots(result.compileResult, result.astFile)
+
+	for _, tt := range []struct {
+		name           string
+		inputRange     Range
+		wantNil        bool
+		wantAcceptType SpxInputType
+		wantInputType  SpxInputType
+		wantInputValue any
+	}{
+		{
+			name: "Direction",
+			inputRange: Range{
+				Start: Position{Line: 2, Character: 6},
+				End:   Position{Line: 2, Character: 13},
+			},
+			wantAcceptType: SpxInputTypeDirection,
+			wantInputType:  SpxInputTypeDirection,
+			wantInputValue: float64(135),
+		},
+		{
+			name: "String",
+			inputRange: Range{
+				Start: Position{Line: 3, Character: 9},
+				End:   Position{Line: 3, Character: 23},
+			},
+			wantAcceptType: SpxInputTypeString,
+			wantInputType:  SpxInputTypeString,
+			wantInputValue: "sprite1",
+		},
+		{
+			name: "Boolean",
+			inputRange: Range{
+				Start: Position{Line: 4, Character: 12},
+				End:   Position{Line: 4, Character: 17},
+			},
+			wantAcceptType: SpxInputTypeBoolean,
+			wantInputType:  SpxInputTypeBoolean,
+			wantInputValue: true,
+		},
+		{
+			name: "NonConstant",
+			inputRange: Range{
+				Start: Position{Line: 6, Character: 10},
+				End:   Position{Line: 6, Character: 19},
+			},
+			wantNil: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			slot := findInputSlotByRange(inputSlots, tt.inputRange)
+			if tt.wantNil {
+				assert.Nil(t, slot)
+				return
+			}
+			require.NotNil(t, slot)
+			assert.Equal(t, SpxInputSlotKindValue, slot.Kind)
+			assert.Equal(t, tt.wantAcceptType, slot.Accept.Type)
+			assert.Equal(t, SpxInputKindInPlace, slot.Input.Kind)
+			assert.Equal(t, tt.wantInputType, slot.Input.Type)
+			assert.Equal(t, tt.wantInputValue, slot.Input.Value)
+		})
+	}
+
+	t.Run("NonConstantOperands", func(t *testing.T) {
+		slot := findInputSlotByRange(inputSlots, Range{
+			Start: Position{Line: 6, Character: 10},
+			End:   Position{Line: 6, Character: 15},
+		})
+		require.NotNil(t, slot)
+		assert.Equal(t, SpxInputKindPredefined, slot.Input.Kind)
+		assert.Equal(t, "count", slot.Input.Name)
+	})
+}
+
+func TestCreateValueInputSlotFromTypeConversion(t *testing.T) {
+	m := map[string][]byte{
+		"main.spx": []byte(`
+onStart => {
+	count := 10
+	a := int(3.7)
+	b := float64(count)
+	c := string(65)
+	d := int(42)
+	println a, b, c, d
+}
+`),
+		"assets/index.json": []byte(`{}`),
+	}
+	s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+	result, err := s.compileAndGetASTFileForDocumentURI(t.Context(), "file:///main.spx")
+	require.NoError(t, err)
+	require.NotNil(t, result.astFile)
+
+	inputSlots := findInputSlots(result.compileResult, result.astFile)
+
+	for _, tt := range []struct {
+		name           string
+		inputRange     Range
+		wantNil        bool
+		wantInputKind  SpxInputKind
+		wantInputType  SpxInputType
+		wantInputValue any
+		wantInputName  string
+	}{
+		{
+			name: "ConstantArgument",
+			inputRange: Range{
+				Start: Position{Line: 3, Character: 6},
+				End:   Position{Line: 3, Character: 14},
+			},
+			wantInputKind:  SpxInputKindInPlace,
+			wantInputType:  SpxInputTypeInteger,
+			wantInputValue: int64(3),
+		},
+		{
+			name: "VariableArgument",
+			inputRange: Range{
+				Start: Position{Line: 4, Character: 6},
+				End:   Position{Line: 4, Character: 20},
+			},
+			wantInputKind: SpxInputKindPredefined,
+			wantInputType: SpxInputTypeDecimal,
+			wantInputName: "count",
+		},
+		{
+			name: "MismatchedConstantArgument",
+			inputRange: Range{
+				Start: Position{Line: 5, Character: 6},
+				End:   Position{Line: 5, Character: 16},
+			},
+			wantNil: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			slot := findInputSlotByRange(inputSlots, tt.inputRange)
+			if tt.wantNil {
+				assert.Nil(t, slot)
+				return
+			}
+			require.NotNil(t, slot)
+			assert.Equal(t, SpxInputSlotKindValue, slot.Kind)
+			assert.Equal(t, tt.wantInputType, slot.Accept.Type)
+			assert.Equal(t, tt.wantInputKind, slot.Input.Kind)
+			assert.Equal(t, tt.wantInputType, slot.Input.Type)
+			assert.Equal(t, tt.wantInputValue, slot.Input.Value)
+			assert.Equal(t, tt.wantInputName, slot.Input.Name)
+		})
+	}
+
+	t.Run("EditRange", func(t *testing.T) {
+		slot := findInputSlotByRange(inputSlots, Range{
+			Start: Position{Line: 6, Character: 6},
+			End:   Position{Line: 6, Character: 13},
+		})
+		require.NotNil(t, slot)
+		assert.Equal(t, int64(42), slot.Input.Value)
+		assert.Equal(t, Range{
+			Start: Position{Line: 6, Character: 10},
+			End:   Position{Line: 6, Character: 12},
+		}, slot.EditRange)
+	})
+
+	t.Run("EditRangeOfLiteral", func(t *testing.T) {
+		slot := findInputSlotByRange(inputSlots, Range{
+			Start: Position{Line: 2, Character: 10},
+			End:   Position{Line: 2, Character: 12},
+		})
+		require.NotNil(t, slot)
+		assert.Equal(t, slot.Range, slot.EditRange)
+	})
+}
+
+func TestCreateValueInputSlotFromStructLit(t *testing.T) {
+	m := map[string][]byte{
+		"main.spx": []byte(`
+type Vector struct {
+	X, Y float64
+	Name string
+}
+
+onStart => {
+	a := &Vector{X: 1.5, Y: 2.5}
+	b := &Vector{Name: "origin"}
+	x := 3.0
+	c := &Vector{X: x}
+	d := &Vector{1, 2, "unkeyed"}
+	println a, b, c, d
+}
+`),
+		"assets/index.json": []byte(`{}`),
+	}
+	s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+	result, err := s.compileAndGetASTFileForDocumentURI(t.Context(), "file:///main.spx")
+	require.NoError(t, err)
+	require.False(t, result.hasErrorSeverityDiagnostic)
+	require.NotNil(t, result.astFile)
+
+	inputSlots := findInputSlots(result.compileResult, result.astFile)
+
+	for _, tt := range []struct {
+		name           string
+		inputRange     Range
+		wantNil        bool
+		wantInputValue map[string]any
+	}{
+		{
+			name: "NumberFields",
+			inputRange: Range{
+				Start: Position{Line: 7, Character: 6},
+				End:   Position{Line: 7, Character: 29},
+			},
+			wantInputValue: map[string]any{"X": 1.5, "Y": 2.5},
+		},
+		{
+			name: "StringField",
+			inputRange: Range{
+				Start: Position{Line: 8, Character: 6},
+				End:   Position{Line: 8, Character: 29},
+			},
+			wantInputValue: map[string]any{"Name": "origin"},
+		},
+		{
+			name: "NonConstantField",
+			inputRange: Range{
+				Start: Position{Line: 10, Character: 6},
+				End:   Position{Line: 10, Character: 19},
+			},
+			wantNil: true,
+		},
+		{
+			name: "UnkeyedFields",
+			inputRange: Range{
+				Start: Position{Line: 11, Character: 6},
+				End:   Position{Line: 11, Character: 30},
+			},
+			wantNil: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			slot := findInputSlotByRange(inputSlots, tt.inputRange)
+			if tt.wantNil {
+				assert.Nil(t, slot)
+				return
+			}
+			require.NotNil(t, slot)
+			assert.Equal(t, SpxInputSlotKindValue, slot.Kind)
+			assert.Equal(t, SpxInputTypeStruct, slot.Accept.Type)
+			assert.Equal(t, SpxInputKindInPlace, slot.Input.Kind)
+			assert.Equal(t, SpxInputTypeStruct, slot.Input.Type)
+			assert.Equal(t, tt.wantInputValue, slot.Input.Value)
+		})
+	}
+}
+
 func TestCheckAddressInputSlot(t *testing.T) {
 	m := map[string][]byte{
 		"main.spx": []byte(`
@@ -1090,12 +1585,12 @@ onStart => {
 `),
 		"assets/index.json": []byte(`{}`),
 	}
-	s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+	s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-	result, _, astFile, err := s.compileAndGetASTFileForDocumentURI("file:///main.spx")
+	result, err := s.compileAndGetASTFileForDocumentURI(t.Context(), "file:///main.spx")
 	require.NoError(t, err)
 	require.False(t, result.hasErrorSeverityDiagnostic)
-	require.NotNil(t, astFile)
+	require.NotNil(t, result.astFile)
 
 	for _, tt := range []struct {
 		name         string
@@ -1124,11 +1619,11 @@ onStart => {
 		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
-			pos := PosAt(result.proj, astFile, tt.exprPosition)
+			pos := PosAt(result.proj, result.astFile, tt.exprPosition)
 			require.True(t, pos.IsValid())
 
 			var expr ast.Expr
-			for node := range xgoutil.PathEnclosingIntervalNodes(astFile, pos, pos, false) {
+			for node := range xgoutil.PathEnclosingIntervalNodes(result.astFile, pos, pos, false) {
 				if node, ok := node.(ast.Expr); ok && tt.exprFilter(node) {
 					expr = node
 					break
@@ -1136,7 +1631,7 @@ onStart => {
 			}
 			require.NotNil(t, expr)
 
-			got := checkAddressInputSlot(result, expr)
+			got := checkAddressInputSlot(result.compileResult, expr)
 			if tt.wantNil {
 				assert.Nil(t, got)
 			} else {
@@ -1175,12 +1670,12 @@ onStart => {
 		"assets/sprites/MySprite/index.json":    []byte(`{}`),
 		"assets/sprites/OtherSprite/index.json": []byte(`{}`),
 	}
-	s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+	s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-	result, _, astFile, err := s.compileAndGetASTFileForDocumentURI("file:///main.spx")
+	result, err := s.compileAndGetASTFileForDocumentURI(t.Context(), "file:///main.spx")
 	require.NoError(t, err)
 	require.False(t, result.hasErrorSeverityDiagnostic)
-	require.NotNil(t, astFile)
+	require.NotNil(t, result.astFile)
 
 	for _, tt := range []struct {
 		name           string
@@ -1242,11 +1737,11 @@ onStart => {
 		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
-			pos := PosAt(result.proj, astFile, tt.litPosition)
+			pos := PosAt(result.proj, result.astFile, tt.litPosition)
 			require.True(t, pos.IsValid())
 
 			var lit *ast.BasicLit
-			for node := range xgoutil.PathEnclosingIntervalNodes(astFile, pos, pos, false) {
+			for node := range xgoutil.PathEnclosingIntervalNodes(result.astFile, pos, pos, false) {
 				if node, ok := node.(*ast.BasicLit); ok {
 					lit = node
 					break
@@ -1254,7 +1749,7 @@ onStart => {
 			}
 			require.NotNil(t, lit)
 
-			got := createValueInputSlotFromBasicLit(result, lit, tt.declaredType)
+			got := createValueInputSlotFromBasicLit(result.compileResult, lit, tt.declaredType)
 			require.NotNil(t, got)
 			assert.Equal(t, SpxInputSlotKindValue, got.Kind)
 			assert.Equal(t, tt.wantAcceptType, got.Accept.Type)
@@ -1270,7 +1765,7 @@ onStart => {
 			Kind:  token.INT,
 			Value: "not.a.int",
 		}
-		got := createValueInputSlotFromBasicLit(result, invalidIntLit, nil)
+		got := createValueInputSlotFromBasicLit(result.compileResult, invalidIntLit, nil)
 		assert.Nil(t, got)
 	})
 
@@ -1279,7 +1774,7 @@ onStart => {
 			Kind:  token.FLOAT,
 			Value: "not.a.float",
 		}
-		got := createValueInputSlotFromBasicLit(result, invalidFloatLit, nil)
+		got := createValueInputSlotFromBasicLit(result.compileResult, invalidFloatLit, nil)
 		assert.Nil(t, got)
 	})
 
@@ -1288,7 +1783,7 @@ onStart => {
 			Kind:  token.CHAR,
 			Value: "'c'",
 		}
-		got := createValueInputSlotFromBasicLit(result, unsupportedLit, nil)
+		got := createValueInputSlotFromBasicLit(result.compileResult, unsupportedLit, nil)
 		assert.Nil(t, got)
 	})
 
@@ -1297,7 +1792,7 @@ onStart => {
 			Kind:  token.STRING,
 			Value: "\"unclosed string literal", // Missing ending quote.
 		}
-		got := createValueInputSlotFromBasicLit(result, invalidStringLit, nil)
+		got := createValueInputSlotFromBasicLit(result.compileResult, invalidStringLit, nil)
 		assert.Nil(t, got)
 	})
 }
@@ -1338,12 +1833,12 @@ onStart => {
 		"assets/sprites/MySprite/index.json": []byte(`{}`),
 		"assets/sounds/MySound/index.json":   []byte(`{}`),
 	}
-	s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+	s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-	result, _, astFile, err := s.compileAndGetASTFileForDocumentURI("file:///main.spx")
+	result, err := s.compileAndGetASTFileForDocumentURI(t.Context(), "file:///main.spx")
 	require.NoError(t, err)
 	require.False(t, result.hasErrorSeverityDiagnostic)
-	require.NotNil(t, astFile)
+	require.NotNil(t, result.astFile)
 
 	for _, tt := range []struct {
 		name           string
@@ -1405,11 +1900,11 @@ onStart => {
 		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
-			pos := PosAt(result.proj, astFile, tt.identPosition)
+			pos := PosAt(result.proj, result.astFile, tt.identPosition)
 			require.True(t, pos.IsValid())
 
 			var ident *ast.Ident
-			for node := range xgoutil.PathEnclosingIntervalNodes(astFile, pos, pos, false) {
+			for node := range xgoutil.PathEnclosingIntervalNodes(result.astFile, pos, pos, false) {
 				if node, ok := node.(*ast.Ident); ok {
 					ident = node
 					break
@@ -1417,7 +1912,7 @@ onStart => {
 			}
 			require.NotNil(t, ident)
 
-			got := createValueInputSlotFromIdent(result, ident, nil)
+			got := createValueInputSlotFromIdent(result.compileResult, ident, nil)
 			require.NotNil(t, got)
 			assert.Equal(t, SpxInputSlotKindValue, got.Kind)
 			assert.Equal(t, tt.wantInputType, got.Accept.Type)
@@ -1441,18 +1936,18 @@ onStart => {
 			"assets/index.json":                []byte(`{}`),
 			"assets/sounds/MySound/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		result, _, astFile, err := s.compileAndGetASTFileForDocumentURI("file:///main.spx")
+		result, err := s.compileAndGetASTFileForDocumentURI(t.Context(), "file:///main.spx")
 		require.NoError(t, err)
 		require.False(t, result.hasErrorSeverityDiagnostic)
-		require.NotNil(t, astFile)
+		require.NotNil(t, result.astFile)
 
-		pos := PosAt(result.proj, astFile, Position{Line: 4, Character: 7})
+		pos := PosAt(result.proj, result.astFile, Position{Line: 4, Character: 7})
 		require.True(t, pos.IsValid())
 
 		var ident *ast.Ident
-		for node := range xgoutil.PathEnclosingIntervalNodes(astFile, pos, pos, false) {
+		for node := range xgoutil.PathEnclosingIntervalNodes(result.astFile, pos, pos, false) {
 			if node, ok := node.(*ast.Ident); ok && node.Name == "mySound" {
 				ident = node
 				break
@@ -1463,7 +1958,7 @@ onStart => {
 		pkg := gotypes.NewPackage("example.com/pkg", "pkg")
 		declaredType := gotypes.NewAlias(gotypes.NewTypeName(0, pkg, "MySoundName", nil), GetSpxSoundNameType())
 
-		got := createValueInputSlotFromIdent(result, ident, declaredType)
+		got := createValueInputSlotFromIdent(result.compileResult, ident, declaredType)
 		require.NotNil(t, got)
 		assert.Equal(t, SpxInputTypeResourceName, got.Accept.Type)
 		assert.Equal(t, ToPtr(SpxSoundResourceContextURI), got.Accept.ResourceContext)
@@ -1495,12 +1990,12 @@ onStart => {
 `),
 		"assets/index.json": []byte(`{}`),
 	}
-	s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+	s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-	result, _, astFile, err := s.compileAndGetASTFileForDocumentURI("file:///main.spx")
+	result, err := s.compileAndGetASTFileForDocumentURI(t.Context(), "file:///main.spx")
 	require.NoError(t, err)
 	require.False(t, result.hasErrorSeverityDiagnostic)
-	require.NotNil(t, astFile)
+	require.NotNil(t, result.astFile)
 
 	for _, tt := range []struct {
 		name           string
@@ -1558,11 +2053,11 @@ onStart => {
 		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
-			pos := PosAt(result.proj, astFile, tt.exprPosition)
+			pos := PosAt(result.proj, result.astFile, tt.exprPosition)
 			require.True(t, pos.IsValid())
 
 			var unaryExpr *ast.UnaryExpr
-			for node := range xgoutil.PathEnclosingIntervalNodes(astFile, pos, pos, false) {
+			for node := range xgoutil.PathEnclosingIntervalNodes(result.astFile, pos, pos, false) {
 				if expr, ok := node.(*ast.UnaryExpr); ok {
 					unaryExpr = expr
 					break
@@ -1570,7 +2065,7 @@ onStart => {
 			}
 			require.NotNil(t, unaryExpr)
 
-			got := createValueInputSlotFromUnaryExpr(result, unaryExpr, nil)
+			got := createValueInputSlotFromUnaryExpr(result.compileResult, unaryExpr, nil)
 			require.NotNil(t, got)
 			assert.Equal(t, tt.wantKind, got.Kind)
 			assert.Equal(t, tt.wantAcceptType, got.Accept.Type)
@@ -1596,12 +2091,12 @@ onStart => {
 `),
 		"assets/index.json": []byte(`{}`),
 	}
-	s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+	s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-	result, _, astFile, err := s.compileAndGetASTFileForDocumentURI("file:///main.spx")
+	result, err := s.compileAndGetASTFileForDocumentURI(t.Context(), "file:///main.spx")
 	require.NoError(t, err)
 	require.False(t, result.hasErrorSeverityDiagnostic)
-	require.NotNil(t, astFile)
+	require.NotNil(t, result.astFile)
 
 	for _, tt := range []struct {
 		name             string
@@ -1632,11 +2127,11 @@ onStart => {
 		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
-			pos := PosAt(result.proj, astFile, tt.callExprPosition)
+			pos := PosAt(result.proj, result.astFile, tt.callExprPosition)
 			require.True(t, pos.IsValid())
 
 			var callExpr *ast.CallExpr
-			for node := range xgoutil.PathEnclosingIntervalNodes(astFile, pos, pos, false) {
+			for node := range xgoutil.PathEnclosingIntervalNodes(result.astFile, pos, pos, false) {
 				if node, ok := node.(*ast.CallExpr); ok {
 					callExpr = node
 					break
@@ -1644,7 +2139,7 @@ onStart => {
 			}
 			require.NotNil(t, callExpr)
 
-			got := createValueInputSlotFromColorFuncCall(result, callExpr, nil)
+			got := createValueInputSlotFromColorFuncCall(result.compileResult, callExpr, nil)
 			if tt.wantNil {
 				assert.Nil(t, got)
 			} else {
@@ -1674,7 +2169,7 @@ onStart => {
 				&ast.BasicLit{Kind: token.INT, Value: "2"},
 			},
 		}
-		got := createValueInputSlotFromColorFuncCall(result, callExpr, nil)
+		got := createValueInputSlotFromColorFuncCall(result.compileResult, callExpr, nil)
 		assert.Nil(t, got)
 	})
 
@@ -1683,7 +2178,7 @@ onStart => {
 			Fun:  &ast.Ident{Name: "unknownFunction"},
 			Args: []ast.Expr{&ast.BasicLit{Kind: token.INT, Value: "1"}},
 		}
-		got := createValueInputSlotFromColorFuncCall(result, callExpr, nil)
+		got := createValueInputSlotFromColorFuncCall(result.compileResult, callExpr, nil)
 		assert.Nil(t, got)
 	})
 }
@@ -1851,20 +2346,20 @@ onStart => {
 		"assets/index.json":                  []byte(`{}`),
 		"assets/sprites/MySprite/index.json": []byte(`{"costumes":[{"name":"costume1"}]}`),
 	}
-	s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+	s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 	t.Run("MainFile", func(t *testing.T) {
-		result, _, astFile, err := s.compileAndGetASTFileForDocumentURI("file:///main.spx")
+		result, err := s.compileAndGetASTFileForDocumentURI(t.Context(), "file:///main.spx")
 		require.NoError(t, err)
 		require.False(t, result.hasErrorSeverityDiagnostic)
-		require.NotNil(t, astFile)
+		require.NotNil(t, result.astFile)
 
 		// MySprite.setXYpos
-		pos := PosAt(result.proj, astFile, Position{Line: 2, Character: 11})
+		pos := PosAt(result.proj, result.astFile, Position{Line: 2, Character: 11})
 		require.True(t, pos.IsValid())
 
 		var callExpr *ast.CallExpr
-		for node := range xgoutil.PathEnclosingIntervalNodes(astFile, pos, pos, false) {
+		for node := range xgoutil.PathEnclosingIntervalNodes(result.astFile, pos, pos, false) {
 			if node, ok := node.(*ast.CallExpr); ok {
 				callExpr = node
 				break
@@ -1872,23 +2367,23 @@ onStart => {
 		}
 		require.NotNil(t, callExpr)
 
-		spxSpriteResource := inferSpxSpriteResourceEnclosingNode(result, callExpr)
+		spxSpriteResource := inferSpxSpriteResourceEnclosingNode(result.compileResult, callExpr)
 		require.NotNil(t, spxSpriteResource)
 		assert.Equal(t, "MySprite", spxSpriteResource.Name)
 	})
 
 	t.Run("SpriteFile", func(t *testing.T) {
-		result, _, astFile, err := s.compileAndGetASTFileForDocumentURI("file:///MySprite.spx")
+		result, err := s.compileAndGetASTFileForDocumentURI(t.Context(), "file:///MySprite.spx")
 		require.NoError(t, err)
 		require.False(t, result.hasErrorSeverityDiagnostic)
-		require.NotNil(t, astFile)
+		require.NotNil(t, result.astFile)
 
 		// setCostume
-		pos := PosAt(result.proj, astFile, Position{Line: 2, Character: 2})
+		pos := PosAt(result.proj, result.astFile, Position{Line: 2, Character: 2})
 		require.True(t, pos.IsValid())
 
 		var callExpr *ast.CallExpr
-		for node := range xgoutil.PathEnclosingIntervalNodes(astFile, pos, pos, false) {
+		for node := range xgoutil.PathEnclosingIntervalNodes(result.astFile, pos, pos, false) {
 			if node, ok := node.(*ast.CallExpr); ok {
 				callExpr = node
 				break
@@ -1896,23 +2391,23 @@ onStart => {
 		}
 		require.NotNil(t, callExpr)
 
-		spxSpriteResource := inferSpxSpriteResourceEnclosingNode(result, callExpr)
+		spxSpriteResource := inferSpxSpriteResourceEnclosingNode(result.compileResult, callExpr)
 		require.NotNil(t, spxSpriteResource)
 		assert.Equal(t, "MySprite", spxSpriteResource.Name)
 	})
 
 	t.Run("NonSpriteNode", func(t *testing.T) {
-		result, _, astFile, err := s.compileAndGetASTFileForDocumentURI("file:///main.spx")
+		result, err := s.compileAndGetASTFileForDocumentURI(t.Context(), "file:///main.spx")
 		require.NoError(t, err)
 		require.False(t, result.hasErrorSeverityDiagnostic)
-		require.NotNil(t, astFile)
+		require.NotNil(t, result.astFile)
 
 		// onStart
-		pos := PosAt(result.proj, astFile, Position{Line: 1, Character: 2})
+		pos := PosAt(result.proj, result.astFile, Position{Line: 1, Character: 2})
 		require.True(t, pos.IsValid())
 
 		var callExpr *ast.CallExpr
-		for node := range xgoutil.PathEnclosingIntervalNodes(astFile, pos, pos, false) {
+		for node := range xgoutil.PathEnclosingIntervalNodes(result.astFile, pos, pos, false) {
 			if node, ok := node.(*ast.CallExpr); ok {
 				callExpr = node
 				break
@@ -1920,7 +2415,7 @@ onStart => {
 		}
 		require.NotNil(t, callExpr)
 
-		spxSpriteResource := inferSpxSpriteResourceEnclosingNode(result, callExpr)
+		spxSpriteResource := inferSpxSpriteResourceEnclosingNode(result.compileResult, callExpr)
 		require.Nil(t, spxSpriteResource)
 	})
 }
@@ -2102,27 +2597,245 @@ func findInputSlot(inputSlots []SpxInputSlot, value any, name string, inputType
 			} else if kind == SpxInputKindPredefined && slot.Input.Name == name && slot.Input.Type == inputType {
 				return &slot
 			}
-		}
-	}
-	return nil
-}
-
-func findInputSlotByRange(inputSlots []SpxInputSlot, inputRange Range) *SpxInputSlot {
-	for i := range inputSlots {
-		if inputSlots[i].Range == inputRange {
-			return &inputSlots[i]
-		}
-	}
-	return nil
-}
-
-func findAddressInputSlot(inputSlots []SpxInputSlot, name string) *SpxInputSlot {
-	for _, slot := range inputSlots {
-		if slot.Kind == SpxInputSlotKindAddress && slot.Input.Name == name {
-			return &slot
-		}
+		}
+	}
+	return nil
+}
+
+func findInputSlotByRange(inputSlots []SpxInputSlot, inputRange Range) *SpxInputSlot {
+	for i := range inputSlots {
+		if inputSlots[i].Range == inputRange {
+			return &inputSlots[i]
+		}
+	}
+	return nil
+}
+
+func findAddressInputSlot(inputSlots []SpxInputSlot, name string) *SpxInputSlot {
+	for _, slot := range inputSlots {
+		if slot.Kind == SpxInputSlotKindAddress && slot.Input.Name == name {
+			return &slot
+		}
+	}
+	return nil
+}
+
+func TestServerXGoSetInputSlot(t *testing.T) {
+	newServer := func() *Server {
+		m := map[string][]byte{
+			"main.spx": []byte(`
+onStart => {
+	println 42
+	myColor := HSB(0, 0, 0)
+	MySprite.stepTo "OtherSprite"
+}
+`),
+			"MySprite.spx":                          []byte(``),
+			"OtherSprite.spx":                       []byte(``),
+			"assets/index.json":                     []byte(`{}`),
+			"assets/sprites/MySprite/index.json":    []byte(`{}`),
+			"assets/sprites/OtherSprite/index.json": []byte(`{}`),
+		}
+		return New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+	}
+	getInputSlots := func(t *testing.T, s *Server) []XGoInputSlot {
+		inputSlots, err := s.spxGetInputSlots(t.Context(), []XGoGetInputSlotsParams{{TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"}}})
+		require.NoError(t, err)
+		return inputSlots
+	}
+
+	t.Run("InPlaceInteger", func(t *testing.T) {
+		s := newServer()
+		slot := findInputSlot(getInputSlots(t, s), int64(42), "", XGoInputTypeInteger, XGoInputKindInPlace)
+		require.NotNil(t, slot)
+
+		arg, err := json.Marshal(XGoSetInputSlotParams{
+			TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+			Range:        slot.Range,
+			Input:        XGoInput{Kind: XGoInputKindInPlace, Type: XGoInputTypeInteger, Value: 99},
+		})
+		require.NoError(t, err)
+		for _, command := range []string{CommandXGoSetInputSlot, CommandSpxSetInputSlot} {
+			edit, err := s.workspaceExecuteCommand(t.Context(), &ExecuteCommandParams{
+				Command:   command,
+				Arguments: []json.RawMessage{arg},
+			})
+			require.NoError(t, err)
+			assert.Equal(t, &WorkspaceEdit{
+				Changes: map[DocumentURI][]TextEdit{
+					"file:///main.spx": {{Range: slot.Range, NewText: "99"}},
+				},
+			}, edit)
+		}
+	})
+
+	t.Run("InPlaceColor", func(t *testing.T) {
+		s := newServer()
+		var slot *XGoInputSlot
+		for _, inputSlot := range getInputSlots(t, s) {
+			if inputSlot.Input.Type == XGoInputTypeSpxColor {
+				slot = &inputSlot
+				break
+			}
+		}
+		require.NotNil(t, slot)
+
+		edit, err := s.xgoSetInputSlot(XGoSetInputSlotParams{
+			TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+			Range:        slot.Range,
+			Input: XGoInput{
+				Kind: XGoInputKindInPlace,
+				Type: XGoInputTypeSpxColor,
+				Value: XGoInputSpxColorValue{
+					Constructor: XGoInputTypeSpxColorConstructorHSB,
+					Args:        []float64{255, 0, 0},
+				},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, edit.Changes["file:///main.spx"], 1)
+		assert.Equal(t, "HSB(255, 0, 0)", edit.Changes["file:///main.spx"][0].NewText)
+	})
+
+	t.Run("Predefined", func(t *testing.T) {
+		s := newServer()
+		slot := findInputSlot(getInputSlots(t, s), XGoResourceURI("spx://resources/sprites/OtherSprite"), "", XGoInputTypeSpxResourceName, XGoInputKindInPlace)
+		require.NotNil(t, slot)
+
+		edit, err := s.xgoSetInputSlot(XGoSetInputSlotParams{
+			TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+			Range:        slot.Range,
+			Input:        XGoInput{Kind: XGoInputKindPredefined, Type: XGoInputTypeSpxResourceName, Name: "OtherSprite"},
+		})
+		require.NoError(t, err)
+		require.Len(t, edit.Changes["file:///main.spx"], 1)
+		assert.Equal(t, "OtherSprite", edit.Changes["file:///main.spx"][0].NewText)
+	})
+
+	t.Run("InvalidValue", func(t *testing.T) {
+		s := newServer()
+
+		edit, err := s.xgoSetInputSlot(XGoSetInputSlotParams{
+			TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+			Input:        XGoInput{Kind: XGoInputKindInPlace, Type: XGoInputTypeInteger, Value: "42"},
+		})
+		require.EqualError(t, err, `invalid value 42 for input type "integer"`)
+		assert.Nil(t, edit)
+	})
+
+	t.Run("NonSpxFile", func(t *testing.T) {
+		s := newServer()
+
+		edit, err := s.xgoSetInputSlot(XGoSetInputSlotParams{
+			TextDocument: TextDocumentIdentifier{URI: "file:///assets/index.json"},
+			Input:        XGoInput{Kind: XGoInputKindInPlace, Type: XGoInputTypeInteger, Value: 1},
+		})
+		require.Error(t, err)
+		assert.Nil(t, edit)
+	})
+}
+
+func TestFormatXGoInput(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		input   XGoInput
+		oldCode string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "String",
+			input: XGoInput{Kind: XGoInputKindInPlace, Type: XGoInputTypeString, Value: "say \"hi\""},
+			want:  `"say \"hi\""`,
+		},
+		{
+			name:  "IntegerFromJSON",
+			input: XGoInput{Kind: XGoInputKindInPlace, Type: XGoInputTypeInteger, Value: float64(-7)},
+			want:  "-7",
+		},
+		{
+			name:    "IntegerWithFraction",
+			input:   XGoInput{Kind: XGoInputKindInPlace, Type: XGoInputTypeInteger, Value: 1.5},
+			wantErr: true,
+		},
+		{
+			name:  "Decimal",
+			input: XGoInput{Kind: XGoInputKindInPlace, Type: XGoInputTypeDecimal, Value: 3.14},
+			want:  "3.14",
+		},
+		{
+			name:  "Boolean",
+			input: XGoInput{Kind: XGoInputKindInPlace, Type: XGoInputTypeBoolean, Value: false},
+			want:  "false",
+		},
+		{
+			name:  "Direction",
+			input: XGoInput{Kind: XGoInputKindInPlace, Type: XGoInputTypeSpxDirection, Value: float64(90)},
+			want:  "90",
+		},
+		{
+			name:  "Key",
+			input: XGoInput{Kind: XGoInputKindInPlace, Type: XGoInputTypeSpxKey, Value: "KeySpace"},
+			want:  "KeySpace",
+		},
+		{
+			name:  "ResourceName",
+			input: XGoInput{Kind: XGoInputKindInPlace, Type: XGoInputTypeSpxResourceName, Value: "spx://resources/sounds/ding"},
+			want:  `"ding"`,
+		},
+		{
+			name:  "SpriteInstance",
+			input: XGoInput{Kind: XGoInputKindInPlace, Type: XGoInputTypeSpxSpriteInstance, Value: "spx://resources/sprites/MySprite"},
+			want:  "MySprite",
+		},
+		{
+			name:  "ColorFromJSON",
+			input: XGoInput{Kind: XGoInputKindInPlace, Type: XGoInputTypeSpxColor, Value: map[string]any{"constructor": "HSBA", "args": []any{10.5, 20, 30, 40}}},
+			want:  "HSBA(10.5, 20, 30, 40)",
+		},
+		{
+			name:    "ColorWithWrongArgCount",
+			input:   XGoInput{Kind: XGoInputKindInPlace, Type: XGoInputTypeSpxColor, Value: XGoInputSpxColorValue{Constructor: XGoInputTypeSpxColorConstructorHSB, Args: []float64{1, 2}}},
+			wantErr: true,
+		},
+		{
+			name:    "Struct",
+			input:   XGoInput{Kind: XGoInputKindInPlace, Type: XGoInputTypeStruct, Value: map[string]any{"Y": float64(2), "X": 1.5, "Name": "p"}},
+			oldCode: "Point{X: 0}",
+			want:    `Point{Name: "p", X: 1.5, Y: 2}`,
+		},
+		{
+			name:    "StructWithInvalidFieldName",
+			input:   XGoInput{Kind: XGoInputKindInPlace, Type: XGoInputTypeStruct, Value: map[string]any{"X: 1, Y": float64(2)}},
+			oldCode: "Point{X: 0}",
+			wantErr: true,
+		},
+		{
+			name:  "Predefined",
+			input: XGoInput{Kind: XGoInputKindPredefined, Type: XGoInputTypeInteger, Name: "count"},
+			want:  "count",
+		},
+		{
+			name:    "InvalidPredefinedName",
+			input:   XGoInput{Kind: XGoInputKindPredefined, Type: XGoInputTypeInteger, Name: "1st"},
+			wantErr: true,
+		},
+		{
+			name:    "UnknownType",
+			input:   XGoInput{Kind: XGoInputKindInPlace, Type: XGoInputTypeUnknown, Value: 1},
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := formatXGoInput(tt.input, tt.oldCode)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
 	}
-	return nil
 }
 
 func TestServerXGoGetProperties(t *testing.T) {
@@ -2139,7 +2852,7 @@ func MainEntry() {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		params := XGoGetPropertiesParams{Target: "Game"}
 		properties, err := s.xgoGetProperties(params)
@@ -2181,7 +2894,7 @@ func onStart() {
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		params := XGoGetPropertiesParams{Target: "MySprite"}
 		properties, err := s.xgoGetProperties(params)
@@ -2219,7 +2932,7 @@ func MainEntry() {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		params := XGoGetPropertiesParams{Target: "Game"}
 		properties, err := s.xgoGetProperties(params)
@@ -2255,7 +2968,7 @@ func MainEntry() {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		params := XGoGetPropertiesParams{Target: "Game"}
 		properties, err := s.xgoGetProperties(params)
@@ -2280,7 +2993,7 @@ func MainEntry() {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		params := XGoGetPropertiesParams{Target: "NonExistent"}
 		properties, err := s.xgoGetProperties(params)
@@ -2300,7 +3013,7 @@ func MainEntry() {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		params := XGoGetPropertiesParams{Target: "MyInt"}
 		properties, err := s.xgoGetProperties(params)
@@ -2321,7 +3034,7 @@ func MainEntry() {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		params := XGoGetPropertiesParams{Target: "Game"}
 		properties, err := s.xgoGetProperties(params)
@@ -2361,7 +3074,7 @@ func MainEntry() {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		params := XGoGetPropertiesParams{Target: "Counter"}
 		properties, err := s.xgoGetProperties(params)
@@ -2402,7 +3115,7 @@ func onStart() {
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		params := XGoGetPropertiesParams{Target: "MySprite"}
 		properties, err := s.xgoGetProperties(params)
@@ -2429,6 +3142,433 @@ func findProperty(properties []XGoProperty, name string, kind XGoPropertyKind) *
 	return nil
 }
 
+func TestServerSpxGetSpriteInfo(t *testing.T) {
+	t.Run("Normal", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.spx": []byte(`
+var MySprite Sprite
+`),
+			"MySprite.spx": []byte(`
+func jump() {}
+
+onStart => {
+	jump
+}
+`),
+			"assets/index.json":                  []byte(`{}`),
+			"assets/sprites/MySprite/index.json": []byte(`{"costumes":[{"name":"costume1"},{"name":"costume2"}]}`),
+		}
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+		info, err := s.workspaceExecuteCommand(t.Context(), &ExecuteCommandParams{
+			Command:   CommandSpxGetSpriteInfo,
+			Arguments: []json.RawMessage{json.RawMessage(`{"sprite":"MySprite"}`)},
+		})
+		require.NoError(t, err)
+		require.IsType(t, &SpxSpriteInfo{}, info)
+		spriteInfo := info.(*SpxSpriteInfo)
+
+		assert.Equal(t, DocumentURI("file:///MySprite.spx"), spriteInfo.File)
+		assert.True(t, spriteInfo.AutoBinding)
+		require.NotNil(t, spriteInfo.Resource)
+		assert.Equal(t, "MySprite", spriteInfo.Resource.Name)
+		var costumeNames []string
+		for _, costume := range spriteInfo.Resource.Costumes {
+			costumeNames = append(costumeNames, costume.Name)
+		}
+		assert.Equal(t, []string{"costume1", "costume2"}, costumeNames)
+
+		var defNames []string
+		for _, def := range spriteInfo.Definitions {
+			defNames = append(defNames, *def.ID.Name)
+		}
+		assert.Contains(t, defNames, "MySprite.jump")
+		assert.Contains(t, defNames, "Sprite.move")
+		assert.Contains(t, defNames, "Sprite.turn")
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.spx":          []byte(`echo "hello"`),
+			"assets/index.json": []byte(`{}`),
+		}
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+		info, err := s.spxGetSpriteInfo(t.Context(), SpxGetSpriteInfoParams{Sprite: "MySprite"})
+		require.EqualError(t, err, `sprite "MySprite" not found`)
+		assert.Nil(t, info)
+	})
+
+	t.Run("InvalidArguments", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.spx":          []byte(`echo "hello"`),
+			"assets/index.json": []byte(`{}`),
+		}
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+		_, err := s.workspaceExecuteCommand(t.Context(), &ExecuteCommandParams{
+			Command: CommandSpxGetSpriteInfo,
+		})
+		require.EqualError(t, err, "expected exactly one argument for command spx.getSpriteInfo")
+	})
+}
+
+func TestServerSpxGetBackdropInfo(t *testing.T) {
+	t.Run("Normal", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.spx": []byte(`
+onStart => {
+	setBackdrop "forest"
+}
+`),
+			"assets/index.json":                  []byte(`{"backdrops":[{"name":"forest","path":"forest.png"}]}`),
+			"assets/backdrops/forest/index.json": []byte(`{}`),
+		}
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+		info, err := s.workspaceExecuteCommand(t.Context(), &ExecuteCommandParams{
+			Command:   CommandSpxGetBackdropInfo,
+			Arguments: []json.RawMessage{json.RawMessage(`{"backdrop":"forest"}`)},
+		})
+		require.NoError(t, err)
+		require.IsType(t, &SpxBackdropInfo{}, info)
+		backdropInfo := info.(*SpxBackdropInfo)
+
+		require.NotNil(t, backdropInfo.Resource)
+		assert.Equal(t, "forest", backdropInfo.Resource.Name)
+		assert.Equal(t, "forest.png", backdropInfo.Resource.Path)
+
+		var defNames []string
+		for _, def := range backdropInfo.Definitions {
+			defNames = append(defNames, *def.ID.Name)
+		}
+		assert.Contains(t, defNames, "Game.setBackdrop")
+		assert.Contains(t, defNames, "Game.setBackdropAndWait")
+		assert.NotContains(t, defNames, "Game.backdropName")
+		assert.NotContains(t, defNames, "Game.play")
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.spx":          []byte(`echo "hello"`),
+			"assets/index.json": []byte(`{}`),
+		}
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+		info, err := s.spxGetBackdropInfo(t.Context(), SpxGetBackdropInfoParams{Backdrop: "forest"})
+		require.EqualError(t, err, `backdrop "forest" not found`)
+		assert.Nil(t, info)
+	})
+}
+
+func TestServerSpxGetAnimationFrames(t *testing.T) {
+	newServer := func() *Server {
+		m := map[string][]byte{
+			"main.spx":          []byte(`MySprite.turn 90`),
+			"MySprite.spx":      []byte(`onStart => {}`),
+			"assets/index.json": []byte(`{}`),
+			"assets/sprites/MySprite/index.json": []byte(`{
+	"costumes": [
+		{"name": "costume1", "path": "costume1.png"},
+		{"name": "costume2", "path": "costume2.svg"},
+		{"name": "costume3", "path": "costume3.png"}
+	],
+	"fAnimations": {
+		"walk": {"frameFrom": "costume1", "frameTo": "costume2", "frameFps": 12},
+		"back": {"frameFrom": "costume3", "frameTo": "costume1", "frameFps": 12},
+		"jump": {"frameFrom": "costume1", "frameTo": "missing", "frameFps": 12}
+	}
+}`),
+			"assets/sprites/MySprite/costume1.png": newTestPNG(64, 48),
+			"assets/sprites/MySprite/costume2.svg": []byte(`<svg xmlns="http://www.w3.org/2000/svg" width="64" height="48"></svg>`),
+		}
+		return New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+	}
+
+	t.Run("Animation", func(t *testing.T) {
+		s := newServer()
+
+		frames, err := s.workspaceExecuteCommand(t.Context(), &ExecuteCommandParams{
+			Command:   CommandSpxGetAnimationFrames,
+			Arguments: []json.RawMessage{json.RawMessage(`{"sprite":"MySprite","animation":"walk"}`)},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []SpxAnimationFrame{
+			{Path: "assets/sprites/MySprite/costume1.png", Width: 64, Height: 48, FrameRate: 12},
+			{Path: "assets/sprites/MySprite/costume2.svg", Width: 64, Height: 48, FrameRate: 12},
+		}, frames)
+	})
+
+	t.Run("Costume", func(t *testing.T) {
+		s := newServer()
+
+		frames, err := s.spxGetAnimationFrames(t.Context(), SpxGetAnimationFramesParams{Sprite: "MySprite", Animation: "costume3"})
+		require.NoError(t, err)
+		assert.Equal(t, []SpxAnimationFrame{
+			{Path: "assets/sprites/MySprite/costume3.png"},
+		}, frames)
+	})
+
+	t.Run("ReversedFrames", func(t *testing.T) {
+		s := newServer()
+
+		frames, err := s.spxGetAnimationFrames(t.Context(), SpxGetAnimationFramesParams{Sprite: "MySprite", Animation: "back"})
+		require.EqualError(t, err, `animation "back" of sprite "MySprite" has no valid frames`)
+		assert.Nil(t, frames)
+	})
+
+	t.Run("MissingFrame", func(t *testing.T) {
+		s := newServer()
+
+		frames, err := s.spxGetAnimationFrames(t.Context(), SpxGetAnimationFramesParams{Sprite: "MySprite", Animation: "jump"})
+		require.EqualError(t, err, `animation "jump" of sprite "MySprite" has no valid frames`)
+		assert.Nil(t, frames)
+	})
+
+	t.Run("AnimationNotFound", func(t *testing.T) {
+		s := newServer()
+
+		frames, err := s.spxGetAnimationFrames(t.Context(), SpxGetAnimationFramesParams{Sprite: "MySprite", Animation: "run"})
+		require.EqualError(t, err, `animation "run" of sprite "MySprite" not found`)
+		assert.Nil(t, frames)
+	})
+
+	t.Run("SpriteNotFound", func(t *testing.T) {
+		s := newServer()
+
+		frames, err := s.spxGetAnimationFrames(t.Context(), SpxGetAnimationFramesParams{Sprite: "OtherSprite", Animation: "walk"})
+		require.EqualError(t, err, `sprite "OtherSprite" not found`)
+		assert.Nil(t, frames)
+	})
+}
+
+func TestServerSpxGetSoundInfo(t *testing.T) {
+	newServer := func() *Server {
+		m := map[string][]byte{
+			"main.spx":                         []byte(`play "ding"`),
+			"assets/index.json":                []byte(`{}`),
+			"assets/sounds/ding/index.json":    []byte(`{"path": "ding.wav"}`),
+			"assets/sounds/ding/ding.wav":      newTestWAV(22050, 1, 16, 11025),
+			"assets/sounds/missing/index.json": []byte(`{"path": "missing.mp3"}`),
+		}
+		return New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+	}
+
+	t.Run("WAV", func(t *testing.T) {
+		s := newServer()
+
+		info, err := s.workspaceExecuteCommand(t.Context(), &ExecuteCommandParams{
+			Command:   CommandSpxGetSoundInfo,
+			Arguments: []json.RawMessage{json.RawMessage(`{"sound":"ding"}`)},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, &SpxSoundInfo{
+			Path:       "assets/sounds/ding/ding.wav",
+			Format:     "wav",
+			Duration:   500,
+			SampleRate: 22050,
+			Channels:   1,
+		}, info)
+	})
+
+	t.Run("FileNotAccessible", func(t *testing.T) {
+		s := newServer()
+
+		info, err := s.spxGetSoundInfo(t.Context(), SpxGetSoundInfoParams{Sound: "missing"})
+		require.NoError(t, err)
+		assert.Equal(t, &SpxSoundInfo{
+			Path:   "assets/sounds/missing/missing.mp3",
+			Format: "mp3",
+		}, info)
+	})
+
+	t.Run("SoundNotFound", func(t *testing.T) {
+		s := newServer()
+
+		info, err := s.spxGetSoundInfo(t.Context(), SpxGetSoundInfoParams{Sound: "boom"})
+		require.EqualError(t, err, `sound "boom" not found`)
+		assert.Nil(t, info)
+	})
+}
+
+func TestServerSpxCheckCode(t *testing.T) {
+	newServer := func() *Server {
+		m := map[string][]byte{
+			"main.spx":          []byte(`echo "hello"`),
+			"assets/index.json": []byte(`{}`),
+		}
+		return New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		s := newServer()
+
+		diagnostics, err := s.workspaceExecuteCommand(t.Context(), &ExecuteCommandParams{
+			Command:   CommandSpxCheckCode,
+			Arguments: []json.RawMessage{json.RawMessage(`{"code":"var x int = 42"}`)},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []Diagnostic{}, diagnostics)
+	})
+
+	t.Run("ValidWithContext", func(t *testing.T) {
+		s := newServer()
+
+		diagnostics, err := s.spxCheckCode(SpxCheckCodeParams{
+			Code:    "onStart => {\n\techo strings.ToUpper(\"hi\")\n}",
+			Package: "main",
+			Imports: []string{"strings"},
+		})
+		require.NoError(t, err)
+		assert.Empty(t, diagnostics)
+	})
+
+	t.Run("ParseError", func(t *testing.T) {
+		s := newServer()
+
+		diagnostics, err := s.spxCheckCode(SpxCheckCodeParams{
+			Code:    "var x int = 42\nvar y int = ",
+			Package: "main",
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, diagnostics)
+		assert.Equal(t, SeverityError, diagnostics[0].Severity)
+		assert.Equal(t, uint32(1), diagnostics[0].Range.Start.Line)
+		assert.NotEmpty(t, diagnostics[0].Message)
+	})
+
+	t.Run("TypeErrorNotReported", func(t *testing.T) {
+		s := newServer()
+
+		diagnostics, err := s.spxCheckCode(SpxCheckCodeParams{Code: `var x int = "hello"`})
+		require.NoError(t, err)
+		assert.Empty(t, diagnostics)
+	})
+}
+
+func TestServerSpxGetProjectStructure(t *testing.T) {
+	m := map[string][]byte{
+		"main.spx": []byte(`
+var (
+	Hero Sprite
+)
+
+play "click"
+`),
+		"Hero.spx":          []byte(`onStart => {}`),
+		"assets/index.json": []byte(`{"backdrops":[{"name":"forest","path":"forest.png"}],"zorder":[{"name":"score","type":"monitor"},"Hero","Enemy"]}`),
+		"assets/sprites/Hero/index.json": []byte(`{
+	"costumes": [{"name": "c1", "path": "c1.png"}, {"name": "c2", "path": "c2.png"}],
+	"fAnimations": {"walk": {"frameFrom": "c1", "frameTo": "c2"}}
+}`),
+		"assets/sprites/Enemy/index.json": []byte(`{"costumes": [{"name": "c1", "path": "c1.png"}]}`),
+		"assets/sounds/click/index.json":  []byte(`{"path":"click.wav"}`),
+		"assets/sounds/boom/index.json":   []byte(`{"path":"boom.wav"}`),
+	}
+	s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+	structure, err := s.workspaceExecuteCommand(t.Context(), &ExecuteCommandParams{
+		Command: CommandSpxGetProjectStructure,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &SpxProjectStructure{
+		Game: SpxGameStructure{
+			File:          "file:///main.spx",
+			BackdropCount: 1,
+		},
+		Sprites: []SpxSpriteStructure{
+			{Name: "Enemy", CostumeCount: 1},
+			{Name: "Hero", File: "file:///Hero.spx", AutoBinding: true, CostumeCount: 2, AnimationCount: 1},
+		},
+		SoundCount: 2,
+		Widgets:    []string{"score"},
+	}, structure)
+
+	cached, err := s.spxGetProjectStructure()
+	require.NoError(t, err)
+	assert.Same(t, structure, cached)
+
+	m["assets/sounds/jump/index.json"] = []byte(`{"path":"jump.wav"}`)
+	updated, err := s.spxGetProjectStructure()
+	require.NoError(t, err)
+	assert.NotSame(t, structure, updated)
+	assert.Equal(t, 3, updated.SoundCount)
+}
+
+func TestServerSpxGetEventHandlerPositions(t *testing.T) {
+	t.Run("Normal", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.spx": []byte(`
+onStart => {
+	echo "start"
+}
+
+onClick => {}
+`),
+			"assets/index.json": []byte(`{}`),
+		}
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+		positions, err := s.workspaceExecuteCommand(t.Context(), &ExecuteCommandParams{
+			Command:   CommandSpxGetEventHandlerPositions,
+			Arguments: []json.RawMessage{json.RawMessage(`{"textDocument":{"uri":"file:///main.spx"}}`)},
+		})
+		require.NoError(t, err)
+		require.IsType(t, []SpxEventHandlerPosition{}, positions)
+		assert.Equal(t, []SpxEventHandlerPosition{
+			{
+				EventType: "onStart",
+				Definition: SpxDefinitionIdentifier{
+					Package: ToPtr(SpxPkgPath),
+					Name:    ToPtr("Game.onStart"),
+				},
+				Range: Range{
+					Start: Position{Line: 1, Character: 0},
+					End:   Position{Line: 3, Character: 1},
+				},
+			},
+			{
+				EventType: "onClick",
+				Definition: SpxDefinitionIdentifier{
+					Package: ToPtr(SpxPkgPath),
+					Name:    ToPtr("Game.onClick"),
+				},
+				Range: Range{
+					Start: Position{Line: 5, Character: 0},
+					End:   Position{Line: 5, Character: 13},
+				},
+			},
+		}, positions)
+	})
+
+	t.Run("NoEventHandlers", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.spx":          []byte(`echo "hello"`),
+			"assets/index.json": []byte(`{}`),
+		}
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+		positions, err := s.spxGetEventHandlerPositions(t.Context(), SpxGetEventHandlerPositionsParams{
+			TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+		})
+		require.NoError(t, err)
+		assert.Empty(t, positions)
+		assert.NotNil(t, positions)
+	})
+
+	t.Run("InvalidArguments", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.spx": []byte(`onStart => {}`),
+		}
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+		_, err := s.workspaceExecuteCommand(t.Context(), &ExecuteCommandParams{
+			Command: CommandSpxGetEventHandlerPositions,
+		})
+		require.EqualError(t, err, "expected exactly one argument for command spx.getEventHandlerPositions")
+	})
+}
+
 func TestIsPropertyOfEnclosingType(t *testing.T) {
 	t.Run("PropertyField", func(t *testing.T) {
 		m := map[string][]byte{
@@ -2453,9 +3593,9 @@ func onStart() {
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		result, err := s.compile()
+		result, err := s.compile(t.Context())
 		require.NoError(t, err)
 		require.False(t, result.hasErrorSeverityDiagnostic)
 
@@ -2526,9 +3666,9 @@ func onStart() {
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		result, err := s.compile()
+		result, err := s.compile(t.Context())
 		require.NoError(t, err)
 		require.False(t, result.hasErrorSeverityDiagnostic)
 
@@ -2610,9 +3750,9 @@ func onStart() {
 			"assets/sprites/MySprite/index.json":    []byte(`{}`),
 			"assets/sprites/OtherSprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		result, err := s.compile()
+		result, err := s.compile(t.Context())
 		require.NoError(t, err)
 		require.False(t, result.hasErrorSeverityDiagnostic)
 
@@ -2734,9 +3874,9 @@ func onStart() {
 			"assets/sprites/MySprite/index.json":    []byte(`{}`),
 			"assets/sprites/OtherSprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		result, err := s.compile()
+		result, err := s.compile(t.Context())
 		require.NoError(t, err)
 		require.False(t, result.hasErrorSeverityDiagnostic)
 
@@ -2847,9 +3987,9 @@ func onStart() {
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		result, err := s.compile()
+		result, err := s.compile(t.Context())
 		require.NoError(t, err)
 		require.False(t, result.hasErrorSeverityDiagnostic)
 
@@ -3083,3 +4223,133 @@ func onStart() {
 		assert.Nil(t, enclosingType, "Field with nil package should return nil (line 315)")
 	})
 }
+
+func predefinedNameStrings(names []SpxPredefinedName) []string {
+	strs := make([]string, 0, len(names))
+	for _, name := range names {
+		strs = append(strs, name.Name)
+	}
+	return strs
+}
+
+func TestCollectPredefinedNamesPriority(t *testing.T) {
+	m := map[string][]byte{
+		"main.spx": []byte(`
+onStart => {
+	localName := "beep"
+	count := 1
+	play "recording"
+	println localName, count
+}
+`),
+		"assets/index.json":                  []byte(`{}`),
+		"assets/sounds/recording/index.json": []byte(`{}`),
+	}
+	s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+	result, err := s.compileAndGetASTFileForDocumentURI(t.Context(), "file:///main.spx")
+	require.NoError(t, err)
+	require.False(t, result.hasErrorSeverityDiagnostic)
+
+	slot := findInputSlot(
+		findInputSlots(result.compileResult, result.astFile),
+		SpxResourceURI("spx://resources/sounds/recording"),
+		"",
+		SpxInputTypeResourceName,
+		SpxInputKindInPlace,
+	)
+	require.NotNil(t, slot)
+
+	names := predefinedNameStrings(slot.PredefinedNames)
+	recordingIndex := slices.Index(names, "recording")
+	localNameIndex := slices.Index(names, "localName")
+	countIndex := slices.Index(names, "count")
+	require.NotEqual(t, -1, recordingIndex)
+	require.NotEqual(t, -1, localNameIndex)
+	require.NotEqual(t, -1, countIndex)
+	assert.Less(t, recordingIndex, localNameIndex)
+	assert.Less(t, localNameIndex, countIndex)
+
+	assert.Equal(t, predefinedNamePriorityResource, slot.PredefinedNames[recordingIndex].Priority)
+	assert.Equal(t, predefinedNamePriorityMatching, slot.PredefinedNames[localNameIndex].Priority)
+	assert.Equal(t, predefinedNamePriorityOther, slot.PredefinedNames[countIndex].Priority)
+	assert.True(t, slices.IsSortedFunc(slot.PredefinedNames, func(a, b SpxPredefinedName) int {
+		return b.Priority - a.Priority
+	}))
+}
+
+func TestServerSpxGetDefinitionAt(t *testing.T) {
+	m := map[string][]byte{
+		"main.spx": []byte(`
+onStart => {
+	MySprite.show
+}
+`),
+		"MySprite.spx": []byte(`
+onStart => {
+	play "recording"
+	turn Left
+	dir := heading
+	echo dir
+}
+`),
+		"assets/index.json":                  []byte(`{}`),
+		"assets/sprites/MySprite/index.json": []byte(`{}`),
+		"assets/sounds/recording/index.json": []byte(`{}`),
+	}
+	s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+	for _, tt := range []struct {
+		name     string
+		position Position
+		wantName string
+	}{
+		{
+			name:     "CallFunc",
+			position: Position{Line: 2, Character: 2},
+			wantName: "Sprite.play",
+		},
+		{
+			name:     "ResourceRef",
+			position: Position{Line: 2, Character: 8},
+			wantName: "SoundName",
+		},
+		{
+			name:     "CallArgIdent",
+			position: Position{Line: 3, Character: 7},
+			wantName: "Sprite.turn",
+		},
+		{
+			name:     "Ident",
+			position: Position{Line: 4, Character: 2},
+			wantName: "dir",
+		},
+		{
+			name:     "NoNode",
+			position: Position{Line: 7, Character: 0},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			arg, err := json.Marshal(TextDocumentPositionParams{
+				TextDocument: TextDocumentIdentifier{URI: "file:///MySprite.spx"},
+				Position:     tt.position,
+			})
+			require.NoError(t, err)
+
+			got, err := s.workspaceExecuteCommand(t.Context(), &ExecuteCommandParams{
+				Command:   CommandSpxGetDefinitionAt,
+				Arguments: []json.RawMessage{arg},
+			})
+			require.NoError(t, err)
+			def, ok := got.(*SpxDefinition)
+			require.True(t, ok)
+			if tt.wantName == "" {
+				assert.Nil(t, def)
+				return
+			}
+			require.NotNil(t, def)
+			require.NotNil(t, def.ID.Name)
+			assert.Equal(t, tt.wantName, *def.ID.Name)
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package server
 
 import (
+	"cmp"
+	"context"
 	"fmt"
 	gotypes "go/types"
 	"iter"
@@ -14,13 +16,13 @@ import (
 	"github.com/goplus/xgo/ast"
 	"github.com/goplus/xgo/scanner"
 	"github.com/goplus/xgo/token"
-	"github.com/goplus/xgo/x/typesutil"
 	"github.com/goplus/xgolsw/internal/analysis/ast/inspector"
 	"github.com/goplus/xgolsw/internal/analysis/passes/inspect"
 	"github.com/goplus/xgolsw/internal/analysis/protocol"
 	"github.com/goplus/xgolsw/internal/pkgdata"
 	"github.com/goplus/xgolsw/pkgdoc"
 	"github.com/goplus/xgolsw/xgo"
+	"github.com/goplus/xgolsw/xgo/types"
 	"github.com/goplus/xgolsw/xgo/xgoutil"
 	"github.com/qiniu/x/errors"
 )
@@ -239,8 +241,7 @@ func (r *compileResult) spxResourceRefAtPosition(position token.Position) *SpxRe
 	)
 	fset := r.proj.Fset
 	for _, ref := range r.spxResourceRefs {
-		nodePos := fset.Position(ref.Node.Pos())
-		nodeEnd := fset.Position(ref.Node.End())
+		nodePos, nodeEnd := xgoutil.TokenRange(fset, ref.Node)
 		if nodePos.Filename != position.Filename ||
 			position.Line != nodePos.Line ||
 			position.Column < nodePos.Column ||
@@ -261,8 +262,7 @@ func (r *compileResult) spxResourceRefAtPosition(position token.Position) *SpxRe
 func (r *compileResult) spxImportsAtASTFilePosition(astFile *ast.File, position token.Position) *SpxReferencePkg {
 	fset := r.proj.Fset
 	for _, imp := range astFile.Imports {
-		nodePos := fset.Position(imp.Pos())
-		nodeEnd := fset.Position(imp.End())
+		nodePos, nodeEnd := xgoutil.TokenRange(fset, imp)
 		if nodePos.Filename != position.Filename ||
 			position.Line != nodePos.Line ||
 			position.Column < nodePos.Column ||
@@ -342,23 +342,25 @@ func (r *compileResult) addDiagnostics(documentURI DocumentURI, diags ...Diagnos
 
 // compile compiles spx source files and returns compile result. It uses cached
 // result if available.
-func (s *Server) compile() (*compileResult, error) {
+func (s *Server) compile(ctx context.Context) (*compileResult, error) {
 	// NOTE(xsw): don't create a snapshot
-	snapshot := s.workspaceRootFS // .Snapshot()
+	snapshot := s.getProj() // .Snapshot()
 
 	// TODO(wyvern): remove this once we have a better way to update files.
-	snapshot.UpdateFiles(s.fileMapGetter())
-	return s.compileAt(snapshot)
+	snapshot.UpdateFiles(s.getFileMap())
+	return s.compileAt(ctx, snapshot)
 }
 
 // compileAt compiles spx source files at the given snapshot and returns the
 // compile result.
-func (s *Server) compileAt(snapshot *xgo.Project) (*compileResult, error) {
+//
+// It returns the cause of ctx as soon as ctx is done between compilation steps.
+// A step that has already started, e.g., type checking the project, is not
+// interrupted, and its result is still cached in the snapshot for later use.
+func (s *Server) compileAt(ctx context.Context, snapshot *xgo.Project) (*compileResult, error) {
 	var spxFiles []string
-	for file := range snapshot.Files() {
-		if path.Ext(file) == ".spx" {
-			spxFiles = append(spxFiles, file)
-		}
+	for file := range snapshot.SpxFiles() {
+		spxFiles = append(spxFiles, file)
 	}
 	if len(spxFiles) == 0 {
 		return nil, errNoMainSpxFile
@@ -366,6 +368,10 @@ func (s *Server) compileAt(snapshot *xgo.Project) (*compileResult, error) {
 
 	result := newCompileResult(snapshot)
 	for _, spxFile := range spxFiles {
+		if ctx.Err() != nil {
+			return nil, context.Cause(ctx)
+		}
+
 		documentURI := s.toDocumentURI(spxFile)
 		result.diagnostics[documentURI] = []Diagnostic{}
 
@@ -422,42 +428,49 @@ func (s *Server) compileAt(snapshot *xgo.Project) (*compileResult, error) {
 		return result, nil
 	}
 
-	handleErr := func(err error) {
-		if typeErr, ok := err.(typesutil.Error); ok {
-			if !typeErr.Pos.IsValid() {
-				panic(fmt.Sprintf("unexpected nopos error: %s", typeErr.Msg))
-			}
-			position := typeErr.Fset.Position(typeErr.Pos)
-			documentURI := s.toDocumentURI(position.Filename)
-			result.addDiagnostics(documentURI, Diagnostic{
-				Severity: SeverityError,
-				Range:    RangeForPosEnd(result.proj, typeErr.Pos, typeErr.End),
-				Message:  typeErr.Msg,
-			})
+	if ctx.Err() != nil {
+		return nil, context.Cause(ctx)
+	}
+	var typeInfo *types.Info
+	typeCheck := func() {
+		typeInfo, _ = snapshot.TypeInfo()
+	}
+	if snapshot.HasTypeInfo() {
+		typeCheck()
+	} else {
+		// Only report progress when the project is actually type checked.
+		s.withProgress(typeCheckProgressTitle, typeCheck)
+	}
+	var typeDiags []xgo.Diagnostic
+	if typeInfo != nil {
+		var err error
+		typeDiags, err = snapshot.Diagnostics()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get type checking diagnostics: %w", err)
 		}
 	}
-
-	typeInfo, err := snapshot.TypeInfo()
-	if err != nil {
-		switch err := err.(type) {
-		case errors.List:
-			for _, e := range err {
-				handleErr(e)
-			}
-		default:
-			handleErr(err)
+	for _, typeDiag := range typeDiags {
+		if !typeDiag.Pos.IsValid() {
+			panic(fmt.Sprintf("unexpected nopos error: %s", typeDiag.Msg))
 		}
+		position := result.proj.Fset.Position(typeDiag.Pos)
+		documentURI := s.toDocumentURI(position.Filename)
+		result.addDiagnostics(documentURI, Diagnostic{
+			Severity: SeverityError,
+			Range:    RangeForPosEnd(result.proj, typeDiag.Pos, typeDiag.End),
+			Message:  typeDiag.Msg,
+		})
+	}
+	if ctx.Err() != nil {
+		return nil, context.Cause(ctx)
 	}
 	pkg := typeInfo.Pkg
 
-	for file := range snapshot.Files() {
+	for file := range snapshot.SpxFiles() {
 		if file == "main.spx" {
 			// Skip the main.spx file, as it is not a sprite file.
 			continue
 		}
-		if path.Ext(file) != ".spx" {
-			continue
-		}
 
 		spriteName := strings.TrimSuffix(path.Base(file), ".spx")
 		obj := pkg.Scope().Lookup(spriteName)
@@ -469,37 +482,107 @@ func (s *Server) compileAt(snapshot *xgo.Project) (*compileResult, error) {
 		}
 	}
 
-	s.inspectForSpxResourceSet(snapshot, result)
-	s.inspectForSpxResourceRefs(result)
-	s.inspectDiagnosticsAnalyzers(result)
-
+	for _, inspect := range []func(){
+		func() { s.inspectForSpxResourceSet(snapshot, result) },
+		func() { s.inspectForSpxResourceRefs(result) },
+		func() { s.inspectForShadowedSpxSprites(result) },
+		func() { s.inspectDiagnosticsAnalyzers(result) },
+	} {
+		if ctx.Err() != nil {
+			return nil, context.Cause(ctx)
+		}
+		inspect()
+	}
 	return result, nil
 }
 
+// documentCompileResult is the [compileResult] of the project together with
+// the spx file of a document and its AST.
+type documentCompileResult struct {
+	*compileResult
+
+	// spxFile is the path of the spx file of the document.
+	spxFile string
+
+	// astFile is the AST of spxFile. It may be nil even if the compilation
+	// succeeded.
+	astFile *ast.File
+}
+
+// IsUsable reports whether r can be used to serve requests for the document,
+// i.e., the compilation did not fail and the AST of the document is available.
+func (r *documentCompileResult) IsUsable() bool {
+	return r != nil && r.compileResult != nil && r.astFile != nil
+}
+
 // compileAndGetASTFileForDocumentURI handles common compilation and file
-// retrieval logic for a given document URI. The returned astFile is probably
-// nil even if the compilation succeeded.
-func (s *Server) compileAndGetASTFileForDocumentURI(uri DocumentURI) (result *compileResult, spxFile string, astFile *ast.File, err error) {
-	spxFile, err = s.fromDocumentURI(uri)
+// retrieval logic for a given document URI. The astFile of the returned
+// result is probably nil even if the compilation succeeded, which can be
+// checked with [documentCompileResult.IsUsable].
+func (s *Server) compileAndGetASTFileForDocumentURI(ctx context.Context, uri DocumentURI) (*documentCompileResult, error) {
+	spxFile, err := s.spxFileForDocumentURI(uri)
 	if err != nil {
-		return nil, "", nil, fmt.Errorf("failed to get file path from document URI %q: %w", uri, err)
+		return nil, err
 	}
-	if path.Ext(spxFile) != ".spx" {
-		return nil, "", nil, fmt.Errorf("file %q does not have .spx extension", spxFile)
+	result, err := s.compile(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile: %w", err)
 	}
-	result, err = s.compile()
+	return newDocumentCompileResult(result, spxFile), nil
+}
+
+// spxFileForDocumentURI returns the path of the spx file of the given document
+// URI.
+func (s *Server) spxFileForDocumentURI(uri DocumentURI) (string, error) {
+	spxFile, err := s.fromDocumentURI(uri)
 	if err != nil {
-		return nil, "", nil, fmt.Errorf("failed to compile: %w", err)
+		return "", fmt.Errorf("failed to get file path from document URI %q: %w", uri, err)
+	}
+	if path.Ext(spxFile) != ".spx" {
+		return "", fmt.Errorf("file %q does not have .spx extension", spxFile)
+	}
+	return spxFile, nil
+}
+
+// newDocumentCompileResult creates a [documentCompileResult] for spxFile from
+// the given [compileResult].
+func newDocumentCompileResult(result *compileResult, spxFile string) *documentCompileResult {
+	docResult := &documentCompileResult{
+		compileResult: result,
+		spxFile:       spxFile,
 	}
 	if astPkg, _ := result.proj.ASTPackage(); astPkg != nil {
-		astFile = astPkg.Files[spxFile]
+		docResult.astFile = astPkg.Files[spxFile]
 	}
-	return
+	return docResult
+}
+
+// spxResourceCacheKind is the cache kind for [SpxResourceSet].
+type spxResourceCacheKind struct{}
+
+// buildSpxResourceCache implements [xgo.CacheBuilder] to build the
+// [SpxResourceSet] of the given project.
+func buildSpxResourceCache(proj *xgo.Project) (any, error) {
+	return NewSpxResourceSet(proj)
+}
+
+// getSpxResourceSet returns the spx resource set of the given project. The
+// set is cached in the project until any asset file changes if
+// [xgo.FeatSpxResourceCache] is enabled.
+func getSpxResourceSet(proj *xgo.Project) (*SpxResourceSet, error) {
+	if proj.Features()&xgo.FeatSpxResourceCache == 0 {
+		return NewSpxResourceSet(proj)
+	}
+	spxResourceSet, err := proj.Cache(spxResourceCacheKind{})
+	if err != nil {
+		return nil, err
+	}
+	return spxResourceSet.(*SpxResourceSet), nil
 }
 
 // inspectForSpxResourceSet inspects for spx resource set in main.spx.
 func (s *Server) inspectForSpxResourceSet(snapshot *xgo.Project, result *compileResult) {
-	spxResourceSet, err := NewSpxResourceSet(snapshot)
+	spxResourceSet, err := getSpxResourceSet(snapshot)
 	if err != nil {
 		documentURI := s.toDocumentURI(result.mainSpxFile)
 		result.addDiagnostics(documentURI, Diagnostic{
@@ -969,6 +1052,53 @@ func (s *Server) inspectSpxResourceRefForTypeAtExpr(result *compileResult, expr
 	}
 }
 
+// shadowSpriteDiagnosticCode is the diagnostic code for local variables that
+// shadow spx sprite auto-bindings.
+const shadowSpriteDiagnosticCode = "shadow-sprite"
+
+// inspectForShadowedSpxSprites inspects for local variables that shadow spx
+// sprite auto-bindings, e.g., `var MySprite int` in an event handler, which
+// makes the sprite inaccessible in the enclosing scope.
+func (s *Server) inspectForShadowedSpxSprites(result *compileResult) {
+	if len(result.spxSpriteResourceAutoBindings) == 0 {
+		return
+	}
+	typeInfo, _ := result.proj.TypeInfo()
+	if typeInfo == nil || typeInfo.Pkg == nil {
+		return
+	}
+
+	spriteNames := make(map[string]struct{}, len(result.spxSpriteResourceAutoBindings))
+	for obj := range result.spxSpriteResourceAutoBindings {
+		spriteNames[obj.Name()] = struct{}{}
+	}
+
+	var shadowingIdents []*ast.Ident
+	for ident, obj := range typeInfo.Defs {
+		if ident == nil || !ident.Pos().IsValid() || ident.Implicit() {
+			continue
+		}
+		v, ok := obj.(*gotypes.Var)
+		if !ok || v.IsField() || v.Parent() == nil || v.Parent() == typeInfo.Pkg.Scope() {
+			continue
+		}
+		if _, ok := spriteNames[v.Name()]; ok {
+			shadowingIdents = append(shadowingIdents, ident)
+		}
+	}
+	slices.SortFunc(shadowingIdents, func(a, b *ast.Ident) int {
+		return cmp.Compare(a.Pos(), b.Pos())
+	})
+	for _, ident := range shadowingIdents {
+		result.addDiagnostics(s.nodeDocumentURI(result.proj, ident), Diagnostic{
+			Severity: SeverityWarning,
+			Range:    RangeForNode(result.proj, ident),
+			Code:     shadowSpriteDiagnosticCode,
+			Message:  s.translate(fmt.Sprintf("variable '%s' shadows sprite auto-binding", ident.Name)),
+		})
+	}
+}
+
 // addEmptySpxResourceNameDiagnostic adds a diagnostic for empty spx resource name.
 func (s *Server) addEmptySpxResourceNameDiagnostic(result *compileResult, expr ast.Expr, resourceType string) {
 	result.addDiagnostics(s.nodeDocumentURI(result.proj, expr), Diagnostic{
@@ -2,6 +2,7 @@ package server
 
 import (
 	"cmp"
+	"context"
 	"fmt"
 	gotypes "go/types"
 	"iter"
@@ -9,6 +10,7 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"unicode"
 
 	"github.com/goplus/xgo/ast"
@@ -22,50 +24,63 @@ import (
 )
 
 // See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.18/specification/#textDocument_completion
-func (s *Server) textDocumentCompletion(params *CompletionParams) (any, error) {
-	result, spxFile, astFile, err := s.compileAndGetASTFileForDocumentURI(params.TextDocument.URI)
+func (s *Server) textDocumentCompletion(ctx context.Context, params *CompletionParams) (any, error) {
+	result, err := s.compileAndGetASTFileForDocumentURI(ctx, params.TextDocument.URI)
 	if err != nil {
 		return nil, err
 	}
-	if astFile == nil {
+	if !result.IsUsable() {
 		return nil, nil
 	}
-	if !astFile.Pos().IsValid() {
+	if !result.astFile.Pos().IsValid() {
 		return nil, nil
 	}
 
-	pos := PosAt(result.proj, astFile, params.Position)
+	pos := PosAt(result.proj, result.astFile, params.Position)
 	if !pos.IsValid() {
 		return nil, nil
 	}
+
+	// NOTE: The type info may be partial if the project has type errors. We
+	// still use it so that completion keeps working while the user is in
+	// the middle of editing, at the cost of some expressions having no type.
 	typeInfo, _ := result.proj.TypeInfo()
 	if typeInfo == nil {
 		return nil, nil
 	}
+	astFileScope := typeInfo.Scopes[result.astFile]
 
 	astPkg, _ := result.proj.ASTPackage()
 	innermostScope := xgoutil.InnermostScopeAt(result.proj.Fset, typeInfo, astPkg, pos)
 	if innermostScope == nil {
-		return nil, nil
+		// The type checker may have given up before recording the scopes
+		// enclosing pos. Fall back to the widest scope still available.
+		innermostScope = astFileScope
+		if innermostScope == nil && typeInfo.Pkg != nil {
+			innermostScope = typeInfo.Pkg.Scope()
+		}
+		if innermostScope == nil {
+			return nil, nil
+		}
 	}
-	ctx := &completionContext{
+	completionCtx := &completionContext{
 		itemSet:        newCompletionItemSet(),
 		proj:           result.proj,
 		typeInfo:       typeInfo,
-		result:         result,
-		spxFile:        spxFile,
-		astFile:        astFile,
-		astFileScope:   typeInfo.Scopes[astFile],
-		tokenFile:      xgoutil.NodeTokenFile(result.proj.Fset, astFile),
+		result:         result.compileResult,
+		spxFile:        result.spxFile,
+		astFile:        result.astFile,
+		astFileScope:   astFileScope,
+		tokenFile:      xgoutil.NodeTokenFile(result.proj.Fset, result.astFile),
 		pos:            pos,
 		innermostScope: innermostScope,
 	}
-	ctx.analyze()
-	if err := ctx.collect(); err != nil {
+	completionCtx.analyze()
+	if err := completionCtx.collect(); err != nil {
 		return nil, fmt.Errorf("failed to collect completion items: %w", err)
 	}
-	items := ctx.sortedItems()
-	if ctx.isIncomplete {
+	items := completionCtx.sortedItems()
+	if completionCtx.isIncomplete {
 		return CompletionList{
 			IsIncomplete: true,
 			Items:        items,
@@ -92,6 +107,7 @@ const (
 	completionKindStructLit
 	completionKindSwitchCase
 	completionKindSelect
+	completionKindBuiltinTypeArg
 )
 
 // completionContext represents the context for completion operations.
@@ -119,7 +135,9 @@ type completionContext struct {
 	assignTargets      []*ast.Ident
 	declValueSpec      *ast.ValueSpec
 	switchTag          ast.Expr
+	typeSwitch         bool
 	returnIndex        int
+	builtinTypeArgFunc string
 
 	inStringLit             bool
 	inCallKwargName         bool
@@ -155,6 +173,12 @@ func (ctx *completionContext) analyze() {
 			if ctx.enclosingCallExpr == nil {
 				ctx.enclosingCallExpr = node
 			}
+			if name, ok := ctx.builtinTypeArgFuncName(node); ok {
+				ctx.kind = completionKindBuiltinTypeArg
+				ctx.enclosingNode = node
+				ctx.builtinTypeArgFunc = name
+				continue
+			}
 			if typ := ctx.typeInfo.TypeOf(node.Fun); !xgoutil.IsValidType(typ) {
 				continue
 			}
@@ -194,10 +218,53 @@ func (ctx *completionContext) analyze() {
 			// Skip FuncLit, as we want general completion inside function literals
 			// to allow access to all variables and identifiers.
 			continue
+		case *ast.LambdaExpr:
+			if ctx.pos <= node.Rarrow {
+				continue
+			}
+			sig := ctx.lambdaSignature(node, path[i+1:])
+			if sig == nil {
+				continue
+			}
+
+			// The results of a single-expression lambda, e.g., `x => x * 2`,
+			// are returned implicitly, so they are expected to match the
+			// results of the lambda's signature.
+			resultIndex := len(node.Rhs)
+			for j, rhs := range node.Rhs {
+				if ctx.pos <= rhs.End() {
+					resultIndex = j
+					break
+				}
+			}
+			ctx.kind = completionKindGeneral
+			ctx.enclosingNode = nil
+			ctx.valueExpression = true
+			ctx.expectedTypes = nil
+			if resultIndex < sig.Results().Len() {
+				ctx.expectedTypes = []gotypes.Type{sig.Results().At(resultIndex).Type()}
+			}
 		case *ast.SliceLit:
-			// Skip SliceLit, as XGo-style slice literals should allow general completion
-			// to access all variables and identifiers.
-			continue
+			// XGo-style slice literals allow general completion to access all
+			// variables and identifiers. When passed to a call, e.g.,
+			// `printInts [1, 2, ]`, elements are still expected to match the
+			// element type of the parameter.
+			if ctx.pos <= node.Lbrack || (node.Rbrack.IsValid() && ctx.pos > node.Rbrack) || i+1 >= len(path) {
+				continue
+			}
+			callExpr, ok := path[i+1].(*ast.CallExpr)
+			if !ok {
+				continue
+			}
+			elemType := ctx.sliceLitCallArgElemType(callExpr, node)
+			if elemType == nil {
+				continue
+			}
+			if ctx.kind == completionKindUnknown && !ctx.isAfterNumberLiteral() {
+				ctx.kind = completionKindGeneral
+			}
+			ctx.valueExpression = true
+			ctx.expectedTypes = []gotypes.Type{elemType}
 		case *ast.CompositeLit:
 			typ := ctx.typeInfo.TypeOf(node)
 			if !xgoutil.IsValidType(typ) {
@@ -298,8 +365,11 @@ func (ctx *completionContext) analyze() {
 						resultVars := make([]*gotypes.Var, 0, len(node.Lhs))
 						hasAllTypes := true
 						for _, lhsExpr := range node.Lhs {
-							typ := ctx.typeInfo.TypeOf(lhsExpr)
-							if !xgoutil.IsValidType(typ) {
+							var typ gotypes.Type
+							if ident, ok := lhsExpr.(*ast.Ident); ok && ident.Name == "_" {
+								// Blank identifiers accept values of any type.
+								typ = gotypes.Typ[gotypes.Invalid]
+							} else if typ = ctx.typeInfo.TypeOf(lhsExpr); !xgoutil.IsValidType(typ) {
 								hasAllTypes = false
 								break
 							}
@@ -313,6 +383,55 @@ func (ctx *completionContext) analyze() {
 					break
 				}
 			}
+		case *ast.TypeAssertExpr:
+			if node.Type == nil || ctx.pos < node.Type.Pos() || ctx.pos > node.Type.End() {
+				continue
+			}
+			// The asserted type is not a value, so the expectations of
+			// enclosing nodes (e.g., `_, ok := i.(T)`) do not apply to it.
+			ctx.kind = completionKindUnknown
+			ctx.valueExpression = false
+			ctx.expectedTypes = nil
+			ctx.expectedFuncResultCount = 0
+		case *ast.IndexExpr:
+			if ctx.pos <= node.Lbrack || (node.Rbrack.IsValid() && ctx.pos > node.Rbrack) {
+				continue
+			}
+			typ := ctx.typeInfo.TypeOf(node.X)
+			if !xgoutil.IsValidType(typ) {
+				continue
+			}
+			mapType, ok := typ.Underlying().(*gotypes.Map)
+			if !ok {
+				continue
+			}
+			ctx.kind = completionKindGeneral
+			ctx.valueExpression = true
+			ctx.expectedTypes = []gotypes.Type{mapType.Key()}
+		case *ast.SendStmt:
+			if ctx.pos <= node.Arrow {
+				continue
+			}
+			ctx.kind = completionKindAssignOrDefine
+			ctx.valueExpression = true
+			ctx.expectedTypes = nil
+			if typ := ctx.typeInfo.TypeOf(node.Chan); xgoutil.IsValidType(typ) {
+				if chanType, ok := typ.Underlying().(*gotypes.Chan); ok {
+					ctx.expectedTypes = []gotypes.Type{chanType.Elem()}
+				}
+			}
+		case *ast.UnaryExpr:
+			if node.Op != token.ARROW || ctx.pos <= node.OpPos {
+				continue
+			}
+
+			// The operand of a receive expression must be a channel whose
+			// element type matches the type expected for the expression.
+			chanTypes := make([]gotypes.Type, 0, len(ctx.expectedTypes))
+			for _, expectedType := range ctx.expectedTypes {
+				chanTypes = append(chanTypes, gotypes.NewChan(gotypes.RecvOnly, expectedType))
+			}
+			ctx.expectedTypes = chanTypes
 		case *ast.ReturnStmt:
 			sig := ctx.enclosingFunction(path[i+1:])
 			if sig == nil {
@@ -429,6 +548,18 @@ func (ctx *completionContext) analyze() {
 		case *ast.SwitchStmt:
 			ctx.kind = completionKindSwitchCase
 			ctx.switchTag = node.Tag
+		case *ast.CaseClause:
+			if node.Colon.IsValid() && ctx.pos > node.Colon {
+				continue
+			}
+			// Within the types of a type switch case, e.g., `case |:`.
+			if i+2 < len(path) {
+				if typeSwitchStmt, ok := path[i+2].(*ast.TypeSwitchStmt); ok {
+					ctx.kind = completionKindSwitchCase
+					ctx.switchTag = typeSwitchAssertedExpr(typeSwitchStmt)
+					ctx.typeSwitch = true
+				}
+			}
 		case *ast.SelectStmt:
 			ctx.kind = completionKindSelect
 		case *ast.DeclStmt:
@@ -451,6 +582,9 @@ func (ctx *completionContext) analyze() {
 				}
 			}
 		case *ast.BasicLit:
+			// Both interpreted and raw (backtick) string literals are scanned
+			// as token.STRING, so resource names completed inside either of
+			// them are inserted without extra quotes.
 			if node.Kind == token.STRING {
 				if ctx.kind == completionKindUnknown {
 					ctx.kind = completionKindStringLit
@@ -461,6 +595,17 @@ func (ctx *completionContext) analyze() {
 			ctx.kind = completionKindUnknown
 		}
 	}
+	if ctx.kind == completionKindUnknown && !ctx.isInComment() {
+		if callExpr := ctx.noParenCallExprBeforePos(); callExpr != nil {
+			// In XGo, funcs can be called without parentheses, e.g.,
+			// `play "sound"`. Since `play ` has no argument yet, there is no
+			// call expression in the AST, so we synthesize one.
+			ctx.kind = completionKindCall
+			ctx.enclosingNode = callExpr
+			ctx.enclosingCallExpr = callExpr
+			ctx.valueExpression = true
+		}
+	}
 	if ctx.kind == completionKindUnknown {
 		switch {
 		case ctx.isInComment():
@@ -501,6 +646,40 @@ func (ctx *completionContext) callArgKeepsCallContext(arg ast.Expr) bool {
 	return true
 }
 
+// sliceLitCallArgElemType returns the element type of the slice parameter
+// that receives sliceLit as an argument of callExpr, or nil if it cannot be
+// determined.
+func (ctx *completionContext) sliceLitCallArgElemType(callExpr *ast.CallExpr, sliceLit *ast.SliceLit) gotypes.Type {
+	resolvedArg, ok := ctx.getCurrentResolvedCallArg(callExpr)
+	if !ok || resolvedArg.Arg != sliceLit || !xgoutil.IsValidType(resolvedArg.ExpectedType) {
+		return nil
+	}
+	slice, ok := resolvedArg.ExpectedType.Underlying().(*gotypes.Slice)
+	if !ok {
+		return nil
+	}
+	return slice.Elem()
+}
+
+// builtinTypeArgFuncName returns the name of the builtin new or make function
+// called by callExpr if the current position is at its type argument.
+func (ctx *completionContext) builtinTypeArgFuncName(callExpr *ast.CallExpr) (string, bool) {
+	ident, ok := callExpr.Fun.(*ast.Ident)
+	if !ok || (ident.Name != "new" && ident.Name != "make") {
+		return "", false
+	}
+	if _, ok := ctx.typeInfo.ObjectOf(ident).(*gotypes.Builtin); !ok {
+		return "", false
+	}
+	if ctx.pos <= callExpr.Lparen {
+		return "", false
+	}
+	if len(callExpr.Args) > 0 && ctx.pos > callExpr.Args[0].End() {
+		return "", false
+	}
+	return ident.Name, true
+}
+
 // isInDisabledIdentifierContext reports whether the completion position is
 // inside an identifier context where completion should be suppressed.
 func (ctx *completionContext) isInDisabledIdentifierContext(path []ast.Node) bool {
@@ -623,6 +802,47 @@ func (ctx *completionContext) isLineStart() bool {
 	return true
 }
 
+// noParenCallExprBeforePos returns a synthesized call expression without
+// arguments if the text before the position on the current line is a known
+// function name followed by whitespace, like `play |`. It returns nil
+// otherwise.
+func (ctx *completionContext) noParenCallExprBeforePos() *ast.CallExpr {
+	fileBase := token.Pos(ctx.tokenFile.Base())
+	relPos := ctx.pos - fileBase
+	if relPos < 0 || int(relPos) > len(ctx.astFile.Code) {
+		return nil
+	}
+	lineStartPos := ctx.tokenFile.LineStart(ctx.tokenFile.Line(ctx.pos))
+	relLineStartPos := lineStartPos - fileBase
+	if relLineStartPos < 0 || relLineStartPos > relPos {
+		return nil
+	}
+
+	lineText := string(ctx.astFile.Code[relLineStartPos:relPos])
+	name := strings.TrimLeftFunc(lineText, unicode.IsSpace)
+	trimmedName := strings.TrimRightFunc(name, unicode.IsSpace)
+	if trimmedName == name || !token.IsIdentifier(trimmedName) {
+		return nil
+	}
+
+	namePos := lineStartPos + token.Pos(len(lineText)-len(name))
+	path, _ := xgoutil.PathEnclosingInterval(ctx.astFile, namePos, namePos+token.Pos(len(trimmedName)))
+	if len(path) == 0 {
+		return nil
+	}
+	ident, ok := path[0].(*ast.Ident)
+	if !ok || ident.Name != trimmedName || ident.Pos() != namePos {
+		return nil
+	}
+	if _, ok := ctx.typeInfo.ObjectOf(ident).(*gotypes.Func); !ok {
+		return nil
+	}
+	return &ast.CallExpr{
+		Fun:        ident,
+		NoParenEnd: ctx.pos,
+	}
+}
+
 // isInIdentifier reports whether the position is within an identifier.
 func (ctx *completionContext) isInIdentifier() bool {
 	fileBase := token.Pos(ctx.tokenFile.Base())
@@ -918,6 +1138,34 @@ func (ctx *completionContext) enclosingFunction(path []ast.Node) *gotypes.Signat
 	return nil
 }
 
+// lambdaSignature returns the signature of the given lambda expression. The
+// path must start with the parent of the lambda expression. It falls back to
+// the type of the parameter receiving the lambda expression if the lambda
+// expression has no type info.
+func (ctx *completionContext) lambdaSignature(lambda *ast.LambdaExpr, path []ast.Node) *gotypes.Signature {
+	if typ := ctx.typeInfo.TypeOf(lambda); xgoutil.IsValidType(typ) {
+		if sig, ok := typ.Underlying().(*gotypes.Signature); ok {
+			return sig
+		}
+	}
+	if len(path) == 0 {
+		return nil
+	}
+	callExpr, ok := path[0].(*ast.CallExpr)
+	if !ok {
+		return nil
+	}
+	for arg := range xgoutil.ResolvedCallExprArgs(ctx.typeInfo, callExpr) {
+		if arg.Arg != lambda || !xgoutil.IsValidType(arg.ExpectedType) {
+			continue
+		}
+		if sig, ok := arg.ExpectedType.Underlying().(*gotypes.Signature); ok {
+			return sig
+		}
+	}
+	return nil
+}
+
 // findReturnValueIndex finds the index of the return value at the current position.
 func (ctx *completionContext) findReturnValueIndex(ret *ast.ReturnStmt) int {
 	if len(ret.Results) == 0 {
@@ -962,6 +1210,8 @@ func (ctx *completionContext) collect() error {
 		return ctx.collectSwitchCase()
 	case completionKindSelect:
 		return ctx.collectSelect()
+	case completionKindBuiltinTypeArg:
+		return ctx.collectBuiltinTypeArg()
 	}
 	return nil
 }
@@ -1570,6 +1820,10 @@ func (ctx *completionContext) collectTypeSpecific(typ gotypes.Type) error {
 		}
 	}
 
+	if chanType, ok := typ.Underlying().(*gotypes.Chan); ok {
+		ctx.collectChanMakeSnippets(chanType)
+	}
+
 	// Handle spx.PropertyName type - provide property name completions.
 	if inferSpxInputTypeFromType(typ) == SpxInputTypePropertyName {
 		if target := ctx.getPropertyTarget(); target != "" {
@@ -1616,8 +1870,12 @@ func (ctx *completionContext) collectTypeSpecific(typ gotypes.Type) error {
 			spxResourceIDs = append(spxResourceIDs, SpxSoundResourceID{spxSoundName})
 		}
 	case GetSpxWidgetNameType():
+		expectedSpxWidgetType := ctx.getSpxWidgetType()
 		spxResourceIDs = slices.Grow(spxResourceIDs, len(ctx.result.spxResourceSet.widgets))
-		for spxWidgetName := range ctx.result.spxResourceSet.widgets {
+		for spxWidgetName, spxWidget := range ctx.result.spxResourceSet.widgets {
+			if expectedSpxWidgetType != nil && !strings.EqualFold(spxWidget.Type, expectedSpxWidgetType.Obj().Name()) {
+				continue
+			}
 			spxResourceIDs = append(spxResourceIDs, SpxWidgetResourceID{spxWidgetName})
 		}
 	}
@@ -1642,6 +1900,51 @@ func (ctx *completionContext) collectTypeSpecific(typ gotypes.Type) error {
 	return nil
 }
 
+// collectChanMakeSnippets collects snippets that make a channel assignable to
+// the given expected channel type, e.g., `make(chan int, ${1:buffer})`. For a
+// directional channel type, a snippet making a channel of the same direction
+// is collected as well, e.g., `make(chan<- int)`.
+func (ctx *completionContext) collectChanMakeSnippets(chanType *gotypes.Chan) {
+	elem := gotypes.TypeString(chanType.Elem(), func(p *gotypes.Package) string {
+		if p == GetSpxPkg() || xgoutil.IsMainPkg(p) {
+			return ""
+		}
+		return p.Name()
+	})
+
+	bidiChan := "chan " + elem
+	ctx.itemSet.add(
+		CompletionItem{
+			Label:            "make(" + bidiChan + ")",
+			Kind:             SnippetCompletion,
+			InsertText:       "make(" + bidiChan + ")",
+			InsertTextFormat: ToPtr(PlainTextTextFormat),
+		},
+		CompletionItem{
+			Label:            "make(" + bidiChan + ", buffer)",
+			Kind:             SnippetCompletion,
+			InsertText:       "make(" + bidiChan + ", ${1:buffer})",
+			InsertTextFormat: ToPtr(SnippetTextFormat),
+		},
+	)
+
+	var dirChan string
+	switch chanType.Dir() {
+	case gotypes.SendOnly:
+		dirChan = "chan<- " + elem
+	case gotypes.RecvOnly:
+		dirChan = "<-chan " + elem
+	default:
+		return
+	}
+	ctx.itemSet.add(CompletionItem{
+		Label:            "make(" + dirChan + ")",
+		Kind:             SnippetCompletion,
+		InsertText:       "make(" + dirChan + ")",
+		InsertTextFormat: ToPtr(PlainTextTextFormat),
+	})
+}
+
 // collectXGoUnitCompletions collects unit suffix completions for number literals.
 func (ctx *completionContext) collectXGoUnitCompletions(expectedTypes []gotypes.Type) bool {
 	completionRange, filterPrefix, ok := ctx.currentXGoUnitCompletionRange()
@@ -1716,6 +2019,40 @@ func (ctx *completionContext) getSpxSpriteResource() *SpxSpriteResource {
 	return ctx.getCurrentFileSpxSpriteResource()
 }
 
+// getSpxWidgetType returns the concrete widget type passed as a type argument
+// to the enclosing call expression, such as [spx.Monitor] in
+// `getWidget Monitor, "name"`. It returns nil if there is no such type
+// argument or it is not a concrete [spx.Widget] implementation.
+func (ctx *completionContext) getSpxWidgetType() *gotypes.Named {
+	callExpr := ctx.getEnclosingCallExpr()
+	if callExpr == nil {
+		return nil
+	}
+	for _, arg := range callExpr.Args {
+		var typ gotypes.Type
+		if tv, ok := ctx.typeInfo.Types[arg]; ok && tv.IsType() {
+			typ = tv.Type
+		} else if ident, ok := arg.(*ast.Ident); ok {
+			if typeName, ok := ctx.typeInfo.ObjectOf(ident).(*gotypes.TypeName); ok {
+				typ = typeName.Type()
+			}
+		}
+		if typ == nil {
+			continue
+		}
+		named, ok := typ.(*gotypes.Named)
+		if !ok || gotypes.IsInterface(named) {
+			return nil
+		}
+		widgetIface := GetSpxWidgetType().Underlying().(*gotypes.Interface)
+		if !gotypes.Implements(gotypes.NewPointer(named), widgetIface) {
+			return nil
+		}
+		return named
+	}
+	return nil
+}
+
 // getEnclosingCallExpr returns the closest call expression in the current
 // completion context.
 func (ctx *completionContext) getEnclosingCallExpr() *ast.CallExpr {
@@ -1820,6 +2157,9 @@ func (ctx *completionContext) collectStructLit() error {
 
 	// Collect already used fields.
 	if composite, ok := ctx.enclosingNode.(*ast.CompositeLit); ok {
+		if len(composite.Elts) == 0 {
+			ctx.collectStructLitSnippet()
+		}
 		for _, elem := range composite.Elts {
 			if kv, ok := elem.(*ast.KeyValueExpr); ok {
 				if ident, ok := kv.Key.(*ast.Ident); ok {
@@ -1847,8 +2187,61 @@ func (ctx *completionContext) collectStructLit() error {
 	return nil
 }
 
+// collectStructLitSnippet collects a snippet that initializes all exported
+// fields of the expected struct type at once, e.g., `${1:X}: ${2:}, ${3:Y}: ${4:}`.
+// It is meant for empty struct literals and complements the per-field
+// completions.
+func (ctx *completionContext) collectStructLitSnippet() {
+	var (
+		fieldNames []string
+		snippet    strings.Builder
+	)
+	for field := range ctx.expectedStructType.Fields() {
+		if !xgoutil.IsExportedOrInMainPkg(field) {
+			continue
+		}
+		if len(fieldNames) > 0 {
+			snippet.WriteString(", ")
+		}
+		fieldNames = append(fieldNames, field.Name())
+		fmt.Fprintf(&snippet, "${%d:%s}: ${%d:}", 2*len(fieldNames)-1, field.Name(), 2*len(fieldNames))
+	}
+	if len(fieldNames) < 2 {
+		return
+	}
+
+	ctx.itemSet.add(CompletionItem{
+		Label:            strings.Join(fieldNames, ", "),
+		Kind:             SnippetCompletion,
+		Detail:           "Initialize all fields of " + ctx.compositeLitType.Obj().Name(),
+		InsertText:       snippet.String(),
+		InsertTextFormat: ToPtr(SnippetTextFormat),
+	})
+}
+
+// typeSwitchAssertedExpr returns the expression x of the type assertion
+// `x.(type)` in the given type switch statement, or nil if not found.
+func typeSwitchAssertedExpr(stmt *ast.TypeSwitchStmt) ast.Expr {
+	var expr ast.Expr
+	switch assign := stmt.Assign.(type) {
+	case *ast.ExprStmt:
+		expr = assign.X
+	case *ast.AssignStmt:
+		if len(assign.Rhs) == 1 {
+			expr = assign.Rhs[0]
+		}
+	}
+	if typeAssertExpr, ok := expr.(*ast.TypeAssertExpr); ok {
+		return typeAssertExpr.X
+	}
+	return nil
+}
+
 // collectSwitchCase collects switch/case completions.
 func (ctx *completionContext) collectSwitchCase() error {
+	if ctx.typeSwitch {
+		return ctx.collectTypeSwitchCase()
+	}
 	if ctx.switchTag == nil {
 		for _, name := range []string{"int", "string", "bool", "error"} {
 			if obj := gotypes.Universe.Lookup(name); obj != nil {
@@ -1894,6 +2287,52 @@ func (ctx *completionContext) collectSwitchCase() error {
 	return nil
 }
 
+// collectTypeSwitchCase collects type switch case completions, i.e., the
+// non-interface named types in scope that implement the interface type of the
+// type-asserted expression. A type whose pointer type is the only one that
+// implements the interface is suggested as the pointer type.
+func (ctx *completionContext) collectTypeSwitchCase() error {
+	if ctx.switchTag == nil {
+		return nil
+	}
+	typ := ctx.typeInfo.TypeOf(ctx.switchTag)
+	if !xgoutil.IsValidType(typ) {
+		return nil
+	}
+	iface, ok := typ.Underlying().(*gotypes.Interface)
+	if !ok {
+		return nil
+	}
+
+	for scope := ctx.innermostScope; scope != nil; scope = scope.Parent() {
+		for _, name := range scope.Names() {
+			typeName, ok := scope.Lookup(name).(*gotypes.TypeName)
+			if !ok || typeName.IsAlias() || !xgoutil.IsExportedOrInMainPkg(typeName) {
+				continue
+			}
+			named, ok := typeName.Type().(*gotypes.Named)
+			if !ok || named.TypeParams().Len() > 0 || gotypes.IsInterface(named) {
+				continue
+			}
+			defs := ctx.result.spxDefinitionsFor(typeName, "")
+			if !gotypes.Implements(named, iface) {
+				if !gotypes.Implements(gotypes.NewPointer(named), iface) {
+					continue
+				}
+
+				// Only the pointer type implements the interface, so
+				// the value type would never match the case.
+				for i := range defs {
+					defs[i].CompletionItemLabel = "*" + defs[i].CompletionItemLabel
+					defs[i].CompletionItemInsertText = "*" + defs[i].CompletionItemInsertText
+				}
+			}
+			ctx.itemSet.addSpxDefs(defs...)
+		}
+	}
+	return nil
+}
+
 // collectSelect collects select statement completions.
 func (ctx *completionContext) collectSelect() error {
 	ctx.itemSet.add(
@@ -1913,27 +2352,179 @@ func (ctx *completionContext) collectSelect() error {
 	return nil
 }
 
-// completionItemKindPriority is the priority order for different completion
-// item kinds.
-var completionItemKindPriority = map[CompletionItemKind]int{
-	VariableCompletion:  1,
-	FieldCompletion:     2,
-	PropertyCompletion:  3,
-	MethodCompletion:    4,
-	FunctionCompletion:  5,
-	ConstantCompletion:  6,
-	UnitCompletion:      7,
-	ClassCompletion:     8,
-	InterfaceCompletion: 9,
-	ModuleCompletion:    10,
-	KeywordCompletion:   11,
+// makeTypeArgElemTypeNames are the element type names used to build the
+// composite type suggestions for the type argument of the builtin make
+// function.
+var makeTypeArgElemTypeNames = []string{"int", "string", "float64", "bool"}
+
+// collectBuiltinTypeArg collects type completions for the type argument of the
+// builtin new and make functions. For new, named struct and interface types are
+// suggested. For make, only map, slice, and channel types are suggested.
+func (ctx *completionContext) collectBuiltinTypeArg() error {
+	isMake := ctx.builtinTypeArgFunc == "make"
+	addTypeName := func(typeName *gotypes.TypeName, defs []SpxDefinition) {
+		switch typeName.Type().Underlying().(type) {
+		case *gotypes.Struct, *gotypes.Interface:
+			if isMake {
+				return
+			}
+		case *gotypes.Slice:
+			if !isMake {
+				return
+			}
+			for i := range defs {
+				defs[i].CompletionItemInsertText = typeName.Name() + ", ${1:len}"
+				defs[i].CompletionItemInsertTextFormat = SnippetTextFormat
+			}
+		case *gotypes.Map, *gotypes.Chan:
+			if !isMake {
+				return
+			}
+		default:
+			return
+		}
+		ctx.itemSet.addSpxDefs(defs...)
+	}
+
+	for scope := ctx.innermostScope; scope != nil; scope = scope.Parent() {
+		for _, name := range scope.Names() {
+			typeName, ok := scope.Lookup(name).(*gotypes.TypeName)
+			if !ok || typeName.IsAlias() || !xgoutil.IsExportedOrInMainPkg(typeName) {
+				continue
+			}
+			addTypeName(typeName, ctx.result.spxDefinitionsFor(typeName, ""))
+		}
+	}
+
+	spxPkg := GetSpxPkg()
+	spxPkgDoc, _ := pkgdata.GetPkgDoc(SpxPkgPath)
+	for _, name := range spxPkg.Scope().Names() {
+		typeName, ok := spxPkg.Scope().Lookup(name).(*gotypes.TypeName)
+		if !ok || typeName.IsAlias() || !typeName.Exported() {
+			continue
+		}
+		addTypeName(typeName, []SpxDefinition{GetSpxDefinitionForType(typeName, spxPkgDoc)})
+	}
+
+	if isMake {
+		for _, elem := range makeTypeArgElemTypeNames {
+			ctx.itemSet.add(
+				CompletionItem{
+					Label:            "[]" + elem,
+					Kind:             ClassCompletion,
+					InsertText:       "[]" + elem + ", ${1:len}",
+					InsertTextFormat: ToPtr(SnippetTextFormat),
+				},
+				CompletionItem{
+					Label:            "map[string]" + elem,
+					Kind:             ClassCompletion,
+					InsertText:       "map[string]" + elem,
+					InsertTextFormat: ToPtr(PlainTextTextFormat),
+				},
+				CompletionItem{
+					Label:            "chan " + elem,
+					Kind:             ClassCompletion,
+					InsertText:       "chan " + elem,
+					InsertTextFormat: ToPtr(PlainTextTextFormat),
+				},
+			)
+		}
+	}
+	return nil
+}
+
+// CompletionSortConfig holds the per-kind weights used to sort completion
+// items. Items whose kind has a lower weight are sorted first, and items with
+// the same weight are sorted by label. Kinds without a field in the config,
+// e.g., snippets, have a weight of 0.
+type CompletionSortConfig struct {
+	Variable  int `json:"variable"`
+	Field     int `json:"field"`
+	Property  int `json:"property"`
+	Method    int `json:"method"`
+	Function  int `json:"function"`
+	Constant  int `json:"constant"`
+	Unit      int `json:"unit"`
+	Class     int `json:"class"`
+	Interface int `json:"interface"`
+	Module    int `json:"module"`
+	Keyword   int `json:"keyword"`
+}
+
+// DefaultCompletionSortConfig returns the default [CompletionSortConfig].
+func DefaultCompletionSortConfig() CompletionSortConfig {
+	return CompletionSortConfig{
+		Variable:  1,
+		Field:     2,
+		Property:  3,
+		Method:    4,
+		Function:  5,
+		Constant:  6,
+		Unit:      7,
+		Class:     8,
+		Interface: 9,
+		Module:    10,
+		Keyword:   11,
+	}
+}
+
+// weight returns the sort weight for the given completion item kind.
+func (cfg CompletionSortConfig) weight(kind CompletionItemKind) int {
+	switch kind {
+	case VariableCompletion:
+		return cfg.Variable
+	case FieldCompletion:
+		return cfg.Field
+	case PropertyCompletion:
+		return cfg.Property
+	case MethodCompletion:
+		return cfg.Method
+	case FunctionCompletion:
+		return cfg.Function
+	case ConstantCompletion:
+		return cfg.Constant
+	case UnitCompletion:
+		return cfg.Unit
+	case ClassCompletion:
+		return cfg.Class
+	case InterfaceCompletion:
+		return cfg.Interface
+	case ModuleCompletion:
+		return cfg.Module
+	case KeywordCompletion:
+		return cfg.Keyword
+	}
+	return 0
 }
 
-// sortedItems returns the sorted items.
+// completionSortConfig holds the [CompletionSortConfig] used by all
+// completion requests. A nil value means [DefaultCompletionSortConfig].
+var completionSortConfig atomic.Pointer[CompletionSortConfig]
+
+// SetCompletionSortConfig sets the [CompletionSortConfig] used by all
+// subsequent completion requests. Every weight is taken from cfg, so callers
+// that only change some weights should start from
+// [DefaultCompletionSortConfig]. It is safe to call concurrently with
+// completion requests.
+func SetCompletionSortConfig(cfg CompletionSortConfig) {
+	completionSortConfig.Store(&cfg)
+}
+
+// sortedItems returns the items sorted with the current [CompletionSortConfig].
 func (ctx *completionContext) sortedItems() []CompletionItem {
+	cfg := completionSortConfig.Load()
+	if cfg == nil {
+		return ctx.sortedItemsWithConfig(DefaultCompletionSortConfig())
+	}
+	return ctx.sortedItemsWithConfig(*cfg)
+}
+
+// sortedItemsWithConfig returns the items sorted with the given
+// [CompletionSortConfig].
+func (ctx *completionContext) sortedItemsWithConfig(cfg CompletionSortConfig) []CompletionItem {
 	slices.SortStableFunc(ctx.itemSet.items, func(a, b CompletionItem) int {
-		if p1, p2 := completionItemKindPriority[a.Kind], completionItemKindPriority[b.Kind]; p1 != p2 {
-			return p1 - p2
+		if w1, w2 := cfg.weight(a.Kind), cfg.weight(b.Kind); w1 != w2 {
+			return w1 - w2
 		}
 		return cmp.Compare(a.Label, b.Label)
 	})
@@ -1949,13 +2540,24 @@ type completionItemSet struct {
 	disallowVoidFuncs             bool
 	expectedFuncResultCount       int
 	expectedTypes                 []gotypes.Type
+
+	// overloadItems maps overload group keys (see [overloadGroupKey]) to the
+	// added overload items that later overloads may be collapsed into.
+	overloadItems map[string][]overloadItem
+}
+
+// overloadItem is a completion item added for an XGo overload.
+type overloadItem struct {
+	index  int
+	spxDef SpxDefinition
 }
 
 // newCompletionItemSet creates a new [completionItemSet].
 func newCompletionItemSet() *completionItemSet {
 	return &completionItemSet{
-		items:       []CompletionItem{},
-		seenSpxDefs: make(map[string]struct{}),
+		items:         []CompletionItem{},
+		seenSpxDefs:   make(map[string]struct{}),
+		overloadItems: make(map[string][]overloadItem),
 	}
 }
 
@@ -2062,6 +2664,112 @@ func (s *completionItemSet) addSpxDefs(spxDefs ...SpxDefinition) {
 		}
 		s.seenSpxDefs[spxDefIDKey] = struct{}{}
 
+		if s.collapseOptionalBoolOverload(spxDef) {
+			continue
+		}
+
+		n := len(s.items)
 		s.add(spxDef.CompletionItem())
+		if len(s.items) > n && spxDef.ID.OverloadID != nil {
+			key := overloadGroupKey(spxDef.ID)
+			s.overloadItems[key] = append(s.overloadItems[key], overloadItem{index: n, spxDef: spxDef})
+		}
+	}
+}
+
+// collapseOptionalBoolOverload collapses spxDef into a previously added
+// overload of the same function if the two overloads differ only by a trailing
+// bool parameter, like `play(name)` and `play(name, loop)`. The collapsed item
+// inserts the shorter overload with the trailing bool argument as an optional
+// snippet section. It reports whether spxDef has been collapsed.
+func (s *completionItemSet) collapseOptionalBoolOverload(spxDef SpxDefinition) bool {
+	if spxDef.ID.OverloadID == nil {
+		return false
 	}
+	sig, ok := spxDef.TypeHint.(*gotypes.Signature)
+	if !ok {
+		return false
+	}
+
+	key := overloadGroupKey(spxDef.ID)
+	for i, prev := range s.overloadItems[key] {
+		prevSig, ok := prev.spxDef.TypeHint.(*gotypes.Signature)
+		if !ok {
+			continue
+		}
+
+		var short SpxDefinition
+		switch {
+		case hasExtraTrailingBoolParam(prevSig, sig):
+			short = prev.spxDef
+		case hasExtraTrailingBoolParam(sig, prevSig):
+			short = spxDef
+		default:
+			continue
+		}
+		s.items[prev.index] = collapsedOverloadCompletionItem(short)
+
+		// A collapsed item represents two overloads and must not absorb more.
+		s.overloadItems[key] = slices.Delete(s.overloadItems[key], i, i+1)
+		return true
+	}
+	return false
+}
+
+// overloadGroupKey returns the key shared by all overloads of the function
+// identified by id.
+func overloadGroupKey(id SpxDefinitionIdentifier) string {
+	id.OverloadID = nil
+	return id.String()
+}
+
+// hasExtraTrailingBoolParam reports whether long has the same parameters as
+// short plus one trailing bool parameter.
+func hasExtraTrailingBoolParam(short, long *gotypes.Signature) bool {
+	if short.Variadic() || long.Variadic() {
+		return false
+	}
+	shortParams, longParams := short.Params(), long.Params()
+	if longParams.Len() != shortParams.Len()+1 {
+		return false
+	}
+	for i := range shortParams.Len() {
+		if !gotypes.Identical(shortParams.At(i).Type(), longParams.At(i).Type()) {
+			return false
+		}
+	}
+	last, ok := longParams.At(longParams.Len() - 1).Type().Underlying().(*gotypes.Basic)
+	return ok && last.Kind() == gotypes.Bool
+}
+
+// collapsedOverloadCompletionItem returns the completion item for an overload
+// that is collapsed with its counterpart taking an extra trailing bool
+// parameter. The item inserts a snippet with placeholders for the parameters
+// of short and an optional section for the trailing bool argument.
+func collapsedOverloadCompletionItem(short SpxDefinition) CompletionItem {
+	params := short.TypeHint.(*gotypes.Signature).Params()
+
+	var sb strings.Builder
+	sb.WriteString(short.CompletionItemInsertText)
+	for i := range params.Len() {
+		if i == 0 {
+			sb.WriteString(" ")
+		} else {
+			sb.WriteString(", ")
+		}
+		placeholder := params.At(i).Name()
+		if placeholder == "" {
+			placeholder = GetSimplifiedTypeString(params.At(i).Type())
+		}
+		fmt.Fprintf(&sb, "${%d:%s}", i+1, placeholder)
+	}
+	sep := ", "
+	if params.Len() == 0 {
+		sep = " "
+	}
+	fmt.Fprintf(&sb, "${%d:%s${%d:true}}", params.Len()+1, sep, params.Len()+2)
+
+	short.CompletionItemInsertText = sb.String()
+	short.CompletionItemInsertTextFormat = SnippetTextFormat
+	return short.CompletionItem()
 }
@@ -3,11 +3,13 @@ package server
 import (
 	gotypes "go/types"
 	"slices"
+	"sync"
 	"testing"
 
 	"github.com/goplus/xgo/ast"
 	"github.com/goplus/xgo/token"
 	"github.com/goplus/xgo/x/typesutil"
+	"github.com/goplus/xgolsw/internal/pkgdata"
 	"github.com/goplus/xgolsw/xgo/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -28,9 +30,9 @@ onStart => {
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		emptyLineItemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		emptyLineItemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 1, Character: 0},
@@ -60,7 +62,7 @@ onStart => {
 			CompletionItemInsertTextFormat: PlainTextTextFormat,
 		}.CompletionItem())
 
-		mySpriteDotItemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		mySpriteDotItemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 2, Character: 9},
@@ -106,9 +108,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 2, Character: 1},
@@ -128,15 +130,225 @@ onStart => {
 		}))
 	})
 
+	t.Run("WithTypeError", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.spx": []byte(`
+var (
+	myCount int
+)
+
+func myHelper() {
+	undefinedIdent
+}
+
+onStart => {
+	undefinedCall myCount
+	my
+}
+`),
+		}
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
+			TextDocumentPositionParams: TextDocumentPositionParams{
+				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+				Position:     Position{Line: 11, Character: 3},
+			},
+		})
+		require.NoError(t, err)
+		items := itemsResult.([]CompletionItem)
+		require.NotNil(t, items)
+		assert.True(t, containsCompletionItemLabel(items, "myCount"))
+		assert.True(t, containsCompletionItemLabel(items, "myHelper"))
+	})
+
+	t.Run("DeprecatedFunc", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.spx": []byte(`
+// Deprecated: use NewMove instead.
+func OldMove() {}
+
+func NewMove() {}
+
+onStart => {
+	
+}
+`),
+		}
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
+			TextDocumentPositionParams: TextDocumentPositionParams{
+				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+				Position:     Position{Line: 7, Character: 1},
+			},
+		})
+		require.NoError(t, err)
+		items := itemsResult.([]CompletionItem)
+
+		oldMoveIdx := slices.IndexFunc(items, func(item CompletionItem) bool { return item.Label == "OldMove" })
+		require.GreaterOrEqual(t, oldMoveIdx, 0)
+		assert.Equal(t, []CompletionItemTag{DeprecatedCompletion}, items[oldMoveIdx].Tags)
+
+		newMoveIdx := slices.IndexFunc(items, func(item CompletionItem) bool { return item.Label == "NewMove" })
+		require.GreaterOrEqual(t, newMoveIdx, 0)
+		assert.Empty(t, items[newMoveIdx].Tags)
+	})
+
+	t.Run("Documentation", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.spx": []byte(`
+onStart => {
+	
+}
+`),
+		}
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
+			TextDocumentPositionParams: TextDocumentPositionParams{
+				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+				Position:     Position{Line: 2, Character: 1},
+			},
+		})
+		require.NoError(t, err)
+		items := itemsResult.([]CompletionItem)
+
+		documentation := func(id SpxDefinitionIdentifier) string {
+			idx := slices.IndexFunc(items, func(item CompletionItem) bool {
+				data, ok := item.Data.(*CompletionItemData)
+				return ok && data.Definition != nil && data.Definition.String() == id.String()
+			})
+			require.GreaterOrEqual(t, idx, 0, "completion item for %s not found", id)
+			require.NotNil(t, items[idx].Documentation)
+			markup, ok := items[idx].Documentation.Value.(MarkupContent)
+			require.True(t, ok)
+			return markup.Value
+		}
+
+		fmtPkgDoc, err := pkgdata.GetPkgDoc("fmt")
+		require.NoError(t, err)
+		printlnDoc, ok := fmtPkgDoc.LookupFunc("Println")
+		require.True(t, ok)
+		require.NotEmpty(t, printlnDoc)
+		assert.Contains(t, documentation(SpxDefinitionIdentifier{
+			Package: ToPtr("builtin"),
+			Name:    ToPtr("println"),
+		}), printlnDoc)
+
+		spxPkgDoc, err := pkgdata.GetPkgDoc(SpxPkgPath)
+		require.NoError(t, err)
+		getWidgetDoc, ok := spxPkgDoc.LookupMethod("Game", "GetWidget")
+		require.True(t, ok)
+		require.NotEmpty(t, getWidgetDoc)
+		assert.Contains(t, documentation(SpxDefinitionIdentifier{
+			Package: ToPtr(SpxPkgPath),
+			Name:    ToPtr("Game.getWidget"),
+		}), getWidgetDoc)
+	})
+
+	t.Run("KeywordSnippets", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.spx": []byte(`
+func myHelper() {
+
+}
+
+`),
+		}
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+		countLabel := func(items []CompletionItem, label string) int {
+			var n int
+			for _, item := range items {
+				if item.Label == label {
+					n++
+				}
+			}
+			return n
+		}
+
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
+			TextDocumentPositionParams: TextDocumentPositionParams{
+				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+				Position:     Position{Line: 5, Character: 0},
+			},
+		})
+		require.NoError(t, err)
+		items := itemsResult.([]CompletionItem)
+		require.NotNil(t, items)
+		assert.True(t, slices.ContainsFunc(items, func(item CompletionItem) bool {
+			return item.Label == "for" && item.InsertText == "for ${1:i} := 0; ${1} < ${2:n}; ${1}++ {\n\t$0\n}"
+		}))
+		assert.True(t, slices.ContainsFunc(items, func(item CompletionItem) bool {
+			return item.Label == "func" && item.Kind == KeywordCompletion
+		}))
+		assert.Equal(t, 1, countLabel(items, "onStart"))
+		assert.Equal(t, 1, countLabel(items, "onClick"))
+
+		itemsResult, err = s.textDocumentCompletion(t.Context(), &CompletionParams{
+			TextDocumentPositionParams: TextDocumentPositionParams{
+				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+				Position:     Position{Line: 2, Character: 0},
+			},
+		})
+		require.NoError(t, err)
+		items = itemsResult.([]CompletionItem)
+		require.NotNil(t, items)
+		assert.True(t, slices.ContainsFunc(items, func(item CompletionItem) bool {
+			return item.Label == "for" && item.InsertText == "for ${1:i} := 0; ${1} < ${2:n}; ${1}++ {\n\t$0\n}"
+		}))
+		assert.False(t, slices.ContainsFunc(items, func(item CompletionItem) bool {
+			return item.Label == "func" && item.Kind == KeywordCompletion
+		}))
+	})
+
+	t.Run("EventHandlerSnippets", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.spx": []byte(`
+onSt
+`),
+			"assets/index.json": []byte(`{}`),
+		}
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
+			TextDocumentPositionParams: TextDocumentPositionParams{
+				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+				Position:     Position{Line: 1, Character: 4},
+			},
+		})
+		require.NoError(t, err)
+		items := itemsResult.([]CompletionItem)
+		require.NotNil(t, items)
+
+		hasFuncSnippet := func(label, insertText string) bool {
+			return slices.ContainsFunc(items, func(item CompletionItem) bool {
+				return item.Label == label &&
+					item.Kind == FunctionCompletion &&
+					item.InsertText == insertText &&
+					item.InsertTextFormat != nil && *item.InsertTextFormat == SnippetTextFormat
+			})
+		}
+		assert.True(t, hasFuncSnippet("onStart", "onStart => {\n\t$0\n}"))
+		assert.True(t, hasFuncSnippet("onKey", "onKey ${1:key}, => {\n\t$0\n}"))
+		assert.True(t, hasFuncSnippet("onMsg", "onMsg (${1:msg}, ${2:data}) => {\n\t$0\n}"))
+		assert.True(t, slices.ContainsFunc(items, func(item CompletionItem) bool {
+			return item.Label == "onEngineStart" &&
+				item.InsertText == "onEngineStart" &&
+				item.InsertTextFormat != nil && *item.InsertTextFormat == PlainTextTextFormat
+		}))
+	})
+
 	t.Run("InStringLit", func(t *testing.T) {
 		m := map[string][]byte{
 			"main.spx": []byte(`
 echo "a
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 1, Character: 7},
@@ -154,9 +366,9 @@ echo "a
 // Run My G
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 1, Character: 11},
@@ -174,9 +386,9 @@ echo "a
 import "f
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 1, Character: 9},
@@ -195,9 +407,9 @@ import "f
 func d
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 1, Character: 6},
@@ -215,9 +427,9 @@ func d
 import f "fmt"
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 1, Character: 8},
@@ -235,9 +447,9 @@ import f "fmt"
 type Fo struct{}
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 1, Character: 7},
@@ -257,9 +469,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 2, Character: 8},
@@ -277,9 +489,9 @@ onStart => {
 const foo = 1
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 1, Character: 8},
@@ -298,9 +510,9 @@ const foo = 1
 func test() {}
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 0, Character: 10},
@@ -320,9 +532,9 @@ type T struct{}
 func (t T) test() {}
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 3, Character: 7},
@@ -340,9 +552,9 @@ func (t T) test() {}
 func test(foo int) {}
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 1, Character: 11},
@@ -360,9 +572,9 @@ func test(foo int) {}
 func test() (result int) { return 0 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 1, Character: 19},
@@ -382,9 +594,9 @@ type T struct {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 2, Character: 4},
@@ -404,9 +616,9 @@ type T interface {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 2, Character: 3},
@@ -429,9 +641,9 @@ loop:
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 2, Character: 3},
@@ -449,9 +661,9 @@ loop:
 println {"key": }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 1, Character: 15},
@@ -469,9 +681,9 @@ import (
 	"f
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 2, Character: 3},
@@ -491,9 +703,9 @@ import "fmt"
 fmt.
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 2, Character: 4},
@@ -515,9 +727,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		items1Result, err := s.textDocumentCompletion(&CompletionParams{
+		items1Result, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 1, Character: 1},
@@ -529,7 +741,7 @@ onStart => {
 		assert.NotEmpty(t, items1)
 		assert.True(t, containsCompletionItemLabel(items1, "len"))
 
-		items2Result, err := s.textDocumentCompletion(&CompletionParams{
+		items2Result, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 2, Character: 12},
@@ -540,7 +752,7 @@ onStart => {
 		require.NotNil(t, items2)
 		assert.Empty(t, items2)
 
-		items3Result, err := s.textDocumentCompletion(&CompletionParams{
+		items3Result, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 3, Character: 1},
@@ -566,9 +778,9 @@ onStart => {
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 3, Character: 8},
@@ -598,9 +810,9 @@ onStart => {
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 2, Character: 22},
@@ -626,9 +838,9 @@ onStart => {
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 4, Character: 24},
@@ -649,9 +861,9 @@ play "r"
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sounds/recording/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 1, Character: 7},
@@ -664,6 +876,112 @@ play "r"
 		assert.True(t, containsCompletionItemLabel(items, "recording"))
 	})
 
+	t.Run("SpxSoundResourceNoParenCallWithoutArgs", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.spx": []byte(`
+onStart => {
+	play 
+}
+`),
+			"assets/index.json":                  []byte(`{}`),
+			"assets/sounds/recording/index.json": []byte(`{}`),
+		}
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
+			TextDocumentPositionParams: TextDocumentPositionParams{
+				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+				Position:     Position{Line: 2, Character: 6},
+			},
+		})
+		require.NoError(t, err)
+		items := itemsResult.([]CompletionItem)
+		require.NotNil(t, items)
+		assert.NotEmpty(t, items)
+		assert.True(t, containsCompletionItemLabel(items, `"recording"`))
+		assert.False(t, containsCompletionItemLabel(items, "onStart"))
+
+		// Unknown names must not trigger call completions.
+		m["main.spx"] = []byte(`
+onStart => {
+	unknownFunc 
+}
+`)
+		s = New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+		itemsResult, err = s.textDocumentCompletion(t.Context(), &CompletionParams{
+			TextDocumentPositionParams: TextDocumentPositionParams{
+				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+				Position:     Position{Line: 2, Character: 13},
+			},
+		})
+		require.NoError(t, err)
+		assert.Empty(t, itemsResult)
+	})
+
+	t.Run("SpxSoundResourceRawStringLit", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.spx":                           []byte("\nplay `r`\n"),
+			"assets/index.json":                  []byte(`{}`),
+			"assets/sounds/recording/index.json": []byte(`{}`),
+		}
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
+			TextDocumentPositionParams: TextDocumentPositionParams{
+				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+				Position:     Position{Line: 1, Character: 7},
+			},
+		})
+		require.NoError(t, err)
+		items := itemsResult.([]CompletionItem)
+		require.True(t, containsCompletionItemLabel(items, "recording"))
+		assert.Equal(t, "recording", completionItemByLabel(items, "recording").InsertText)
+		assert.False(t, containsCompletionItemLabel(items, `"recording"`))
+	})
+
+	t.Run("SpxSoundResourceUnterminatedRawStringLit", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.spx":                           []byte("\nplay `r\n"),
+			"assets/index.json":                  []byte(`{}`),
+			"assets/sounds/recording/index.json": []byte(`{}`),
+		}
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
+			TextDocumentPositionParams: TextDocumentPositionParams{
+				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+				Position:     Position{Line: 1, Character: 7},
+			},
+		})
+		require.NoError(t, err)
+		items := itemsResult.([]CompletionItem)
+		require.True(t, containsCompletionItemLabel(items, "recording"))
+		assert.Equal(t, "recording", completionItemByLabel(items, "recording").InsertText)
+	})
+
+	t.Run("SpxWidgetResourceWithWidgetType", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.spx": []byte(`
+onStart => {
+	getWidget Monitor, "m"
+}
+`),
+			"assets/index.json": []byte(`{"zorder":[{"name":"myMonitor","type":"monitor"},{"name":"myButton","type":"button"},"MySprite"]}`),
+		}
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
+			TextDocumentPositionParams: TextDocumentPositionParams{
+				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+				Position:     Position{Line: 2, Character: 22},
+			},
+		})
+		require.NoError(t, err)
+		items := itemsResult.([]CompletionItem)
+		assert.True(t, containsCompletionItemLabel(items, "myMonitor"))
+		assert.False(t, containsCompletionItemLabel(items, "myButton"))
+	})
+
 	t.Run("FuncOverloads", func(t *testing.T) {
 		m := map[string][]byte{
 			"main.spx": []byte(`
@@ -672,9 +990,9 @@ play r
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sounds/recording/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 1, Character: 6},
@@ -699,9 +1017,9 @@ onClick => {
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{"costumes":[{"name":"costume"}]}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///MySprite.spx"},
 				Position:     Position{Line: 2, Character: 14},
@@ -723,9 +1041,9 @@ MySprite.setCostume "c"
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{"costumes":[{"name":"costume"}]}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 1, Character: 22},
@@ -752,9 +1070,9 @@ onClick => {
 			"assets/sprites/Sprite1/index.json": []byte(`{"costumes":[{"name":"Sprite1Costume"}]}`),
 			"assets/sprites/Sprite2/index.json": []byte(`{"costumes":[{"name":"Sprite2Costume"}]}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///Sprite1.spx"},
 				Position:     Position{Line: 2, Character: 22},
@@ -780,9 +1098,9 @@ onClick => {
 			"assets/sprites/Sprite1/index.json": []byte(`{"costumes":[{"name":"Sprite1Costume"}]}`),
 			"assets/sprites/Sprite2/index.json": []byte(`{"costumes":[{"name":"Sprite2Costume"}]}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///Sprite1.spx"},
 				Position:     Position{Line: 2, Character: 25},
@@ -808,9 +1126,9 @@ onClick => {
 			"assets/sprites/Sprite1/index.json": []byte(`{"costumes":[{"name":"Sprite1Costume"}]}`),
 			"assets/sprites/Sprite2/index.json": []byte(`{"costumes":[{"name":"Sprite2Costume"}]}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///Sprite1.spx"},
 				Position:     Position{Line: 2, Character: 28},
@@ -838,9 +1156,9 @@ onStart => {
 			"assets/sprites/Sprite2/index.json": []byte(`{"costumes":[{"name":"Crab2"}]}`),
 			"assets/sprites/Sprite3/index.json": []byte(`{"costumes":[{"name":"Crab2"}]}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///Sprite1.spx"},
 				Position:     Position{Line: 2, Character: 13},
@@ -868,9 +1186,9 @@ onStart => {
 			"assets/sprites/Sprite2/index.json": []byte(`{"costumes":[{"name":"Crab2"}]}`),
 			"assets/sprites/Sprite3/index.json": []byte(`{"costumes":[{"name":"Crab2"}]}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 2, Character: 34},
@@ -898,9 +1216,9 @@ onStart => {
 			"assets/sprites/Crab2/index.json":  []byte(`{"costumes":[]}`),
 			"assets/sprites/Crab3/index.json":  []byte(`{"costumes":[]}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///Runner.spx"},
 				Position:     Position{Line: 2, Character: 10},
@@ -921,9 +1239,9 @@ onClick => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 2, Character: 3},
@@ -948,9 +1266,9 @@ onClick => {
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		items1Result, err := s.textDocumentCompletion(&CompletionParams{
+		items1Result, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 1, Character: 14},
@@ -962,7 +1280,7 @@ onClick => {
 		assert.NotEmpty(t, items1)
 		assert.True(t, containsCompletionItemLabel(items1, "setCostume"))
 
-		items2Result, err := s.textDocumentCompletion(&CompletionParams{
+		items2Result, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///MySprite.spx"},
 				Position:     Position{Line: 2, Character: 15},
@@ -990,9 +1308,9 @@ onClick => {
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		items1Result, err := s.textDocumentCompletion(&CompletionParams{
+		items1Result, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 2, Character: 9},
@@ -1004,7 +1322,7 @@ onClick => {
 		assert.NotEmpty(t, items1)
 		assert.True(t, containsCompletionItemLabel(items1, "int128"))
 
-		items2Result, err := s.textDocumentCompletion(&CompletionParams{
+		items2Result, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///MySprite.spx"},
 				Position:     Position{Line: 2, Character: 3},
@@ -1033,9 +1351,9 @@ onStart => {}
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 10, Character: 3},
@@ -1073,9 +1391,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 12, Character: 3},
@@ -1109,9 +1427,9 @@ onStart => {}
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 8, Character: 3},
@@ -1144,9 +1462,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 7, Character: 12},
@@ -1176,6 +1494,136 @@ onStart => {
 		}))
 	})
 
+	t.Run("ChanDirectionExpectedType", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.spx": []byte(`
+func feed(ch chan<- int) {}
+
+func drain(ch <-chan int) {}
+
+onStart => {
+	bidiCh := make(chan int)
+	var recvCh <-chan int
+	var sendCh chan<- int
+	feed 
+	drain 
+	echo bidiCh, recvCh, sendCh
+}
+`),
+			"assets/index.json": []byte(`{}`),
+		}
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+		completionItems := func(position Position) []CompletionItem {
+			itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
+				TextDocumentPositionParams: TextDocumentPositionParams{
+					TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+					Position:     position,
+				},
+			})
+			require.NoError(t, err)
+			items := itemsResult.([]CompletionItem)
+			require.NotNil(t, items)
+			return items
+		}
+
+		sendItems := completionItems(Position{Line: 9, Character: 6})
+		assert.True(t, containsCompletionItemLabel(sendItems, "bidiCh"))
+		assert.True(t, containsCompletionItemLabel(sendItems, "sendCh"))
+		assert.False(t, containsCompletionItemLabel(sendItems, "recvCh"))
+		assert.True(t, containsCompletionItemLabel(sendItems, "make(chan int)"))
+		assert.True(t, containsCompletionItemLabel(sendItems, "make(chan<- int)"))
+		assert.False(t, containsCompletionItemLabel(sendItems, "make(<-chan int)"))
+		idx := slices.IndexFunc(sendItems, func(item CompletionItem) bool {
+			return item.Label == "make(chan int, buffer)"
+		})
+		require.GreaterOrEqual(t, idx, 0)
+		assert.Equal(t, "make(chan int, ${1:buffer})", sendItems[idx].InsertText)
+		assert.Equal(t, ToPtr(SnippetTextFormat), sendItems[idx].InsertTextFormat)
+
+		recvItems := completionItems(Position{Line: 10, Character: 7})
+		assert.True(t, containsCompletionItemLabel(recvItems, "bidiCh"))
+		assert.True(t, containsCompletionItemLabel(recvItems, "recvCh"))
+		assert.False(t, containsCompletionItemLabel(recvItems, "sendCh"))
+		assert.True(t, containsCompletionItemLabel(recvItems, "make(<-chan int)"))
+		assert.False(t, containsCompletionItemLabel(recvItems, "make(chan<- int)"))
+	})
+
+	t.Run("StructLiteralSnippetInCallArg", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.spx": []byte(`
+type Config struct {
+	Title string
+	Width int
+}
+
+func start(res string, conf Config) {}
+
+onStart => {
+	start "assets", {}
+}
+`),
+		}
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
+			TextDocumentPositionParams: TextDocumentPositionParams{
+				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+				Position:     Position{Line: 9, Character: 18},
+			},
+		})
+		require.NoError(t, err)
+		items := itemsResult.([]CompletionItem)
+		require.NotNil(t, items)
+		assert.True(t, slices.ContainsFunc(items, func(item CompletionItem) bool {
+			itemData, ok := item.Data.(*CompletionItemData)
+			return ok && itemData.Definition.String() == "xgo:main?Config.Title"
+		}))
+		assert.True(t, slices.ContainsFunc(items, func(item CompletionItem) bool {
+			itemData, ok := item.Data.(*CompletionItemData)
+			return ok && itemData.Definition.String() == "xgo:main?Config.Width"
+		}))
+		idx := slices.IndexFunc(items, func(item CompletionItem) bool {
+			return item.Kind == SnippetCompletion
+		})
+		require.GreaterOrEqual(t, idx, 0)
+		assert.Equal(t, "Title, Width", items[idx].Label)
+		assert.Equal(t, "${1:Title}: ${2:}, ${3:Width}: ${4:}", items[idx].InsertText)
+		assert.Equal(t, ToPtr(SnippetTextFormat), items[idx].InsertTextFormat)
+	})
+
+	t.Run("StructLiteralSnippetNotOfferedForNonEmptyLiteral", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.spx": []byte(`
+type Config struct {
+	Title string
+	Width int
+}
+
+onStart => {
+	c := Config{Title: "Game", }
+}
+`),
+		}
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
+			TextDocumentPositionParams: TextDocumentPositionParams{
+				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+				Position:     Position{Line: 7, Character: 28},
+			},
+		})
+		require.NoError(t, err)
+		items := itemsResult.([]CompletionItem)
+		assert.True(t, slices.ContainsFunc(items, func(item CompletionItem) bool {
+			itemData, ok := item.Data.(*CompletionItemData)
+			return ok && itemData.Definition.String() == "xgo:main?Config.Width"
+		}))
+		assert.False(t, slices.ContainsFunc(items, func(item CompletionItem) bool {
+			return item.Kind == SnippetCompletion
+		}))
+	})
+
 	t.Run("MainPackageStructLiteralFieldWithAlias", func(t *testing.T) {
 		m := map[string][]byte{
 			"main.spx": []byte(`
@@ -1191,9 +1639,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 9, Character: 17},
@@ -1230,9 +1678,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 10, Character: 3},
@@ -1262,9 +1710,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 4, Character: 17},
@@ -1292,9 +1740,9 @@ onStar => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 1, Character: 6},
@@ -1317,9 +1765,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 3, Character: 6},
@@ -1347,9 +1795,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 9, Character: 10},
@@ -1370,9 +1818,9 @@ onStart => {
 )
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 9, Character: 10},
@@ -1395,9 +1843,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 2, Character: 8},
@@ -1422,9 +1870,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 6, Character: 9},
@@ -1451,9 +1899,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 7, Character: 4},
@@ -1479,9 +1927,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 7, Character: 15},
@@ -1502,9 +1950,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 2, Character: 13}, // After "123."
@@ -1524,9 +1972,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 2, Character: 10}, // After "123."
@@ -1553,9 +2001,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 8, Character: 10}, // After "f"
@@ -1584,9 +2032,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 9, Character: 13}, // After "b" in second value
@@ -1617,9 +2065,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 9, Character: 13}, // After "c" in nested map
@@ -1647,9 +2095,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 8, Character: 11}, // After "m" in map value
@@ -1682,9 +2130,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 13, Character: 9}, // After "m" in struct field value
@@ -1709,9 +2157,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 5, Character: 14}, // After "value" in map value
@@ -1738,9 +2186,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 8, Character: 12}, // After "n" in map value
@@ -1769,9 +2217,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 9, Character: 3}, // After "F" in struct literal
@@ -1796,9 +2244,9 @@ func getName() string {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 3, Character: 9}, // After "s" in return
@@ -1821,9 +2269,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 3, Character: 8}, // After "s" in assignment
@@ -1846,9 +2294,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 3, Character: 10}, // After "s" in call argument
@@ -1862,6 +2310,39 @@ onStart => {
 		assert.True(t, containsCompletionItemLabel(items, "string"))
 	})
 
+	t.Run("MultiAssignWithBlankIdent", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.spx": []byte(`
+func fIntErr() (int, error) { return 0, nil }
+func fStrErr() (string, error) { return "", nil }
+func fErrErr() (error, error) { return nil, nil }
+func fTwoInts() (int, int) { return 0, 1 }
+func fErr() error { return nil }
+
+onStart => {
+	var err error
+	_, err = f
+}
+`),
+		}
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
+			TextDocumentPositionParams: TextDocumentPositionParams{
+				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+				Position:     Position{Line: 9, Character: 11},
+			},
+		})
+		require.NoError(t, err)
+		items := itemsResult.([]CompletionItem)
+		require.NotNil(t, items)
+		assert.True(t, containsCompletionItemLabel(items, "fIntErr"))
+		assert.True(t, containsCompletionItemLabel(items, "fStrErr"))
+		assert.True(t, containsCompletionItemLabel(items, "fErrErr"))
+		assert.False(t, containsCompletionItemLabel(items, "fTwoInts"))
+		assert.False(t, containsCompletionItemLabel(items, "fErr"))
+	})
+
 	t.Run("TypedMapLitInReturn", func(t *testing.T) {
 		m := map[string][]byte{
 			"main.spx": []byte(`
@@ -1877,9 +2358,9 @@ func getData() map[string]int {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 8, Character: 12}, // After "c" in map value
@@ -1910,9 +2391,9 @@ func getConfig() map[string]string {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 8, Character: 11}, // After "a" in map value
@@ -1941,9 +2422,9 @@ func getNestedData() map[string]map[string]int {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 5, Character: 13}, // After "t" in nested map value
@@ -1967,9 +2448,9 @@ func getResult() (map[string]int, error) {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 4, Character: 12}, // After "r" in map value
@@ -1999,9 +2480,9 @@ func getPerson() Person {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 10, Character: 9}, // After "m" in struct field value
@@ -2032,9 +2513,9 @@ func getConfig() *Config {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 10, Character: 9}, // After "d" in struct field value
@@ -2059,9 +2540,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 4, Character: 10}, // After "r" in return statement
@@ -2090,9 +2571,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 9, Character: 10}, // After "p" in return statement
@@ -2118,9 +2599,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 5, Character: 3}, // After "f" in slice literal
@@ -2145,9 +2626,9 @@ func getNumbers() []int {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 5, Character: 3}, // After "n" in slice literal
@@ -2177,9 +2658,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 9, Character: 3}, // After "i" in slice literal
@@ -2193,6 +2674,131 @@ onStart => {
 		assert.True(t, containsCompletionItemLabel(items, "item2"))
 	})
 
+	t.Run("XGoStyleSliceLiteralElementType", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.spx": []byte(`
+func printInts(s []int) {
+	echo s
+}
+
+onStart => {
+	var count = 3
+	var name = "hello"
+	printInts [1, 2, ]
+}
+`),
+		}
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
+			TextDocumentPositionParams: TextDocumentPositionParams{
+				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+				Position:     Position{Line: 8, Character: 18}, // After "2, " in slice literal
+			},
+		})
+		require.NoError(t, err)
+		items := itemsResult.([]CompletionItem)
+		require.NotNil(t, items)
+		assert.True(t, containsCompletionItemLabel(items, "count"))
+		assert.False(t, containsCompletionItemLabel(items, "name"))
+	})
+
+	t.Run("LambdaExprImplicitReturn", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.spx": []byte(`
+func apply(f func(int) string) {
+	echo f(1)
+}
+
+onStart => {
+	var count = 1
+	var name = "hello"
+	apply x => n
+}
+`),
+		}
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
+			TextDocumentPositionParams: TextDocumentPositionParams{
+				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+				Position:     Position{Line: 8, Character: 13}, // After "n" in lambda body
+			},
+		})
+		require.NoError(t, err)
+		items := itemsResult.([]CompletionItem)
+		require.NotNil(t, items)
+		assert.True(t, containsCompletionItemLabel(items, "name"))
+		assert.False(t, containsCompletionItemLabel(items, "count"))
+	})
+
+	t.Run("LambdaExprEmptyBody", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.spx": []byte(`
+var count = 1
+onStart => `),
+		}
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
+			TextDocumentPositionParams: TextDocumentPositionParams{
+				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+				Position:     Position{Line: 2, Character: 11}, // After "onStart => "
+			},
+		})
+		require.NoError(t, err)
+		items := itemsResult.([]CompletionItem)
+		require.NotNil(t, items)
+		assert.True(t, containsCompletionItemLabel(items, "count"))
+		assert.True(t, containsCompletionItemLabel(items, "println"))
+	})
+
+	t.Run("TypeSwitchCase", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.spx": []byte(`
+type MyError struct{}
+
+func (e MyError) Error() string {
+	return "my error"
+}
+
+type PtrError struct{}
+
+func (e *PtrError) Error() string {
+	return "ptr error"
+}
+
+type NotAnError struct{}
+
+var err error
+
+onStart => {
+	switch v := err.(type) {
+	case 
+	}
+}
+`),
+		}
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
+			TextDocumentPositionParams: TextDocumentPositionParams{
+				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+				Position:     Position{Line: 19, Character: 6}, // After "case "
+			},
+		})
+		require.NoError(t, err)
+		items := itemsResult.([]CompletionItem)
+		require.NotNil(t, items)
+		assert.True(t, containsCompletionItemLabel(items, "MyError"))
+		assert.False(t, containsCompletionItemLabel(items, "*MyError"))
+		assert.True(t, containsCompletionItemLabel(items, "*PtrError"))
+		assert.False(t, containsCompletionItemLabel(items, "PtrError"))
+		assert.False(t, containsCompletionItemLabel(items, "NotAnError"))
+		assert.False(t, containsCompletionItemLabel(items, "error"))
+		assert.False(t, containsCompletionItemLabel(items, "err"))
+	})
+
 	t.Run("XGoStyleSliceLiteralInReturn", func(t *testing.T) {
 		m := map[string][]byte{
 			"main.spx": []byte(`
@@ -2205,9 +2811,9 @@ func getItems() []string {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 5, Character: 3}, // After "i" in slice literal
@@ -2236,9 +2842,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 8, Character: 9}, // After "v" in nested slice
@@ -2263,9 +2869,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 5, Character: 3}, // After "e" in array literal
@@ -2291,9 +2897,9 @@ func getHandler() func(int) int {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 5, Character: 10}, // After "r" in inner return
@@ -2315,9 +2921,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 3, Character: 17}, // After "s" in var declaration with value
@@ -2340,9 +2946,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 3, Character: 12}, // After "s" in const declaration
@@ -2366,9 +2972,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 3, Character: 7}, // After "s" in short var decl
@@ -2396,9 +3002,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 8, Character: 9}, // After "g" in assignment
@@ -2425,9 +3031,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 6, Character: 10}, // After "g" in short var decl
@@ -2456,9 +3062,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 8, Character: 19}, // After "g" in second expression
@@ -2489,9 +3095,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 10, Character: 11}, // After "g" in assignment
@@ -2519,9 +3125,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 5, Character: 13}, // After "g" in if statement init
@@ -2550,9 +3156,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 9, Character: 15}, // After "g"
@@ -2577,9 +3183,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 5, Character: 17}, // After "g" in int assignment
@@ -2600,9 +3206,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 3, Character: 22}, // After "c"
@@ -2626,9 +3232,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 7, Character: 9}, // After "g"
@@ -2661,9 +3267,9 @@ onStart => {}
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 8, Character: 9}, // After "n"
@@ -2697,9 +3303,9 @@ onStart => {
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 3, Character: 10}, // inside 'x' arg of showVar
@@ -2734,9 +3340,9 @@ onStart => {
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///MySprite.spx"},
 				// Line 4: "\tshowVar(x)" — tab(0)+showVar(1-7)+(8)+x(9)
@@ -2764,9 +3370,9 @@ var hp int
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				// Line 3: "\tMySprite.showVar(x)" — tab(0)+MySprite(1-8)+.(9)+showVar(10-16)+(17)+x(18)
@@ -2796,9 +3402,9 @@ showVar(
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///MySprite.spx"},
 				Position:     Position{Line: 3, Character: 8},
@@ -2827,9 +3433,9 @@ showVar("s
 `),
 			"assets/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 3, Character: 10},
@@ -2853,9 +3459,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		timeNowItemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		timeNowItemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 4, Character: 16},
@@ -2882,9 +3488,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		nowItemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		nowItemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 8, Character: 11},
@@ -2912,9 +3518,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 9, Character: 13},
@@ -2972,9 +3578,9 @@ onStart => {
 				m := map[string][]byte{
 					"main.spx": []byte(tt.code),
 				}
-				s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+				s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-				itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+				itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 					TextDocumentPositionParams: TextDocumentPositionParams{
 						TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 						Position:     Position{Line: 9, Character: 13},
@@ -3004,9 +3610,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 9, Character: 13},
@@ -3033,9 +3639,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 9, Character: 23},
@@ -3075,9 +3681,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 22, Character: 18},
@@ -3124,9 +3730,9 @@ onStart => {
 					"assets/sprites/MySprite/index.json": []byte(`{}`),
 					"assets/sprites/Red/index.json":      []byte(`{}`),
 				}
-				s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+				s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-				itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+				itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 					TextDocumentPositionParams: TextDocumentPositionParams{
 						TextDocument: TextDocumentIdentifier{URI: "file:///MySprite.spx"},
 						Position:     Position{Line: 2, Character: tt.character},
@@ -3175,9 +3781,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 22, Character: 27},
@@ -3216,9 +3822,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 20, Character: 17},
@@ -3261,9 +3867,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 24, Character: 27},
@@ -3292,9 +3898,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 10, Character: 18},
@@ -3319,9 +3925,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 6, Character: 14},
@@ -3355,9 +3961,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 15, Character: 27},
@@ -3394,9 +4000,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 13, Character: 27},
@@ -3423,9 +4029,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+		itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 9, Character: 20},
@@ -3440,11 +4046,11 @@ onStart => {
 	t.Run("XGoUnits", func(t *testing.T) {
 		t.Run("CallArguments", func(t *testing.T) {
 			s := newXGoUnitTestServer(xgoUnitCompletionSource)
-			result, _, _, err := s.compileAndGetASTFileForDocumentURI("file:///main.spx")
+			result, err := s.compileAndGetASTFileForDocumentURI(t.Context(), "file:///main.spx")
 			require.NoError(t, err)
 			require.Falsef(t, result.hasErrorSeverityDiagnostic, "%#v", result.diagnostics)
 
-			durationItemsResult, err := s.textDocumentCompletion(&CompletionParams{
+			durationItemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 				TextDocumentPositionParams: TextDocumentPositionParams{
 					TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 					Position:     Position{Line: 14, Character: 7},
@@ -3466,7 +4072,7 @@ onStart => {
 			})
 			assert.Equal(t, "1s", completionItemByLabel(durationItems, "s").FilterText)
 
-			durationPartialItemsResult, err := s.textDocumentCompletion(&CompletionParams{
+			durationPartialItemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 				TextDocumentPositionParams: TextDocumentPositionParams{
 					TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 					Position:     Position{Line: 15, Character: 8},
@@ -3484,7 +4090,7 @@ onStart => {
 			})
 			assert.Equal(t, "1ms", completionItemByLabel(durationPartialItems, "ms").FilterText)
 
-			distanceItemsResult, err := s.textDocumentCompletion(&CompletionParams{
+			distanceItemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 				TextDocumentPositionParams: TextDocumentPositionParams{
 					TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 					Position:     Position{Line: 16, Character: 8},
@@ -3507,7 +4113,7 @@ onStart => {
 		t.Run("StructKwargUnsupported", func(t *testing.T) {
 			s := newXGoUnitTestServer(xgoUnitCompletionSource)
 
-			itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+			itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 				TextDocumentPositionParams: TextDocumentPositionParams{
 					TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 					Position:     Position{Line: 17, Character: 20},
@@ -3538,7 +4144,7 @@ onStart => {
 }
 `)
 
-			itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+			itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 				TextDocumentPositionParams: TextDocumentPositionParams{
 					TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 					Position:     Position{Line: 14, Character: 16},
@@ -3579,7 +4185,7 @@ onStart => {
 				{name: "StructField", position: Position{Line: 13, Character: 21}},
 			} {
 				t.Run(tt.name, func(t *testing.T) {
-					itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+					itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 						TextDocumentPositionParams: TextDocumentPositionParams{
 							TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 							Position:     tt.position,
@@ -3602,7 +4208,7 @@ onStart => {
 }
 `)
 
-			itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+			itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 				TextDocumentPositionParams: TextDocumentPositionParams{
 					TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 					Position:     Position{Line: 5, Character: 10},
@@ -3626,7 +4232,7 @@ onStart => {
 }
 `)
 
-			itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+			itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 				TextDocumentPositionParams: TextDocumentPositionParams{
 					TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 					Position:     Position{Line: 7, Character: 7},
@@ -3650,7 +4256,7 @@ onStart => {
 }
 `)
 
-			itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+			itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 				TextDocumentPositionParams: TextDocumentPositionParams{
 					TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 					Position:     Position{Line: 7, Character: 8},
@@ -3672,7 +4278,7 @@ onStart => {
 }
 `)
 
-			itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+			itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 				TextDocumentPositionParams: TextDocumentPositionParams{
 					TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 					Position:     Position{Line: 5, Character: 8},
@@ -3693,9 +4299,9 @@ onStart => {
 `),
 				"assets/index.json": []byte(`{}`),
 			}
-			s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+			s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-			itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+			itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 				TextDocumentPositionParams: TextDocumentPositionParams{
 					TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 					Position:     Position{Line: 1, Character: 7},
@@ -3720,7 +4326,7 @@ onStart => {
 }
 `)
 
-			itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+			itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 				TextDocumentPositionParams: TextDocumentPositionParams{
 					TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 					Position:     Position{Line: 5, Character: 7},
@@ -3753,7 +4359,7 @@ onStart => {
 }
 `)
 
-			itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+			itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 				TextDocumentPositionParams: TextDocumentPositionParams{
 					TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 					Position:     Position{Line: 15, Character: 16},
@@ -3775,7 +4381,7 @@ onStart => {
 }
 `)
 
-			itemsResult, err := s.textDocumentCompletion(&CompletionParams{
+			itemsResult, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 				TextDocumentPositionParams: TextDocumentPositionParams{
 					TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 					Position:     Position{Line: 4, Character: 8},
@@ -3793,9 +4399,9 @@ onStart => {
 			m := map[string][]byte{
 				"main.spx": []byte("var x = 100\necho x"),
 			}
-			s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+			s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-			result, err := s.textDocumentCompletion(&CompletionParams{
+			result, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 				TextDocumentPositionParams: TextDocumentPositionParams{
 					TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 					Position:     Position{Line: 1, Character: 5},
@@ -3810,7 +4416,7 @@ onStart => {
 		t.Run("IncompleteUnitList", func(t *testing.T) {
 			s := newXGoUnitTestServer(xgoUnitCompletionSource)
 
-			result, err := s.textDocumentCompletion(&CompletionParams{
+			result, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 				TextDocumentPositionParams: TextDocumentPositionParams{
 					TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 					Position:     Position{Line: 14, Character: 7},
@@ -3841,7 +4447,7 @@ onStart => {
 }
 `)
 
-			result, err := s.textDocumentCompletion(&CompletionParams{
+			result, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
 				TextDocumentPositionParams: TextDocumentPositionParams{
 					TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 					Position:     Position{Line: 5, Character: 8},
@@ -3861,6 +4467,243 @@ onStart => {
 			})
 		})
 	})
+
+	t.Run("BuiltinNewTypeArg", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.spx": []byte(`
+type Point struct {
+	X int
+}
+
+type Scores []int
+
+func getPoint() Point { return Point{} }
+
+onStart => {
+	p := new()
+}
+`),
+		}
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+		result, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
+			TextDocumentPositionParams: TextDocumentPositionParams{
+				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+				Position:     Position{Line: 10, Character: 10},
+			},
+		})
+		require.NoError(t, err)
+		items := result.([]CompletionItem)
+		assert.True(t, containsCompletionItemLabel(items, "Point"))
+		assert.True(t, containsCompletionItemLabel(items, "Sprite"))
+		assert.False(t, containsCompletionItemLabel(items, "Scores"))
+		assert.False(t, containsCompletionItemLabel(items, "getPoint"))
+		assert.False(t, containsCompletionItemLabel(items, "println"))
+	})
+
+	t.Run("BuiltinMakeTypeArg", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.spx": []byte(`
+type Point struct {
+	X int
+}
+
+type Scores []int
+
+func getScores() Scores { return nil }
+
+onStart => {
+	s := make()
+}
+`),
+		}
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+		result, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
+			TextDocumentPositionParams: TextDocumentPositionParams{
+				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+				Position:     Position{Line: 10, Character: 11},
+			},
+		})
+		require.NoError(t, err)
+		items := result.([]CompletionItem)
+		require.True(t, containsCompletionItemLabel(items, "[]int"))
+		assert.Equal(t, "[]int, ${1:len}", completionItemByLabel(items, "[]int").InsertText)
+		assert.True(t, containsCompletionItemLabel(items, "map[string]int"))
+		assert.True(t, containsCompletionItemLabel(items, "chan int"))
+		require.True(t, containsCompletionItemLabel(items, "Scores"))
+		assert.Equal(t, "Scores, ${1:len}", completionItemByLabel(items, "Scores").InsertText)
+		assert.False(t, containsCompletionItemLabel(items, "Point"))
+		assert.False(t, containsCompletionItemLabel(items, "getScores"))
+		assert.False(t, containsCompletionItemLabel(items, "println"))
+	})
+
+	t.Run("ChanSendValue", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.spx": []byte(`
+var (
+	intChan chan int
+	strChan chan string
+	n       int
+	str     string
+)
+
+onStart => {
+	strChan <- 
+}
+`),
+		}
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+		result, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
+			TextDocumentPositionParams: TextDocumentPositionParams{
+				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+				Position:     Position{Line: 9, Character: 12},
+			},
+		})
+		require.NoError(t, err)
+		items := result.([]CompletionItem)
+		assert.True(t, containsCompletionItemLabel(items, "str"))
+		assert.False(t, containsCompletionItemLabel(items, "n"))
+		assert.False(t, containsCompletionItemLabel(items, "intChan"))
+	})
+
+	t.Run("ChanReceiveOperand", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.spx": []byte(`
+var (
+	intChan  chan int
+	strChan  chan string
+	recvOnly <-chan int
+	n        int
+)
+
+onStart => {
+	n = <-
+}
+`),
+		}
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+		result, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
+			TextDocumentPositionParams: TextDocumentPositionParams{
+				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+				Position:     Position{Line: 9, Character: 7},
+			},
+		})
+		require.NoError(t, err)
+		items := result.([]CompletionItem)
+		assert.True(t, containsCompletionItemLabel(items, "intChan"))
+		assert.True(t, containsCompletionItemLabel(items, "recvOnly"))
+		assert.False(t, containsCompletionItemLabel(items, "strChan"))
+		assert.False(t, containsCompletionItemLabel(items, "n"))
+	})
+
+	t.Run("MapIndexKeyStringLit", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.spx": []byte(`
+var scores map[SpriteName]int
+
+onStart => {
+	echo scores[""]
+}
+`),
+			"MySprite.spx":                       []byte(``),
+			"assets/index.json":                  []byte(`{}`),
+			"assets/sprites/MySprite/index.json": []byte(`{}`),
+		}
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+		result, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
+			TextDocumentPositionParams: TextDocumentPositionParams{
+				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+				Position:     Position{Line: 4, Character: 14},
+			},
+		})
+		require.NoError(t, err)
+		items := result.([]CompletionItem)
+		require.Len(t, items, 1)
+		assert.Equal(t, "MySprite", items[0].Label)
+		assert.Equal(t, "MySprite", items[0].InsertText)
+	})
+
+	t.Run("MapIndexKey", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.spx": []byte(`
+var (
+	scores map[SpriteName]int
+	name   SpriteName
+	count  int
+)
+
+onStart => {
+	echo scores[]
+}
+`),
+			"MySprite.spx":                       []byte(``),
+			"assets/index.json":                  []byte(`{}`),
+			"assets/sprites/MySprite/index.json": []byte(`{}`),
+		}
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+		result, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
+			TextDocumentPositionParams: TextDocumentPositionParams{
+				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+				Position:     Position{Line: 8, Character: 13},
+			},
+		})
+		require.NoError(t, err)
+		items := result.([]CompletionItem)
+		assert.True(t, containsCompletionItemLabel(items, `"MySprite"`))
+		assert.True(t, containsCompletionItemLabel(items, "name"))
+		assert.False(t, containsCompletionItemLabel(items, "count"))
+	})
+
+	t.Run("CollapsedOptionalBoolOverloads", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.spx": []byte(`
+onStart => {
+	
+}
+`),
+		}
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+		result, err := s.textDocumentCompletion(t.Context(), &CompletionParams{
+			TextDocumentPositionParams: TextDocumentPositionParams{
+				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+				Position:     Position{Line: 2, Character: 1},
+			},
+		})
+		require.NoError(t, err)
+		items := result.([]CompletionItem)
+
+		assert.Equal(t, 1, countCompletionItemLabel(items, "play"))
+		playItem := completionItemByLabel(items, "play")
+		require.NotNil(t, playItem)
+		assert.Equal(t, "play ${1:name}${2:, ${3:true}}", playItem.InsertText)
+		require.NotNil(t, playItem.InsertTextFormat)
+		assert.Equal(t, SnippetTextFormat, *playItem.InsertTextFormat)
+		assert.True(t, containsCompletionSpxDefinitionID([]CompletionItem{*playItem}, SpxDefinitionIdentifier{
+			Package:    ToPtr(SpxPkgPath),
+			Name:       ToPtr("Game.play"),
+			OverloadID: ToPtr("0"),
+		}))
+
+		// findPath has three overloads, each adding a trailing bool parameter.
+		// Only the first two are collapsed, the third one is kept as is.
+		assert.Equal(t, 2, countCompletionItemLabel(items, "findPath"))
+		assert.True(t, containsCompletionSpxDefinitionID(items, SpxDefinitionIdentifier{
+			Package:    ToPtr(SpxPkgPath),
+			Name:       ToPtr("Game.findPath"),
+			OverloadID: ToPtr("0"),
+		}))
+		assert.True(t, containsCompletionSpxDefinitionID(items, SpxDefinitionIdentifier{
+			Package:    ToPtr(SpxPkgPath),
+			Name:       ToPtr("Game.findPath"),
+			OverloadID: ToPtr("2"),
+		}))
+	})
 }
 
 func TestCompletionContextResolvePropertyLikeExprType(t *testing.T) {
@@ -3979,6 +4822,70 @@ func TestCompletionContextResolvePropertyLikeFuncResultType(t *testing.T) {
 	})
 }
 
+func TestCompletionContextSortedItemsWithConfig(t *testing.T) {
+	newCtx := func() *completionContext {
+		itemSet := newCompletionItemSet()
+		itemSet.add(
+			CompletionItem{Label: "fnB", Kind: FunctionCompletion},
+			CompletionItem{Label: "varA", Kind: VariableCompletion},
+			CompletionItem{Label: "varC", Kind: VariableCompletion},
+			CompletionItem{Label: "fnA", Kind: FunctionCompletion},
+		)
+		return &completionContext{itemSet: itemSet}
+	}
+	labels := func(items []CompletionItem) []string {
+		got := make([]string, 0, len(items))
+		for _, item := range items {
+			got = append(got, item.Label)
+		}
+		return got
+	}
+
+	t.Run("Default", func(t *testing.T) {
+		items := newCtx().sortedItemsWithConfig(DefaultCompletionSortConfig())
+		assert.Equal(t, []string{"varA", "varC", "fnA", "fnB"}, labels(items))
+	})
+
+	t.Run("CustomWeights", func(t *testing.T) {
+		cfg := DefaultCompletionSortConfig()
+		cfg.Variable = 10
+		cfg.Function = 1
+		items := newCtx().sortedItemsWithConfig(cfg)
+		assert.Equal(t, []string{"fnA", "fnB", "varA", "varC"}, labels(items))
+	})
+
+	t.Run("SetCompletionSortConfig", func(t *testing.T) {
+		cfg := DefaultCompletionSortConfig()
+		cfg.Variable = 10
+		cfg.Function = 1
+		SetCompletionSortConfig(cfg)
+		t.Cleanup(func() { SetCompletionSortConfig(DefaultCompletionSortConfig()) })
+
+		items := newCtx().sortedItems()
+		assert.Equal(t, []string{"fnA", "fnB", "varA", "varC"}, labels(items))
+	})
+
+	t.Run("SetCompletionSortConfigConcurrently", func(t *testing.T) {
+		t.Cleanup(func() { SetCompletionSortConfig(DefaultCompletionSortConfig()) })
+
+		var wg sync.WaitGroup
+		for i := range 10 {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				cfg := DefaultCompletionSortConfig()
+				cfg.Variable = i
+				SetCompletionSortConfig(cfg)
+			}()
+			go func() {
+				defer wg.Done()
+				assert.Len(t, newCtx().sortedItems(), 4)
+			}()
+		}
+		wg.Wait()
+	})
+}
+
 func newPropertyLikeTestCompletionContext(pkg *gotypes.Package, innermostScope *gotypes.Scope, uses map[*ast.Ident]gotypes.Object) *completionContext {
 	if uses == nil {
 		uses = make(map[*ast.Ident]gotypes.Object)
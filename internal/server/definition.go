@@ -5,53 +5,85 @@ import (
 	gotypes "go/types"
 
 	"github.com/goplus/xgo/token"
+	"github.com/goplus/xgolsw/xgo"
+	"github.com/goplus/xgolsw/xgo/types"
 	"github.com/goplus/xgolsw/xgo/xgoutil"
 )
 
 // See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.18/specification/#textDocument_declaration
 func (s *Server) textDocumentDeclaration(params *DeclarationParams) (any, error) {
-	return s.textDocumentDefinition(&DefinitionParams{
-		TextDocumentPositionParams: params.TextDocumentPositionParams,
-		WorkDoneProgressParams:     params.WorkDoneProgressParams,
-		PartialResultParams:        params.PartialResultParams,
-	})
+	proj, typeInfo, obj, err := s.objectAtTextDocumentPosition(params.TextDocumentPositionParams)
+	if err != nil || obj == nil {
+		return nil, err
+	}
+	loc := s.objectDefinitionLocation(proj, typeInfo, obj)
+	if loc == nil {
+		return nil, nil
+	}
+	return *loc, nil
 }
 
 // See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.18/specification/#textDocument_definition
 func (s *Server) textDocumentDefinition(params *DefinitionParams) (any, error) {
+	proj, typeInfo, obj, err := s.objectAtTextDocumentPosition(params.TextDocumentPositionParams)
+	if err != nil || obj == nil {
+		return nil, err
+	}
+	loc := s.objectDefinitionLocation(proj, typeInfo, definitionObject(obj))
+	if loc == nil {
+		return nil, nil
+	}
+	return *loc, nil
+}
+
+// objectAtTextDocumentPosition returns the object referenced by the
+// identifier at the given text document position. It returns a nil object if
+// there is no such identifier, or if the identifier is blank or a synthetic
+// this.
+func (s *Server) objectAtTextDocumentPosition(params TextDocumentPositionParams) (*xgo.Project, *types.Info, gotypes.Object, error) {
 	proj := s.getProjWithFile()
 	if proj == nil {
-		return nil, nil
+		return nil, nil, nil, nil
 	}
 
 	spxFile, err := s.fromDocumentURI(params.TextDocument.URI)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get file path from document URI %q: %w", params.TextDocument.URI, err)
+		return nil, nil, nil, fmt.Errorf("failed to get file path from document URI %q: %w", params.TextDocument.URI, err)
 	}
 
 	astFile, _ := proj.ASTFile(spxFile)
 	if astFile == nil {
-		return nil, nil
+		return nil, nil, nil, nil
 	}
 	position := ToPosition(proj, astFile, params.Position)
 	typeInfo, _ := proj.TypeInfo()
 	if typeInfo == nil {
-		return nil, nil
+		return nil, nil, nil, nil
 	}
 	astPkg, _ := proj.ASTPackage()
 
 	ident, obj, _ := objectAtPosition(proj, typeInfo, astFile, position)
 	if xgoutil.IsBlankIdent(ident) || xgoutil.IsSyntheticThisIdent(proj.Fset, typeInfo, astPkg, ident) {
-		return nil, nil
+		return nil, nil, nil, nil
 	}
-	if obj == nil {
-		return nil, nil
+	return proj, typeInfo, obj, nil
+}
+
+// definitionObject returns the object defining obj. Unlike its declaration,
+// the definition of a type alias declared in the main package is the named
+// type it denotes, as long as that type is also declared in the main package.
+// For any other object, including interface methods whose concrete
+// implementations are unknown statically, it is obj itself.
+func definitionObject(obj gotypes.Object) gotypes.Object {
+	typeName, ok := obj.(*gotypes.TypeName)
+	if !ok || !typeName.IsAlias() || !xgoutil.IsInMainPkg(typeName) {
+		return obj
 	}
-	loc := s.objectDefinitionLocation(proj, typeInfo, obj)
-	if loc == nil {
-		return nil, nil
+	named, ok := gotypes.Unalias(typeName.Type()).(*gotypes.Named)
+	if !ok || !xgoutil.IsInMainPkg(named.Obj()) {
+		return obj
 	}
-	return *loc, nil
+	return named.Obj()
 }
 
 // See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.18/specification/#textDocument_typeDefinition
@@ -21,7 +21,7 @@ onStart => {
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		mainSpxMySpriteDef, err := s.textDocumentDefinition(&DefinitionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
@@ -57,7 +57,7 @@ onStart => {
 var x int
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		def, err := s.textDocumentDefinition(&DefinitionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
@@ -74,7 +74,7 @@ var x int
 			"main.spx": []byte(`
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		def, err := s.textDocumentDefinition(&DefinitionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
@@ -92,7 +92,7 @@ var x int
 const _ = 1
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		def, err := s.textDocumentDefinition(&DefinitionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
@@ -110,7 +110,7 @@ const _ = 1
 var x int
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		def, err := s.textDocumentDefinition(&DefinitionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
@@ -129,7 +129,7 @@ import "fmt"
 fmt.println "Hello, spx!"
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		def, err := s.textDocumentDefinition(&DefinitionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
@@ -156,7 +156,7 @@ import fmt2 "fmt"
 fmt2.println "Hello, spx!"
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		def, err := s.textDocumentDefinition(&DefinitionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
@@ -182,7 +182,7 @@ fmt2.println "Hello, spx!"
 var x int
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		def, err := s.textDocumentDefinition(&DefinitionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
@@ -208,7 +208,7 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		def, err := s.textDocumentDefinition(&DefinitionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
@@ -238,7 +238,7 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		def, err := s.textDocumentDefinition(&DefinitionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
@@ -270,7 +270,7 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		innerDef, err := s.textDocumentDefinition(&DefinitionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
@@ -335,7 +335,7 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		def, err := s.textDocumentDefinition(&DefinitionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
@@ -385,7 +385,7 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		def, err := s.textDocumentDefinition(&DefinitionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
@@ -419,7 +419,7 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		def, err := s.textDocumentDefinition(&DefinitionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
@@ -459,7 +459,7 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		def, err := s.textDocumentDefinition(&DefinitionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
@@ -480,6 +480,98 @@ onStart => {
 	})
 }
 
+func TestServerTextDocumentDeclaration(t *testing.T) {
+	t.Run("AliasType", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.spx": []byte(`
+type MyType struct{}
+type MyAlias = MyType
+var x MyAlias
+`),
+		}
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+		position := TextDocumentPositionParams{
+			TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+			Position:     Position{Line: 3, Character: 6},
+		}
+
+		decl, err := s.textDocumentDeclaration(&DeclarationParams{TextDocumentPositionParams: position})
+		require.NoError(t, err)
+		require.NotNil(t, decl)
+		assert.Equal(t, Location{
+			URI: "file:///main.spx",
+			Range: Range{
+				Start: Position{Line: 2, Character: 5},
+				End:   Position{Line: 2, Character: 12},
+			},
+		}, requireLocation(t, decl))
+
+		def, err := s.textDocumentDefinition(&DefinitionParams{TextDocumentPositionParams: position})
+		require.NoError(t, err)
+		require.NotNil(t, def)
+		assert.Equal(t, Location{
+			URI: "file:///main.spx",
+			Range: Range{
+				Start: Position{Line: 1, Character: 5},
+				End:   Position{Line: 1, Character: 11},
+			},
+		}, requireLocation(t, def))
+	})
+
+	t.Run("InterfaceMethod", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.spx": []byte(`
+type Speaker interface {
+	Speak() string
+}
+
+type Dog struct{}
+
+func (d Dog) Speak() string { return "woof" }
+
+var speaker Speaker = Dog{}
+
+onStart => {
+	echo speaker.Speak()
+}
+`),
+		}
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+		decl, err := s.textDocumentDeclaration(&DeclarationParams{
+			TextDocumentPositionParams: TextDocumentPositionParams{
+				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+				Position:     Position{Line: 12, Character: 15},
+			},
+		})
+		require.NoError(t, err)
+		require.NotNil(t, decl)
+		assert.Equal(t, Location{
+			URI: "file:///main.spx",
+			Range: Range{
+				Start: Position{Line: 2, Character: 1},
+				End:   Position{Line: 2, Character: 6},
+			},
+		}, requireLocation(t, decl))
+	})
+
+	t.Run("InvalidPosition", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.spx": []byte(`var x int`),
+		}
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+		decl, err := s.textDocumentDeclaration(&DeclarationParams{
+			TextDocumentPositionParams: TextDocumentPositionParams{
+				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+				Position:     Position{Line: 10, Character: 0},
+			},
+		})
+		require.NoError(t, err)
+		assert.Nil(t, decl)
+	})
+}
+
 func requireLocation(t *testing.T, v any) Location {
 	t.Helper()
 
@@ -498,7 +590,7 @@ type MyType struct {
 var x MyType
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		def, err := s.textDocumentTypeDefinition(&TypeDefinitionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
@@ -526,7 +618,7 @@ type MyAlias = MyType
 var x MyAlias
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		def, err := s.textDocumentTypeDefinition(&TypeDefinitionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
@@ -562,7 +654,7 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		def, err := s.textDocumentTypeDefinition(&TypeDefinitionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
@@ -592,7 +684,7 @@ var (
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		def, err := s.textDocumentTypeDefinition(&TypeDefinitionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
@@ -610,7 +702,7 @@ var (
 var x int
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		def, err := s.textDocumentTypeDefinition(&TypeDefinitionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
@@ -628,7 +720,7 @@ var x int
 var x int
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		def, err := s.textDocumentTypeDefinition(&TypeDefinitionParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
@@ -1,29 +1,79 @@
 package server
 
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/goplus/xgolsw/xgo"
+)
+
 // See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.18/specification#textDocument_diagnostic
-func (s *Server) textDocumentDiagnostic(params *DocumentDiagnosticParams) (*DocumentDiagnosticReport, error) {
-	result, err := s.compile()
+func (s *Server) textDocumentDiagnostic(ctx context.Context, params *DocumentDiagnosticParams) (*DocumentDiagnosticReport, error) {
+	resultID := diagnosticResultID(s.getProjWithFile())
+	if params.PreviousResultID == resultID {
+		return &DocumentDiagnosticReport{Value: RelatedUnchangedDocumentDiagnosticReport{
+			UnchangedDocumentDiagnosticReport: UnchangedDocumentDiagnosticReport{
+				Kind:     string(DiagnosticUnchanged),
+				ResultID: resultID,
+			},
+		}}, nil
+	}
+
+	result, err := s.compile(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	return &DocumentDiagnosticReport{Value: RelatedFullDocumentDiagnosticReport{
 		FullDocumentDiagnosticReport: FullDocumentDiagnosticReport{
-			Kind:  string(DiagnosticFull),
-			Items: result.diagnostics[params.TextDocument.URI],
+			Kind:     string(DiagnosticFull),
+			ResultID: resultID,
+			Items:    s.limitFileDiagnostics(result.diagnostics[NormalizeURI(params.TextDocument.URI)]),
 		},
 	}}, nil
 }
 
+// diagnosticResultID returns the result ID of the diagnostics computed for
+// proj. Since diagnostics of a file may depend on any other file in the
+// project, the ID covers the version and content of every file, so that it
+// changes whenever any file does.
+func diagnosticResultID(proj *xgo.Project) string {
+	files := maps.Collect(proj.Files())
+	h := sha256.New()
+	for _, path := range slices.Sorted(maps.Keys(files)) {
+		file := files[path]
+		hash := file.Hash()
+		fmt.Fprintf(h, "%s\x00%d\x00%x\x00", path, file.Version, hash)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
 // See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.18/specification#workspace_diagnostic
-func (s *Server) workspaceDiagnostic(params *WorkspaceDiagnosticParams) (*WorkspaceDiagnosticReport, error) {
-	result, err := s.compile()
+func (s *Server) workspaceDiagnostic(ctx context.Context, params *WorkspaceDiagnosticParams) (*WorkspaceDiagnosticReport, error) {
+	result, err := s.compile(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	items := make([]WorkspaceDocumentDiagnosticReport, 0, len(result.diagnostics))
-	for file, fileDiags := range result.diagnostics {
+	// Iterate in a stable order so that the total limit always keeps the
+	// same diagnostics.
+	files := slices.Sorted(maps.Keys(result.diagnostics))
+	remaining := s.options.MaxDiagnosticsTotal
+	items := make([]WorkspaceDocumentDiagnosticReport, 0, len(files))
+	for _, file := range files {
+		fileDiags := result.diagnostics[file]
+		limit := s.options.MaxDiagnosticsPerFile
+		if remaining >= 0 {
+			if limit < 0 || limit > remaining {
+				limit = remaining
+			}
+			remaining -= min(len(fileDiags), limit)
+		}
+		fileDiags = limitDiagnostics(fileDiags, limit)
 		items = append(items, WorkspaceDocumentDiagnosticReport{
 			Value: WorkspaceFullDocumentDiagnosticReport{
 				URI: DocumentURI(file),
@@ -36,3 +86,25 @@ func (s *Server) workspaceDiagnostic(params *WorkspaceDiagnosticParams) (*Worksp
 	}
 	return &WorkspaceDiagnosticReport{Items: items}, nil
 }
+
+// limitFileDiagnostics limits diagnostics reported for a single file according
+// to [ServerOptions.MaxDiagnosticsPerFile].
+func (s *Server) limitFileDiagnostics(diagnostics []Diagnostic) []Diagnostic {
+	return limitDiagnostics(diagnostics, s.options.MaxDiagnosticsPerFile)
+}
+
+// limitDiagnostics truncates diagnostics to at most limit items and appends a
+// summary diagnostic stating how many were dropped. A negative limit means no
+// limit. The returned slice is a new slice if truncation happens, so the
+// cached diagnostics are never modified.
+func limitDiagnostics(diagnostics []Diagnostic, limit int) []Diagnostic {
+	if limit < 0 || len(diagnostics) <= limit {
+		return diagnostics
+	}
+	limited := make([]Diagnostic, 0, limit+1)
+	limited = append(limited, diagnostics[:limit]...)
+	return append(limited, Diagnostic{
+		Severity: SeverityInformation,
+		Message:  fmt.Sprintf("%d more errors not shown", len(diagnostics)-limit),
+	})
+}
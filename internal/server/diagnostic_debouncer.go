@@ -0,0 +1,77 @@
+package server
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// DefaultDiagnosticDebounceDelay is the default value of
+// [ServerOptions.DiagnosticDebounceDelay].
+const DefaultDiagnosticDebounceDelay = 300 * time.Millisecond
+
+// DiagnosticDebouncer coalesces file changes that happen in quick succession,
+// e.g., while the user is typing, into a single diagnostic run.
+//
+// Every call to [DiagnosticDebouncer.Schedule] restarts the delay, so the run
+// happens once no file has changed for the whole delay. A non-positive delay
+// runs diagnostics as soon as possible.
+type DiagnosticDebouncer struct {
+	run func(paths []string)
+
+	mu      sync.Mutex // Guards the fields below.
+	delay   time.Duration
+	timer   *time.Timer
+	pending []string // Paths of changed files awaiting diagnostics, in order of first change.
+}
+
+// NewDiagnosticDebouncer creates a new [DiagnosticDebouncer] that calls run
+// with the paths of the changed files once they settle for delay.
+func NewDiagnosticDebouncer(delay time.Duration, run func(paths []string)) *DiagnosticDebouncer {
+	return &DiagnosticDebouncer{
+		run:   run,
+		delay: delay,
+	}
+}
+
+// SetDelay sets the delay used by subsequent calls to
+// [DiagnosticDebouncer.Schedule].
+func (d *DiagnosticDebouncer) SetDelay(delay time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.delay = delay
+}
+
+// Schedule records that the files at the given paths have changed and
+// (re)starts the delay before diagnostics are run for them.
+func (d *DiagnosticDebouncer) Schedule(paths ...string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, path := range paths {
+		if !slices.Contains(d.pending, path) {
+			d.pending = append(d.pending, path)
+		}
+	}
+	delay := max(d.delay, 0)
+	if d.timer == nil {
+		d.timer = time.AfterFunc(delay, d.Flush)
+	} else {
+		d.timer.Reset(delay)
+	}
+}
+
+// Flush immediately runs diagnostics for all pending files, if any, without
+// waiting for the delay to elapse.
+func (d *DiagnosticDebouncer) Flush() {
+	d.mu.Lock()
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+	paths := d.pending
+	d.pending = nil
+	d.mu.Unlock()
+
+	if len(paths) > 0 {
+		d.run(paths)
+	}
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/goplus/xgolsw/jsonrpc2"
+	"github.com/goplus/xgolsw/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runRecorder records the calls made by a [DiagnosticDebouncer].
+type runRecorder struct {
+	mu    sync.Mutex
+	calls [][]string
+}
+
+func (r *runRecorder) run(paths []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, paths)
+}
+
+func (r *runRecorder) getCalls() [][]string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([][]string(nil), r.calls...)
+}
+
+func TestDiagnosticDebouncer(t *testing.T) {
+	t.Run("CoalescesRapidChanges", func(t *testing.T) {
+		recorder := &runRecorder{}
+		d := NewDiagnosticDebouncer(200*time.Millisecond, recorder.run)
+
+		d.Schedule("main.spx")
+		time.Sleep(50 * time.Millisecond)
+		d.Schedule("Sprite.spx")
+		time.Sleep(50 * time.Millisecond)
+		d.Schedule("main.spx")
+		assert.Empty(t, recorder.getCalls())
+
+		require.Eventually(t, func() bool {
+			return len(recorder.getCalls()) > 0
+		}, time.Second, 10*time.Millisecond)
+		time.Sleep(300 * time.Millisecond)
+		assert.Equal(t, [][]string{{"main.spx", "Sprite.spx"}}, recorder.getCalls())
+	})
+
+	t.Run("Flush", func(t *testing.T) {
+		recorder := &runRecorder{}
+		d := NewDiagnosticDebouncer(200*time.Millisecond, recorder.run)
+
+		d.Schedule("main.spx")
+		d.Flush()
+		assert.Equal(t, [][]string{{"main.spx"}}, recorder.getCalls())
+
+		time.Sleep(300 * time.Millisecond)
+		assert.Len(t, recorder.getCalls(), 1)
+	})
+
+	t.Run("FlushWithoutPendingChanges", func(t *testing.T) {
+		recorder := &runRecorder{}
+		d := NewDiagnosticDebouncer(200*time.Millisecond, recorder.run)
+
+		d.Flush()
+		assert.Empty(t, recorder.getCalls())
+	})
+
+	t.Run("NegativeDelay", func(t *testing.T) {
+		recorder := &runRecorder{}
+		d := NewDiagnosticDebouncer(-1, recorder.run)
+
+		d.Schedule("main.spx")
+		require.Eventually(t, func() bool {
+			return len(recorder.getCalls()) == 1
+		}, time.Second, time.Millisecond)
+	})
+}
+
+func TestServerDiagnosticDebounce(t *testing.T) {
+	m := map[string][]byte{
+		"main.spx":          []byte(`echo "Hello"`),
+		"assets/index.json": []byte(`{}`),
+	}
+	replier := newMockReplier()
+	s := New(newProjectWithoutModTime(m), replier, fileMapGetter(m), &MockScheduler{}, &ServerOptions{
+		DiagnosticDebounceDelay: 200 * time.Millisecond,
+	})
+
+	for i, content := range []string{`echo "H"`, `echo "He"`, `echo "Hel"`} {
+		err := s.didChange(&DidChangeTextDocumentParams{
+			TextDocument: protocol.VersionedTextDocumentIdentifier{
+				TextDocumentIdentifier: TextDocumentIdentifier{URI: "file:///main.spx"},
+				Version:                int32(i + 1),
+			},
+			ContentChanges: []protocol.TextDocumentContentChangeEvent{{Text: content}},
+		})
+		require.NoError(t, err)
+		time.Sleep(30 * time.Millisecond)
+	}
+
+	replier.waitForMessages(1, time.Second)
+	time.Sleep(300 * time.Millisecond)
+	messages := replier.getMessages()
+	require.Len(t, messages, 1)
+	n, ok := messages[0].(*jsonrpc2.Notification)
+	require.True(t, ok)
+	assert.Equal(t, "textDocument/publishDiagnostics", n.Method())
+}
@@ -1,8 +1,11 @@
 package server
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/goplus/xgolsw/xgo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -63,12 +66,12 @@ func requireWorkspaceFullDocumentDiagnosticReport(t *testing.T, item WorkspaceDo
 
 func TestServerTextDocumentDiagnostic(t *testing.T) {
 	t.Run("Normal", func(t *testing.T) {
-		s := New(newProjectWithoutModTime(newTestFileMap()), nil, fileMapGetter(newTestFileMap()), &MockScheduler{})
+		s := New(newProjectWithoutModTime(newTestFileMap()), nil, fileMapGetter(newTestFileMap()), &MockScheduler{}, nil)
 		params := &DocumentDiagnosticParams{
 			TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 		}
 
-		report, err := s.textDocumentDiagnostic(params)
+		report, err := s.textDocumentDiagnostic(t.Context(), params)
 		require.NoError(t, err)
 		require.NotNil(t, report)
 
@@ -77,6 +80,55 @@ func TestServerTextDocumentDiagnostic(t *testing.T) {
 		assert.Empty(t, fullReport.Items)
 	})
 
+	t.Run("Unchanged", func(t *testing.T) {
+		fileMap := newTestFileMap()
+		s := New(newProjectWithoutModTime(fileMap), nil, fileMapGetter(fileMap), &MockScheduler{}, nil)
+		params := &DocumentDiagnosticParams{
+			TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+		}
+
+		report, err := s.textDocumentDiagnostic(t.Context(), params)
+		require.NoError(t, err)
+		fullReport := requireRelatedFullDocumentDiagnosticReport(t, report)
+		require.NotEmpty(t, fullReport.ResultID)
+
+		params.PreviousResultID = fullReport.ResultID
+		report, err = s.textDocumentDiagnostic(t.Context(), params)
+		require.NoError(t, err)
+		unchangedReport, ok := report.Value.(RelatedUnchangedDocumentDiagnosticReport)
+		require.True(t, ok, "want RelatedUnchangedDocumentDiagnosticReport, got %T", report.Value)
+		assert.Equal(t, string(DiagnosticUnchanged), unchangedReport.Kind)
+		assert.Equal(t, fullReport.ResultID, unchangedReport.ResultID)
+
+		fileMap["main.spx"] = []byte(`var x int`)
+		report, err = s.textDocumentDiagnostic(t.Context(), params)
+		require.NoError(t, err)
+		changedReport := requireRelatedFullDocumentDiagnosticReport(t, report)
+		assert.NotEqual(t, fullReport.ResultID, changedReport.ResultID)
+	})
+
+	t.Run("TypeErrorWithoutDiagnosticsCache", func(t *testing.T) {
+		fileMap := newTestFileMap()
+		fileMap["main.spx"] = []byte(`var x int = "hello"`)
+		files := make(map[string]*xgo.File)
+		for path, content := range fileMap {
+			files[path] = &xgo.File{Content: content}
+		}
+		proj := xgo.NewProject(nil, files, xgo.FeatAll&^xgo.FeatDiagnosticsCache)
+		s := New(proj, nil, fileMapGetter(fileMap), &MockScheduler{}, nil)
+		params := &DocumentDiagnosticParams{
+			TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+		}
+
+		report, err := s.textDocumentDiagnostic(t.Context(), params)
+		require.NoError(t, err)
+
+		fullReport := requireRelatedFullDocumentDiagnosticReport(t, report)
+		require.Len(t, fullReport.Items, 1)
+		assert.Equal(t, SeverityError, fullReport.Items[0].Severity)
+		assert.Contains(t, fullReport.Items[0].Message, `cannot use "hello"`)
+	})
+
 	t.Run("ParseError", func(t *testing.T) {
 		fileMap := newTestFileMap()
 		fileMap["main.spx"] = []byte(`
@@ -84,12 +136,12 @@ func TestServerTextDocumentDiagnostic(t *testing.T) {
 var (
 	Foobar string
 `)
-		s := New(newProjectWithoutModTime(fileMap), nil, fileMapGetter(fileMap), &MockScheduler{})
+		s := New(newProjectWithoutModTime(fileMap), nil, fileMapGetter(fileMap), &MockScheduler{}, nil)
 		params := &DocumentDiagnosticParams{
 			TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 		}
 
-		report, err := s.textDocumentDiagnostic(params)
+		report, err := s.textDocumentDiagnostic(t.Context(), params)
 		require.NoError(t, err)
 		require.NotNil(t, report)
 
@@ -114,6 +166,35 @@ var (
 		})
 	})
 
+	t.Run("ShadowedSprite", func(t *testing.T) {
+		fileMap := newTestFileMap()
+		fileMap["main.spx"] = []byte(`
+onStart => {
+	var MyAircraft int
+	echo MyAircraft
+}
+`)
+		s := New(newProjectWithoutModTime(fileMap), nil, fileMapGetter(fileMap), &MockScheduler{}, nil)
+		params := &DocumentDiagnosticParams{
+			TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+		}
+
+		report, err := s.textDocumentDiagnostic(t.Context(), params)
+		require.NoError(t, err)
+		require.NotNil(t, report)
+
+		fullReport := requireRelatedFullDocumentDiagnosticReport(t, report)
+		assert.Equal(t, []Diagnostic{{
+			Severity: SeverityWarning,
+			Code:     "shadow-sprite",
+			Message:  "variable 'MyAircraft' shadows sprite auto-binding",
+			Range: Range{
+				Start: Position{Line: 2, Character: 5},
+				End:   Position{Line: 2, Character: 15},
+			},
+		}}, fullReport.Items)
+	})
+
 	t.Run("TypeError", func(t *testing.T) {
 		fileMap := newTestFileMap()
 		// case for https://github.com/goplus/xgolsw/issues/163
@@ -127,12 +208,12 @@ onStart => {
 	x, y = calcPos()
 }
 `)
-		s := New(newProjectWithoutModTime(fileMap), nil, fileMapGetter(fileMap), &MockScheduler{})
+		s := New(newProjectWithoutModTime(fileMap), nil, fileMapGetter(fileMap), &MockScheduler{}, nil)
 		params := &DocumentDiagnosticParams{
 			TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 		}
 
-		report, err := s.textDocumentDiagnostic(params)
+		report, err := s.textDocumentDiagnostic(t.Context(), params)
 		require.NoError(t, err)
 		require.NotNil(t, report)
 
@@ -156,12 +237,12 @@ func getValue() int {
 	x++
 }
 `)
-		s := New(newProjectWithoutModTime(fileMap), nil, fileMapGetter(fileMap), &MockScheduler{})
+		s := New(newProjectWithoutModTime(fileMap), nil, fileMapGetter(fileMap), &MockScheduler{}, nil)
 		params := &DocumentDiagnosticParams{
 			TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 		}
 
-		report, err := s.textDocumentDiagnostic(params)
+		report, err := s.textDocumentDiagnostic(t.Context(), params)
 		require.NoError(t, err)
 		require.NotNil(t, report)
 
@@ -180,12 +261,12 @@ func getValue() int {
 	t.Run("NonSpxFile", func(t *testing.T) {
 		fileMap := newTestFileMap()
 		fileMap["main.xgo"] = []byte(`echo "Hello, XGo!"`)
-		s := New(newProjectWithoutModTime(fileMap), nil, fileMapGetter(fileMap), &MockScheduler{})
+		s := New(newProjectWithoutModTime(fileMap), nil, fileMapGetter(fileMap), &MockScheduler{}, nil)
 		params := &DocumentDiagnosticParams{
 			TextDocument: TextDocumentIdentifier{URI: "file:///main.xgo"},
 		}
 
-		report, err := s.textDocumentDiagnostic(params)
+		report, err := s.textDocumentDiagnostic(t.Context(), params)
 		require.NoError(t, err)
 		require.NotNil(t, report)
 
@@ -197,12 +278,12 @@ func getValue() int {
 	t.Run("NonMainPackageDecl", func(t *testing.T) {
 		fileMap := newTestFileMap()
 		fileMap["main.spx"] = []byte("package nonmain")
-		s := New(newProjectWithoutModTime(fileMap), nil, fileMapGetter(fileMap), &MockScheduler{})
+		s := New(newProjectWithoutModTime(fileMap), nil, fileMapGetter(fileMap), &MockScheduler{}, nil)
 		params := &DocumentDiagnosticParams{
 			TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 		}
 
-		report, err := s.textDocumentDiagnostic(params)
+		report, err := s.textDocumentDiagnostic(t.Context(), params)
 		require.NoError(t, err)
 		require.NotNil(t, report)
 
@@ -220,12 +301,12 @@ func getValue() int {
 	})
 
 	t.Run("FileNotFound", func(t *testing.T) {
-		s := New(newProjectWithoutModTime(newTestFileMap()), nil, fileMapGetter(newTestFileMap()), &MockScheduler{})
+		s := New(newProjectWithoutModTime(newTestFileMap()), nil, fileMapGetter(newTestFileMap()), &MockScheduler{}, nil)
 		params := &DocumentDiagnosticParams{
 			TextDocument: TextDocumentIdentifier{URI: "file:///notexist.spx"},
 		}
 
-		report, err := s.textDocumentDiagnostic(params)
+		report, err := s.textDocumentDiagnostic(t.Context(), params)
 		require.NoError(t, err)
 		require.NotNil(t, report)
 
@@ -237,9 +318,9 @@ func getValue() int {
 
 func TestServerWorkspaceDiagnostic(t *testing.T) {
 	t.Run("Normal", func(t *testing.T) {
-		s := New(newProjectWithoutModTime(newTestFileMap()), nil, fileMapGetter(newTestFileMap()), &MockScheduler{})
+		s := New(newProjectWithoutModTime(newTestFileMap()), nil, fileMapGetter(newTestFileMap()), &MockScheduler{}, nil)
 
-		report, err := s.workspaceDiagnostic(&WorkspaceDiagnosticParams{})
+		report, err := s.workspaceDiagnostic(t.Context(), &WorkspaceDiagnosticParams{})
 		require.NoError(t, err)
 		require.NotNil(t, report)
 		assert.Len(t, report.Items, 3)
@@ -268,9 +349,9 @@ var (
 			"assets/index.json":                    []byte(`{}`),
 			"assets/sprites/MyAircraft/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		report, err := s.workspaceDiagnostic(&WorkspaceDiagnosticParams{})
+		report, err := s.workspaceDiagnostic(t.Context(), &WorkspaceDiagnosticParams{})
 		require.NoError(t, err)
 		require.NotNil(t, report)
 		assert.Len(t, report.Items, 2)
@@ -301,9 +382,9 @@ var (
 	})
 
 	t.Run("EmptyWorkspace", func(t *testing.T) {
-		s := New(newProjectWithoutModTime(map[string][]byte{}), nil, fileMapGetter(map[string][]byte{}), &MockScheduler{})
+		s := New(newProjectWithoutModTime(map[string][]byte{}), nil, fileMapGetter(map[string][]byte{}), &MockScheduler{}, nil)
 
-		report, err := s.workspaceDiagnostic(&WorkspaceDiagnosticParams{})
+		report, err := s.workspaceDiagnostic(t.Context(), &WorkspaceDiagnosticParams{})
 		require.EqualError(t, err, "no valid main.spx file found in main package")
 		require.Nil(t, report)
 	})
@@ -330,9 +411,9 @@ onStart => {
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		report, err := s.workspaceDiagnostic(&WorkspaceDiagnosticParams{})
+		report, err := s.workspaceDiagnostic(t.Context(), &WorkspaceDiagnosticParams{})
 		require.NoError(t, err)
 		require.NotNil(t, report)
 		assert.Len(t, report.Items, 2)
@@ -421,9 +502,9 @@ onStart => {
 `),
 			"assets/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		report, err := s.workspaceDiagnostic(&WorkspaceDiagnosticParams{})
+		report, err := s.workspaceDiagnostic(t.Context(), &WorkspaceDiagnosticParams{})
 		require.NoError(t, err)
 		require.NotNil(t, report)
 		require.Len(t, report.Items, 1)
@@ -478,9 +559,9 @@ onStart => {
 `),
 			"assets/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		report, err := s.workspaceDiagnostic(&WorkspaceDiagnosticParams{})
+		report, err := s.workspaceDiagnostic(t.Context(), &WorkspaceDiagnosticParams{})
 		require.NoError(t, err)
 		require.NotNil(t, report)
 		require.Len(t, report.Items, 1)
@@ -519,9 +600,9 @@ onStart => {
 `),
 			"assets/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		report, err := s.workspaceDiagnostic(&WorkspaceDiagnosticParams{})
+		report, err := s.workspaceDiagnostic(t.Context(), &WorkspaceDiagnosticParams{})
 		require.NoError(t, err)
 		require.NotNil(t, report)
 		require.Len(t, report.Items, 1)
@@ -555,9 +636,9 @@ onStart => {
 `),
 			"assets/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		report, err := s.workspaceDiagnostic(&WorkspaceDiagnosticParams{})
+		report, err := s.workspaceDiagnostic(t.Context(), &WorkspaceDiagnosticParams{})
 		require.NoError(t, err)
 		require.NotNil(t, report)
 		assert.Len(t, report.Items, 2)
@@ -622,9 +703,9 @@ onStart => {
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		report, err := s.workspaceDiagnostic(&WorkspaceDiagnosticParams{})
+		report, err := s.workspaceDiagnostic(t.Context(), &WorkspaceDiagnosticParams{})
 		require.NoError(t, err)
 		require.NotNil(t, report)
 		assert.Len(t, report.Items, 2)
@@ -671,9 +752,9 @@ onStart => {
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		report, err := s.workspaceDiagnostic(&WorkspaceDiagnosticParams{})
+		report, err := s.workspaceDiagnostic(t.Context(), &WorkspaceDiagnosticParams{})
 		require.NoError(t, err)
 		require.NotNil(t, report)
 		assert.Len(t, report.Items, 2)
@@ -718,9 +799,9 @@ onStart => {
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		report, err := s.workspaceDiagnostic(&WorkspaceDiagnosticParams{})
+		report, err := s.workspaceDiagnostic(t.Context(), &WorkspaceDiagnosticParams{})
 		require.NoError(t, err)
 		require.NotNil(t, report)
 		assert.Len(t, report.Items, 2)
@@ -769,9 +850,9 @@ onStart => {
 `),
 			"assets/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		report, err := s.workspaceDiagnostic(&WorkspaceDiagnosticParams{})
+		report, err := s.workspaceDiagnostic(t.Context(), &WorkspaceDiagnosticParams{})
 		require.NoError(t, err)
 		require.NotNil(t, report)
 		assert.Len(t, report.Items, 2)
@@ -825,9 +906,9 @@ onStart => {
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		report, err := s.workspaceDiagnostic(&WorkspaceDiagnosticParams{})
+		report, err := s.workspaceDiagnostic(t.Context(), &WorkspaceDiagnosticParams{})
 		require.NoError(t, err)
 		require.NotNil(t, report)
 		assert.Len(t, report.Items, 2)
@@ -848,9 +929,9 @@ onKey [KeyRight, KeyUp, KeyDown], => {}
 `),
 			"assets/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		report, err := s.workspaceDiagnostic(&WorkspaceDiagnosticParams{})
+		report, err := s.workspaceDiagnostic(t.Context(), &WorkspaceDiagnosticParams{})
 		require.NoError(t, err)
 		require.NotNil(t, report)
 		assert.Len(t, report.Items, 1)
@@ -861,3 +942,94 @@ onKey [KeyRight, KeyUp, KeyDown], => {}
 		}
 	})
 }
+
+func newTooManyDiagnosticsFileMap(n int) map[string][]byte {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	for i := range n {
+		fmt.Fprintf(&sb, "play \"Sound%d\"\n", i)
+	}
+	return map[string][]byte{
+		"main.spx":                           []byte(sb.String()),
+		"MySprite.spx":                       []byte(sb.String()),
+		"assets/index.json":                  []byte(`{}`),
+		"assets/sprites/MySprite/index.json": []byte(`{}`),
+	}
+}
+
+func TestServerDiagnosticLimits(t *testing.T) {
+	t.Run("DefaultMaxDiagnosticsPerFile", func(t *testing.T) {
+		m := newTooManyDiagnosticsFileMap(100)
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+		report, err := s.textDocumentDiagnostic(t.Context(), &DocumentDiagnosticParams{
+			TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+		})
+		require.NoError(t, err)
+		require.NotNil(t, report)
+		fullReport := requireRelatedFullDocumentDiagnosticReport(t, report)
+		require.Len(t, fullReport.Items, DefaultMaxDiagnosticsPerFile+1)
+		for _, diag := range fullReport.Items[:DefaultMaxDiagnosticsPerFile] {
+			assert.Equal(t, SeverityError, diag.Severity)
+		}
+		assert.Equal(t, Diagnostic{
+			Severity: SeverityInformation,
+			Message:  "50 more errors not shown",
+		}, fullReport.Items[DefaultMaxDiagnosticsPerFile])
+	})
+
+	t.Run("CustomMaxDiagnosticsPerFile", func(t *testing.T) {
+		m := newTooManyDiagnosticsFileMap(100)
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, &ServerOptions{MaxDiagnosticsPerFile: 10})
+
+		report, err := s.workspaceDiagnostic(t.Context(), &WorkspaceDiagnosticParams{})
+		require.NoError(t, err)
+		require.NotNil(t, report)
+		require.Len(t, report.Items, 2)
+		for _, item := range report.Items {
+			fullReport := requireWorkspaceFullDocumentDiagnosticReport(t, item)
+			require.Len(t, fullReport.Items, 11)
+			assert.Equal(t, "90 more errors not shown", fullReport.Items[10].Message)
+		}
+	})
+
+	t.Run("NoLimit", func(t *testing.T) {
+		m := newTooManyDiagnosticsFileMap(100)
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, &ServerOptions{
+			MaxDiagnosticsPerFile: -1,
+			MaxDiagnosticsTotal:   -1,
+		})
+
+		report, err := s.workspaceDiagnostic(t.Context(), &WorkspaceDiagnosticParams{})
+		require.NoError(t, err)
+		require.NotNil(t, report)
+		require.Len(t, report.Items, 2)
+		for _, item := range report.Items {
+			fullReport := requireWorkspaceFullDocumentDiagnosticReport(t, item)
+			assert.Len(t, fullReport.Items, 100)
+		}
+	})
+
+	t.Run("MaxDiagnosticsTotal", func(t *testing.T) {
+		m := newTooManyDiagnosticsFileMap(100)
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, &ServerOptions{
+			MaxDiagnosticsPerFile: -1,
+			MaxDiagnosticsTotal:   150,
+		})
+
+		report, err := s.workspaceDiagnostic(t.Context(), &WorkspaceDiagnosticParams{})
+		require.NoError(t, err)
+		require.NotNil(t, report)
+		require.Len(t, report.Items, 2)
+
+		// Files are reported in sorted order, so file:///MySprite.spx comes first.
+		first := requireWorkspaceFullDocumentDiagnosticReport(t, report.Items[0])
+		assert.Equal(t, DocumentURI("file:///MySprite.spx"), first.URI)
+		assert.Len(t, first.Items, 100)
+
+		second := requireWorkspaceFullDocumentDiagnosticReport(t, report.Items[1])
+		assert.Equal(t, DocumentURI("file:///main.spx"), second.URI)
+		require.Len(t, second.Items, 51)
+		assert.Equal(t, "50 more errors not shown", second.Items[50].Message)
+	})
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"cmp"
+	"context"
 	"slices"
 
 	"github.com/goplus/xgo/ast"
@@ -9,19 +10,19 @@ import (
 )
 
 // See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.18/specification#textDocument_documentLink
-func (s *Server) textDocumentDocumentLink(params *DocumentLinkParams) ([]DocumentLink, error) {
-	result, spxFile, astFile, err := s.compileAndGetASTFileForDocumentURI(params.TextDocument.URI)
+func (s *Server) textDocumentDocumentLink(ctx context.Context, params *DocumentLinkParams) ([]DocumentLink, error) {
+	result, err := s.compileAndGetASTFileForDocumentURI(ctx, params.TextDocument.URI)
 	if err != nil {
 		return nil, err
 	}
-	if astFile == nil {
+	if !result.IsUsable() {
 		return nil, nil
 	}
 
 	// Add links for spx resource references.
 	links := make([]DocumentLink, 0, len(result.spxResourceRefs))
 	for _, spxResourceRef := range result.spxResourceRefs {
-		if xgoutil.NodeFilename(result.proj.Fset, spxResourceRef.Node) != spxFile {
+		if xgoutil.NodeFilename(result.proj.Fset, spxResourceRef.Node) != result.spxFile {
 			continue
 		}
 		if !result.spxResourceSet.Contains(spxResourceRef.ID) {
@@ -46,7 +47,7 @@ func (s *Server) textDocumentDocumentLink(params *DocumentLinkParams) ([]Documen
 	// Add links for spx definitions.
 	links = slices.Grow(links, len(typeInfo.Defs)+len(typeInfo.Uses))
 	addLinksForIdent := func(ident *ast.Ident) {
-		if ident.Implicit() || xgoutil.NodeFilename(result.proj.Fset, ident) != spxFile {
+		if ident.Implicit() || xgoutil.NodeFilename(result.proj.Fset, ident) != result.spxFile {
 			return
 		}
 		if xgoutil.IsBlankIdent(ident) || xgoutil.IsSyntheticThisIdent(result.proj.Fset, typeInfo, astPkg, ident) {
@@ -62,7 +63,7 @@ func (s *Server) textDocumentDocumentLink(params *DocumentLinkParams) ([]Documen
 	for ident := range typeInfo.Uses {
 		addLinksForIdent(ident)
 	}
-	links = append(links, kwargDocumentLinks(result, astFile)...)
+	links = append(links, kwargDocumentLinks(result.compileResult, result.astFile)...)
 	sortDocumentLinks(links)
 	return links, nil
 }
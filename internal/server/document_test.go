@@ -29,9 +29,9 @@ onStart => {
 			"assets/sprites/MySprite/index.json": []byte(`{"costumes":[{"name":"costume1"}],"fAnimations":{"anim1":{}}}`),
 			"assets/sounds/MySound/index.json":   []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		linksForMainSpx, err := s.textDocumentDocumentLink(&DocumentLinkParams{
+		linksForMainSpx, err := s.textDocumentDocumentLink(t.Context(), &DocumentLinkParams{
 			TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 		})
 		require.NoError(t, err)
@@ -84,7 +84,7 @@ onStart => {
 				Kind: SpxResourceRefKindConstantReference,
 			},
 		})
-		linksForMySpriteSpx, err := s.textDocumentDocumentLink(&DocumentLinkParams{
+		linksForMySpriteSpx, err := s.textDocumentDocumentLink(t.Context(), &DocumentLinkParams{
 			TextDocument: TextDocumentIdentifier{URI: "file:///MySprite.spx"},
 		})
 		require.NoError(t, err)
@@ -208,9 +208,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		links, err := s.textDocumentDocumentLink(&DocumentLinkParams{
+		links, err := s.textDocumentDocumentLink(t.Context(), &DocumentLinkParams{
 			TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 		})
 		require.NoError(t, err)
@@ -272,9 +272,9 @@ onStart => {
 			"assets/sounds/MapSound/index.json":       []byte(`{}`),
 			"assets/sounds/InterfaceSound/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		links, err := s.textDocumentDocumentLink(&DocumentLinkParams{
+		links, err := s.textDocumentDocumentLink(t.Context(), &DocumentLinkParams{
 			TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 		})
 		require.NoError(t, err)
@@ -317,9 +317,9 @@ onStart => {
 			"assets/sounds/OverloadSound/index.json":     []byte(`{}`),
 			"assets/sounds/UnreferencedSound/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		links, err := s.textDocumentDocumentLink(&DocumentLinkParams{
+		links, err := s.textDocumentDocumentLink(t.Context(), &DocumentLinkParams{
 			TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 		})
 		require.NoError(t, err)
@@ -338,9 +338,9 @@ onStart => {
 		m := map[string][]byte{
 			"main.xgo": []byte(`echo "Hello, XGo!"`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		links, err := s.textDocumentDocumentLink(&DocumentLinkParams{
+		links, err := s.textDocumentDocumentLink(t.Context(), &DocumentLinkParams{
 			TextDocument: TextDocumentIdentifier{URI: "file:///main.xgo"},
 		})
 		assert.EqualError(t, err, `file "main.xgo" does not have .spx extension`)
@@ -348,9 +348,9 @@ onStart => {
 	})
 
 	t.Run("FileNotFound", func(t *testing.T) {
-		s := New(newProjectWithoutModTime(map[string][]byte{}), nil, fileMapGetter(map[string][]byte{}), &MockScheduler{})
+		s := New(newProjectWithoutModTime(map[string][]byte{}), nil, fileMapGetter(map[string][]byte{}), &MockScheduler{}, nil)
 
-		links, err := s.textDocumentDocumentLink(&DocumentLinkParams{
+		links, err := s.textDocumentDocumentLink(t.Context(), &DocumentLinkParams{
 			TextDocument: TextDocumentIdentifier{URI: "file:///notexist.spx"},
 		})
 		assert.ErrorIs(t, err, errNoMainSpxFile)
@@ -367,9 +367,9 @@ const (
 			"assets/index.json":                []byte(`{}`),
 			"assets/sounds/MySound/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		links, err := s.textDocumentDocumentLink(&DocumentLinkParams{
+		links, err := s.textDocumentDocumentLink(t.Context(), &DocumentLinkParams{
 			TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 		})
 		require.NoError(t, err)
@@ -448,9 +448,9 @@ func getMixedTypesMySprite() (int, SpriteCostumeName, string, SpriteAnimationNam
 			"assets/sprites/MySprite/index.json": []byte(`{"costumes":[{"name":"costume1"}],"fAnimations":{"anim1":{}}}`),
 			"assets/sounds/MySound/index.json":   []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		linksForMainSpx, err := s.textDocumentDocumentLink(&DocumentLinkParams{
+		linksForMainSpx, err := s.textDocumentDocumentLink(t.Context(), &DocumentLinkParams{
 			TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 		})
 		require.NoError(t, err)
@@ -535,7 +535,7 @@ func getMixedTypesMySprite() (int, SpriteCostumeName, string, SpriteAnimationNam
 			},
 		})
 
-		linksForMySprite, err := s.textDocumentDocumentLink(&DocumentLinkParams{
+		linksForMySprite, err := s.textDocumentDocumentLink(t.Context(), &DocumentLinkParams{
 			TextDocument: TextDocumentIdentifier{URI: "file:///MySprite.spx"},
 		})
 		require.NoError(t, err)
@@ -634,9 +634,9 @@ setup backdrop = "backdrop1"
 `),
 			"assets/index.json": []byte(`{"backdrops":[{"name":"backdrop1"}]}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		links, err := s.textDocumentDocumentLink(&DocumentLinkParams{
+		links, err := s.textDocumentDocumentLink(t.Context(), &DocumentLinkParams{
 			TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 		})
 		require.NoError(t, err)
@@ -657,9 +657,9 @@ setup backdrop = "backdrop1"
 			"main.spx":          []byte(`type`),
 			"assets/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		links, err := s.textDocumentDocumentLink(&DocumentLinkParams{
+		links, err := s.textDocumentDocumentLink(t.Context(), &DocumentLinkParams{
 			TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 		})
 		require.NoError(t, err)
@@ -673,9 +673,9 @@ const _ = 1
 `),
 			"assets/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		links, err := s.textDocumentDocumentLink(&DocumentLinkParams{
+		links, err := s.textDocumentDocumentLink(t.Context(), &DocumentLinkParams{
 			TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 		})
 		require.NoError(t, err)
@@ -701,7 +701,7 @@ onStart => {
 			"assets/index.json":                  []byte(`{"backdrops":[{"name":"backdrop1"}],"zorder":[]}`),
 			"assets/sprites/MySprite/index.json": []byte(`{"costumes":[],"fAnimations":{}}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		collectTargets := func(links []DocumentLink) []string {
 			t.Helper()
@@ -715,7 +715,7 @@ onStart => {
 			return targets
 		}
 
-		linksForMainSpx, err := s.textDocumentDocumentLink(&DocumentLinkParams{
+		linksForMainSpx, err := s.textDocumentDocumentLink(t.Context(), &DocumentLinkParams{
 			TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 		})
 		require.NoError(t, err)
@@ -723,7 +723,7 @@ onStart => {
 		assert.Contains(t, targetsForMainSpx, "spx://resources/backdrops/backdrop1")
 		assert.NotContains(t, targetsForMainSpx, "spx://resources/backdrops/missingBackdrop")
 
-		linksForMySpriteSpx, err := s.textDocumentDocumentLink(&DocumentLinkParams{
+		linksForMySpriteSpx, err := s.textDocumentDocumentLink(t.Context(), &DocumentLinkParams{
 			TextDocument: TextDocumentIdentifier{URI: "file:///MySprite.spx"},
 		})
 		require.NoError(t, err)
@@ -119,7 +119,7 @@ func (s *Server) formatSpxXGo(snapshot *xgo.Project, spxFile string) ([]byte, er
 
 // formatSpxLambda formats an spx source file by eliminating unused lambda parameters.
 func (s *Server) formatSpxLambda(snapshot *xgo.Project, spxFile string) ([]byte, error) {
-	snapshot.UpdateFiles(s.fileMapGetter())
+	snapshot.UpdateFiles(s.getFileMap())
 	astFile, _ := snapshot.ASTFile(spxFile)
 	if astFile == nil {
 		return nil, nil
@@ -232,8 +232,8 @@ func (s *Server) formatSpxDecls(snapshot *xgo.Project, spxFile string) ([]byte,
 		if doc := getDeclDoc(decl); doc != nil {
 			processedComments[doc] = struct{}{}
 		}
-		startLine := fset.Position(decl.Pos()).Line
-		endLine := fset.Position(decl.End()).Line
+		declStart, declEnd := xgoutil.TokenRange(fset, decl)
+		startLine, endLine := declStart.Line, declEnd.Line
 		for _, cg := range astFile.Comments {
 			if _, ok := processedComments[cg]; ok {
 				continue
@@ -327,9 +327,8 @@ func (s *Server) formatSpxDecls(snapshot *xgo.Project, spxFile string) ([]byte,
 
 				var doc []byte
 				if varBlock.Doc != nil && len(varBlock.Doc.List) > 0 {
-					docStart := fset.Position(varBlock.Doc.Pos()).Offset
-					docEnd := fset.Position(varBlock.Doc.End()).Offset
-					doc = astFile.Code[docStart:docEnd]
+					docStart, docEnd := xgoutil.TokenRange(fset, varBlock.Doc)
+					doc = astFile.Code[docStart.Offset:docEnd.Offset]
 				}
 
 				if doc != nil && varBlock.Lparen.IsValid() && (i > 0 || len(currentVarBlocks) == 1) {
@@ -353,9 +352,8 @@ func (s *Server) formatSpxDecls(snapshot *xgo.Project, spxFile string) ([]byte,
 				var bodyStartPos token.Pos
 				if varBlock.Lparen.IsValid() {
 					if cg := findInlineComments(varBlock.Lparen); cg != nil {
-						cgStart := fset.Position(cg.Pos()).Offset
-						cgEnd := fset.Position(cg.End()).Offset
-						formattedBuf.Write(astFile.Code[cgStart:cgEnd])
+						cgStart, cgEnd := xgoutil.TokenRange(fset, cg)
+						formattedBuf.Write(astFile.Code[cgStart.Offset:cgEnd.Offset])
 						formattedBuf.WriteByte('\n')
 						if i > 0 {
 							formattedBuf.WriteByte('\n')
@@ -385,9 +383,8 @@ func (s *Server) formatSpxDecls(snapshot *xgo.Project, spxFile string) ([]byte,
 				var trailingComments []byte
 				if varBlock.Rparen.IsValid() {
 					if cg := findInlineComments(varBlock.Rparen); cg != nil {
-						cgStart := fset.Position(cg.Pos()).Offset
-						cgEnd := fset.Position(cg.End()).Offset
-						trailingComments = astFile.Code[cgStart:cgEnd]
+						cgStart, cgEnd := xgoutil.TokenRange(fset, cg)
+						trailingComments = astFile.Code[cgStart.Offset:cgEnd.Offset]
 					}
 				}
 
@@ -466,9 +463,8 @@ func (s *Server) formatSpxDecls(snapshot *xgo.Project, spxFile string) ([]byte,
 
 		// Add the floating comment.
 		ensureTrailingNewlines(2)
-		start := fset.Position(cg.Pos()).Offset
-		end := fset.Position(cg.End()).Offset
-		formattedBuf.Write(astFile.Code[start:end])
+		start, end := xgoutil.TokenRange(fset, cg)
+		formattedBuf.Write(astFile.Code[start.Offset:end.Offset])
 		ensureTrailingNewlines(1)
 	}
 
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"slices"
 
 	"github.com/goplus/xgo/ast"
@@ -9,20 +10,20 @@ import (
 )
 
 // See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.18/specification/#textDocument_documentHighlight
-func (s *Server) textDocumentDocumentHighlight(params *DocumentHighlightParams) (*[]DocumentHighlight, error) {
-	result, _, astFile, err := s.compileAndGetASTFileForDocumentURI(params.TextDocument.URI)
+func (s *Server) textDocumentDocumentHighlight(ctx context.Context, params *DocumentHighlightParams) (*[]DocumentHighlight, error) {
+	result, err := s.compileAndGetASTFileForDocumentURI(ctx, params.TextDocument.URI)
 	if err != nil {
 		return nil, err
 	}
-	if astFile == nil {
+	if !result.IsUsable() {
 		return nil, nil
 	}
-	position := ToPosition(result.proj, astFile, params.Position)
+	position := ToPosition(result.proj, result.astFile, params.Position)
 	typeInfo, _ := result.proj.TypeInfo()
 	if typeInfo == nil {
 		return nil, nil
 	}
-	_, targetObj, _ := objectAtPosition(result.proj, typeInfo, astFile, position)
+	_, targetObj, _ := objectAtPosition(result.proj, typeInfo, result.astFile, position)
 	if targetObj == nil {
 		return nil, nil
 	}
@@ -34,7 +35,7 @@ func (s *Server) textDocumentDocumentHighlight(params *DocumentHighlightParams)
 		}
 		highlights = append(highlights, highlight)
 	}
-	ast.Inspect(astFile, func(node ast.Node) bool {
+	ast.Inspect(result.astFile, func(node ast.Node) bool {
 		if node == nil {
 			return true
 		}
@@ -46,7 +47,7 @@ func (s *Server) textDocumentDocumentHighlight(params *DocumentHighlightParams)
 		if obj != targetObj {
 			return true
 		}
-		path, _ := xgoutil.PathEnclosingInterval(astFile, ident.Pos(), ident.End())
+		path, _ := xgoutil.PathEnclosingInterval(result.astFile, ident.Pos(), ident.End())
 		if len(path) < 2 {
 			return true
 		}
@@ -163,7 +164,7 @@ func (s *Server) textDocumentDocumentHighlight(params *DocumentHighlightParams)
 		})
 		return true
 	})
-	for _, loc := range s.kwargReferenceLocations(result, targetObj) {
+	for _, loc := range s.kwargReferenceLocations(result.compileResult, targetObj) {
 		appendHighlight(DocumentHighlight{
 			Range: loc.Range,
 			Kind:  Read,
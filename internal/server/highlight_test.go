@@ -22,9 +22,9 @@ onStart => {
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		mySpriteHighlights, err := s.textDocumentDocumentHighlight(&DocumentHighlightParams{
+		mySpriteHighlights, err := s.textDocumentDocumentHighlight(t.Context(), &DocumentHighlightParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 1, Character: 0},
@@ -48,7 +48,7 @@ onStart => {
 			Kind: Read,
 		})
 
-		leftHighlights, err := s.textDocumentDocumentHighlight(&DocumentHighlightParams{
+		leftHighlights, err := s.textDocumentDocumentHighlight(t.Context(), &DocumentHighlightParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 2, Character: 14},
@@ -81,9 +81,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		highlights, err := s.textDocumentDocumentHighlight(&DocumentHighlightParams{
+		highlights, err := s.textDocumentDocumentHighlight(t.Context(), &DocumentHighlightParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 8, Character: 12},
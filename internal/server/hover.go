@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	godoc "go/doc"
 	"strings"
 
@@ -10,18 +11,18 @@ import (
 )
 
 // See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.18/specification#textDocument_hover
-func (s *Server) textDocumentHover(params *HoverParams) (*Hover, error) {
-	result, _, astFile, err := s.compileAndGetASTFileForDocumentURI(params.TextDocument.URI)
+func (s *Server) textDocumentHover(ctx context.Context, params *HoverParams) (*Hover, error) {
+	result, err := s.compileAndGetASTFileForDocumentURI(ctx, params.TextDocument.URI)
 	if err != nil {
 		return nil, err
 	}
-	if astFile == nil {
+	if !result.IsUsable() {
 		return nil, nil
 	}
-	if !astFile.Pos().IsValid() {
+	if !result.astFile.Pos().IsValid() {
 		return nil, nil
 	}
-	position := ToPosition(result.proj, astFile, params.Position)
+	position := ToPosition(result.proj, result.astFile, params.Position)
 
 	if spxResourceRef := result.spxResourceRefAtPosition(position); spxResourceRef != nil {
 		return &Hover{
@@ -37,17 +38,17 @@ func (s *Server) textDocumentHover(params *HoverParams) (*Hover, error) {
 	if typeInfo == nil {
 		return nil, nil
 	}
-	if hover := hoverForXGoUnit(result.proj, typeInfo, astFile, position); hover != nil {
+	if hover := hoverForXGoUnit(result.proj, typeInfo, result.astFile, position); hover != nil {
 		return hover, nil
 	}
-	ident, obj, kwargTarget := objectAtPosition(result.proj, typeInfo, astFile, position)
+	ident, obj, kwargTarget := objectAtPosition(result.proj, typeInfo, result.astFile, position)
 	if kwargTarget != nil {
 		return hoverForSpxDefs(result.proj, result.spxDefinitionsFor(obj, getTypeFromObject(typeInfo, obj)), kwargTarget.ident), nil
 	}
 	if ident == nil {
 		// Check if the position is within an import declaration.
 		// If so, return the package documentation.
-		rpkg := result.spxImportsAtASTFilePosition(astFile, position)
+		rpkg := result.spxImportsAtASTFilePosition(result.astFile, position)
 		if rpkg != nil {
 			return &Hover{
 				Contents: MarkupContent{
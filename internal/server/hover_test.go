@@ -63,9 +63,9 @@ onTouchStart "MySprite", => {}
 			"assets/sprites/MySprite/index.json": []byte(`{"costumes":[{"name":"costume1"}]}`),
 			"assets/sounds/MySound/index.json":   []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		varHover, err := s.textDocumentHover(&HoverParams{
+		varHover, err := s.textDocumentHover(t.Context(), &HoverParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 8, Character: 1},
@@ -84,7 +84,7 @@ onTouchStart "MySprite", => {}
 			},
 		}, varHover)
 
-		constHover, err := s.textDocumentHover(&HoverParams{
+		constHover, err := s.textDocumentHover(t.Context(), &HoverParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 14, Character: 6},
@@ -103,7 +103,7 @@ onTouchStart "MySprite", => {}
 			},
 		}, constHover)
 
-		funcHover, err := s.textDocumentHover(&HoverParams{
+		funcHover, err := s.textDocumentHover(t.Context(), &HoverParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 17, Character: 5},
@@ -122,7 +122,7 @@ onTouchStart "MySprite", => {}
 			},
 		}, funcHover)
 
-		typeHover, err := s.textDocumentHover(&HoverParams{
+		typeHover, err := s.textDocumentHover(t.Context(), &HoverParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 22, Character: 5},
@@ -141,7 +141,7 @@ onTouchStart "MySprite", => {}
 			},
 		}, typeHover)
 
-		typeFieldHover, err := s.textDocumentHover(&HoverParams{
+		typeFieldHover, err := s.textDocumentHover(t.Context(), &HoverParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 24, Character: 1},
@@ -160,7 +160,7 @@ onTouchStart "MySprite", => {}
 			},
 		}, typeFieldHover)
 
-		pkgHover, err := s.textDocumentHover(&HoverParams{
+		pkgHover, err := s.textDocumentHover(t.Context(), &HoverParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 30, Character: 0},
@@ -173,7 +173,7 @@ onTouchStart "MySprite", => {}
 			End:   Position{Line: 30, Character: 3},
 		}, pkgHover.Range)
 
-		pkgFuncHover, err := s.textDocumentHover(&HoverParams{
+		pkgFuncHover, err := s.textDocumentHover(t.Context(), &HoverParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 30, Character: 4},
@@ -186,7 +186,7 @@ onTouchStart "MySprite", => {}
 			End:   Position{Line: 30, Character: 11},
 		}, pkgFuncHover.Range)
 
-		builtinFuncHover, err := s.textDocumentHover(&HoverParams{
+		builtinFuncHover, err := s.textDocumentHover(t.Context(), &HoverParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 30, Character: 12},
@@ -205,7 +205,7 @@ onTouchStart "MySprite", => {}
 			},
 		}, builtinFuncHover)
 
-		mySoundRefHover, err := s.textDocumentHover(&HoverParams{
+		mySoundRefHover, err := s.textDocumentHover(t.Context(), &HoverParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 32, Character: 5},
@@ -224,7 +224,7 @@ onTouchStart "MySprite", => {}
 			},
 		}, mySoundRefHover)
 
-		mySpriteRefHover, err := s.textDocumentHover(&HoverParams{
+		mySpriteRefHover, err := s.textDocumentHover(t.Context(), &HoverParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 33, Character: 0},
@@ -243,7 +243,7 @@ onTouchStart "MySprite", => {}
 			},
 		}, mySpriteRefHover)
 
-		mySpriteCostumeRefHover, err := s.textDocumentHover(&HoverParams{
+		mySpriteCostumeRefHover, err := s.textDocumentHover(t.Context(), &HoverParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 34, Character: 20},
@@ -262,7 +262,7 @@ onTouchStart "MySprite", => {}
 			},
 		}, mySpriteCostumeRefHover)
 
-		mySpriteSetCostumeFuncHover, err := s.textDocumentHover(&HoverParams{
+		mySpriteSetCostumeFuncHover, err := s.textDocumentHover(t.Context(), &HoverParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 34, Character: 9},
@@ -275,7 +275,7 @@ onTouchStart "MySprite", => {}
 			End:   Position{Line: 34, Character: 19},
 		}, mySpriteSetCostumeFuncHover.Range)
 
-		GameOnClickHover, err := s.textDocumentHover(&HoverParams{
+		GameOnClickHover, err := s.textDocumentHover(t.Context(), &HoverParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 35, Character: 5},
@@ -294,7 +294,7 @@ onTouchStart "MySprite", => {}
 			},
 		}, GameOnClickHover)
 
-		mainSpxOnClickHover, err := s.textDocumentHover(&HoverParams{
+		mainSpxOnClickHover, err := s.textDocumentHover(t.Context(), &HoverParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 36, Character: 0},
@@ -313,7 +313,7 @@ onTouchStart "MySprite", => {}
 			},
 		}, mainSpxOnClickHover)
 
-		mainSpxCameraFollowHover, err := s.textDocumentHover(&HoverParams{
+		mainSpxCameraFollowHover, err := s.textDocumentHover(t.Context(), &HoverParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 37, Character: 8},
@@ -327,7 +327,7 @@ onTouchStart "MySprite", => {}
 			End:   Position{Line: 37, Character: 13},
 		}, mainSpxCameraFollowHover.Range)
 
-		mySpriteOnClickFuncHover, err := s.textDocumentHover(&HoverParams{
+		mySpriteOnClickFuncHover, err := s.textDocumentHover(t.Context(), &HoverParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///MySprite.spx"},
 				Position:     Position{Line: 1, Character: 9},
@@ -346,7 +346,7 @@ onTouchStart "MySprite", => {}
 			},
 		}, mySpriteOnClickFuncHover)
 
-		mySpriteSpxOnClickFuncHover, err := s.textDocumentHover(&HoverParams{
+		mySpriteSpxOnClickFuncHover, err := s.textDocumentHover(t.Context(), &HoverParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///MySprite.spx"},
 				Position:     Position{Line: 2, Character: 0},
@@ -365,7 +365,7 @@ onTouchStart "MySprite", => {}
 			},
 		}, mySpriteSpxOnClickFuncHover)
 
-		mySpriteCloneFuncHover, err := s.textDocumentHover(&HoverParams{
+		mySpriteCloneFuncHover, err := s.textDocumentHover(t.Context(), &HoverParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///MySprite.spx"},
 				Position:     Position{Line: 5, Character: 1},
@@ -384,7 +384,7 @@ onTouchStart "MySprite", => {}
 			},
 		}, mySpriteCloneFuncHover)
 
-		imagePointFieldHover, err := s.textDocumentHover(&HoverParams{
+		imagePointFieldHover, err := s.textDocumentHover(t.Context(), &HoverParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///MySprite.spx"},
 				Position:     Position{Line: 6, Character: 12},
@@ -403,7 +403,7 @@ onTouchStart "MySprite", => {}
 			},
 		}, imagePointFieldHover)
 
-		onTouchStartFirstArgHover, err := s.textDocumentHover(&HoverParams{
+		onTouchStartFirstArgHover, err := s.textDocumentHover(t.Context(), &HoverParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///MySprite.spx"},
 				Position:     Position{Line: 8, Character: 14},
@@ -427,9 +427,9 @@ onTouchStart "MySprite", => {}
 		m := map[string][]byte{
 			"main.spx": []byte(`var x int`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		hover, err := s.textDocumentHover(&HoverParams{
+		hover, err := s.textDocumentHover(t.Context(), &HoverParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 99, Character: 99},
@@ -450,9 +450,9 @@ import (
 fmt.Println("Hello, World!")
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		importHover, err := s.textDocumentHover(&HoverParams{
+		importHover, err := s.textDocumentHover(t.Context(), &HoverParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 2, Character: 1},
@@ -479,9 +479,9 @@ var nums []int
 nums = append(nums, 1)
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		hover, err := s.textDocumentHover(&HoverParams{
+		hover, err := s.textDocumentHover(t.Context(), &HoverParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 2, Character: 7},
@@ -503,9 +503,9 @@ var num int128
 echo num
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		hover1, err := s.textDocumentHover(&HoverParams{
+		hover1, err := s.textDocumentHover(t.Context(), &HoverParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 1, Character: 8},
@@ -519,7 +519,7 @@ echo num
 			End:   Position{Line: 1, Character: 14},
 		}, hover1.Range)
 
-		hover2, err := s.textDocumentHover(&HoverParams{
+		hover2, err := s.textDocumentHover(t.Context(), &HoverParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 2, Character: 0},
@@ -544,9 +544,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		hover1, err := s.textDocumentHover(&HoverParams{
+		hover1, err := s.textDocumentHover(t.Context(), &HoverParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 3, Character: 14},
@@ -560,7 +560,7 @@ onStart => {
 			End:   Position{Line: 3, Character: 15},
 		}, hover1.Range)
 
-		hover2, err := s.textDocumentHover(&HoverParams{
+		hover2, err := s.textDocumentHover(t.Context(), &HoverParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 3, Character: 18},
@@ -569,7 +569,7 @@ onStart => {
 		require.NoError(t, err)
 		require.Nil(t, hover2)
 
-		hover3, err := s.textDocumentHover(&HoverParams{
+		hover3, err := s.textDocumentHover(t.Context(), &HoverParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 4, Character: 17},
@@ -592,9 +592,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		hover, err := s.textDocumentHover(&HoverParams{
+		hover, err := s.textDocumentHover(t.Context(), &HoverParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 2, Character: 1},
@@ -619,9 +619,9 @@ onStart => {
 `),
 			"assets/index.json": []byte(`{"zorder":[{"name":"myWidget"}]}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		hover, err := s.textDocumentHover(&HoverParams{
+		hover, err := s.textDocumentHover(t.Context(), &HoverParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 2, Character: 1},
@@ -646,9 +646,9 @@ onStart => {
 )
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		hover, err := s.textDocumentHover(&HoverParams{
+		hover, err := s.textDocumentHover(t.Context(), &HoverParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 2, Character: 1},
@@ -664,9 +664,9 @@ onStart => {
 			"main.spx": []byte(`const _ = 1
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		hover, err := s.textDocumentHover(&HoverParams{
+		hover, err := s.textDocumentHover(t.Context(), &HoverParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 0, Character: 6},
@@ -683,9 +683,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		hover, err := s.textDocumentHover(&HoverParams{
+		hover, err := s.textDocumentHover(t.Context(), &HoverParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 1, Character: 8},
@@ -702,9 +702,9 @@ var this int
 this = 1
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		hover, err := s.textDocumentHover(&HoverParams{
+		hover, err := s.textDocumentHover(t.Context(), &HoverParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 1, Character: 4},
@@ -727,11 +727,11 @@ this = 1
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		// The characters on `onStart` should map to `onStart`, not synthetic `this`.
 		for _, ch := range []uint32{0, 1, 2, 3, 4, 5, 6} {
-			hover, err := s.textDocumentHover(&HoverParams{
+			hover, err := s.textDocumentHover(t.Context(), &HoverParams{
 				TextDocumentPositionParams: TextDocumentPositionParams{
 					TextDocument: TextDocumentIdentifier{URI: "file:///MySprite.spx"},
 					Position:     Position{Line: 0, Character: ch},
@@ -746,7 +746,7 @@ this = 1
 		// The first four characters on indented lines are whitespaces and should not produce hover.
 		for _, line := range []uint32{1, 2} {
 			for _, ch := range []uint32{0, 1, 2, 3} {
-				hover, err := s.textDocumentHover(&HoverParams{
+				hover, err := s.textDocumentHover(t.Context(), &HoverParams{
 					TextDocumentPositionParams: TextDocumentPositionParams{
 						TextDocument: TextDocumentIdentifier{URI: "file:///MySprite.spx"},
 						Position:     Position{Line: line, Character: ch},
@@ -773,9 +773,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		hover, err := s.textDocumentHover(&HoverParams{
+		hover, err := s.textDocumentHover(t.Context(), &HoverParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 9, Character: 12},
@@ -805,9 +805,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		hover, err := s.textDocumentHover(&HoverParams{
+		hover, err := s.textDocumentHover(t.Context(), &HoverParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 4, Character: 12},
@@ -838,9 +838,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		hover, err := s.textDocumentHover(&HoverParams{
+		hover, err := s.textDocumentHover(t.Context(), &HoverParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 15, Character: 25},
@@ -859,7 +859,7 @@ onStart => {
 	t.Run("XGoUnit", func(t *testing.T) {
 		s := newXGoUnitTestServer(xgoUnitCompletionSource)
 
-		hover, err := s.textDocumentHover(&HoverParams{
+		hover, err := s.textDocumentHover(t.Context(), &HoverParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 15, Character: 7},
@@ -879,7 +879,7 @@ onStart => {
 	t.Run("XGoUnicodeUnit", func(t *testing.T) {
 		s := newXGoUnitTestServer("import \"time\"\n\nfunc wait(d time.Duration) {}\n\nonStart => {\n\twait 1\u00b5s\n}\n")
 
-		hover, err := s.textDocumentHover(&HoverParams{
+		hover, err := s.textDocumentHover(t.Context(), &HoverParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 5, Character: 7},
@@ -898,7 +898,7 @@ onStart => {
 	t.Run("XGoUnitImportedAliasFallback", func(t *testing.T) {
 		s := newXGoUnitTestServer("import \"example.com/unit\"\n\nfunc wait(d unit.Delay) {}\n\nonStart => {\n\twait 1ms\n}\n")
 
-		hover, err := s.textDocumentHover(&HoverParams{
+		hover, err := s.textDocumentHover(t.Context(), &HoverParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 5, Character: 7},
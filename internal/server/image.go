@@ -0,0 +1,79 @@
+package server
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"strconv"
+	"strings"
+)
+
+// parseImageSize parses the size in pixels of the image file in data, which
+// is a PNG, JPEG, GIF, or SVG file. It reports false if the format is not
+// supported or the size cannot be determined.
+func parseImageSize(data []byte) (width, height int, ok bool) {
+	if config, _, err := image.DecodeConfig(bytes.NewReader(data)); err == nil {
+		return config.Width, config.Height, true
+	}
+	return parseSVGSize(data)
+}
+
+// parseSVGSize parses the size of the SVG file in data from the width and
+// height attributes of its root element, falling back to its viewBox
+// attribute.
+func parseSVGSize(data []byte) (width, height int, ok bool) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := decoder.Token()
+		if err != nil {
+			return 0, 0, false
+		}
+		start, isStart := tok.(xml.StartElement)
+		if !isStart {
+			continue
+		}
+		if start.Name.Local != "svg" {
+			return 0, 0, false
+		}
+
+		var widthAttr, heightAttr, viewBoxAttr string
+		for _, attr := range start.Attr {
+			switch attr.Name.Local {
+			case "width":
+				widthAttr = attr.Value
+			case "height":
+				heightAttr = attr.Value
+			case "viewBox":
+				viewBoxAttr = attr.Value
+			}
+		}
+		if w, wok := parseSVGLength(widthAttr); wok {
+			if h, hok := parseSVGLength(heightAttr); hok {
+				return w, h, true
+			}
+		}
+		if fields := strings.FieldsFunc(viewBoxAttr, func(r rune) bool {
+			return r == ' ' || r == ','
+		}); len(fields) == 4 {
+			w, werr := strconv.ParseFloat(fields[2], 64)
+			h, herr := strconv.ParseFloat(fields[3], 64)
+			if werr == nil && herr == nil && w > 0 && h > 0 {
+				return int(w + 0.5), int(h + 0.5), true
+			}
+		}
+		return 0, 0, false
+	}
+}
+
+// parseSVGLength parses an SVG length in user units or pixels, e.g., "64" or
+// "64px". It reports false for other units such as percentages.
+func parseSVGLength(s string) (int, bool) {
+	v, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(s), "px"), 64)
+	if err != nil || v <= 0 {
+		return 0, false
+	}
+	return int(v + 0.5), true
+}
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestPNG returns a blank PNG image of the given size.
+func newTestPNG(width, height int) []byte {
+	var buf bytes.Buffer
+	png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height)))
+	return buf.Bytes()
+}
+
+func TestParseImageSize(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		data       []byte
+		wantWidth  int
+		wantHeight int
+		wantOK     bool
+	}{
+		{
+			name:       "PNG",
+			data:       newTestPNG(64, 48),
+			wantWidth:  64,
+			wantHeight: 48,
+			wantOK:     true,
+		},
+		{
+			name:       "SVGWidthHeight",
+			data:       []byte(`<?xml version="1.0"?><svg xmlns="http://www.w3.org/2000/svg" width="64px" height="48.4"></svg>`),
+			wantWidth:  64,
+			wantHeight: 48,
+			wantOK:     true,
+		},
+		{
+			name:       "SVGViewBox",
+			data:       []byte(`<svg xmlns="http://www.w3.org/2000/svg" width="100%" viewBox="0 0 32,24"></svg>`),
+			wantWidth:  32,
+			wantHeight: 24,
+			wantOK:     true,
+		},
+		{
+			name: "SVGWithoutSize",
+			data: []byte(`<svg xmlns="http://www.w3.org/2000/svg"></svg>`),
+		},
+		{
+			name: "NotSVG",
+			data: []byte(`<html width="64" height="48"></html>`),
+		},
+		{
+			name: "Invalid",
+			data: []byte("not an image"),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			width, height, ok := parseImageSize(tt.data)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantWidth, width)
+			assert.Equal(t, tt.wantHeight, height)
+		})
+	}
+}
@@ -1,26 +1,27 @@
 package server
 
 import (
+	"context"
 	gotypes "go/types"
 
 	"github.com/goplus/xgolsw/xgo/xgoutil"
 )
 
 // See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.18/specification/#textDocument_implementation
-func (s *Server) textDocumentImplementation(params *ImplementationParams) (any, error) {
-	result, _, astFile, err := s.compileAndGetASTFileForDocumentURI(params.TextDocument.URI)
+func (s *Server) textDocumentImplementation(ctx context.Context, params *ImplementationParams) (any, error) {
+	result, err := s.compileAndGetASTFileForDocumentURI(ctx, params.TextDocument.URI)
 	if err != nil {
 		return nil, err
 	}
-	if astFile == nil {
+	if !result.IsUsable() {
 		return nil, nil
 	}
-	position := ToPosition(result.proj, astFile, params.Position)
+	position := ToPosition(result.proj, result.astFile, params.Position)
 	typeInfo, _ := result.proj.TypeInfo()
 	if typeInfo == nil {
 		return nil, nil
 	}
-	_, obj, _ := objectAtPosition(result.proj, typeInfo, astFile, position)
+	_, obj, _ := objectAtPosition(result.proj, typeInfo, result.astFile, position)
 	if !xgoutil.IsInMainPkg(obj) {
 		return nil, nil
 	}
@@ -28,7 +29,7 @@ func (s *Server) textDocumentImplementation(params *ImplementationParams) (any,
 	if method, ok := obj.(*gotypes.Func); ok {
 		if recv := method.Signature().Recv(); recv != nil {
 			if recvType := recv.Type(); gotypes.IsInterface(recvType) {
-				locations := s.findImplementingMethodDefinitions(result, recvType.(*gotypes.Interface), method.Name())
+				locations := s.findImplementingMethodDefinitions(result.compileResult, recvType.(*gotypes.Interface), method.Name())
 				return DedupeLocations(locations), nil
 			}
 		}
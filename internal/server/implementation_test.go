@@ -26,9 +26,9 @@ func (t MyType2) myMethod() {}
 var x MyInterface
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		implementations, err := s.textDocumentImplementation(&ImplementationParams{
+		implementations, err := s.textDocumentImplementation(t.Context(), &ImplementationParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 2, Character: 1},
@@ -63,9 +63,9 @@ type MyType struct{}
 func (t MyType) myMethod() {}
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		implementation, err := s.textDocumentImplementation(&ImplementationParams{
+		implementation, err := s.textDocumentImplementation(t.Context(), &ImplementationParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 3, Character: 16},
@@ -107,9 +107,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		implementation, err := s.textDocumentImplementation(&ImplementationParams{
+		implementation, err := s.textDocumentImplementation(t.Context(), &ImplementationParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 18, Character: 26},
@@ -135,9 +135,9 @@ onStart => {
 type MyType struct{}
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		implementation, err := s.textDocumentImplementation(&ImplementationParams{
+		implementation, err := s.textDocumentImplementation(t.Context(), &ImplementationParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 99, Character: 99},
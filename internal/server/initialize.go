@@ -1,9 +1,14 @@
 package server
 
 import (
+	"fmt"
+
 	"golang.org/x/text/language"
 
 	"github.com/goplus/xgolsw/i18n"
+	"github.com/goplus/xgolsw/internal/pkgdata"
+	"github.com/goplus/xgolsw/jsonrpc2"
+	"github.com/goplus/xgolsw/protocol"
 )
 
 // initialize handles the initialize request and sets up the server language preference
@@ -11,14 +16,8 @@ func (s *Server) initialize(params *InitializeParams) (*InitializeResult, error)
 	// Set language based on client locale
 	s.setLanguageFromLocale(params.Locale)
 
-	// Create server capabilities
-	capabilities := ServerCapabilities{
-		// TODO(wyvern): Configure server capabilities based on client capabilities
-		// For now, return empty capabilities as placeholder
-	}
-
 	return &InitializeResult{
-		Capabilities: capabilities,
+		Capabilities: serverCapabilities(),
 		ServerInfo: &ServerInfo{
 			Name:    "XGo Language Server",
 			Version: "0.1.0",
@@ -26,6 +25,203 @@ func (s *Server) initialize(params *InitializeParams) (*InitializeResult, error)
 	}, nil
 }
 
+// serverCapabilities returns the capabilities of the server, i.e., the
+// requests it handles in [Server.handleCall].
+func serverCapabilities() ServerCapabilities {
+	return ServerCapabilities{
+		TextDocumentSync: protocol.TextDocumentSyncOptions{
+			OpenClose: true,
+			Change:    protocol.Incremental,
+			Save:      &protocol.SaveOptions{IncludeText: true},
+		},
+		CompletionProvider: &protocol.CompletionOptions{
+			TriggerCharacters: []string{"."},
+		},
+		HoverProvider: &protocol.Or_ServerCapabilities_hoverProvider{Value: true},
+		SignatureHelpProvider: &protocol.SignatureHelpOptions{
+			TriggerCharacters: []string{"(", ","},
+		},
+		DeclarationProvider:       &protocol.Or_ServerCapabilities_declarationProvider{Value: true},
+		DefinitionProvider:        &protocol.Or_ServerCapabilities_definitionProvider{Value: true},
+		TypeDefinitionProvider:    &protocol.Or_ServerCapabilities_typeDefinitionProvider{Value: true},
+		ImplementationProvider:    &protocol.Or_ServerCapabilities_implementationProvider{Value: true},
+		ReferencesProvider:        &protocol.Or_ServerCapabilities_referencesProvider{Value: true},
+		DocumentHighlightProvider: &protocol.Or_ServerCapabilities_documentHighlightProvider{Value: true},
+		DocumentLinkProvider:      &protocol.DocumentLinkOptions{},
+		ColorProvider:             &protocol.Or_ServerCapabilities_colorProvider{Value: true},
+		DocumentFormattingProvider: &protocol.Or_ServerCapabilities_documentFormattingProvider{
+			Value: true,
+		},
+		DocumentOnTypeFormattingProvider: &protocol.DocumentOnTypeFormattingOptions{
+			FirstTriggerCharacter: "\n",
+			MoreTriggerCharacter:  []string{"{", "}"},
+		},
+		RenameProvider:         protocol.RenameOptions{PrepareProvider: true},
+		SelectionRangeProvider: &protocol.Or_ServerCapabilities_selectionRangeProvider{Value: true},
+		ExecuteCommandProvider: &protocol.ExecuteCommandOptions{
+			Commands: []string{
+				CommandXGoRenameResources,
+				CommandSpxRenameResources,
+				CommandXGoGetInputSlots,
+				CommandSpxGetInputSlots,
+				CommandXGoSetInputSlot,
+				CommandSpxSetInputSlot,
+				CommandXGoGetProperties,
+				CommandSpxGetSpriteInfo,
+				CommandSpxGetBackdropInfo,
+				CommandSpxGetDefinitionAt,
+				CommandSpxGetAnimationFrames,
+				CommandSpxGetSoundInfo,
+				CommandSpxCheckCode,
+				CommandSpxGetProjectStructure,
+				CommandSpxGetEventHandlerPositions,
+			},
+		},
+		CallHierarchyProvider:      &protocol.Or_ServerCapabilities_callHierarchyProvider{Value: true},
+		LinkedEditingRangeProvider: &protocol.Or_ServerCapabilities_linkedEditingRangeProvider{Value: true},
+		SemanticTokensProvider: protocol.SemanticTokensOptions{
+			Legend: protocol.SemanticTokensLegend{
+				TokenTypes:     semanticTokenLegendStrings(semanticTokenTypesLegend),
+				TokenModifiers: semanticTokenLegendStrings(semanticTokenModifiersLegend),
+			},
+			Full: &protocol.Or_SemanticTokensOptions_full{Value: true},
+		},
+		MonikerProvider:   &protocol.Or_ServerCapabilities_monikerProvider{Value: true},
+		InlayHintProvider: true,
+		DiagnosticProvider: &protocol.Or_ServerCapabilities_diagnosticProvider{
+			Value: protocol.DiagnosticOptions{
+				InterFileDependencies: true,
+				WorkspaceDiagnostics:  true,
+			},
+		},
+		Workspace: &protocol.WorkspaceOptions{
+			FileOperations: &protocol.FileOperationOptions{
+				WillRename: &protocol.FileOperationRegistrationOptions{
+					Filters: []protocol.FileOperationFilter{{
+						Pattern: protocol.FileOperationPattern{Glob: "**/*.spx"},
+					}},
+				},
+			},
+		},
+	}
+}
+
+// semanticTokenLegendStrings converts the given semantic token legend to
+// strings.
+func semanticTokenLegendStrings[T ~string](legend []T) []string {
+	strs := make([]string, len(legend))
+	for i, v := range legend {
+		strs[i] = string(v)
+	}
+	return strs
+}
+
+// initialized handles the initialized notification by preloading the package
+// data in the background, so that the first requests do not pay for it.
+func (s *Server) initialized(params *InitializedParams) error {
+	if pkgdata.IsPreloaded() {
+		return nil
+	}
+	go func() {
+		if err := pkgdata.PreloadAll(); err != nil {
+			s.LogMessage(MessageTypeWarning, fmt.Sprintf("failed to preload package data: %v", err))
+		}
+	}()
+	return nil
+}
+
+// maxPendingMessages is the maximum number of messages queued by
+// [Server.deferUntilInitialized].
+const maxPendingMessages = 1000
+
+// serverNotInitialized is the error replied to requests that cannot be
+// handled because the initialize request has not completed.
+var serverNotInitialized = jsonrpc2.NewError(int64(ServerNotInitialized), "Server not initialized")
+
+// deferUntilInitialized reports whether m has been queued, or rejected,
+// because the initialize request has not completed yet. Queued messages are
+// handled in order by [Server.markInitialized]. Once [maxPendingMessages]
+// messages are queued, further requests are replied with an error and further
+// notifications are dropped. The initialize request and the exit notification
+// are never queued.
+func (s *Server) deferUntilInitialized(m jsonrpc2.Message) bool {
+	switch m := m.(type) {
+	case *jsonrpc2.Call:
+		if m.Method() == "initialize" {
+			return false
+		}
+	case *jsonrpc2.Notification:
+		if m.Method() == "exit" {
+			return false
+		}
+	case *jsonrpc2.Response:
+		return false
+	}
+
+	s.initMu.Lock()
+	if s.initializeDone {
+		s.initMu.Unlock()
+		return false
+	}
+	if len(s.pendingMessages) < maxPendingMessages {
+		s.pendingMessages = append(s.pendingMessages, m)
+		s.initMu.Unlock()
+		return true
+	}
+	s.initMu.Unlock()
+
+	if call, ok := m.(*jsonrpc2.Call); ok {
+		s.replyError(call.ID(), fmt.Errorf("%w: too many requests before initialize", serverNotInitialized))
+	}
+	return true
+}
+
+// finishInitialize finishes the initialize request that failed with err, or
+// succeeded if err is nil. On success, the queued messages are handled by
+// [Server.markInitialized]. Otherwise, they are discarded and queued requests
+// are replied with an error, so they do not wait for an initialize request
+// that may never succeed.
+func (s *Server) finishInitialize(err error) {
+	if err == nil {
+		s.markInitialized()
+		return
+	}
+
+	s.initMu.Lock()
+	pending := s.pendingMessages
+	s.pendingMessages = nil
+	s.initMu.Unlock()
+	for _, m := range pending {
+		if call, ok := m.(*jsonrpc2.Call); ok {
+			s.replyError(call.ID(), fmt.Errorf("%w: initialize request failed: %v", serverNotInitialized, err))
+		}
+	}
+}
+
+// markInitialized marks the initialize request as completed and handles all
+// messages queued by [Server.deferUntilInitialized] in order.
+func (s *Server) markInitialized() {
+	for {
+		s.initMu.Lock()
+		pending := s.pendingMessages
+		s.pendingMessages = nil
+		if len(pending) == 0 {
+			s.initializeDone = true
+			s.initMu.Unlock()
+			return
+		}
+		s.initMu.Unlock()
+
+		// Messages received while handling the pending ones are queued and
+		// handled in the next iteration, so the order is preserved.
+		for _, m := range pending {
+			if err := s.handleMessage(m); err != nil {
+				s.LogMessage(MessageTypeError, fmt.Sprintf("failed to handle queued message: %v", err))
+			}
+		}
+	}
+}
+
 // setLanguageFromLocale sets the server language based on the client locale
 func (s *Server) setLanguageFromLocale(locale string) {
 	// Default to English
@@ -2,6 +2,7 @@ package server
 
 import (
 	"cmp"
+	"context"
 	"slices"
 
 	"github.com/goplus/xgo/ast"
@@ -10,21 +11,21 @@ import (
 )
 
 // See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.18/specification/#textDocument_inlayHint
-func (s *Server) textDocumentInlayHint(params *InlayHintParams) ([]InlayHint, error) {
-	result, _, astFile, err := s.compileAndGetASTFileForDocumentURI(params.TextDocument.URI)
+func (s *Server) textDocumentInlayHint(ctx context.Context, params *InlayHintParams) ([]InlayHint, error) {
+	result, err := s.compileAndGetASTFileForDocumentURI(ctx, params.TextDocument.URI)
 	if err != nil {
 		return nil, err
 	}
-	if astFile == nil {
+	if !result.IsUsable() {
 		return nil, nil
 	}
-	if !astFile.Pos().IsValid() {
+	if !result.astFile.Pos().IsValid() {
 		return nil, nil
 	}
 
-	rangeStart := PosAt(result.proj, astFile, params.Range.Start)
-	rangeEnd := PosAt(result.proj, astFile, params.Range.End)
-	return collectInlayHints(result, astFile, rangeStart, rangeEnd), nil
+	rangeStart := PosAt(result.proj, result.astFile, params.Range.Start)
+	rangeEnd := PosAt(result.proj, result.astFile, params.Range.End)
+	return collectInlayHints(result.compileResult, result.astFile, rangeStart, rangeEnd), nil
 }
 
 // collectInlayHints collects inlay hints from the given AST file. If
@@ -28,7 +28,7 @@ onStart => {
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		params := &InlayHintParams{
 			TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
@@ -38,7 +38,7 @@ onStart => {
 			},
 		}
 
-		inlayHints, err := s.textDocumentInlayHint(params)
+		inlayHints, err := s.textDocumentInlayHint(t.Context(), params)
 		require.NoError(t, err)
 		require.NotNil(t, inlayHints)
 		assert.NotEmpty(t, inlayHints)
@@ -72,7 +72,7 @@ onStart => {
 		m := map[string][]byte{
 			"main.spx": []byte(``),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		params := &InlayHintParams{
 			TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
@@ -82,13 +82,13 @@ onStart => {
 			},
 		}
 
-		inlayHints, err := s.textDocumentInlayHint(params)
+		inlayHints, err := s.textDocumentInlayHint(t.Context(), params)
 		require.NoError(t, err)
 		assert.Empty(t, inlayHints)
 	})
 
 	t.Run("NonExistentFile", func(t *testing.T) {
-		s := New(newProjectWithoutModTime(map[string][]byte{}), nil, fileMapGetter(map[string][]byte{}), &MockScheduler{})
+		s := New(newProjectWithoutModTime(map[string][]byte{}), nil, fileMapGetter(map[string][]byte{}), &MockScheduler{}, nil)
 
 		params := &InlayHintParams{
 			TextDocument: TextDocumentIdentifier{URI: "file:///nonexistent.spx"},
@@ -98,7 +98,7 @@ onStart => {
 			},
 		}
 
-		inlayHints, err := s.textDocumentInlayHint(params)
+		inlayHints, err := s.textDocumentInlayHint(t.Context(), params)
 		require.Error(t, err)
 		assert.Nil(t, inlayHints)
 	})
@@ -124,7 +124,7 @@ onStart => {
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		params := &InlayHintParams{
 			TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
@@ -134,7 +134,7 @@ onStart => {
 			},
 		}
 
-		inlayHints, err := s.textDocumentInlayHint(params)
+		inlayHints, err := s.textDocumentInlayHint(t.Context(), params)
 		require.NoError(t, err)
 		require.NotNil(t, inlayHints)
 		assert.Len(t, inlayHints, 2)
@@ -187,13 +187,13 @@ onStart => {
 			"assets/sprites/MySprite/index.json":    []byte(`{}`),
 			"assets/sprites/OtherSprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		result, _, astFile, err := s.compileAndGetASTFileForDocumentURI("file:///main.spx")
+		result, err := s.compileAndGetASTFileForDocumentURI(t.Context(), "file:///main.spx")
 		require.NoError(t, err)
-		require.NotNil(t, astFile)
+		require.NotNil(t, result.astFile)
 
-		inlayHints := collectInlayHints(result, astFile, 0, 0)
+		inlayHints := collectInlayHints(result.compileResult, result.astFile, 0, 0)
 		require.NotNil(t, inlayHints)
 		assert.NotEmpty(t, inlayHints)
 
@@ -234,11 +234,11 @@ onStart => {
 		}
 		assert.Equal(t, 3, hsbHintCount)
 
-		spriteResult, _, spriteAstFile, err := s.compileAndGetASTFileForDocumentURI("file:///MySprite.spx")
+		spriteResult, err := s.compileAndGetASTFileForDocumentURI(t.Context(), "file:///MySprite.spx")
 		require.NoError(t, err)
-		require.NotNil(t, spriteAstFile)
+		require.NotNil(t, spriteResult.astFile)
 
-		spriteInlayHints := collectInlayHints(spriteResult, spriteAstFile, 0, 0)
+		spriteInlayHints := collectInlayHints(spriteResult.compileResult, spriteResult.astFile, 0, 0)
 		require.NotNil(t, spriteInlayHints)
 		assert.NotEmpty(t, spriteInlayHints)
 
@@ -263,13 +263,13 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		result, _, astFile, err := s.compileAndGetASTFileForDocumentURI("file:///main.spx")
+		result, err := s.compileAndGetASTFileForDocumentURI(t.Context(), "file:///main.spx")
 		require.NoError(t, err)
-		require.NotNil(t, astFile)
+		require.NotNil(t, result.astFile)
 
-		inlayHints := collectInlayHints(result, astFile, 0, 0)
+		inlayHints := collectInlayHints(result.compileResult, result.astFile, 0, 0)
 		assert.Empty(t, inlayHints)
 	})
 
@@ -284,13 +284,13 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		result, _, astFile, err := s.compileAndGetASTFileForDocumentURI("file:///main.spx")
+		result, err := s.compileAndGetASTFileForDocumentURI(t.Context(), "file:///main.spx")
 		require.NoError(t, err)
-		require.NotNil(t, astFile)
+		require.NotNil(t, result.astFile)
 
-		inlayHints := collectInlayHints(result, astFile, 0, 0)
+		inlayHints := collectInlayHints(result.compileResult, result.astFile, 0, 0)
 		require.NotNil(t, inlayHints)
 		assert.NotEmpty(t, inlayHints)
 
@@ -307,13 +307,13 @@ onStart => {
 		m := map[string][]byte{
 			"main.spx": []byte(``),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		result, _, astFile, err := s.compileAndGetASTFileForDocumentURI("file:///main.spx")
+		result, err := s.compileAndGetASTFileForDocumentURI(t.Context(), "file:///main.spx")
 		require.NoError(t, err)
-		require.NotNil(t, astFile)
+		require.NotNil(t, result.astFile)
 
-		inlayHints := collectInlayHints(result, astFile, 0, 0)
+		inlayHints := collectInlayHints(result.compileResult, result.astFile, 0, 0)
 		assert.Empty(t, inlayHints)
 	})
 
@@ -335,19 +335,19 @@ onStart => {
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		result, _, astFile, err := s.compileAndGetASTFileForDocumentURI("file:///main.spx")
+		result, err := s.compileAndGetASTFileForDocumentURI(t.Context(), "file:///main.spx")
 		require.NoError(t, err)
-		require.NotNil(t, astFile)
+		require.NotNil(t, result.astFile)
 
-		rangeStart := PosAt(result.proj, astFile, Position{Line: 3, Character: 0})
-		rangeEnd := PosAt(result.proj, astFile, Position{Line: 6, Character: 0})
-		filteredHints := collectInlayHints(result, astFile, rangeStart, rangeEnd)
+		rangeStart := PosAt(result.proj, result.astFile, Position{Line: 3, Character: 0})
+		rangeEnd := PosAt(result.proj, result.astFile, Position{Line: 6, Character: 0})
+		filteredHints := collectInlayHints(result.compileResult, result.astFile, rangeStart, rangeEnd)
 		require.NotNil(t, filteredHints)
 		assert.NotEmpty(t, filteredHints)
 
-		allHints := collectInlayHints(result, astFile, 0, 0)
+		allHints := collectInlayHints(result.compileResult, result.astFile, 0, 0)
 		require.NotNil(t, allHints)
 		assert.NotEmpty(t, allHints)
 
@@ -380,13 +380,13 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		result, _, astFile, err := s.compileAndGetASTFileForDocumentURI("file:///main.spx")
+		result, err := s.compileAndGetASTFileForDocumentURI(t.Context(), "file:///main.spx")
 		require.NoError(t, err)
-		require.NotNil(t, astFile)
+		require.NotNil(t, result.astFile)
 
-		inlayHints := collectInlayHints(result, astFile, 0, 0)
+		inlayHints := collectInlayHints(result.compileResult, result.astFile, 0, 0)
 		require.Nil(t, inlayHints)
 	})
 
@@ -400,13 +400,13 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		result, _, astFile, err := s.compileAndGetASTFileForDocumentURI("file:///main.spx")
+		result, err := s.compileAndGetASTFileForDocumentURI(t.Context(), "file:///main.spx")
 		require.NoError(t, err)
-		require.NotNil(t, astFile)
+		require.NotNil(t, result.astFile)
 
-		inlayHints := collectInlayHints(result, astFile, 0, 0)
+		inlayHints := collectInlayHints(result.compileResult, result.astFile, 0, 0)
 		require.Len(t, inlayHints, 1)
 		assert.Equal(t, InlayHint{
 			Position: Position{Line: 4, Character: 8},
@@ -433,13 +433,13 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		result, _, astFile, err := s.compileAndGetASTFileForDocumentURI("file:///main.spx")
+		result, err := s.compileAndGetASTFileForDocumentURI(t.Context(), "file:///main.spx")
 		require.NoError(t, err)
-		require.NotNil(t, astFile)
+		require.NotNil(t, result.astFile)
 
-		inlayHints := collectInlayHints(result, astFile, 0, 0)
+		inlayHints := collectInlayHints(result.compileResult, result.astFile, 0, 0)
 		require.Len(t, inlayHints, 1)
 		assert.Equal(t, InlayHint{
 			Position: Position{Line: 12, Character: 15},
@@ -459,13 +459,13 @@ onStart => {
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		result, _, astFile, err := s.compileAndGetASTFileForDocumentURI("file:///MySprite.spx")
+		result, err := s.compileAndGetASTFileForDocumentURI(t.Context(), "file:///MySprite.spx")
 		require.NoError(t, err)
-		require.NotNil(t, astFile)
+		require.NotNil(t, result.astFile)
 
-		inlayHints := collectInlayHints(result, astFile, 0, 0)
+		inlayHints := collectInlayHints(result.compileResult, result.astFile, 0, 0)
 		assert.Empty(t, inlayHints)
 	})
 
@@ -480,13 +480,13 @@ onStart => {
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		result, _, astFile, err := s.compileAndGetASTFileForDocumentURI("file:///MySprite.spx")
+		result, err := s.compileAndGetASTFileForDocumentURI(t.Context(), "file:///MySprite.spx")
 		require.NoError(t, err)
-		require.NotNil(t, astFile)
+		require.NotNil(t, result.astFile)
 
-		inlayHints := collectInlayHints(result, astFile, 0, 0)
+		inlayHints := collectInlayHints(result.compileResult, result.astFile, 0, 0)
 		require.Len(t, inlayHints, 2)
 		assert.Equal(t, InlayHint{
 			Position: Position{Line: 2, Character: 12},
@@ -509,13 +509,13 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		result, _, astFile, err := s.compileAndGetASTFileForDocumentURI("file:///main.spx")
+		result, err := s.compileAndGetASTFileForDocumentURI(t.Context(), "file:///main.spx")
 		require.NoError(t, err)
-		require.NotNil(t, astFile)
+		require.NotNil(t, result.astFile)
 
-		inlayHints := collectInlayHints(result, astFile, 0, 0)
+		inlayHints := collectInlayHints(result.compileResult, result.astFile, 0, 0)
 		require.NotNil(t, inlayHints)
 		require.Len(t, inlayHints, 2)
 		assert.Equal(t, "a...", inlayHints[0].Label)
@@ -532,13 +532,13 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		result, _, astFile, err := s.compileAndGetASTFileForDocumentURI("file:///main.spx")
+		result, err := s.compileAndGetASTFileForDocumentURI(t.Context(), "file:///main.spx")
 		require.NoError(t, err)
-		require.NotNil(t, astFile)
+		require.NotNil(t, result.astFile)
 
-		inlayHints := collectInlayHints(result, astFile, 0, 0)
+		inlayHints := collectInlayHints(result.compileResult, result.astFile, 0, 0)
 		require.Len(t, inlayHints, 1)
 		assert.Equal(t, InlayHint{
 			Position: Position{Line: 4, Character: 9},
@@ -631,7 +631,7 @@ func TestSortInlayHints(t *testing.T) {
 )
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		params := &InlayHintParams{
 			TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
@@ -641,7 +641,7 @@ func TestSortInlayHints(t *testing.T) {
 			},
 		}
 
-		inlayHints, err := s.textDocumentInlayHint(params)
+		inlayHints, err := s.textDocumentInlayHint(t.Context(), params)
 		require.NoError(t, err)
 		require.Nil(t, inlayHints)
 		assert.Empty(t, inlayHints)
@@ -326,30 +326,23 @@ func (s *Server) kwargReferenceLocations(result *compileResult, obj gotypes.Obje
 	if typeInfo == nil {
 		return nil
 	}
-	astPkg, _ := result.proj.ASTPackage()
-	if astPkg == nil {
-		return nil
-	}
-
 	var locations []Location
-	for _, astFile := range astPkg.Files {
-		ast.Inspect(astFile, func(node ast.Node) bool {
-			callExpr, ok := node.(*ast.CallExpr)
-			if !ok {
-				return true
-			}
+	xgoutil.WalkAllNodes(result.proj, func(spxFile string, node ast.Node) error {
+		callExpr, ok := node.(*ast.CallExpr)
+		if !ok {
+			return nil
+		}
 
-			for _, kwarg := range callExpr.Kwargs {
-				for _, target := range lookupCallExprKwargTargets(result.proj, typeInfo, callExpr, kwarg.Name.Name) {
-					if !kwargTargetMatchesObject(target, obj) {
-						continue
-					}
-					locations = append(locations, s.locationForNode(result.proj, kwarg.Name))
+		for _, kwarg := range callExpr.Kwargs {
+			for _, target := range lookupCallExprKwargTargets(result.proj, typeInfo, callExpr, kwarg.Name.Name) {
+				if !kwargTargetMatchesObject(target, obj) {
+					continue
 				}
+				locations = append(locations, s.locationForNode(result.proj, kwarg.Name))
 			}
-			return true
-		})
-	}
+		}
+		return nil
+	})
 	return locations
 }
 
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"context"
+	gotypes "go/types"
+
+	"github.com/goplus/xgo/ast"
+	"github.com/goplus/xgo/token"
+	"github.com/goplus/xgolsw/xgo/xgoutil"
+)
+
+// xgoIdentWordPattern is the word pattern for valid XGo identifiers.
+const xgoIdentWordPattern = `[A-Za-z_][A-Za-z0-9_]*`
+
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#textDocument_linkedEditingRange
+func (s *Server) textDocumentLinkedEditingRange(ctx context.Context, params *LinkedEditingRangeParams) (*LinkedEditingRanges, error) {
+	result, err := s.compileAndGetASTFileForDocumentURI(ctx, params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	if !result.IsUsable() || result.spxFile != result.mainSpxFile {
+		return nil, nil
+	}
+	typeInfo, _ := result.proj.TypeInfo()
+	if typeInfo == nil {
+		return nil, nil
+	}
+	decl := result.astFile.ClassFieldsDecl()
+	if decl == nil {
+		return nil, nil
+	}
+	pos := PosAt(result.proj, result.astFile, params.Position)
+
+	for _, spec := range decl.Specs {
+		valueSpec, ok := spec.(*ast.ValueSpec)
+		if !ok {
+			continue
+		}
+		typeIdent, ok := valueSpec.Type.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		typeName, ok := typeInfo.ObjectOf(typeIdent).(*gotypes.TypeName)
+		if !ok {
+			continue
+		}
+		named, ok := xgoutil.DerefType(typeName.Type()).(*gotypes.Named)
+		if !ok || !result.hasSpxSpriteType(named) {
+			continue
+		}
+
+		idents := []*ast.Ident{typeIdent}
+		for _, name := range valueSpec.Names {
+			if name.Name == typeIdent.Name {
+				idents = append(idents, name)
+			}
+		}
+		if !identsContainPos(idents, pos) {
+			continue
+		}
+
+		ranges := make([]Range, 0, len(idents))
+		for _, ident := range idents {
+			ranges = append(ranges, RangeForNode(result.proj, ident))
+		}
+		return &LinkedEditingRanges{
+			Ranges:      ranges,
+			WordPattern: xgoIdentWordPattern,
+		}, nil
+	}
+	return nil, nil
+}
+
+// identsContainPos reports whether any of the given identifiers contains pos.
+func identsContainPos(idents []*ast.Ident, pos token.Pos) bool {
+	for _, ident := range idents {
+		if ident.Pos() <= pos && pos <= ident.End() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerTextDocumentLinkedEditingRange(t *testing.T) {
+	m := map[string][]byte{
+		"main.spx": []byte(`
+var (
+	MySprite MySprite
+	score    int
+)
+
+MySprite.turn Left
+`),
+		"MySprite.spx": []byte(`
+onStart => {
+	say "Hi"
+}
+`),
+		"assets/index.json":                  []byte(`{}`),
+		"assets/sprites/MySprite/index.json": []byte(`{}`),
+	}
+	s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+	wantRanges := &LinkedEditingRanges{
+		Ranges: []Range{
+			{
+				Start: Position{Line: 2, Character: 10},
+				End:   Position{Line: 2, Character: 18},
+			},
+			{
+				Start: Position{Line: 2, Character: 1},
+				End:   Position{Line: 2, Character: 9},
+			},
+		},
+		WordPattern: xgoIdentWordPattern,
+	}
+
+	for _, tt := range []struct {
+		name     string
+		uri      DocumentURI
+		position Position
+		want     *LinkedEditingRanges
+	}{
+		{
+			name:     "OnFieldName",
+			uri:      "file:///main.spx",
+			position: Position{Line: 2, Character: 3},
+			want:     wantRanges,
+		},
+		{
+			name:     "OnTypeName",
+			uri:      "file:///main.spx",
+			position: Position{Line: 2, Character: 12},
+			want:     wantRanges,
+		},
+		{
+			name:     "OnNonSpriteField",
+			uri:      "file:///main.spx",
+			position: Position{Line: 3, Character: 2},
+		},
+		{
+			name:     "OutsideFirstVarBlock",
+			uri:      "file:///main.spx",
+			position: Position{Line: 6, Character: 2},
+		},
+		{
+			name:     "NotMainSpxFile",
+			uri:      "file:///MySprite.spx",
+			position: Position{Line: 2, Character: 2},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.textDocumentLinkedEditingRange(t.Context(), &LinkedEditingRangeParams{
+				TextDocumentPositionParams: TextDocumentPositionParams{
+					TextDocument: TextDocumentIdentifier{URI: tt.uri},
+					Position:     tt.position,
+				},
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
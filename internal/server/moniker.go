@@ -0,0 +1,59 @@
+package server
+
+import (
+	"context"
+	gotypes "go/types"
+	"strings"
+
+	"github.com/goplus/xgolsw/xgo/xgoutil"
+)
+
+// monikerScheme is the scheme of monikers returned by [Server.textDocumentMoniker].
+const monikerScheme = "xgo"
+
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.18/specification/#textDocument_moniker
+func (s *Server) textDocumentMoniker(ctx context.Context, params *MonikerParams) ([]Moniker, error) {
+	result, err := s.compileAndGetASTFileForDocumentURI(ctx, params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	if !result.IsUsable() {
+		return nil, nil
+	}
+	typeInfo, _ := result.proj.TypeInfo()
+	if typeInfo == nil {
+		return nil, nil
+	}
+	position := ToPosition(result.proj, result.astFile, params.Position)
+	ident, obj, _ := objectAtPosition(result.proj, typeInfo, result.astFile, position)
+	if ident == nil || obj == nil {
+		return nil, nil
+	}
+
+	kind, unique := monikerKindForObj(obj)
+	spxDefs := result.spxDefinitionsForIdent(ident)
+	monikers := make([]Moniker, 0, len(spxDefs))
+	for _, spxDef := range spxDefs {
+		monikers = append(monikers, Moniker{
+			Scheme:     monikerScheme,
+			Identifier: strings.TrimPrefix(spxDef.ID.String(), monikerScheme+":"),
+			Unique:     unique,
+			Kind:       &kind,
+		})
+	}
+	return monikers, nil
+}
+
+// monikerKindForObj returns the moniker kind and the uniqueness level of the
+// moniker for the given object. Objects from other packages are imported, and
+// package-level objects of the main package, including their fields and
+// methods, are exported. Other objects of the main package are local.
+func monikerKindForObj(obj gotypes.Object) (MonikerKind, UniquenessLevel) {
+	if !xgoutil.IsInMainPkg(obj) {
+		return MonikerKindImport, UniquenessGlobal
+	}
+	if parent := obj.Parent(); parent != nil && parent != obj.Pkg().Scope() {
+		return MonikerKindLocal, UniquenessDocument
+	}
+	return MonikerKindExport, UniquenessProject
+}
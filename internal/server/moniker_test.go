@@ -0,0 +1,79 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerTextDocumentMoniker(t *testing.T) {
+	m := map[string][]byte{
+		"main.spx": []byte(`
+var (
+	count int
+)
+
+func add(n int) {
+	sum := count + n
+	echo sum
+}
+`),
+		"MySprite.spx": []byte(`
+onStart => {
+	move 10
+	add 1
+}
+`),
+		"assets/index.json":                  []byte(`{}`),
+		"assets/sprites/MySprite/index.json": []byte(`{}`),
+	}
+	s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+	monikerAt := func(t *testing.T, uri DocumentURI, position Position) []Moniker {
+		t.Helper()
+		monikers, err := s.textDocumentMoniker(t.Context(), &MonikerParams{
+			TextDocumentPositionParams: TextDocumentPositionParams{
+				TextDocument: TextDocumentIdentifier{URI: uri},
+				Position:     position,
+			},
+		})
+		require.NoError(t, err)
+		return monikers
+	}
+
+	t.Run("SpxMethod", func(t *testing.T) {
+		monikers := monikerAt(t, "file:///MySprite.spx", Position{Line: 2, Character: 2})
+		require.NotEmpty(t, monikers)
+		for _, moniker := range monikers {
+			assert.Equal(t, "xgo", moniker.Scheme)
+			assert.Contains(t, moniker.Identifier, SpxPkgPath+"?Sprite.move")
+			assert.Equal(t, UniquenessGlobal, moniker.Unique)
+			require.NotNil(t, moniker.Kind)
+			assert.Equal(t, MonikerKindImport, *moniker.Kind)
+		}
+	})
+
+	t.Run("MainPkgFunc", func(t *testing.T) {
+		monikers := monikerAt(t, "file:///MySprite.spx", Position{Line: 3, Character: 2})
+		require.Len(t, monikers, 1)
+		assert.Equal(t, "main?Game.add", monikers[0].Identifier)
+		assert.Equal(t, UniquenessProject, monikers[0].Unique)
+		require.NotNil(t, monikers[0].Kind)
+		assert.Equal(t, MonikerKindExport, *monikers[0].Kind)
+	})
+
+	t.Run("LocalVar", func(t *testing.T) {
+		monikers := monikerAt(t, "file:///main.spx", Position{Line: 7, Character: 7})
+		require.Len(t, monikers, 1)
+		assert.Equal(t, "main?sum", monikers[0].Identifier)
+		assert.Equal(t, UniquenessDocument, monikers[0].Unique)
+		require.NotNil(t, monikers[0].Kind)
+		assert.Equal(t, MonikerKindLocal, *monikers[0].Kind)
+	})
+
+	t.Run("NoIdent", func(t *testing.T) {
+		monikers := monikerAt(t, "file:///main.spx", Position{Line: 0, Character: 0})
+		assert.Nil(t, monikers)
+	})
+}
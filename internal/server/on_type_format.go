@@ -0,0 +1,194 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/goplus/xgo/scanner"
+	"github.com/goplus/xgo/token"
+)
+
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.18/specification/#textDocument_onTypeFormatting
+func (s *Server) textDocumentOnTypeFormatting(params *DocumentOnTypeFormattingParams) ([]TextEdit, error) {
+	spxFile, err := s.fromDocumentURI(params.TextDocument.URI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file path from document uri %q: %w", params.TextDocument.URI, err)
+	}
+	if path.Ext(spxFile) != ".spx" {
+		return nil, nil // Not an spx source file.
+	}
+
+	file, ok := s.getProj().File(spxFile)
+	if !ok {
+		return nil, fmt.Errorf("failed to read spx source file: %w", fs.ErrNotExist)
+	}
+	// FIXME(wyvern): Remove this workaround when the server supports CRLF line endings.
+	content := bytes.ReplaceAll(file.Content, []byte("\r\n"), []byte("\n"))
+
+	lines := strings.Split(string(content), "\n")
+	if int(params.Position.Line) >= len(lines) {
+		return nil, nil
+	}
+	f := &onTypeFormatter{
+		content: content,
+		lines:   lines,
+		line:    params.Position.Line,
+		offset:  PositionOffset(content, params.Position),
+	}
+	switch params.Ch {
+	case "\n":
+		return f.formatNewline(), nil
+	case "{":
+		return f.formatOpenBrace(), nil
+	case "}":
+		return f.formatCloseBrace(), nil
+	}
+	return nil, nil
+}
+
+// onTypeFormatter computes on-type formatting edits for a document. Following
+// the XGo formatting rules, each nested block is indented by one tab.
+type onTypeFormatter struct {
+	content []byte
+	lines   []string
+	line    uint32 // Line of the position where the character was typed.
+	offset  int    // Byte offset of the position where the character was typed.
+}
+
+// formatNewline handles a newline typed at the start of f.line. It indents the
+// new line according to the enclosing block depth, and closes the block if
+// the previous line opens a block that is never closed.
+func (f *onTypeFormatter) formatNewline() []TextEdit {
+	if f.line == 0 {
+		return nil
+	}
+	depth := len(unclosedBraceOffsets(f.content, f.offset))
+	indent := strings.Repeat("\t", depth)
+	closeIndent := strings.Repeat("\t", max(depth-1, 0))
+
+	lineText := f.lines[f.line]
+	leading := leadingWhitespace(lineText)
+	rest := lineText[len(leading):]
+	leadingRange := Range{
+		Start: Position{Line: f.line, Character: 0},
+		End:   Position{Line: f.line, Character: uint32(UTF16Len(leading))},
+	}
+
+	prevLineText := strings.TrimSpace(f.lines[f.line-1])
+	opensBlock := strings.HasSuffix(prevLineText, "{")
+	if opensBlock && strings.HasPrefix(rest, "}") {
+		// The closing brace was auto-inserted by the client, move it to
+		// its own line.
+		return []TextEdit{{Range: leadingRange, NewText: indent + "\n" + closeIndent}}
+	}
+	if opensBlock && len(unclosedBraceOffsets(f.content, len(f.content))) > 0 {
+		closing := "\n" + closeIndent + "}"
+		if rest == "" {
+			return []TextEdit{{Range: leadingRange, NewText: indent + closing}}
+		}
+		lineEnd := Position{Line: f.line, Character: uint32(UTF16Len(lineText))}
+		return []TextEdit{
+			{Range: leadingRange, NewText: indent},
+			{Range: Range{Start: lineEnd, End: lineEnd}, NewText: closing},
+		}
+	}
+	if leading == indent {
+		return nil
+	}
+	return []TextEdit{{Range: leadingRange, NewText: indent}}
+}
+
+// formatOpenBrace handles a `{` typed at the end of f.line. It inserts the
+// matching `}` on the next line if the block is never closed.
+func (f *onTypeFormatter) formatOpenBrace() []TextEdit {
+	lineText := f.lines[f.line]
+	if strings.TrimSpace(lineText[len(f.lineTextBefore()):]) != "" {
+		return nil
+	}
+	if len(unclosedBraceOffsets(f.content, len(f.content))) == 0 {
+		return nil
+	}
+	lineEnd := Position{Line: f.line, Character: uint32(UTF16Len(lineText))}
+	return []TextEdit{{
+		Range:   Range{Start: lineEnd, End: lineEnd},
+		NewText: "\n" + leadingWhitespace(lineText) + "}",
+	}}
+}
+
+// formatCloseBrace handles a `}` typed at the start of f.line. It aligns the
+// indentation of the line with the line of the matching `{`.
+func (f *onTypeFormatter) formatCloseBrace() []TextEdit {
+	before := f.lineTextBefore()
+	if !strings.HasSuffix(before, "}") {
+		return nil
+	}
+	leading := strings.TrimSuffix(before, "}")
+	if strings.TrimSpace(leading) != "" {
+		return nil
+	}
+
+	openOffsets := unclosedBraceOffsets(f.content, f.offset-1)
+	if len(openOffsets) == 0 {
+		return nil
+	}
+	openOffset := openOffsets[len(openOffsets)-1]
+	openLineStart := bytes.LastIndexByte(f.content[:openOffset], '\n') + 1
+	indent := leadingWhitespace(string(f.content[openLineStart:openOffset]))
+	if leading == indent {
+		return nil
+	}
+	return []TextEdit{{
+		Range: Range{
+			Start: Position{Line: f.line, Character: 0},
+			End:   Position{Line: f.line, Character: uint32(UTF16Len(leading))},
+		},
+		NewText: indent,
+	}}
+}
+
+// lineTextBefore returns the text of f.line before f.offset.
+func (f *onTypeFormatter) lineTextBefore() string {
+	lineStart := bytes.LastIndexByte(f.content[:f.offset], '\n') + 1
+	return string(f.content[lineStart:f.offset])
+}
+
+// unclosedBraceOffsets returns the byte offsets of all `{` tokens in src
+// before offset that are not closed before offset, from outermost to
+// innermost. Braces in comments and string literals are ignored.
+func unclosedBraceOffsets(src []byte, offset int) []int {
+	fset := token.NewFileSet()
+	file := fset.AddFile("", -1, len(src))
+
+	var (
+		s       scanner.Scanner
+		offsets []int
+	)
+	s.Init(file, src, nil, 0)
+	for {
+		pos, tok, _ := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		tokOffset := file.Offset(pos)
+		if tokOffset >= offset {
+			break
+		}
+		switch tok {
+		case token.LBRACE:
+			offsets = append(offsets, tokOffset)
+		case token.RBRACE:
+			if len(offsets) > 0 {
+				offsets = offsets[:len(offsets)-1]
+			}
+		}
+	}
+	return offsets
+}
+
+// leadingWhitespace returns the leading spaces and tabs of s.
+func leadingWhitespace(s string) string {
+	return s[:len(s)-len(strings.TrimLeft(s, " \t"))]
+}
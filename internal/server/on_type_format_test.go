@@ -0,0 +1,133 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerTextDocumentOnTypeFormatting(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		content  string
+		position Position
+		ch       string
+		want     []TextEdit
+	}{
+		{
+			name:     "NewlineAfterEventHandler",
+			content:  "onStart => {\n",
+			position: Position{Line: 1, Character: 0},
+			ch:       "\n",
+			want: []TextEdit{{
+				Range:   Range{Start: Position{Line: 1, Character: 0}, End: Position{Line: 1, Character: 0}},
+				NewText: "\t\n}",
+			}},
+		},
+		{
+			name:     "NewlineAfterNestedBlock",
+			content:  "onStart => {\n\tif true {\n\n}\n",
+			position: Position{Line: 2, Character: 0},
+			ch:       "\n",
+			want: []TextEdit{{
+				Range:   Range{Start: Position{Line: 2, Character: 0}, End: Position{Line: 2, Character: 0}},
+				NewText: "\t\t\n\t}",
+			}},
+		},
+		{
+			name:     "NewlineBeforeAutoClosedBrace",
+			content:  "onStart => {\n}\n",
+			position: Position{Line: 1, Character: 0},
+			ch:       "\n",
+			want: []TextEdit{{
+				Range:   Range{Start: Position{Line: 1, Character: 0}, End: Position{Line: 1, Character: 0}},
+				NewText: "\t\n",
+			}},
+		},
+		{
+			name:     "NewlineInClosedBlock",
+			content:  "onStart => {\n\tsay \"Hi\"\n  \n}\n",
+			position: Position{Line: 2, Character: 2},
+			ch:       "\n",
+			want: []TextEdit{{
+				Range:   Range{Start: Position{Line: 2, Character: 0}, End: Position{Line: 2, Character: 2}},
+				NewText: "\t",
+			}},
+		},
+		{
+			name:     "NewlineWithCorrectIndent",
+			content:  "onStart => {\n\tsay \"Hi\"\n\t\n}\n",
+			position: Position{Line: 2, Character: 1},
+			ch:       "\n",
+		},
+		{
+			name:     "NewlineIgnoresBracesInStrings",
+			content:  "echo \"{\"\n",
+			position: Position{Line: 1, Character: 0},
+			ch:       "\n",
+		},
+		{
+			name:     "OpenBrace",
+			content:  "\tonStart => {\n",
+			position: Position{Line: 0, Character: 13},
+			ch:       "{",
+			want: []TextEdit{{
+				Range:   Range{Start: Position{Line: 0, Character: 13}, End: Position{Line: 0, Character: 13}},
+				NewText: "\n\t}",
+			}},
+		},
+		{
+			name:     "OpenBraceAlreadyClosed",
+			content:  "onStart => {}\n",
+			position: Position{Line: 0, Character: 12},
+			ch:       "{",
+		},
+		{
+			name:     "CloseBrace",
+			content:  "onStart => {\n\tsay \"Hi\"\n\t}\n",
+			position: Position{Line: 2, Character: 2},
+			ch:       "}",
+			want: []TextEdit{{
+				Range:   Range{Start: Position{Line: 2, Character: 0}, End: Position{Line: 2, Character: 1}},
+				NewText: "",
+			}},
+		},
+		{
+			name:     "CloseBraceNotAtLineStart",
+			content:  "onStart => { say \"Hi\" }\n",
+			position: Position{Line: 0, Character: 23},
+			ch:       "}",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			m := map[string][]byte{
+				"main.spx": []byte(tt.content),
+			}
+			s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+			edits, err := s.textDocumentOnTypeFormatting(&DocumentOnTypeFormattingParams{
+				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+				Position:     tt.position,
+				Ch:           tt.ch,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, edits)
+		})
+	}
+
+	t.Run("NonSpxFile", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.xgo": []byte("func f() {\n"),
+		}
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+		edits, err := s.textDocumentOnTypeFormatting(&DocumentOnTypeFormattingParams{
+			TextDocument: TextDocumentIdentifier{URI: "file:///main.xgo"},
+			Position:     Position{Line: 1, Character: 0},
+			Ch:           "\n",
+		})
+		require.NoError(t, err)
+		assert.Nil(t, edits)
+	})
+}
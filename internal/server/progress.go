@@ -0,0 +1,181 @@
+package server
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/goplus/xgolsw/jsonrpc2"
+	"github.com/goplus/xgolsw/protocol"
+)
+
+// ProgressReporter is an interface for reporting the progress of slow
+// operations, such as type-checking a large project.
+type ProgressReporter interface {
+	// Begin reports that the operation identified by token has started.
+	Begin(token, title string)
+
+	// End reports that the operation identified by token has finished.
+	End(token string)
+}
+
+// nopProgressReporter is a [ProgressReporter] that reports nothing.
+type nopProgressReporter struct{}
+
+// Begin implements [ProgressReporter].
+func (nopProgressReporter) Begin(token, title string) {}
+
+// End implements [ProgressReporter].
+func (nopProgressReporter) End(token string) {}
+
+// NewProgressNotifier creates a [ProgressReporter] that reports progress to
+// the client via `$/progress` notifications.
+//
+// Each progress is first created with a `window/workDoneProgress/create`
+// request, and its notifications are only sent once the client has accepted
+// the request. The [Server] using the reporter forwards the client responses
+// to it.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#progress
+func NewProgressNotifier(replier MessageReplier) ProgressReporter {
+	return &progressNotifier{
+		replier:    replier,
+		progresses: make(map[jsonrpc2.ID]*notifiedProgress),
+	}
+}
+
+// progressNotifier implements [ProgressReporter] by sending `$/progress`
+// notifications.
+type progressNotifier struct {
+	replier MessageReplier
+
+	mu         sync.Mutex
+	progresses map[jsonrpc2.ID]*notifiedProgress // Keyed by the ID of the create request.
+}
+
+// notifiedProgress is a progress reported by [progressNotifier] that has not
+// ended yet, or whose creation has not been accepted by the client yet.
+type notifiedProgress struct {
+	token   string
+	title   string
+	created bool // Whether the client has accepted the create request.
+	ended   bool // Whether [progressNotifier.End] has been called.
+}
+
+// Begin implements [ProgressReporter].
+func (n *progressNotifier) Begin(token, title string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	id := jsonrpc2.NewStringID(token)
+	call, err := jsonrpc2.NewCall(id, "window/workDoneProgress/create", &protocol.WorkDoneProgressCreateParams{
+		Token: token,
+	})
+	if err != nil {
+		return
+	}
+	n.progresses[id] = &notifiedProgress{token: token, title: title}
+	if err := n.replier.ReplyMessage(call); err != nil {
+		delete(n.progresses, id)
+	}
+}
+
+// End implements [ProgressReporter].
+func (n *progressNotifier) End(token string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	id := jsonrpc2.NewStringID(token)
+	progress, ok := n.progresses[id]
+	if !ok {
+		return
+	}
+	if !progress.created {
+		// The end is reported once the client accepts the create request.
+		progress.ended = true
+		return
+	}
+	delete(n.progresses, id)
+	n.notifyEnd(progress)
+}
+
+// handleResponse handles the client response to a create request sent by
+// [progressNotifier.Begin]. It reports false if resp is not such a response.
+func (n *progressNotifier) handleResponse(resp *jsonrpc2.Response) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	progress, ok := n.progresses[resp.ID()]
+	if !ok {
+		return false
+	}
+	if resp.Err() != nil {
+		// The token must not be used if the client failed to create it.
+		delete(n.progresses, resp.ID())
+		return true
+	}
+
+	progress.created = true
+	n.notify(progress.token, &protocol.WorkDoneProgressBegin{
+		Kind:  "begin",
+		Title: progress.title,
+	})
+	if progress.ended {
+		delete(n.progresses, resp.ID())
+		n.notifyEnd(progress)
+	}
+	return true
+}
+
+// notifyEnd sends the `$/progress` notification that ends the given progress.
+func (n *progressNotifier) notifyEnd(progress *notifiedProgress) {
+	n.notify(progress.token, &protocol.WorkDoneProgressEnd{
+		Kind: "end",
+	})
+}
+
+// notify sends a `$/progress` notification with the given token and value.
+func (n *progressNotifier) notify(token string, value any) {
+	notification, err := jsonrpc2.NewNotification("$/progress", &protocol.ProgressParams{
+		Token: token,
+		Value: value,
+	})
+	if err != nil {
+		return
+	}
+	n.replier.ReplyMessage(notification)
+}
+
+// responseHandler is implemented by a [ProgressReporter] that handles client
+// responses to the requests it sends.
+type responseHandler interface {
+	// handleResponse handles resp and reports whether it was expected.
+	handleResponse(resp *jsonrpc2.Response) bool
+}
+
+// handleResponse handles a client response to a request sent by the server.
+// Unexpected responses are ignored.
+func (s *Server) handleResponse(resp *jsonrpc2.Response) {
+	if h, ok := s.progressReporter.(responseHandler); ok {
+		h.handleResponse(resp)
+	}
+}
+
+// SetProgressReporter sets the [ProgressReporter] used to report the progress
+// of slow operations. A nil reporter disables progress reporting.
+func (s *Server) SetProgressReporter(reporter ProgressReporter) {
+	if reporter == nil {
+		reporter = nopProgressReporter{}
+	}
+	s.progressReporter = reporter
+}
+
+// typeCheckProgressTitle is the title of type-check progress reports.
+const typeCheckProgressTitle = "Type checking"
+
+// withProgress runs fn while reporting its progress with the given title.
+func (s *Server) withProgress(title string, fn func()) {
+	token := fmt.Sprintf("xgolsw/progress/%d", s.progressTokenSeq.Add(1))
+	s.progressReporter.Begin(token, title)
+	defer s.progressReporter.End(token)
+	fn()
+}
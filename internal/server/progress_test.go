@@ -0,0 +1,208 @@
+package server
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/goplus/xgolsw/jsonrpc2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockProgressReporter implements [ProgressReporter] by recording calls.
+type mockProgressReporter struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *mockProgressReporter) Begin(token, title string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, "Begin:"+token+":"+title)
+}
+
+func (r *mockProgressReporter) End(token string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, "End:"+token)
+}
+
+func TestServerProgressReporter(t *testing.T) {
+	t.Run("TypeCheck", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.spx": []byte(`
+var x = 100
+echo x
+`),
+		}
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+		reporter := &mockProgressReporter{}
+		s.SetProgressReporter(reporter)
+
+		_, err := s.compile(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"Begin:xgolsw/progress/1:" + typeCheckProgressTitle,
+			"End:xgolsw/progress/1",
+		}, reporter.calls)
+	})
+
+	t.Run("CachedTypeInfo", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.spx": []byte(`
+var x = 100
+echo x
+`),
+		}
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+		reporter := &mockProgressReporter{}
+		s.SetProgressReporter(reporter)
+
+		_, err := s.compile(t.Context())
+		require.NoError(t, err)
+		_, err = s.compile(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"Begin:xgolsw/progress/1:" + typeCheckProgressTitle,
+			"End:xgolsw/progress/1",
+		}, reporter.calls)
+	})
+
+	t.Run("NilReporter", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.spx": []byte(`echo 1`),
+		}
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+		s.SetProgressReporter(nil)
+
+		_, err := s.compile(t.Context())
+		require.NoError(t, err)
+	})
+}
+
+func TestProgressNotifier(t *testing.T) {
+	// progressKinds returns the kinds of the `$/progress` notifications in
+	// messages, and requires the other messages to be create requests.
+	progressKinds := func(t *testing.T, messages []jsonrpc2.Message) []string {
+		var kinds []string
+		for _, msg := range messages {
+			switch msg := msg.(type) {
+			case *jsonrpc2.Call:
+				assert.Equal(t, "window/workDoneProgress/create", msg.Method())
+				var params struct {
+					Token string `json:"token"`
+				}
+				require.NoError(t, json.Unmarshal(msg.Params(), &params))
+				assert.Equal(t, "token", params.Token)
+				kinds = append(kinds, "create")
+			case *jsonrpc2.Notification:
+				assert.Equal(t, "$/progress", msg.Method())
+				var params struct {
+					Token string `json:"token"`
+					Value struct {
+						Kind  string `json:"kind"`
+						Title string `json:"title"`
+					} `json:"value"`
+				}
+				require.NoError(t, json.Unmarshal(msg.Params(), &params))
+				assert.Equal(t, "token", params.Token)
+				if params.Value.Kind == "begin" {
+					assert.Equal(t, "Title", params.Value.Title)
+				}
+				kinds = append(kinds, params.Value.Kind)
+			default:
+				t.Fatalf("unexpected message type: %T", msg)
+			}
+		}
+		return kinds
+	}
+	newResponse := func(t *testing.T, err error) *jsonrpc2.Response {
+		resp, rerr := jsonrpc2.NewResponse(jsonrpc2.NewStringID("token"), nil, err)
+		require.NoError(t, rerr)
+		return resp
+	}
+
+	t.Run("Created", func(t *testing.T) {
+		replier := newMockReplier()
+		notifier := NewProgressNotifier(replier).(*progressNotifier)
+
+		notifier.Begin("token", "Title")
+		assert.Equal(t, []string{"create"}, progressKinds(t, replier.getMessages()))
+
+		assert.True(t, notifier.handleResponse(newResponse(t, nil)))
+		assert.Equal(t, []string{"create", "begin"}, progressKinds(t, replier.getMessages()))
+
+		notifier.End("token")
+		assert.Equal(t, []string{"create", "begin", "end"}, progressKinds(t, replier.getMessages()))
+		assert.Empty(t, notifier.progresses)
+	})
+
+	t.Run("EndedBeforeCreated", func(t *testing.T) {
+		replier := newMockReplier()
+		notifier := NewProgressNotifier(replier).(*progressNotifier)
+
+		notifier.Begin("token", "Title")
+		notifier.End("token")
+		assert.Equal(t, []string{"create"}, progressKinds(t, replier.getMessages()))
+
+		assert.True(t, notifier.handleResponse(newResponse(t, nil)))
+		assert.Equal(t, []string{"create", "begin", "end"}, progressKinds(t, replier.getMessages()))
+		assert.Empty(t, notifier.progresses)
+	})
+
+	t.Run("CreateFailed", func(t *testing.T) {
+		replier := newMockReplier()
+		notifier := NewProgressNotifier(replier).(*progressNotifier)
+
+		notifier.Begin("token", "Title")
+		assert.True(t, notifier.handleResponse(newResponse(t, jsonrpc2.NewError(-32601, "method not found"))))
+		notifier.End("token")
+		assert.Equal(t, []string{"create"}, progressKinds(t, replier.getMessages()))
+		assert.Empty(t, notifier.progresses)
+	})
+
+	t.Run("UnexpectedResponse", func(t *testing.T) {
+		replier := newMockReplier()
+		notifier := NewProgressNotifier(replier).(*progressNotifier)
+
+		assert.False(t, notifier.handleResponse(newResponse(t, nil)))
+		assert.Empty(t, replier.getMessages())
+	})
+
+	t.Run("ServerForwardsResponses", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.spx": []byte(`echo 1`),
+		}
+		replier := newMockReplier()
+		s := New(newProjectWithoutModTime(m), replier, fileMapGetter(m), &MockScheduler{}, nil)
+		s.SetProgressReporter(NewProgressNotifier(replier))
+
+		_, err := s.compile(t.Context())
+		require.NoError(t, err)
+		messages := replier.getMessages()
+		require.Len(t, messages, 1)
+		call, ok := messages[0].(*jsonrpc2.Call)
+		require.True(t, ok)
+		assert.Equal(t, "window/workDoneProgress/create", call.Method())
+
+		resp, err := jsonrpc2.NewResponse(call.ID(), nil, nil)
+		require.NoError(t, err)
+		require.NoError(t, s.HandleMessage(resp))
+
+		var kinds []string
+		for _, msg := range replier.getMessages()[1:] {
+			notification, ok := msg.(*jsonrpc2.Notification)
+			require.True(t, ok)
+			assert.Equal(t, "$/progress", notification.Method())
+			var params struct {
+				Value struct {
+					Kind string `json:"kind"`
+				} `json:"value"`
+			}
+			require.NoError(t, json.Unmarshal(notification.Params(), &params))
+			kinds = append(kinds, params.Value.Kind)
+		}
+		assert.Equal(t, []string{"begin", "end"}, kinds)
+	})
+}
@@ -30,25 +30,31 @@ type (
 	DocumentHighlightParams = protocol.DocumentHighlightParams
 	DocumentHighlight       = protocol.DocumentHighlight
 
-	DocumentFormattingParams = protocol.DocumentFormattingParams
+	DocumentFormattingParams       = protocol.DocumentFormattingParams
+	DocumentOnTypeFormattingParams = protocol.DocumentOnTypeFormattingParams
 
 	PrepareRenameParams = protocol.PrepareRenameParams
 	RenameParams        = protocol.RenameParams
-
-	Diagnostic                            = protocol.Diagnostic
-	DocumentDiagnosticParams              = protocol.DocumentDiagnosticParams
-	WorkspaceDiagnosticParams             = protocol.WorkspaceDiagnosticParams
-	DocumentDiagnosticReport              = protocol.DocumentDiagnosticReport
-	FullDocumentDiagnosticReport          = protocol.FullDocumentDiagnosticReport
-	RelatedFullDocumentDiagnosticReport   = protocol.RelatedFullDocumentDiagnosticReport
-	WorkspaceDiagnosticReport             = protocol.WorkspaceDiagnosticReport
-	WorkspaceDocumentDiagnosticReport     = protocol.WorkspaceDocumentDiagnosticReport
-	WorkspaceFullDocumentDiagnosticReport = protocol.WorkspaceFullDocumentDiagnosticReport
-	PublishDiagnosticsParams              = protocol.PublishDiagnosticsParams
-	PropertyRenamedParams                 = protocol.PropertyRenamedParams
+	RenameFilesParams   = protocol.RenameFilesParams
+	FileRename          = protocol.FileRename
+
+	Diagnostic                               = protocol.Diagnostic
+	DocumentDiagnosticParams                 = protocol.DocumentDiagnosticParams
+	WorkspaceDiagnosticParams                = protocol.WorkspaceDiagnosticParams
+	DocumentDiagnosticReport                 = protocol.DocumentDiagnosticReport
+	FullDocumentDiagnosticReport             = protocol.FullDocumentDiagnosticReport
+	RelatedFullDocumentDiagnosticReport      = protocol.RelatedFullDocumentDiagnosticReport
+	UnchangedDocumentDiagnosticReport        = protocol.UnchangedDocumentDiagnosticReport
+	RelatedUnchangedDocumentDiagnosticReport = protocol.RelatedUnchangedDocumentDiagnosticReport
+	WorkspaceDiagnosticReport                = protocol.WorkspaceDiagnosticReport
+	WorkspaceDocumentDiagnosticReport        = protocol.WorkspaceDocumentDiagnosticReport
+	WorkspaceFullDocumentDiagnosticReport    = protocol.WorkspaceFullDocumentDiagnosticReport
+	PublishDiagnosticsParams                 = protocol.PublishDiagnosticsParams
+	PropertyRenamedParams                    = protocol.PropertyRenamedParams
 
 	CompletionItem                  = protocol.CompletionItem
 	CompletionItemKind              = protocol.CompletionItemKind
+	CompletionItemTag               = protocol.CompletionItemTag
 	CompletionList                  = protocol.CompletionList
 	CompletionParams                = protocol.CompletionParams
 	Or_CompletionItem_documentation = protocol.Or_CompletionItem_documentation
@@ -95,11 +101,39 @@ type (
 	InlayHintParams = protocol.InlayHintParams
 	InlayHint       = protocol.InlayHint
 	InlayHintKind   = protocol.InlayHintKind
+
+	LinkedEditingRangeParams = protocol.LinkedEditingRangeParams
+	LinkedEditingRanges      = protocol.LinkedEditingRanges
+
+	SymbolKind                       = protocol.SymbolKind
+	CallHierarchyPrepareParams       = protocol.CallHierarchyPrepareParams
+	CallHierarchyItem                = protocol.CallHierarchyItem
+	CallHierarchyIncomingCallsParams = protocol.CallHierarchyIncomingCallsParams
+	CallHierarchyIncomingCall        = protocol.CallHierarchyIncomingCall
+
+	SelectionRangeParams = protocol.SelectionRangeParams
+	SelectionRange       = protocol.SelectionRange
+
+	DocumentColorParams     = protocol.DocumentColorParams
+	ColorInformation        = protocol.ColorInformation
+	Color                   = protocol.Color
+	ColorPresentationParams = protocol.ColorPresentationParams
+	ColorPresentation       = protocol.ColorPresentation
+
+	MonikerParams   = protocol.MonikerParams
+	Moniker         = protocol.Moniker
+	MonikerKind     = protocol.MonikerKind
+	UniquenessLevel = protocol.UniquenessLevel
+
+	MessageType       = protocol.MessageType
+	ShowMessageParams = protocol.ShowMessageParams
+	LogMessageParams  = protocol.LogMessageParams
 )
 
 const (
-	SeverityError   = protocol.SeverityError
-	SeverityWarning = protocol.SeverityWarning
+	SeverityError       = protocol.SeverityError
+	SeverityWarning     = protocol.SeverityWarning
+	SeverityInformation = protocol.SeverityInformation
 
 	TextCompletion      = protocol.TextCompletion
 	ClassCompletion     = protocol.ClassCompletion
@@ -114,12 +148,21 @@ const (
 	PropertyCompletion  = protocol.PropertyCompletion
 	FunctionCompletion  = protocol.FunctionCompletion
 	ModuleCompletion    = protocol.ModuleCompletion
+	SnippetCompletion   = protocol.SnippetCompletion
+
+	DeprecatedCompletion = protocol.ComplDeprecated
 
-	DiagnosticFull = protocol.DiagnosticFull
+	DiagnosticFull      = protocol.DiagnosticFull
+	DiagnosticUnchanged = protocol.DiagnosticUnchanged
 
 	Markdown = protocol.Markdown
 	Text     = protocol.Text
 
+	Class    = protocol.Class
+	Method   = protocol.Method
+	Function = protocol.Function
+	Event    = protocol.Event
+
 	Write = protocol.Write
 	Read  = protocol.Read
 
@@ -153,7 +196,22 @@ const (
 	Type      = protocol.Type
 	Parameter = protocol.Parameter
 
-	RequestCancelled = protocol.RequestCancelled
+	RequestCancelled     = protocol.RequestCancelled
+	ContentModified      = protocol.ContentModified
+	ServerNotInitialized = protocol.ServerNotInitialized
+
+	MonikerKindImport = protocol.Import
+	MonikerKindExport = protocol.Export
+	MonikerKindLocal  = protocol.Local
+
+	UniquenessDocument = protocol.Document
+	UniquenessProject  = protocol.Project
+	UniquenessGlobal   = protocol.Global
+
+	MessageTypeError   = protocol.Error
+	MessageTypeWarning = protocol.Warning
+	MessageTypeInfo    = protocol.Info
+	MessageTypeLog     = protocol.Log
 )
 
 // UnmarshalJSON unmarshals msg into the variable pointed to by params.
@@ -255,6 +313,19 @@ type XGoGetInputSlotsParams struct {
 	TextDocument protocol.TextDocumentIdentifier `json:"textDocument"`
 }
 
+// XGoSetInputSlotParams holds parameters to set the input of an XGo input
+// slot.
+type XGoSetInputSlotParams struct {
+	// The text document.
+	TextDocument protocol.TextDocumentIdentifier `json:"textDocument"`
+
+	// The range of the input slot, i.e., [XGoInputSlot.Range].
+	Range Range `json:"range"`
+
+	// The new input of the slot.
+	Input XGoInput `json:"input"`
+}
+
 // XGoGetPropertiesParams holds parameters to get properties for a specific target.
 type XGoGetPropertiesParams struct {
 	// The target name (object type) to retrieve properties for (e.g., 'Game' type or a sprite type name).
@@ -286,13 +357,204 @@ type XGoProperty struct {
 	Definition XGoDefinitionIdentifier `json:"definition"`
 }
 
+// SpxGetSpriteInfoParams holds parameters to get information about an spx
+// sprite.
+type SpxGetSpriteInfoParams struct {
+	// The sprite name.
+	Sprite string `json:"sprite"`
+}
+
+// SpxSpriteInfo describes an spx sprite.
+type SpxSpriteInfo struct {
+	// The URI of the source file of the sprite. It is empty if the sprite has
+	// no source file.
+	File DocumentURI `json:"file,omitempty"`
+
+	// The sprite resource.
+	Resource *SpxSpriteResource `json:"resource"`
+
+	// Whether the sprite is auto-bound to a field of the game.
+	AutoBinding bool `json:"autoBinding"`
+
+	// The definitions available on the sprite.
+	Definitions []SpxDefinition `json:"definitions"`
+}
+
+// SpxGetBackdropInfoParams holds parameters to get information about an spx
+// backdrop.
+type SpxGetBackdropInfoParams struct {
+	// The backdrop name.
+	Backdrop string `json:"backdrop"`
+}
+
+// SpxBackdropInfo describes an spx backdrop.
+type SpxBackdropInfo struct {
+	// The backdrop resource.
+	Resource *SpxBackdropResource `json:"resource"`
+
+	// The backdrop-related definitions of the game.
+	Definitions []SpxDefinition `json:"definitions"`
+}
+
+// SpxGetSoundInfoParams holds parameters to get information about an spx
+// sound.
+type SpxGetSoundInfoParams struct {
+	// The sound name.
+	Sound string `json:"sound"`
+}
+
+// SpxSoundInfo describes an spx sound.
+type SpxSoundInfo struct {
+	// The path of the sound file in the project, e.g.,
+	// "assets/sounds/ding/ding.wav".
+	Path string `json:"path"`
+
+	// The format of the sound file, which is one of "mp3", "wav", and "ogg",
+	// or "" if unknown.
+	Format string `json:"format"`
+
+	// The duration of the sound in milliseconds. It is 0 if the sound file is
+	// not accessible or its header cannot be parsed.
+	Duration int64 `json:"duration"`
+
+	// The sample rate of the sound in Hz. It is 0 if unknown.
+	SampleRate int `json:"sampleRate"`
+
+	// The number of channels of the sound. It is 0 if unknown.
+	Channels int `json:"channels"`
+}
+
+// SpxGetAnimationFramesParams holds parameters to get the frames of an spx
+// sprite animation or costume.
+type SpxGetAnimationFramesParams struct {
+	// The sprite name.
+	Sprite string `json:"sprite"`
+
+	// The animation name, or the costume name for a single frame.
+	Animation string `json:"animation"`
+}
+
+// SpxAnimationFrame describes a frame of an spx sprite animation.
+type SpxAnimationFrame struct {
+	// The path of the frame image in the project, e.g.,
+	// "assets/sprites/MySprite/costume1.png".
+	Path string `json:"path"`
+
+	// The width of the frame image in pixels. It is 0 if the image is not
+	// accessible in the project or its size cannot be determined.
+	Width int `json:"width"`
+
+	// The height of the frame image in pixels. It is 0 if the image is not
+	// accessible in the project or its size cannot be determined.
+	Height int `json:"height"`
+
+	// The frame rate of the animation in frames per second. It is 0 for a
+	// single costume or if not declared in the metadata.
+	FrameRate float64 `json:"frameRate"`
+}
+
+// SpxCheckCodeParams holds parameters to check the syntax of a snippet of XGo
+// source code.
+type SpxCheckCodeParams struct {
+	// The XGo source code to check.
+	Code string `json:"code"`
+
+	// The optional package name of the code.
+	Package string `json:"package,omitempty"`
+
+	// The optional import paths available to the code.
+	Imports []string `json:"imports,omitempty"`
+}
+
+// SpxProjectStructure gives an overview of an spx project.
+type SpxProjectStructure struct {
+	// The game defined in main.spx.
+	Game SpxGameStructure `json:"game"`
+
+	// The sprites of the project, sorted by name.
+	Sprites []SpxSpriteStructure `json:"sprites"`
+
+	// The number of sounds in the project.
+	SoundCount int `json:"soundCount"`
+
+	// The names of the widgets in the project, sorted.
+	Widgets []string `json:"widgets"`
+}
+
+// SpxGameStructure describes the game of an spx project.
+type SpxGameStructure struct {
+	// The URI of main.spx.
+	File DocumentURI `json:"file"`
+
+	// The number of backdrops of the game.
+	BackdropCount int `json:"backdropCount"`
+}
+
+// SpxSpriteStructure describes a sprite of an spx project.
+type SpxSpriteStructure struct {
+	// The sprite name.
+	Name string `json:"name"`
+
+	// The URI of the source file of the sprite. It is empty if the sprite has
+	// no source file.
+	File DocumentURI `json:"file,omitempty"`
+
+	// Whether the sprite is auto-bound to a field of the game.
+	AutoBinding bool `json:"autoBinding"`
+
+	// The number of costumes of the sprite.
+	CostumeCount int `json:"costumeCount"`
+
+	// The number of animations of the sprite.
+	AnimationCount int `json:"animationCount"`
+}
+
+// SpxGetEventHandlerPositionsParams represents parameters to get the
+// positions of the spx event handlers in a document.
+type SpxGetEventHandlerPositionsParams struct {
+	// The text document.
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+}
+
+// SpxEventHandlerPosition describes the position of an spx event handler.
+type SpxEventHandlerPosition struct {
+	// The event type, i.e., the name of the event handler function, e.g.,
+	// "onStart".
+	EventType string `json:"eventType"`
+
+	// The definition identifier of the event handler function.
+	Definition SpxDefinitionIdentifier `json:"definition"`
+
+	// The range of the whole event handler, including its body.
+	Range Range `json:"range"`
+}
+
+// XGoGetInputSlotsBatchResult is the result of getting XGo input slots for
+// multiple documents, keyed by document URI.
+type XGoGetInputSlotsBatchResult map[DocumentURI][]XGoInputSlot
+
 // XGoInputSlot describes a modifiable item in code.
 type XGoInputSlot struct {
-	Range           Range              `json:"range"`
-	Kind            XGoInputSlotKind   `json:"kind"`
-	Accept          XGoInputSlotAccept `json:"accept"`
-	Input           XGoInput           `json:"input"`
-	PredefinedNames []string           `json:"predefinedNames"`
+	Range           Range               `json:"range"`
+	EditRange       Range               `json:"editRange"`
+	Kind            XGoInputSlotKind    `json:"kind"`
+	Accept          XGoInputSlotAccept  `json:"accept"`
+	Input           XGoInput            `json:"input"`
+	PredefinedNames []XGoPredefinedName `json:"predefinedNames"`
+
+	// CanDelete indicates whether the expression of the slot can be removed
+	// without breaking the surrounding code, e.g., a trailing variadic argument.
+	CanDelete bool `json:"canDelete"`
+}
+
+// XGoPredefinedName describes a predefined name available for an input slot.
+type XGoPredefinedName struct {
+	// Name is the predefined name.
+	Name string `json:"name"`
+
+	// Priority is the relevance of the name. Names with higher priority are
+	// more relevant to the slot.
+	Priority int `json:"priority"`
 }
 
 // XGoInputSlotKind enumerates kinds of XGo input slots.
@@ -328,6 +590,7 @@ const (
 	XGoInputTypeDecimal           XGoInputType = "decimal"
 	XGoInputTypeBoolean           XGoInputType = "boolean"
 	XGoInputTypeUnknown           XGoInputType = "unknown"
+	XGoInputTypeStruct            XGoInputType = "struct"
 	XGoInputTypeSpxResourceName   XGoInputType = "spx-resource-name"
 	XGoInputTypeSpxSpriteInstance XGoInputType = "spx-sprite-instance"
 	XGoInputTypeSpxDirection      XGoInputType = "spx-direction"
@@ -421,6 +684,9 @@ type SpxInputSlotAccept = XGoInputSlotAccept
 // Deprecated: use XGoInput.
 type SpxInput = XGoInput
 
+// Deprecated: use XGoPredefinedName.
+type SpxPredefinedName = XGoPredefinedName
+
 // Deprecated: use XGoInputType.
 type SpxInputType = XGoInputType
 
@@ -431,6 +697,7 @@ const (
 	SpxInputTypeDecimal        SpxInputType = XGoInputTypeDecimal
 	SpxInputTypeBoolean        SpxInputType = XGoInputTypeBoolean
 	SpxInputTypeUnknown        SpxInputType = XGoInputTypeUnknown
+	SpxInputTypeStruct         SpxInputType = XGoInputTypeStruct
 	SpxInputTypeResourceName   SpxInputType = XGoInputTypeSpxResourceName
 	SpxInputTypeSpriteInstance SpxInputType = XGoInputTypeSpxSpriteInstance
 	SpxInputTypeDirection      SpxInputType = XGoInputTypeSpxDirection
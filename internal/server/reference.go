@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	gotypes "go/types"
 
 	"github.com/goplus/xgo/ast"
@@ -9,33 +10,33 @@ import (
 )
 
 // See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.18/specification/#textDocument_references
-func (s *Server) textDocumentReferences(params *ReferenceParams) ([]Location, error) {
-	result, _, astFile, err := s.compileAndGetASTFileForDocumentURI(params.TextDocument.URI)
+func (s *Server) textDocumentReferences(ctx context.Context, params *ReferenceParams) ([]Location, error) {
+	result, err := s.compileAndGetASTFileForDocumentURI(ctx, params.TextDocument.URI)
 	if err != nil {
 		return nil, err
 	}
-	if astFile == nil {
+	if !result.IsUsable() {
 		return nil, nil
 	}
-	position := ToPosition(result.proj, astFile, params.Position)
+	position := ToPosition(result.proj, result.astFile, params.Position)
 
 	typeInfo, _ := result.proj.TypeInfo()
 	if typeInfo == nil {
 		return nil, nil
 	}
-	_, obj, _ := objectAtPosition(result.proj, typeInfo, astFile, position)
+	_, obj, _ := objectAtPosition(result.proj, typeInfo, result.astFile, position)
 	if obj == nil {
 		return nil, nil
 	}
 
 	var locations []Location
 
-	locations = append(locations, s.findReferenceLocations(result, obj)...)
-	locations = append(locations, s.kwargReferenceLocations(result, obj)...)
+	locations = append(locations, s.findReferenceLocations(result.compileResult, obj)...)
+	locations = append(locations, s.kwargReferenceLocations(result.compileResult, obj)...)
 
 	if fn, ok := obj.(*gotypes.Func); ok && fn.Signature().Recv() != nil {
-		locations = append(locations, s.handleMethodReferences(result, fn)...)
-		locations = append(locations, s.handleEmbeddedFieldReferences(result, obj)...)
+		locations = append(locations, s.handleMethodReferences(result.compileResult, fn)...)
+		locations = append(locations, s.handleEmbeddedFieldReferences(result.compileResult, obj)...)
 	}
 
 	if params.Context.IncludeDeclaration {
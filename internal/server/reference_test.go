@@ -22,9 +22,9 @@ onStart => {
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		mainSpxMySpriteRef, err := s.textDocumentReferences(&ReferenceParams{
+		mainSpxMySpriteRef, err := s.textDocumentReferences(t.Context(), &ReferenceParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 1, Character: 0},
@@ -58,7 +58,7 @@ onStart => {
 			},
 		})
 
-		mainSpxTurnRef, err := s.textDocumentReferences(&ReferenceParams{
+		mainSpxTurnRef, err := s.textDocumentReferences(t.Context(), &ReferenceParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 1, Character: 9},
@@ -97,9 +97,9 @@ onStart => {
 		m := map[string][]byte{
 			"main.spx": []byte(`var x int`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		refs, err := s.textDocumentReferences(&ReferenceParams{
+		refs, err := s.textDocumentReferences(t.Context(), &ReferenceParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 99, Character: 99},
@@ -124,9 +124,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		refs, err := s.textDocumentReferences(&ReferenceParams{
+		refs, err := s.textDocumentReferences(t.Context(), &ReferenceParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 2, Character: 4},
@@ -190,9 +190,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		refs, err := s.textDocumentReferences(&ReferenceParams{
+		refs, err := s.textDocumentReferences(t.Context(), &ReferenceParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 4, Character: 5},
@@ -248,9 +248,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		refs, err := s.textDocumentReferences(&ReferenceParams{
+		refs, err := s.textDocumentReferences(t.Context(), &ReferenceParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 14, Character: 25},
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"fmt"
 	gotypes "go/types"
 	"slices"
@@ -52,15 +53,15 @@ func (s *Server) textDocumentPrepareRename(params *PrepareRenameParams) (*Range,
 }
 
 // See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.18/specification/#textDocument_rename
-func (s *Server) textDocumentRename(params *RenameParams) (*WorkspaceEdit, error) {
-	result, _, astFile, err := s.compileAndGetASTFileForDocumentURI(params.TextDocument.URI)
+func (s *Server) textDocumentRename(ctx context.Context, params *RenameParams) (*WorkspaceEdit, error) {
+	result, err := s.compileAndGetASTFileForDocumentURI(ctx, params.TextDocument.URI)
 	if err != nil {
 		return nil, err
 	}
-	if astFile == nil {
+	if !result.IsUsable() {
 		return nil, nil
 	}
-	position := ToPosition(result.proj, astFile, params.Position)
+	position := ToPosition(result.proj, result.astFile, params.Position)
 
 	typeInfo, _ := result.proj.TypeInfo()
 	if typeInfo == nil {
@@ -68,7 +69,7 @@ func (s *Server) textDocumentRename(params *RenameParams) (*WorkspaceEdit, error
 	}
 	astPkg, _ := result.proj.ASTPackage()
 
-	ident, obj, kwargTarget := objectAtPosition(result.proj, typeInfo, astFile, position)
+	ident, obj, kwargTarget := objectAtPosition(result.proj, typeInfo, result.astFile, position)
 	if xgoutil.IsBlankIdent(ident) || xgoutil.IsSyntheticThisIdent(result.proj.Fset, typeInfo, astPkg, ident) {
 		return nil, nil
 	}
@@ -80,7 +81,7 @@ func (s *Server) textDocumentRename(params *RenameParams) (*WorkspaceEdit, error
 		kwargParams.NewName = kwargDefinitionRenameText(obj, params.NewName)
 		params = &kwargParams
 	}
-	return s.renameObjectAtPosition(result, params, typeInfo, obj)
+	return s.renameObjectAtPosition(result.compileResult, params, typeInfo, obj)
 }
 
 // renameObjectAtPosition builds a workspace edit for renaming obj.
@@ -156,8 +157,7 @@ func (s *Server) spxRenameResourceAtRefs(result *compileResult, id SpxResourceID
 		}
 
 		node := ref.Node
-		nodePos := fset.Position(node.Pos())
-		nodeEnd := fset.Position(node.End())
+		nodePos, nodeEnd := xgoutil.TokenRange(fset, node)
 
 		if expr, ok := node.(ast.Expr); ok && gotypes.AssignableTo(typeInfo.TypeOf(expr), gotypes.Typ[gotypes.String]) {
 			if ident, ok := expr.(*ast.Ident); ok {
@@ -168,8 +168,7 @@ func (s *Server) spxRenameResourceAtRefs(result *compileResult, id SpxResourceID
 					parent, ok := defIdent.Obj.Decl.(*ast.ValueSpec)
 					if ok && slices.Contains(parent.Names, defIdent) && len(parent.Values) > 0 {
 						node = parent.Values[0]
-						nodePos = fset.Position(node.Pos())
-						nodeEnd = fset.Position(node.End())
+						nodePos, nodeEnd = xgoutil.TokenRange(fset, node)
 					}
 				}
 			}
@@ -185,8 +184,8 @@ func (s *Server) spxRenameResourceAtRefs(result *compileResult, id SpxResourceID
 		astFile := xgoutil.NodeASTFile(result.proj.Fset, astPkg, node)
 		textEdit := TextEdit{
 			Range: Range{
-				Start: FromPosition(result.proj, astFile, nodePos),
-				End:   FromPosition(result.proj, astFile, nodeEnd),
+				Start: LSPPosition(astFile.Code, nodePos),
+				End:   LSPPosition(astFile.Code, nodeEnd),
 			},
 			NewText: newName,
 		}
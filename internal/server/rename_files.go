@@ -0,0 +1,50 @@
+package server
+
+import (
+	"context"
+	"go/token"
+	"path"
+	"strings"
+)
+
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.18/specification/#workspace_willRenameFiles
+func (s *Server) workspaceWillRenameFiles(ctx context.Context, params *RenameFilesParams) (*WorkspaceEdit, error) {
+	result, err := s.compile(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var renameParams []XGoRenameResourceParams
+	for _, file := range params.Files {
+		oldSpxFile, err := s.fromDocumentURI(DocumentURI(file.OldURI))
+		if err != nil {
+			return nil, err
+		}
+		newSpxFile, err := s.fromDocumentURI(DocumentURI(file.NewURI))
+		if err != nil {
+			return nil, err
+		}
+		if path.Ext(oldSpxFile) != ".spx" || path.Ext(newSpxFile) != ".spx" || oldSpxFile == result.mainSpxFile {
+			continue
+		}
+
+		// The sprite name is derived from the spx file name, e.g.,
+		// `MySprite` for `MySprite.spx`.
+		oldSpriteName := strings.TrimSuffix(path.Base(oldSpxFile), ".spx")
+		newSpriteName := strings.TrimSuffix(path.Base(newSpxFile), ".spx")
+		if oldSpriteName == newSpriteName || !token.IsIdentifier(newSpriteName) {
+			continue
+		}
+		if result.spxResourceSet.Sprite(oldSpriteName) == nil {
+			continue
+		}
+		renameParams = append(renameParams, XGoRenameResourceParams{
+			Resource: XGoResourceIdentifier{URI: XGoResourceURI(SpxSpriteResourceID{SpriteName: oldSpriteName}.URI())},
+			NewName:  newSpriteName,
+		})
+	}
+	if len(renameParams) == 0 {
+		return nil, nil
+	}
+	return s.spxRenameResourcesWithCompileResult(result, renameParams)
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerWorkspaceWillRenameFiles(t *testing.T) {
+	m := map[string][]byte{
+		"main.spx": []byte(`
+onStart => {
+	MySprite.turn Left
+}
+`),
+		"MySprite.spx": []byte(`
+onStart => {
+	MySprite.say "Hi"
+}
+`),
+		"OtherSprite.spx": []byte(`
+onClick => {
+	MySprite.show
+}
+`),
+		"assets/index.json":                     []byte(`{}`),
+		"assets/sprites/MySprite/index.json":    []byte(`{}`),
+		"assets/sprites/OtherSprite/index.json": []byte(`{}`),
+	}
+	s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+	t.Run("SpriteFile", func(t *testing.T) {
+		workspaceEdit, err := s.workspaceWillRenameFiles(t.Context(), &RenameFilesParams{
+			Files: []FileRename{{OldURI: "file:///MySprite.spx", NewURI: "file:///Hero.spx"}},
+		})
+		require.NoError(t, err)
+		require.NotNil(t, workspaceEdit)
+
+		assert.ElementsMatch(t, []TextEdit{
+			{
+				Range:   Range{Start: Position{Line: 2, Character: 1}, End: Position{Line: 2, Character: 9}},
+				NewText: "Hero",
+			},
+		}, workspaceEdit.Changes["file:///main.spx"])
+		assert.ElementsMatch(t, []TextEdit{
+			{
+				Range:   Range{Start: Position{Line: 2, Character: 1}, End: Position{Line: 2, Character: 9}},
+				NewText: "Hero",
+			},
+		}, workspaceEdit.Changes["file:///MySprite.spx"])
+		assert.ElementsMatch(t, []TextEdit{
+			{
+				Range:   Range{Start: Position{Line: 2, Character: 1}, End: Position{Line: 2, Character: 9}},
+				NewText: "Hero",
+			},
+		}, workspaceEdit.Changes["file:///OtherSprite.spx"])
+	})
+
+	t.Run("MainSpxFile", func(t *testing.T) {
+		workspaceEdit, err := s.workspaceWillRenameFiles(t.Context(), &RenameFilesParams{
+			Files: []FileRename{{OldURI: "file:///main.spx", NewURI: "file:///Main.spx"}},
+		})
+		require.NoError(t, err)
+		assert.Nil(t, workspaceEdit)
+	})
+
+	t.Run("NonSpxFile", func(t *testing.T) {
+		workspaceEdit, err := s.workspaceWillRenameFiles(t.Context(), &RenameFilesParams{
+			Files: []FileRename{{OldURI: "file:///assets/index.json", NewURI: "file:///assets/index2.json"}},
+		})
+		require.NoError(t, err)
+		assert.Nil(t, workspaceEdit)
+	})
+
+	t.Run("InvalidNewSpriteName", func(t *testing.T) {
+		workspaceEdit, err := s.workspaceWillRenameFiles(t.Context(), &RenameFilesParams{
+			Files: []FileRename{{OldURI: "file:///MySprite.spx", NewURI: "file:///My Sprite.spx"}},
+		})
+		require.NoError(t, err)
+		assert.Nil(t, workspaceEdit)
+	})
+
+	t.Run("ExistingSpriteName", func(t *testing.T) {
+		_, err := s.workspaceWillRenameFiles(t.Context(), &RenameFilesParams{
+			Files: []FileRename{{OldURI: "file:///MySprite.spx", NewURI: "file:///OtherSprite.spx"}},
+		})
+		require.Error(t, err)
+	})
+}
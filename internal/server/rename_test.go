@@ -22,7 +22,7 @@ onStart => {
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		range1, err := s.textDocumentPrepareRename(&PrepareRenameParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
@@ -71,7 +71,7 @@ onClick => {
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		range1, err := s.textDocumentPrepareRename(&PrepareRenameParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
@@ -107,7 +107,7 @@ onClick => {
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		range1, err := s.textDocumentPrepareRename(&PrepareRenameParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
@@ -126,7 +126,7 @@ const _ = 1
 `),
 			"assets/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		range1, err := s.textDocumentPrepareRename(&PrepareRenameParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
@@ -137,7 +137,7 @@ const _ = 1
 		require.NoError(t, err)
 		require.Nil(t, range1)
 
-		workspaceEdit, err := s.textDocumentRename(&RenameParams{
+		workspaceEdit, err := s.textDocumentRename(t.Context(), &RenameParams{
 			TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 			Position:     Position{Line: 1, Character: 6},
 			NewName:      "x",
@@ -160,7 +160,7 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		rng, err := s.textDocumentPrepareRename(&PrepareRenameParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
@@ -186,7 +186,7 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		rng, err := s.textDocumentPrepareRename(&PrepareRenameParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
@@ -218,7 +218,7 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
 		rng, err := s.textDocumentPrepareRename(&PrepareRenameParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
@@ -251,9 +251,9 @@ onStart => {
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		workspaceEdit, err := s.textDocumentRename(&RenameParams{
+		workspaceEdit, err := s.textDocumentRename(t.Context(), &RenameParams{
 			TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 			Position:     Position{Line: 1, Character: 6},
 			NewName:      "Bar",
@@ -298,9 +298,9 @@ onStart => {
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		workspaceEdit, err := s.textDocumentRename(&RenameParams{
+		workspaceEdit, err := s.textDocumentRename(t.Context(), &RenameParams{
 			TextDocument: TextDocumentIdentifier{URI: "file:///MySprite.spx"},
 			Position:     Position{Line: 1, Character: 9},
 			NewName:      "Bar",
@@ -345,9 +345,9 @@ onStart => {
 			"assets/sprites/MySprite/index.json":    []byte(`{}`),
 			"assets/sprites/OtherSprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		workspaceEdit, err := s.textDocumentRename(&RenameParams{
+		workspaceEdit, err := s.textDocumentRename(t.Context(), &RenameParams{
 			TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 			Position:     Position{Line: 1, Character: 0},
 			NewName:      "NewSprite",
@@ -355,7 +355,7 @@ onStart => {
 		require.NoError(t, err)
 		require.Nil(t, workspaceEdit)
 
-		workspaceEdit1, err := s.textDocumentRename(&RenameParams{
+		workspaceEdit1, err := s.textDocumentRename(t.Context(), &RenameParams{
 			TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 			Position:     Position{Line: 1, Character: 16},
 			NewName:      "NewSprite",
@@ -379,9 +379,9 @@ onClick => {
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		mainSpxWorkspaceEdit, err := s.textDocumentRename(&RenameParams{
+		mainSpxWorkspaceEdit, err := s.textDocumentRename(t.Context(), &RenameParams{
 			TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 			Position:     Position{Line: 2, Character: 5},
 			NewName:      "that",
@@ -389,7 +389,7 @@ onClick => {
 		require.NoError(t, err)
 		require.Nil(t, mainSpxWorkspaceEdit)
 
-		mySpriteSpxWorkspaceEdit, err := s.textDocumentRename(&RenameParams{
+		mySpriteSpxWorkspaceEdit, err := s.textDocumentRename(t.Context(), &RenameParams{
 			TextDocument: TextDocumentIdentifier{URI: "file:///MySprite.spx"},
 			Position:     Position{Line: 2, Character: 5},
 			NewName:      "that",
@@ -413,9 +413,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), newMockReplier(), fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), newMockReplier(), fileMapGetter(m), &MockScheduler{}, nil)
 
-		workspaceEdit, err := s.textDocumentRename(&RenameParams{
+		workspaceEdit, err := s.textDocumentRename(t.Context(), &RenameParams{
 			TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 			Position:     Position{Line: 8, Character: 12},
 			NewName:      "total",
@@ -470,9 +470,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		workspaceEdit, err := s.textDocumentRename(&RenameParams{
+		workspaceEdit, err := s.textDocumentRename(t.Context(), &RenameParams{
 			TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 			Position:     Position{Line: 14, Character: 25},
 			NewName:      "limit",
@@ -520,8 +520,8 @@ onStart => {
 `),
 			"assets/index.json": []byte(`{"backdrops":[{"name":"backdrop1","path":"backdrop1.png"}]}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
-		result, err := s.compile()
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+		result, err := s.compile(t.Context())
 		require.NoError(t, err)
 		require.False(t, result.hasErrorSeverityDiagnostic)
 
@@ -566,8 +566,8 @@ onStart => {
 `),
 			"assets/index.json": []byte(`{"backdrops":[{"name":"backdrop1","path":"backdrop1.png"}]}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
-		result, err := s.compile()
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+		result, err := s.compile(t.Context())
 		require.NoError(t, err)
 		require.False(t, result.hasErrorSeverityDiagnostic)
 
@@ -605,8 +605,8 @@ onStart => {
 `),
 			"assets/index.json": []byte(`{"backdrops":[{"name":"backdrop1","path":"backdrop1.png"}]}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
-		result, err := s.compile()
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+		result, err := s.compile(t.Context())
 		require.NoError(t, err)
 		require.False(t, result.hasErrorSeverityDiagnostic)
 
@@ -652,8 +652,8 @@ onBackdrop "backdrop1", func() {}
 `),
 			"assets/index.json": []byte(`{"backdrops":[{"name":"backdrop1","path":"backdrop1.png"},{"name":"backdrop2","path":"backdrop2.png"}]}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
-		result, err := s.compile()
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+		result, err := s.compile(t.Context())
 		require.NoError(t, err)
 		require.False(t, result.hasErrorSeverityDiagnostic)
 
@@ -681,8 +681,8 @@ onStart => {
 			"assets/sprites/MySprite/index.json": []byte(`{}`),
 			"assets/sounds/Sound1/index.json":    []byte(`{"path":"sound1.wav"}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
-		result, err := s.compile()
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+		result, err := s.compile(t.Context())
 		require.NoError(t, err)
 		require.False(t, result.hasErrorSeverityDiagnostic)
 
@@ -723,8 +723,8 @@ play "Sound1"
 			"assets/sounds/Sound1/index.json": []byte(`{"path":"sound1.wav"}`),
 			"assets/sounds/Sound2/index.json": []byte(`{"path":"sound2.wav"}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
-		result, err := s.compile()
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+		result, err := s.compile(t.Context())
 		require.NoError(t, err)
 		require.False(t, result.hasErrorSeverityDiagnostic)
 
@@ -751,8 +751,8 @@ onStart => {
 			"assets/index.json":                 []byte(`{}`),
 			"assets/sprites/Sprite1/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
-		result, err := s.compile()
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+		result, err := s.compile(t.Context())
 		require.NoError(t, err)
 		require.False(t, result.hasErrorSeverityDiagnostic)
 
@@ -804,8 +804,8 @@ onStart => {
 			"assets/sprites/Sprite1/index.json": []byte(`{}`),
 			"assets/sprites/Sprite2/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
-		result, err := s.compile()
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+		result, err := s.compile(t.Context())
 		require.NoError(t, err)
 		require.False(t, result.hasErrorSeverityDiagnostic)
 
@@ -834,8 +834,8 @@ func invalidFunc() {
 			"assets/index.json":                 []byte(`{}`),
 			"assets/sprites/Sprite1/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
-		result, err := s.compile()
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+		result, err := s.compile(t.Context())
 		require.NoError(t, err)
 		require.True(t, result.hasErrorSeverityDiagnostic)
 
@@ -872,8 +872,8 @@ onStart => {
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{"costumes":[{"name":"costume1"}]}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
-		result, err := s.compile()
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+		result, err := s.compile(t.Context())
 		require.NoError(t, err)
 		require.False(t, result.hasErrorSeverityDiagnostic)
 
@@ -918,8 +918,8 @@ onStart => {
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{"costumes":[{"name":"costume1"},{"name":"costume2"}]}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
-		result, err := s.compile()
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+		result, err := s.compile(t.Context())
 		require.NoError(t, err)
 		require.False(t, result.hasErrorSeverityDiagnostic)
 
@@ -944,8 +944,8 @@ onStart => {
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{"costumes":[{"name":"costume1"}]}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
-		result, err := s.compile()
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+		result, err := s.compile(t.Context())
 		require.NoError(t, err)
 		require.False(t, result.hasErrorSeverityDiagnostic)
 
@@ -972,8 +972,8 @@ onStart => {
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{"fAnimations":{"anim1":{}}}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
-		result, err := s.compile()
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+		result, err := s.compile(t.Context())
 		require.NoError(t, err)
 		require.False(t, result.hasErrorSeverityDiagnostic)
 
@@ -1018,8 +1018,8 @@ onStart => {
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{"fAnimations":{"anim1":{},"anim2":{}}}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
-		result, err := s.compile()
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+		result, err := s.compile(t.Context())
 		require.NoError(t, err)
 		require.False(t, result.hasErrorSeverityDiagnostic)
 
@@ -1044,8 +1044,8 @@ onStart => {
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{"fAnimations":{"anim1":{}}}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
-		result, err := s.compile()
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+		result, err := s.compile(t.Context())
 		require.NoError(t, err)
 		require.False(t, result.hasErrorSeverityDiagnostic)
 
@@ -1070,8 +1070,8 @@ onStart => {
 `),
 			"assets/index.json": []byte(`{"zorder":[{"name":"widget1"}]}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
-		result, err := s.compile()
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+		result, err := s.compile(t.Context())
 		require.NoError(t, err)
 		require.False(t, result.hasErrorSeverityDiagnostic)
 
@@ -1104,8 +1104,8 @@ onStart => {
 `),
 			"assets/index.json": []byte(`{"zorder":[{"name":"widget1"},{"name":"widget2"}]}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
-		result, err := s.compile()
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+		result, err := s.compile(t.Context())
 		require.NoError(t, err)
 		require.False(t, result.hasErrorSeverityDiagnostic)
 
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"context"
+
+	"github.com/goplus/xgo/ast"
+	"github.com/goplus/xgo/token"
+	"github.com/goplus/xgolsw/xgo"
+	"github.com/goplus/xgolsw/xgo/types"
+	"github.com/goplus/xgolsw/xgo/xgoutil"
+)
+
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.18/specification/#textDocument_selectionRange
+func (s *Server) textDocumentSelectionRange(ctx context.Context, params *SelectionRangeParams) ([]SelectionRange, error) {
+	result, err := s.compileAndGetASTFileForDocumentURI(ctx, params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	if !result.IsUsable() {
+		return nil, nil
+	}
+	typeInfo, _ := result.proj.TypeInfo()
+
+	selectionRanges := make([]SelectionRange, 0, len(params.Positions))
+	for _, position := range params.Positions {
+		pos := PosAt(result.proj, result.astFile, position)
+		selectionRange := selectionRangeAt(result.proj, typeInfo, result.astFile, pos)
+		if selectionRange == nil {
+			// Each position must have a corresponding selection range, so
+			// fall back to an empty range at the position itself.
+			selectionRange = &SelectionRange{Range: Range{Start: position, End: position}}
+		}
+		selectionRanges = append(selectionRanges, *selectionRange)
+	}
+	return selectionRanges, nil
+}
+
+// selectionRangeAt returns the innermost [SelectionRange] at the given
+// position, with its parents following the enclosing AST nodes outward.
+//
+// The function literal of an spx event handler (e.g., `=> { ... }` in
+// `onStart => { ... }`) is skipped so that the selection expands from the
+// handler body directly to the whole event handler.
+func selectionRangeAt(proj *xgo.Project, typeInfo *types.Info, astFile *ast.File, pos token.Pos) *SelectionRange {
+	path, _ := xgoutil.PathEnclosingInterval(astFile, pos, pos)
+
+	var selectionRange *SelectionRange
+	for i := len(path) - 1; i >= 0; i-- {
+		node := path[i]
+		if _, ok := node.(*ast.File); ok {
+			continue
+		}
+		if !node.Pos().IsValid() || !node.End().IsValid() {
+			continue
+		}
+		if i+1 < len(path) && isSpxEventHandlerFuncLit(typeInfo, node, path[i+1]) {
+			continue
+		}
+
+		nodeRange := RangeForNode(proj, node)
+		if selectionRange != nil && selectionRange.Range == nodeRange {
+			continue
+		}
+		selectionRange = &SelectionRange{
+			Range:  nodeRange,
+			Parent: selectionRange,
+		}
+	}
+	return selectionRange
+}
+
+// isSpxEventHandlerFuncLit reports whether node is a function literal passed
+// to the spx event handler call parent.
+func isSpxEventHandlerFuncLit(typeInfo *types.Info, node, parent ast.Node) bool {
+	switch node.(type) {
+	case *ast.LambdaExpr, *ast.LambdaExpr2, *ast.FuncLit:
+	default:
+		return false
+	}
+	callExpr, ok := parent.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	funIdent, ok := callExpr.Fun.(*ast.Ident)
+	if !ok || !IsSpxEventHandlerFuncName(funIdent.Name) {
+		return false
+	}
+	return typeInfo == nil || IsInSpxPkg(typeInfo.ObjectOf(funIdent))
+}
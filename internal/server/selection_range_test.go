@@ -0,0 +1,74 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerTextDocumentSelectionRange(t *testing.T) {
+	m := map[string][]byte{
+		"main.spx": []byte(`
+func add(a, b int) int {
+	return a + b
+}
+
+onStart => {
+	MySprite.move 10, 0
+}
+`),
+		"MySprite.spx":                       []byte(``),
+		"assets/index.json":                  []byte(`{}`),
+		"assets/sprites/MySprite/index.json": []byte(`{}`),
+	}
+	s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+	selectionRangeChain := func(sr *SelectionRange) []Range {
+		var ranges []Range
+		for ; sr != nil; sr = sr.Parent {
+			ranges = append(ranges, sr.Range)
+		}
+		return ranges
+	}
+
+	got, err := s.textDocumentSelectionRange(t.Context(), &SelectionRangeParams{
+		TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+		Positions: []Position{
+			{Line: 6, Character: 11},
+			{Line: 2, Character: 12},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+
+	t.Run("EventHandler", func(t *testing.T) {
+		assert.Equal(t, []Range{
+			// move
+			{Start: Position{Line: 6, Character: 10}, End: Position{Line: 6, Character: 14}},
+			// MySprite.move
+			{Start: Position{Line: 6, Character: 1}, End: Position{Line: 6, Character: 14}},
+			// MySprite.move 10, 0
+			{Start: Position{Line: 6, Character: 1}, End: Position{Line: 6, Character: 20}},
+			// { ... }
+			{Start: Position{Line: 5, Character: 11}, End: Position{Line: 7, Character: 1}},
+			// onStart => { ... }
+			{Start: Position{Line: 5, Character: 0}, End: Position{Line: 7, Character: 1}},
+		}, selectionRangeChain(&got[0]))
+	})
+
+	t.Run("FuncDecl", func(t *testing.T) {
+		assert.Equal(t, []Range{
+			// b
+			{Start: Position{Line: 2, Character: 12}, End: Position{Line: 2, Character: 13}},
+			// a + b
+			{Start: Position{Line: 2, Character: 8}, End: Position{Line: 2, Character: 13}},
+			// return a + b
+			{Start: Position{Line: 2, Character: 1}, End: Position{Line: 2, Character: 13}},
+			// { ... }
+			{Start: Position{Line: 1, Character: 23}, End: Position{Line: 3, Character: 1}},
+			// func add(a, b int) int { ... }
+			{Start: Position{Line: 1, Character: 0}, End: Position{Line: 3, Character: 1}},
+		}, selectionRangeChain(&got[1]))
+	})
+}
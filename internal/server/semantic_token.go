@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	gotypes "go/types"
 	"slices"
 	"sort"
@@ -71,12 +72,12 @@ type semanticTokenInfo struct {
 }
 
 // See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.18/specification/#textDocument_semanticTokens
-func (s *Server) textDocumentSemanticTokensFull(params *SemanticTokensParams) (*SemanticTokens, error) {
-	result, _, astFile, err := s.compileAndGetASTFileForDocumentURI(params.TextDocument.URI)
+func (s *Server) textDocumentSemanticTokensFull(ctx context.Context, params *SemanticTokensParams) (*SemanticTokens, error) {
+	result, err := s.compileAndGetASTFileForDocumentURI(ctx, params.TextDocument.URI)
 	if err != nil {
 		return nil, err
 	}
-	if astFile == nil {
+	if !result.IsUsable() {
 		return nil, nil
 	}
 	typeInfo, _ := result.proj.TypeInfo()
@@ -105,7 +106,7 @@ func (s *Server) textDocumentSemanticTokensFull(params *SemanticTokensParams) (*
 		})
 	}
 
-	ast.Inspect(astFile, func(node ast.Node) bool {
+	ast.Inspect(result.astFile, func(node ast.Node) bool {
 		if node == nil || !node.Pos().IsValid() {
 			return true
 		}
@@ -22,9 +22,9 @@ onStart => {
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		mainSpxTokens, err := s.textDocumentSemanticTokensFull(&SemanticTokensParams{
+		mainSpxTokens, err := s.textDocumentSemanticTokensFull(t.Context(), &SemanticTokensParams{
 			TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 		})
 		require.NoError(t, err)
@@ -38,7 +38,7 @@ onStart => {
 			0, 4, 1, 13, 0, // }
 		}, mainSpxTokens.Data)
 
-		mySpriteTokens, err := s.textDocumentSemanticTokensFull(&SemanticTokensParams{
+		mySpriteTokens, err := s.textDocumentSemanticTokensFull(t.Context(), &SemanticTokensParams{
 			TextDocument: TextDocumentIdentifier{URI: "file:///MySprite.spx"},
 		})
 		require.NoError(t, err)
@@ -71,9 +71,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		tokens, err := s.textDocumentSemanticTokensFull(&SemanticTokensParams{
+		tokens, err := s.textDocumentSemanticTokensFull(t.Context(), &SemanticTokensParams{
 			TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 		})
 		require.NoError(t, err)
@@ -97,7 +97,7 @@ onStart => {
 }
 `)
 
-		tokens, err := s.textDocumentSemanticTokensFull(&SemanticTokensParams{
+		tokens, err := s.textDocumentSemanticTokensFull(t.Context(), &SemanticTokensParams{
 			TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 		})
 		require.NoError(t, err)
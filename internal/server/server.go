@@ -2,13 +2,13 @@ package server
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	gotypes "go/types"
 	"maps"
 	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/goplus/mod/modload"
@@ -30,6 +30,8 @@ type MessageReplier interface {
 	// The message can be one of:
 	//   - [jsonrpc2.Response]: sent in response to a call.
 	//   - [jsonrpc2.Notification]: sent for server-initiated notifications.
+	//   - [jsonrpc2.Call]: sent for server-initiated requests, whose responses
+	//     are passed back to [Server.HandleMessage].
 	ReplyMessage(m jsonrpc2.Message) error
 }
 
@@ -43,38 +45,135 @@ type Scheduler interface {
 	Sched()
 }
 
+// TimeoutScheduler is a [Scheduler] that can also yield the processor for a
+// bounded duration. The server yields through it when available.
+type TimeoutScheduler interface {
+	Scheduler
+
+	// SchedWithTimeout yields the processor for at most about maxWait.
+	SchedWithTimeout(maxWait time.Duration)
+}
+
+const (
+	// DefaultMaxDiagnosticsPerFile is the default value of
+	// [ServerOptions.MaxDiagnosticsPerFile].
+	DefaultMaxDiagnosticsPerFile = 50
+
+	// DefaultMaxDiagnosticsTotal is the default value of
+	// [ServerOptions.MaxDiagnosticsTotal].
+	DefaultMaxDiagnosticsTotal = 200
+)
+
+// ServerOptions configures a [Server].
+type ServerOptions struct {
+	// MaxDiagnosticsPerFile is the maximum number of diagnostics reported for
+	// a single file. Zero means [DefaultMaxDiagnosticsPerFile], and a negative
+	// value means no limit.
+	MaxDiagnosticsPerFile int
+
+	// MaxDiagnosticsTotal is the maximum number of diagnostics reported for
+	// the whole workspace. Zero means [DefaultMaxDiagnosticsTotal], and a
+	// negative value means no limit.
+	MaxDiagnosticsTotal int
+
+	// DiagnosticDebounceDelay is how long file changes must settle before
+	// diagnostics are published for them. Zero means
+	// [DefaultDiagnosticDebounceDelay], and a negative value means no delay.
+	DiagnosticDebounceDelay time.Duration
+
+	// SchedMaxWait is the maximum duration a request yields the processor for
+	// before it runs, if the scheduler is a [TimeoutScheduler]. Zero means
+	// yielding as briefly as possible.
+	SchedMaxWait time.Duration
+}
+
+// withDefaults returns a copy of opts with zero fields set to their defaults.
+// A nil opts is treated as an empty [ServerOptions].
+func (opts *ServerOptions) withDefaults() ServerOptions {
+	var o ServerOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.MaxDiagnosticsPerFile == 0 {
+		o.MaxDiagnosticsPerFile = DefaultMaxDiagnosticsPerFile
+	}
+	if o.MaxDiagnosticsTotal == 0 {
+		o.MaxDiagnosticsTotal = DefaultMaxDiagnosticsTotal
+	}
+	if o.DiagnosticDebounceDelay == 0 {
+		o.DiagnosticDebounceDelay = DefaultDiagnosticDebounceDelay
+	}
+	return o
+}
+
 // Server is the core language server implementation that handles LSP messages.
 type Server struct {
-	workspaceRootURI DocumentURI
-	workspaceRootFS  *xgo.Project
-	replier          MessageReplier
-	analyzers        []*analysis.Analyzer
-	fileMapGetter    FileMapGetter // TODO(wyvern): Remove this field.
-	cancelCauseFuncs sync.Map      // Map of request IDs to cancel functions (with cause).
-	scheduler        Scheduler
-	language         i18n.Language // Current language for error message translation
+	workspaceRootURI    DocumentURI
+	projMu              sync.RWMutex // Guards workspaceRootFS and fileMapGetter.
+	workspaceRootFS     *xgo.Project
+	replier             MessageReplier
+	analyzers           []*analysis.Analyzer
+	fileMapGetter       FileMapGetter // TODO(wyvern): Remove this field.
+	cancelCauseFuncs    sync.Map      // Map of request IDs to cancel functions (with cause).
+	scheduler           Scheduler
+	language            i18n.Language // Current language for error message translation
+	progressReporter    ProgressReporter
+	progressTokenSeq    atomic.Uint64 // Sequence for generating progress tokens.
+	options             ServerOptions
+	diagnosticDebouncer *DiagnosticDebouncer
+	initMu              sync.Mutex         // Guards initializeDone and pendingMessages.
+	initializeDone      bool               // Whether the initialize request has completed.
+	pendingMessages     []jsonrpc2.Message // Messages received before the initialize request completed.
 }
 
 func (s *Server) getProj() *xgo.Project {
+	s.projMu.RLock()
+	defer s.projMu.RUnlock()
 	return s.workspaceRootFS
 }
 
 func (s *Server) getProjWithFile() *xgo.Project {
-	proj := s.workspaceRootFS
-	proj.UpdateFiles(s.fileMapGetter())
+	proj := s.getProj()
+	proj.UpdateFiles(s.getFileMap())
 	return proj
 }
 
-// New creates a new Server instance.
-func New(proj *xgo.Project, replier MessageReplier, fileMapGetter FileMapGetter, scheduler Scheduler) *Server {
-	mod := xgomod.New(modload.Default)
-	if err := mod.ImportClasses(); err != nil {
-		panic(fmt.Errorf("failed to import classes: %w", err))
+// getFileMap returns the current files from the file map getter.
+func (s *Server) getFileMap() map[string]*xgo.File {
+	s.projMu.RLock()
+	fileMapGetter := s.fileMapGetter
+	s.projMu.RUnlock()
+	return fileMapGetter()
+}
+
+// CacheMetrics returns the cache metrics of the project served by s.
+func (s *Server) CacheMetrics() xgo.CacheMetrics {
+	return s.getProj().CacheMetrics()
+}
+
+// SaveProjectSnapshot serializes the project served by s with
+// [xgo.Project.MarshalSnapshot].
+func (s *Server) SaveProjectSnapshot() ([]byte, error) {
+	return s.getProj().MarshalSnapshot()
+}
+
+// LoadProjectSnapshot replaces the project served by s with the one restored
+// from data produced by [Server.SaveProjectSnapshot]. The files of the
+// restored project keep being updated by the current getter of files.
+func (s *Server) LoadProjectSnapshot(data []byte) error {
+	proj, err := xgo.UnmarshalSnapshot(data)
+	if err != nil {
+		return err
 	}
-	proj.PkgPath = "main"
-	proj.Mod = mod
-	proj.Importer = internal.Importer
-	return &Server{
+	s.projMu.RLock()
+	fileMapGetter := s.fileMapGetter
+	s.projMu.RUnlock()
+	return s.SetProject(proj, fileMapGetter)
+}
+
+// New creates a new Server instance. A nil opts uses the default options.
+func New(proj *xgo.Project, replier MessageReplier, fileMapGetter FileMapGetter, scheduler Scheduler, opts *ServerOptions) *Server {
+	s := &Server{
 		// TODO(spxls): Initialize request should set workspaceRootURI value
 		workspaceRootURI: "file:///",
 		workspaceRootFS:  proj,
@@ -83,7 +182,53 @@ func New(proj *xgo.Project, replier MessageReplier, fileMapGetter FileMapGetter,
 		fileMapGetter:    fileMapGetter,
 		scheduler:        scheduler,
 		language:         i18n.LanguageEN, // Default to English until initialize is called
+		progressReporter: nopProgressReporter{},
+		options:          opts.withDefaults(),
+	}
+	s.diagnosticDebouncer = NewDiagnosticDebouncer(s.options.DiagnosticDebounceDelay, s.publishFileDiagnostics)
+	if err := s.initProject(proj); err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// SetProject replaces the project served by s and the getter of its files,
+// e.g., after the classfile configuration changed and the project must be
+// rebuilt from scratch. Requests still running on the old project are
+// cancelled.
+func (s *Server) SetProject(proj *xgo.Project, fileMapGetter FileMapGetter) error {
+	if err := s.initProject(proj); err != nil {
+		return err
 	}
+
+	s.projMu.Lock()
+	s.workspaceRootFS = proj
+	s.fileMapGetter = fileMapGetter
+	s.projMu.Unlock()
+
+	s.cancelCauseFuncs.Range(func(id, cancelCauseFunc any) bool {
+		if cancelWithCause, ok := cancelCauseFunc.(context.CancelCauseFunc); ok {
+			cancelWithCause(projectReplaced)
+		}
+		return true
+	})
+	return nil
+}
+
+var projectReplaced = jsonrpc2.NewError(int64(ContentModified), "Project replaced")
+
+// initProject prepares proj to be served by s.
+func (s *Server) initProject(proj *xgo.Project) error {
+	mod := xgomod.New(modload.Default)
+	if err := mod.ImportClasses(); err != nil {
+		return fmt.Errorf("failed to import classes: %w", err)
+	}
+	proj.PkgPath = "main"
+	proj.Mod = mod
+	proj.Importer = internal.Importer
+	proj.RegisterCacheBuilder(spxProjectStructureCacheKind{}, s.buildSpxProjectStructureCache)
+	proj.RegisterAssetCacheBuilder(spxResourceCacheKind{}, buildSpxResourceCache)
+	return nil
 }
 
 // InitAnalyzers initializes the analyzers for the server.
@@ -96,12 +241,28 @@ func initAnalyzers(staticcheck bool) []*analysis.Analyzer {
 }
 
 // HandleMessage handles an incoming LSP message.
-func (s *Server) HandleMessage(m jsonrpc2.Message) error {
+func (s *Server) HandleMessage(m jsonrpc2.Message) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = s.handlePanic("HandleMessage", r)
+		}
+	}()
+	if s.deferUntilInitialized(m) {
+		return nil
+	}
+	return s.handleMessage(m)
+}
+
+// handleMessage dispatches an incoming LSP message to its handler.
+func (s *Server) handleMessage(m jsonrpc2.Message) error {
 	switch m := m.(type) {
 	case *jsonrpc2.Call:
 		return s.handleCall(m)
 	case *jsonrpc2.Notification:
 		return s.handleNotification(m)
+	case *jsonrpc2.Response:
+		s.handleResponse(m)
+		return nil
 	}
 	return fmt.Errorf("unsupported message type: %T", m)
 }
@@ -112,13 +273,18 @@ func (s *Server) handleCall(c *jsonrpc2.Call) error {
 	case "initialize":
 		var params InitializeParams
 		if err := UnmarshalJSON(c.Params(), &params); err != nil {
-			return s.replyParseError(c.ID(), err)
+			replyErr := s.replyParseError(c.ID(), err)
+			s.finishInitialize(err)
+			return replyErr
 		}
-		s.runForCall(c, func() (any, error) {
+		// Queued messages must not be handled until the initialize
+		// response has been sent, so their replies and notifications
+		// cannot reach the client ahead of it.
+		s.runForCallThen(c, func(context.Context) (any, error) {
 			return s.initialize(&params)
-		})
+		}, s.finishInitialize)
 	case "shutdown":
-		s.runForCall(c, func() (any, error) {
+		s.runForCall(c, func(context.Context) (any, error) {
 			return nil, nil // Protocol conformance only.
 		})
 	case "textDocument/hover":
@@ -126,31 +292,31 @@ func (s *Server) handleCall(c *jsonrpc2.Call) error {
 		if err := UnmarshalJSON(c.Params(), &params); err != nil {
 			return s.replyParseError(c.ID(), err)
 		}
-		s.runForCall(c, func() (any, error) {
-			return s.textDocumentHover(&params)
+		s.runForCall(c, func(ctx context.Context) (any, error) {
+			return s.textDocumentHover(ctx, &params)
 		})
 	case "textDocument/completion":
 		var params CompletionParams
 		if err := UnmarshalJSON(c.Params(), &params); err != nil {
 			return s.replyParseError(c.ID(), err)
 		}
-		s.runForCall(c, func() (any, error) {
-			return s.textDocumentCompletion(&params)
+		s.runForCall(c, func(ctx context.Context) (any, error) {
+			return s.textDocumentCompletion(ctx, &params)
 		})
 	case "textDocument/signatureHelp":
 		var params SignatureHelpParams
 		if err := UnmarshalJSON(c.Params(), &params); err != nil {
 			return s.replyParseError(c.ID(), err)
 		}
-		s.runForCall(c, func() (any, error) {
-			return s.textDocumentSignatureHelp(&params)
+		s.runForCall(c, func(ctx context.Context) (any, error) {
+			return s.textDocumentSignatureHelp(ctx, &params)
 		})
 	case "textDocument/declaration":
 		var params DeclarationParams
 		if err := UnmarshalJSON(c.Params(), &params); err != nil {
 			return s.replyParseError(c.ID(), err)
 		}
-		s.runForCall(c, func() (any, error) {
+		s.runForCall(c, func(context.Context) (any, error) {
 			return s.textDocumentDeclaration(&params)
 		})
 	case "textDocument/definition":
@@ -158,7 +324,7 @@ func (s *Server) handleCall(c *jsonrpc2.Call) error {
 		if err := UnmarshalJSON(c.Params(), &params); err != nil {
 			return s.replyParseError(c.ID(), err)
 		}
-		s.runForCall(c, func() (any, error) {
+		s.runForCall(c, func(context.Context) (any, error) {
 			return s.textDocumentDefinition(&params)
 		})
 	case "textDocument/typeDefinition":
@@ -166,7 +332,7 @@ func (s *Server) handleCall(c *jsonrpc2.Call) error {
 		if err := UnmarshalJSON(c.Params(), &params); err != nil {
 			return s.replyParseError(c.ID(), err)
 		}
-		s.runForCall(c, func() (any, error) {
+		s.runForCall(c, func(context.Context) (any, error) {
 			return s.textDocumentTypeDefinition(&params)
 		})
 	case "textDocument/implementation":
@@ -174,63 +340,71 @@ func (s *Server) handleCall(c *jsonrpc2.Call) error {
 		if err := UnmarshalJSON(c.Params(), &params); err != nil {
 			return s.replyParseError(c.ID(), err)
 		}
-		s.runForCall(c, func() (any, error) {
-			return s.textDocumentImplementation(&params)
+		s.runForCall(c, func(ctx context.Context) (any, error) {
+			return s.textDocumentImplementation(ctx, &params)
 		})
 	case "textDocument/references":
 		var params ReferenceParams
 		if err := UnmarshalJSON(c.Params(), &params); err != nil {
 			return s.replyParseError(c.ID(), err)
 		}
-		s.runForCall(c, func() (any, error) {
-			return s.textDocumentReferences(&params)
+		s.runForCall(c, func(ctx context.Context) (any, error) {
+			return s.textDocumentReferences(ctx, &params)
 		})
 	case "textDocument/documentHighlight":
 		var params DocumentHighlightParams
 		if err := UnmarshalJSON(c.Params(), &params); err != nil {
 			return s.replyParseError(c.ID(), err)
 		}
-		s.runForCall(c, func() (any, error) {
-			return s.textDocumentDocumentHighlight(&params)
+		s.runForCall(c, func(ctx context.Context) (any, error) {
+			return s.textDocumentDocumentHighlight(ctx, &params)
 		})
 	case "textDocument/documentLink":
 		var params DocumentLinkParams
 		if err := UnmarshalJSON(c.Params(), &params); err != nil {
 			return s.replyParseError(c.ID(), err)
 		}
-		s.runForCall(c, func() (any, error) {
-			return s.textDocumentDocumentLink(&params)
+		s.runForCall(c, func(ctx context.Context) (any, error) {
+			return s.textDocumentDocumentLink(ctx, &params)
 		})
 	case "textDocument/diagnostic":
 		var params DocumentDiagnosticParams
 		if err := UnmarshalJSON(c.Params(), &params); err != nil {
 			return s.replyParseError(c.ID(), err)
 		}
-		s.runForCall(c, func() (any, error) {
-			return s.textDocumentDiagnostic(&params)
+		s.runForCall(c, func(ctx context.Context) (any, error) {
+			return s.textDocumentDiagnostic(ctx, &params)
 		})
 	case "workspace/diagnostic":
 		var params WorkspaceDiagnosticParams
 		if err := UnmarshalJSON(c.Params(), &params); err != nil {
 			return s.replyParseError(c.ID(), err)
 		}
-		s.runForCall(c, func() (any, error) {
-			return s.workspaceDiagnostic(&params)
+		s.runForCall(c, func(ctx context.Context) (any, error) {
+			return s.workspaceDiagnostic(ctx, &params)
 		})
 	case "textDocument/formatting":
 		var params DocumentFormattingParams
 		if err := UnmarshalJSON(c.Params(), &params); err != nil {
 			return s.replyParseError(c.ID(), err)
 		}
-		s.runForCall(c, func() (any, error) {
+		s.runForCall(c, func(context.Context) (any, error) {
 			return s.textDocumentFormatting(&params)
 		})
+	case "textDocument/onTypeFormatting":
+		var params DocumentOnTypeFormattingParams
+		if err := UnmarshalJSON(c.Params(), &params); err != nil {
+			return s.replyParseError(c.ID(), err)
+		}
+		s.runForCall(c, func(context.Context) (any, error) {
+			return s.textDocumentOnTypeFormatting(&params)
+		})
 	case "textDocument/prepareRename":
 		var params PrepareRenameParams
 		if err := UnmarshalJSON(c.Params(), &params); err != nil {
 			return s.replyParseError(c.ID(), err)
 		}
-		s.runForCall(c, func() (any, error) {
+		s.runForCall(c, func(context.Context) (any, error) {
 			return s.textDocumentPrepareRename(&params)
 		})
 	case "textDocument/rename":
@@ -238,32 +412,96 @@ func (s *Server) handleCall(c *jsonrpc2.Call) error {
 		if err := UnmarshalJSON(c.Params(), &params); err != nil {
 			return s.replyParseError(c.ID(), err)
 		}
-		s.runForCall(c, func() (any, error) {
-			return s.textDocumentRename(&params)
+		s.runForCall(c, func(ctx context.Context) (any, error) {
+			return s.textDocumentRename(ctx, &params)
 		})
 	case "textDocument/semanticTokens/full":
 		var params SemanticTokensParams
 		if err := UnmarshalJSON(c.Params(), &params); err != nil {
 			return s.replyParseError(c.ID(), err)
 		}
-		s.runForCall(c, func() (any, error) {
-			return s.textDocumentSemanticTokensFull(&params)
+		s.runForCall(c, func(ctx context.Context) (any, error) {
+			return s.textDocumentSemanticTokensFull(ctx, &params)
 		})
 	case "textDocument/inlayHint":
 		var params InlayHintParams
 		if err := UnmarshalJSON(c.Params(), &params); err != nil {
 			return s.replyParseError(c.ID(), err)
 		}
-		s.runForCall(c, func() (any, error) {
-			return s.textDocumentInlayHint(&params)
+		s.runForCall(c, func(ctx context.Context) (any, error) {
+			return s.textDocumentInlayHint(ctx, &params)
+		})
+	case "textDocument/linkedEditingRange":
+		var params LinkedEditingRangeParams
+		if err := UnmarshalJSON(c.Params(), &params); err != nil {
+			return s.replyParseError(c.ID(), err)
+		}
+		s.runForCall(c, func(ctx context.Context) (any, error) {
+			return s.textDocumentLinkedEditingRange(ctx, &params)
+		})
+	case "textDocument/selectionRange":
+		var params SelectionRangeParams
+		if err := UnmarshalJSON(c.Params(), &params); err != nil {
+			return s.replyParseError(c.ID(), err)
+		}
+		s.runForCall(c, func(ctx context.Context) (any, error) {
+			return s.textDocumentSelectionRange(ctx, &params)
+		})
+	case "textDocument/documentColor":
+		var params DocumentColorParams
+		if err := UnmarshalJSON(c.Params(), &params); err != nil {
+			return s.replyParseError(c.ID(), err)
+		}
+		s.runForCall(c, func(ctx context.Context) (any, error) {
+			return s.textDocumentDocumentColor(ctx, &params)
+		})
+	case "textDocument/colorPresentation":
+		var params ColorPresentationParams
+		if err := UnmarshalJSON(c.Params(), &params); err != nil {
+			return s.replyParseError(c.ID(), err)
+		}
+		s.runForCall(c, func(context.Context) (any, error) {
+			return s.textDocumentColorPresentation(&params)
+		})
+	case "textDocument/moniker":
+		var params MonikerParams
+		if err := UnmarshalJSON(c.Params(), &params); err != nil {
+			return s.replyParseError(c.ID(), err)
+		}
+		s.runForCall(c, func(ctx context.Context) (any, error) {
+			return s.textDocumentMoniker(ctx, &params)
+		})
+	case "textDocument/prepareCallHierarchy":
+		var params CallHierarchyPrepareParams
+		if err := UnmarshalJSON(c.Params(), &params); err != nil {
+			return s.replyParseError(c.ID(), err)
+		}
+		s.runForCall(c, func(ctx context.Context) (any, error) {
+			return s.textDocumentPrepareCallHierarchy(ctx, &params)
+		})
+	case "callHierarchy/incomingCalls":
+		var params CallHierarchyIncomingCallsParams
+		if err := UnmarshalJSON(c.Params(), &params); err != nil {
+			return s.replyParseError(c.ID(), err)
+		}
+		s.runForCall(c, func(ctx context.Context) (any, error) {
+			return s.callHierarchyIncomingCalls(ctx, &params)
+		})
+	case "workspace/willRenameFiles":
+		var params RenameFilesParams
+		if err := UnmarshalJSON(c.Params(), &params); err != nil {
+			return s.replyParseError(c.ID(), err)
+		}
+		s.runForCall(c, func(ctx context.Context) (any, error) {
+			return s.workspaceWillRenameFiles(ctx, &params)
 		})
 	case "workspace/executeCommand":
 		var params ExecuteCommandParams
 		if err := UnmarshalJSON(c.Params(), &params); err != nil {
 			return s.replyParseError(c.ID(), err)
 		}
-		s.runForCall(c, func() (any, error) {
-			return s.workspaceExecuteCommand(&params)
+		s.runForCall(c, func(ctx context.Context) (any, error) {
+			return s.workspaceExecuteCommand(ctx, &params)
 		})
 	default:
 		return s.replyMethodNotFound(c.ID(), c.Method())
@@ -280,7 +518,7 @@ func (s *Server) handleNotification(n *jsonrpc2.Notification) error {
 			return fmt.Errorf("failed to parse initialized params: %w", err)
 		}
 		s.runForNotification(n, func() error {
-			return errors.New("TODO")
+			return s.initialized(&params)
 		})
 	case "exit":
 		// Protocol conformance only.
@@ -368,8 +606,46 @@ func (s *Server) sendTelemetryEvent(data map[string]any) error {
 	return s.replier.ReplyMessage(n)
 }
 
+// ShowMessage asks the client to display a message of the given type to the
+// user.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#window_showMessage
+func (s *Server) ShowMessage(typ MessageType, msg string) error {
+	n, err := jsonrpc2.NewNotification("window/showMessage", &ShowMessageParams{
+		Type:    typ,
+		Message: msg,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create show message notification: %w", err)
+	}
+	return s.replier.ReplyMessage(n)
+}
+
+// LogMessage asks the client to log a message of the given type.
+//
+// See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/#window_logMessage
+func (s *Server) LogMessage(typ MessageType, msg string) error {
+	n, err := jsonrpc2.NewNotification("window/logMessage", &LogMessageParams{
+		Type:    typ,
+		Message: msg,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create log message notification: %w", err)
+	}
+	return s.replier.ReplyMessage(n)
+}
+
+// handlePanic converts a recovered panic value into an error and reports it
+// to the user.
+func (s *Server) handlePanic(method string, r any) error {
+	err := fmt.Errorf("panic in %s: %v", method, r)
+	s.ShowMessage(MessageTypeError, fmt.Sprintf("Internal error: %v", err))
+	return err
+}
+
 // publishDiagnostics sends diagnostic notifications to the client.
 func (s *Server) publishDiagnostics(uri DocumentURI, diagnostics []Diagnostic) error {
+	diagnostics = s.limitFileDiagnostics(diagnostics)
 	params := &PublishDiagnosticsParams{
 		URI:         uri,
 		Diagnostics: diagnostics,
@@ -421,26 +697,62 @@ func (s *Server) wrapWithMetrics(msg jsonrpc2.Message, fn func() (any, error)) f
 }
 
 // runForCall runs a function for a call message and replies with the result or error.
-func (s *Server) runForCall(call *jsonrpc2.Call, fn func() (any, error)) {
+// The function is given a context that is cancelled when the call is cancelled
+// by the client with `$/cancelRequest`.
+func (s *Server) runForCall(call *jsonrpc2.Call, fn func(ctx context.Context) (any, error)) {
+	s.runForCallThen(call, fn, nil)
+}
+
+// sched yields the processor with the scheduler of s, for at most
+// [ServerOptions.SchedMaxWait] if the scheduler is a [TimeoutScheduler].
+func (s *Server) sched() {
+	if ts, ok := s.scheduler.(TimeoutScheduler); ok {
+		ts.SchedWithTimeout(s.options.SchedMaxWait)
+		return
+	}
+	s.scheduler.Sched()
+}
+
+// runForCallThen is like [Server.runForCall], but also calls then, if not
+// nil, once the call has been replied. It is passed nil if the call succeeded,
+// or the error of the call otherwise.
+func (s *Server) runForCallThen(call *jsonrpc2.Call, fn func(ctx context.Context) (any, error), then func(err error)) {
 	ctx, cancelCauseFunc := context.WithCancelCause(context.TODO())
 	s.cancelCauseFuncs.Store(call.ID(), cancelCauseFunc)
-	wrap := s.wrapWithMetrics(call, fn)
+	wrap := s.wrapWithMetrics(call, func() (any, error) {
+		return fn(ctx)
+	})
 	go func() (err error) {
+		var callErr error // The error of fn, which is replied in a response.
 		defer func() {
+			if r := recover(); r != nil {
+				err = s.handlePanic(call.Method(), r)
+			}
 			s.cancelCauseFuncs.Delete(call.ID())
 			if err != nil {
 				s.replyError(call.ID(), err)
+				callErr = err
+			}
+			if then != nil {
+				then(callErr)
 			}
 		}()
 
-		s.scheduler.Sched() // Do scheduling to receive (cancel) notifications on the fly.
+		s.sched() // Do scheduling to receive (cancel) notifications on the fly.
 		if ctx.Err() != nil {
 			err = context.Cause(ctx)
 			return err
 		}
 
-		result, err := wrap()
-		resp, err := jsonrpc2.NewResponse(call.ID(), result, err)
+		var result any
+		result, callErr = wrap()
+		if ctx.Err() != nil {
+			// The request was cancelled while running, so its result is
+			// stale and must not be reported to the client.
+			err = context.Cause(ctx)
+			return err
+		}
+		resp, err := jsonrpc2.NewResponse(call.ID(), result, callErr)
 		if err != nil {
 			return err
 		}
@@ -453,7 +765,14 @@ func (s *Server) runForNotification(notify *jsonrpc2.Notification, fn func() err
 	wrap := s.wrapWithMetrics(notify, func() (any, error) {
 		return nil, fn()
 	})
-	go wrap()
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				s.handlePanic(notify.Method(), r)
+			}
+		}()
+		wrap()
+	}()
 }
 
 var requestCancelled = jsonrpc2.NewError(int64(RequestCancelled), "Request cancelled")
@@ -495,9 +814,10 @@ func (s *Server) replyParseError(id jsonrpc2.ID, err error) error {
 	return s.replyError(id, fmt.Errorf("%w: %s", jsonrpc2.ErrParse, err))
 }
 
-// fromDocumentURI returns the relative path from a [DocumentURI].
+// fromDocumentURI returns the relative path from a [DocumentURI]. The URI is
+// normalized with [NormalizeURI] first.
 func (s *Server) fromDocumentURI(documentURI DocumentURI) (string, error) {
-	uri := string(documentURI)
+	uri := string(NormalizeURI(documentURI))
 	rootURI := string(s.workspaceRootURI)
 	if !strings.HasPrefix(uri, rootURI) {
 		return "", fmt.Errorf("document URI %q does not have workspace root URI %q as prefix", uri, rootURI)
@@ -1,9 +1,12 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -101,12 +104,259 @@ func fileMapGetter(files map[string][]byte) func() map[string]*xgo.File {
 }
 
 // MockScheduler implements [Scheduler]
-type MockScheduler struct{}
+type MockScheduler struct {
+	calls atomic.Int64
+}
 
 func (s *MockScheduler) Sched() {
+	s.calls.Add(1)
 	time.Sleep(1 * time.Millisecond)
 }
 
+// RecordedCalls returns the number of calls to [MockScheduler.Sched] since
+// the scheduler was created or last reset.
+func (s *MockScheduler) RecordedCalls() int {
+	return int(s.calls.Load())
+}
+
+// Reset resets the number of recorded calls to zero.
+func (s *MockScheduler) Reset() {
+	s.calls.Store(0)
+}
+
+// MockTimeoutScheduler implements [TimeoutScheduler]
+type MockTimeoutScheduler struct {
+	MockScheduler
+	mu       sync.Mutex
+	maxWaits []time.Duration
+}
+
+func (s *MockTimeoutScheduler) SchedWithTimeout(maxWait time.Duration) {
+	s.mu.Lock()
+	s.maxWaits = append(s.maxWaits, maxWait)
+	s.mu.Unlock()
+}
+
+// RecordedMaxWaits returns the maxWait arguments of all calls to
+// [MockTimeoutScheduler.SchedWithTimeout].
+func (s *MockTimeoutScheduler) RecordedMaxWaits() []time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.maxWaits)
+}
+
+func TestServerScheduling(t *testing.T) {
+	files := map[string][]byte{
+		"main.spx": []byte(`
+var x = 100
+echo x
+`),
+	}
+
+	t.Run("Call", func(t *testing.T) {
+		replier := newMockReplier()
+		scheduler := &MockScheduler{}
+		s := New(newProjectWithoutModTime(files), replier, fileMapGetter(files), scheduler, nil)
+		s.markInitialized()
+
+		call, err := jsonrpc2.NewCall(jsonrpc2.NewIntID(1), "textDocument/hover", &HoverParams{
+			TextDocumentPositionParams: TextDocumentPositionParams{
+				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+				Position:     Position{Line: 2, Character: 5},
+			},
+		})
+		require.NoError(t, err)
+		require.NoError(t, s.HandleMessage(call))
+
+		messages := replier.waitForMessages(1, 5*time.Second)
+		require.NotEmpty(t, messages)
+		assert.GreaterOrEqual(t, scheduler.RecordedCalls(), 1)
+
+		scheduler.Reset()
+		assert.Zero(t, scheduler.RecordedCalls())
+	})
+
+	t.Run("CallWithTimeoutScheduler", func(t *testing.T) {
+		replier := newMockReplier()
+		scheduler := &MockTimeoutScheduler{}
+		s := New(newProjectWithoutModTime(files), replier, fileMapGetter(files), scheduler, &ServerOptions{
+			SchedMaxWait: 5 * time.Millisecond,
+		})
+		s.markInitialized()
+
+		call, err := jsonrpc2.NewCall(jsonrpc2.NewIntID(1), "textDocument/hover", &HoverParams{
+			TextDocumentPositionParams: TextDocumentPositionParams{
+				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+				Position:     Position{Line: 2, Character: 5},
+			},
+		})
+		require.NoError(t, err)
+		require.NoError(t, s.HandleMessage(call))
+
+		messages := replier.waitForMessages(1, 5*time.Second)
+		require.NotEmpty(t, messages)
+		assert.Equal(t, []time.Duration{5 * time.Millisecond}, scheduler.RecordedMaxWaits())
+		assert.Zero(t, scheduler.RecordedCalls())
+	})
+
+	t.Run("NoOpNotification", func(t *testing.T) {
+		replier := newMockReplier()
+		scheduler := &MockScheduler{}
+		s := New(newProjectWithoutModTime(files), replier, fileMapGetter(files), scheduler, nil)
+		s.markInitialized()
+
+		notification, err := jsonrpc2.NewNotification("exit", nil)
+		require.NoError(t, err)
+		require.NoError(t, s.HandleMessage(notification))
+
+		messages := replier.waitForMessages(0, 0)
+		assert.Empty(t, messages)
+		assert.Zero(t, scheduler.RecordedCalls())
+	})
+}
+
+func TestServerInitialize(t *testing.T) {
+	files := map[string][]byte{
+		"main.spx": []byte(`
+var x = 100
+echo x
+`),
+	}
+
+	t.Run("Capabilities", func(t *testing.T) {
+		replier := newMockReplier()
+		s := New(newProjectWithoutModTime(files), replier, fileMapGetter(files), &MockScheduler{}, nil)
+
+		call, err := jsonrpc2.NewCall(jsonrpc2.NewIntID(1), "initialize", &InitializeParams{})
+		require.NoError(t, err)
+		require.NoError(t, s.HandleMessage(call))
+
+		var resp *jsonrpc2.Response
+		for _, msg := range replier.waitForMessages(2, 5*time.Second) {
+			if r, ok := msg.(*jsonrpc2.Response); ok {
+				resp = r
+			}
+		}
+		require.NotNil(t, resp)
+		require.NoError(t, resp.Err())
+
+		var result InitializeResult
+		require.NoError(t, json.Unmarshal(resp.Result(), &result))
+		require.NotNil(t, result.Capabilities.CompletionProvider)
+		assert.Equal(t, []string{"."}, result.Capabilities.CompletionProvider.TriggerCharacters)
+		require.NotNil(t, result.Capabilities.ExecuteCommandProvider)
+		assert.Contains(t, result.Capabilities.ExecuteCommandProvider.Commands, CommandSpxGetDefinitionAt)
+	})
+
+	t.Run("QueueRequestsBeforeInitialize", func(t *testing.T) {
+		replier := newMockReplier()
+		s := New(newProjectWithoutModTime(files), replier, fileMapGetter(files), &MockScheduler{}, nil)
+
+		hoverCall, err := jsonrpc2.NewCall(jsonrpc2.NewIntID(1), "textDocument/hover", &HoverParams{
+			TextDocumentPositionParams: TextDocumentPositionParams{
+				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+				Position:     Position{Line: 2, Character: 5},
+			},
+		})
+		require.NoError(t, err)
+		require.NoError(t, s.HandleMessage(hoverCall))
+		assert.Empty(t, replier.waitForMessages(0, 0))
+
+		initializeCall, err := jsonrpc2.NewCall(jsonrpc2.NewIntID(2), "initialize", &InitializeParams{})
+		require.NoError(t, err)
+		require.NoError(t, s.HandleMessage(initializeCall))
+
+		var respIDs []jsonrpc2.ID
+		for range 50 {
+			respIDs = nil
+			for _, msg := range replier.waitForMessages(4, 100*time.Millisecond) {
+				if resp, ok := msg.(*jsonrpc2.Response); ok {
+					respIDs = append(respIDs, resp.ID())
+				}
+			}
+			if len(respIDs) == 2 {
+				break
+			}
+		}
+		// The initialize response must be sent before any queued request is
+		// answered.
+		assert.Equal(t, []jsonrpc2.ID{jsonrpc2.NewIntID(2), jsonrpc2.NewIntID(1)}, respIDs)
+	})
+
+	t.Run("FailedInitializeRejectsQueuedRequests", func(t *testing.T) {
+		replier := newMockReplier()
+		s := New(newProjectWithoutModTime(files), replier, fileMapGetter(files), &MockScheduler{}, nil)
+
+		hoverCall, err := jsonrpc2.NewCall(jsonrpc2.NewIntID(1), "textDocument/hover", &HoverParams{
+			TextDocumentPositionParams: TextDocumentPositionParams{
+				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+				Position:     Position{Line: 2, Character: 5},
+			},
+		})
+		require.NoError(t, err)
+		require.NoError(t, s.HandleMessage(hoverCall))
+		didSave, err := jsonrpc2.NewNotification("textDocument/didSave", &DidSaveTextDocumentParams{
+			TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+		})
+		require.NoError(t, err)
+		require.NoError(t, s.HandleMessage(didSave))
+
+		initializeCall, err := jsonrpc2.NewCall(jsonrpc2.NewIntID(2), "initialize", json.RawMessage(`"invalid"`))
+		require.NoError(t, err)
+		require.NoError(t, s.HandleMessage(initializeCall))
+
+		responses := make(map[jsonrpc2.ID]*jsonrpc2.Response)
+		for _, msg := range replier.waitForMessages(2, 5*time.Second) {
+			resp, ok := msg.(*jsonrpc2.Response)
+			require.True(t, ok, "want only responses, got %T", msg)
+			responses[resp.ID()] = resp
+		}
+		require.Len(t, responses, 2)
+
+		var wireErr *jsonrpc2.WireError
+		require.True(t, errors.As(responses[jsonrpc2.NewIntID(1)].Err(), &wireErr))
+		assert.Equal(t, int64(ServerNotInitialized), wireErr.Code)
+		assert.Error(t, responses[jsonrpc2.NewIntID(2)].Err())
+
+		s.initMu.Lock()
+		defer s.initMu.Unlock()
+		assert.Empty(t, s.pendingMessages)
+		assert.False(t, s.initializeDone)
+	})
+
+	t.Run("PendingMessagesLimit", func(t *testing.T) {
+		replier := newMockReplier()
+		s := New(newProjectWithoutModTime(files), replier, fileMapGetter(files), &MockScheduler{}, nil)
+
+		for range maxPendingMessages + 1 {
+			notification, err := jsonrpc2.NewNotification("unknown/method", nil)
+			require.NoError(t, err)
+			require.NoError(t, s.HandleMessage(notification))
+		}
+		hoverCall, err := jsonrpc2.NewCall(jsonrpc2.NewIntID(1), "textDocument/hover", &HoverParams{
+			TextDocumentPositionParams: TextDocumentPositionParams{
+				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+				Position:     Position{Line: 2, Character: 5},
+			},
+		})
+		require.NoError(t, err)
+		require.NoError(t, s.HandleMessage(hoverCall))
+
+		messages := replier.waitForMessages(1, 5*time.Second)
+		require.Len(t, messages, 1)
+		resp, ok := messages[0].(*jsonrpc2.Response)
+		require.True(t, ok)
+		assert.Equal(t, jsonrpc2.NewIntID(1), resp.ID())
+		var wireErr *jsonrpc2.WireError
+		require.True(t, errors.As(resp.Err(), &wireErr))
+		assert.Equal(t, int64(ServerNotInitialized), wireErr.Code)
+
+		s.initMu.Lock()
+		defer s.initMu.Unlock()
+		assert.Len(t, s.pendingMessages, maxPendingMessages)
+	})
+}
+
 func TestServerCancellation(t *testing.T) {
 	t.Run("CancelRequest", func(t *testing.T) {
 		files := map[string][]byte{
@@ -116,18 +366,18 @@ echo x
 `),
 		}
 		replier := newMockReplier()
-		s := New(newProjectWithoutModTime(files), replier, fileMapGetter(files), &MockScheduler{})
+		s := New(newProjectWithoutModTime(files), replier, fileMapGetter(files), &MockScheduler{}, nil)
 
 		call1, _ := jsonrpc2.NewCall(jsonrpc2.NewStringID("test-request-1"), "$/cancelRequest", &CancelParams{ID: "test-request-1"})
 		call2, _ := jsonrpc2.NewCall(jsonrpc2.NewStringID("test-request-2"), "$/cancelRequest", &CancelParams{ID: "test-request-2"})
 
 		var request1Runned bool
 		var request2Runned bool
-		s.runForCall(call1, func() (any, error) {
+		s.runForCall(call1, func(context.Context) (any, error) {
 			request1Runned = true
 			return "should not reach here", nil
 		})
-		s.runForCall(call2, func() (any, error) {
+		s.runForCall(call2, func(context.Context) (any, error) {
 			request2Runned = true
 			return "should not reach here either", nil
 		})
@@ -170,12 +420,90 @@ echo x
 		assert.Contains(t, wireErr2.Message, "Request cancelled")
 	})
 
+	t.Run("CancelRunningRequest", func(t *testing.T) {
+		files := map[string][]byte{
+			"main.spx": []byte(`var x = 100`),
+		}
+		replier := newMockReplier()
+		s := New(newProjectWithoutModTime(files), replier, fileMapGetter(files), &MockScheduler{}, nil)
+
+		call, _ := jsonrpc2.NewCall(jsonrpc2.NewStringID("test-running-request"), "textDocument/hover", nil)
+
+		started := make(chan struct{})
+		cancelled := make(chan struct{})
+		s.runForCall(call, func(context.Context) (any, error) {
+			close(started)
+			<-cancelled
+			return "stale result", nil
+		})
+
+		<-started
+		err := s.cancelRequest(&CancelParams{ID: "test-running-request"})
+		require.NoError(t, err)
+		close(cancelled)
+
+		var response *jsonrpc2.Response
+		for _, msg := range replier.waitForMessages(2, 5*time.Second) {
+			if resp, ok := msg.(*jsonrpc2.Response); ok {
+				response = resp
+			}
+		}
+		require.NotNil(t, response, "Should receive a Response message")
+		assert.Equal(t, call.ID(), response.ID())
+		var wireErr *jsonrpc2.WireError
+		require.True(t, errors.As(response.Err(), &wireErr))
+		assert.Equal(t, int64(RequestCancelled), wireErr.Code)
+	})
+
+	t.Run("CancelledRequestStopsCompile", func(t *testing.T) {
+		files := map[string][]byte{
+			"main.spx": []byte(`var x = 100`),
+		}
+		replier := newMockReplier()
+		s := New(newProjectWithoutModTime(files), replier, fileMapGetter(files), &MockScheduler{}, nil)
+
+		call, _ := jsonrpc2.NewCall(jsonrpc2.NewStringID("test-compile-request"), "textDocument/hover", nil)
+
+		started := make(chan struct{})
+		cancelled := make(chan struct{})
+		compileErr := make(chan error, 1)
+		s.runForCall(call, func(ctx context.Context) (any, error) {
+			close(started)
+			<-cancelled
+			_, err := s.compile(ctx)
+			compileErr <- err
+			return nil, err
+		})
+
+		<-started
+		err := s.cancelRequest(&CancelParams{ID: "test-compile-request"})
+		require.NoError(t, err)
+		close(cancelled)
+
+		select {
+		case err := <-compileErr:
+			assert.ErrorIs(t, err, requestCancelled)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for compile")
+		}
+
+		ctx, cancel := context.WithCancelCause(t.Context())
+		cancel(requestCancelled)
+		_, err = s.textDocumentHover(ctx, &HoverParams{
+			TextDocumentPositionParams: TextDocumentPositionParams{
+				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+				Position:     Position{Line: 0, Character: 4},
+			},
+		})
+		assert.ErrorIs(t, err, requestCancelled)
+	})
+
 	t.Run("CancelRequestWithInvalidID", func(t *testing.T) {
 		files := map[string][]byte{
 			"main.spx": []byte(`var x = 100`),
 		}
 		replier := &mockReplier{}
-		s := New(newProjectWithoutModTime(files), replier, fileMapGetter(files), &MockScheduler{})
+		s := New(newProjectWithoutModTime(files), replier, fileMapGetter(files), &MockScheduler{}, nil)
 
 		for _, tc := range []struct {
 			name string
@@ -194,6 +522,95 @@ echo x
 	})
 }
 
+func TestServerSetProject(t *testing.T) {
+	t.Run("Normal", func(t *testing.T) {
+		oldFiles := map[string][]byte{
+			"main.spx":          []byte(`var x = 100`),
+			"assets/index.json": []byte(`{}`),
+		}
+		s := New(newProjectWithoutModTime(oldFiles), nil, fileMapGetter(oldFiles), &MockScheduler{}, nil)
+
+		result, err := s.compile(t.Context())
+		require.NoError(t, err)
+		require.False(t, result.hasErrorSeverityDiagnostic)
+
+		newFiles := map[string][]byte{
+			"main.spx":          []byte(`var y int = "hello"`),
+			"assets/index.json": []byte(`{}`),
+		}
+		err = s.SetProject(newProjectWithoutModTime(newFiles), fileMapGetter(newFiles))
+		require.NoError(t, err)
+
+		result, err = s.compile(t.Context())
+		require.NoError(t, err)
+		assert.True(t, result.hasErrorSeverityDiagnostic)
+		assert.Same(t, s.getProj(), result.proj)
+		file, ok := result.proj.File("main.spx")
+		require.True(t, ok)
+		assert.Equal(t, newFiles["main.spx"], file.Content)
+	})
+
+	t.Run("CancelRunningRequest", func(t *testing.T) {
+		files := map[string][]byte{
+			"main.spx": []byte(`var x = 100`),
+		}
+		replier := newMockReplier()
+		s := New(newProjectWithoutModTime(files), replier, fileMapGetter(files), &MockScheduler{}, nil)
+
+		call, _ := jsonrpc2.NewCall(jsonrpc2.NewStringID("test-running-request"), "textDocument/hover", nil)
+
+		started := make(chan struct{})
+		replaced := make(chan struct{})
+		s.runForCall(call, func(context.Context) (any, error) {
+			close(started)
+			<-replaced
+			return "stale result", nil
+		})
+
+		<-started
+		err := s.SetProject(newProjectWithoutModTime(files), fileMapGetter(files))
+		require.NoError(t, err)
+		close(replaced)
+
+		var response *jsonrpc2.Response
+		for _, msg := range replier.waitForMessages(2, 5*time.Second) {
+			if resp, ok := msg.(*jsonrpc2.Response); ok {
+				response = resp
+			}
+		}
+		require.NotNil(t, response, "Should receive a Response message")
+		var wireErr *jsonrpc2.WireError
+		require.True(t, errors.As(response.Err(), &wireErr))
+		assert.Equal(t, int64(ContentModified), wireErr.Code)
+	})
+}
+
+func TestServerProjectSnapshot(t *testing.T) {
+	files := map[string][]byte{
+		"main.spx":          []byte(`var x = 100`),
+		"assets/index.json": []byte(`{}`),
+	}
+	s := New(newProjectWithoutModTime(files), nil, fileMapGetter(files), &MockScheduler{}, nil)
+	pkgDoc, err := s.getProj().PkgDoc()
+	require.NoError(t, err)
+	data, err := s.SaveProjectSnapshot()
+	require.NoError(t, err)
+
+	s = New(newProjectWithoutModTime(files), nil, fileMapGetter(files), &MockScheduler{}, nil)
+	require.NoError(t, s.LoadProjectSnapshot(data))
+	result, err := s.compile(t.Context())
+	require.NoError(t, err)
+	require.False(t, result.hasErrorSeverityDiagnostic)
+
+	s.getProj().ResetCacheMetrics()
+	restoredPkgDoc, err := s.getProj().PkgDoc()
+	require.NoError(t, err)
+	assert.Equal(t, pkgDoc, restoredPkgDoc)
+	assert.Zero(t, s.CacheMetrics().Project.Builds)
+
+	assert.Error(t, s.LoadProjectSnapshot([]byte("invalid")))
+}
+
 func TestHandleMessageCall(t *testing.T) {
 	for _, tc := range []struct {
 		name   string
@@ -489,7 +906,8 @@ fmt.Println("Hello, World!")
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			replier := newMockReplier()
-			server := New(newProjectWithoutModTime(tc.files), replier, fileMapGetter(tc.files), &MockScheduler{})
+			server := New(newProjectWithoutModTime(tc.files), replier, fileMapGetter(tc.files), &MockScheduler{}, nil)
+			server.markInitialized()
 
 			var params json.RawMessage
 			if tc.params != nil {
@@ -617,7 +1035,8 @@ func TestHandleMessageNotification(t *testing.T) {
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			replier := newMockReplier()
-			server := New(newProjectWithoutModTime(tc.files), replier, fileMapGetter(tc.files), &MockScheduler{})
+			server := New(newProjectWithoutModTime(tc.files), replier, fileMapGetter(tc.files), &MockScheduler{}, nil)
+			server.markInitialized()
 
 			var params json.RawMessage
 			if tc.params != nil {
@@ -660,10 +1079,10 @@ var (
 		}
 
 		replier := newMockReplier()
-		s := New(newProjectWithoutModTime(m), replier, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), replier, fileMapGetter(m), &MockScheduler{}, nil)
 
 		// Perform rename on property field x
-		workspaceEdit, err := s.textDocumentRename(&RenameParams{
+		workspaceEdit, err := s.textDocumentRename(t.Context(), &RenameParams{
 			TextDocument: TextDocumentIdentifier{URI: "file:///MySprite.spx"},
 			Position:     Position{Line: 2, Character: 1}, // Position on "x"
 			NewName:      "posX",
@@ -689,3 +1108,93 @@ var (
 		assert.Equal(t, DocumentURI("file:///MySprite.spx"), params.TextDocument.URI)
 	})
 }
+
+func TestServerWindowMessages(t *testing.T) {
+	files := map[string][]byte{
+		"main.spx": []byte(`echo 1`),
+	}
+
+	t.Run("ShowMessageAndLogMessage", func(t *testing.T) {
+		replier := newMockReplier()
+		s := New(newProjectWithoutModTime(files), replier, fileMapGetter(files), &MockScheduler{}, nil)
+
+		require.NoError(t, s.ShowMessage(MessageTypeWarning, "missing asset"))
+		require.NoError(t, s.LogMessage(MessageTypeLog, "debug info"))
+
+		messages := replier.getMessages()
+		require.Len(t, messages, 2)
+
+		showMessage, ok := messages[0].(*jsonrpc2.Notification)
+		require.True(t, ok)
+		assert.Equal(t, "window/showMessage", showMessage.Method())
+		var showParams ShowMessageParams
+		require.NoError(t, json.Unmarshal(showMessage.Params(), &showParams))
+		assert.Equal(t, MessageTypeWarning, showParams.Type)
+		assert.Equal(t, "missing asset", showParams.Message)
+
+		logMessage, ok := messages[1].(*jsonrpc2.Notification)
+		require.True(t, ok)
+		assert.Equal(t, "window/logMessage", logMessage.Method())
+		var logParams LogMessageParams
+		require.NoError(t, json.Unmarshal(logMessage.Params(), &logParams))
+		assert.Equal(t, MessageTypeLog, logParams.Type)
+		assert.Equal(t, "debug info", logParams.Message)
+	})
+
+	t.Run("PanicInCall", func(t *testing.T) {
+		replier := newMockReplier()
+		s := New(newProjectWithoutModTime(files), replier, fileMapGetter(files), &MockScheduler{}, nil)
+
+		call, _ := jsonrpc2.NewCall(jsonrpc2.NewStringID("test-panic"), "textDocument/hover", nil)
+		s.runForCall(call, func(context.Context) (any, error) {
+			panic("boom")
+		})
+
+		var (
+			showParams *ShowMessageParams
+			response   *jsonrpc2.Response
+		)
+		for _, msg := range replier.waitForMessages(2, 5*time.Second) {
+			switch msg := msg.(type) {
+			case *jsonrpc2.Notification:
+				if msg.Method() == "window/showMessage" {
+					showParams = &ShowMessageParams{}
+					require.NoError(t, json.Unmarshal(msg.Params(), showParams))
+				}
+			case *jsonrpc2.Response:
+				response = msg
+			}
+		}
+		require.NotNil(t, showParams)
+		assert.Equal(t, MessageTypeError, showParams.Type)
+		assert.Contains(t, showParams.Message, "boom")
+		require.NotNil(t, response)
+		assert.Equal(t, call.ID(), response.ID())
+		require.Error(t, response.Err())
+		assert.Contains(t, response.Err().Error(), "boom")
+	})
+}
+
+func TestServerFromDocumentURI(t *testing.T) {
+	s := New(newProjectWithoutModTime(nil), nil, fileMapGetter(nil), &MockScheduler{}, nil)
+
+	for _, tt := range []struct {
+		name string
+		uri  DocumentURI
+		want string
+	}{
+		{name: "Plain", uri: "file:///main.spx", want: "main.spx"},
+		{name: "EncodedSpace", uri: "file:///My%20Sprite.spx", want: "My Sprite.spx"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.fromDocumentURI(tt.uri)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("OutsideWorkspace", func(t *testing.T) {
+		_, err := s.fromDocumentURI("untitled:main.spx")
+		assert.Error(t, err)
+	})
+}
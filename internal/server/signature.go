@@ -2,32 +2,34 @@ package server
 
 import (
 	"cmp"
+	"context"
 	gotypes "go/types"
 	"strings"
 
 	"github.com/goplus/xgo/ast"
 	"github.com/goplus/xgo/token"
+	"github.com/goplus/xgolsw/pkgdoc"
 	"github.com/goplus/xgolsw/xgo"
 	"github.com/goplus/xgolsw/xgo/types"
 	"github.com/goplus/xgolsw/xgo/xgoutil"
 )
 
 // See https://microsoft.github.io/language-server-protocol/specifications/lsp/3.18/specification/#textDocument_signatureHelp
-func (s *Server) textDocumentSignatureHelp(params *SignatureHelpParams) (*SignatureHelp, error) {
-	result, _, astFile, err := s.compileAndGetASTFileForDocumentURI(params.TextDocument.URI)
+func (s *Server) textDocumentSignatureHelp(ctx context.Context, params *SignatureHelpParams) (*SignatureHelp, error) {
+	result, err := s.compileAndGetASTFileForDocumentURI(ctx, params.TextDocument.URI)
 	if err != nil {
 		return nil, err
 	}
-	if astFile == nil {
+	if !result.IsUsable() {
 		return nil, nil
 	}
-	pos := PosAt(result.proj, astFile, params.Position)
+	pos := PosAt(result.proj, result.astFile, params.Position)
 	typeInfo, _ := result.proj.TypeInfo()
 	if typeInfo == nil {
 		return nil, nil
 	}
 
-	callExpr := enclosingCallExprAtPosition(astFile, pos)
+	callExpr := enclosingCallExprAtPosition(result.astFile, pos)
 	if callExpr != nil && !callExprCoversSignaturePosition(callExpr, pos) {
 		callExpr = nil
 	}
@@ -45,7 +47,7 @@ func (s *Server) textDocumentSignatureHelp(params *SignatureHelpParams) (*Signat
 		}
 		activeParameter = signatureHelpActiveParameter(typeInfo, callExpr, pos, sig, resolvedParams)
 	} else {
-		ident := signatureHelpIdentAtPosition(typeInfo, astFile, pos)
+		ident := signatureHelpIdentAtPosition(typeInfo, result.astFile, pos)
 		obj := typeInfo.ObjectOf(ident)
 		if obj == nil {
 			return nil, nil
@@ -65,7 +67,7 @@ func (s *Server) textDocumentSignatureHelp(params *SignatureHelpParams) (*Signat
 		displayedName = signatureHelpResolvedCallName(result.proj, typeInfo, callExpr, fun)
 	}
 	help := &SignatureHelp{
-		Signatures: []SignatureInformation{signatureHelpInformation(fun, sig, resolvedParams, displayedName)},
+		Signatures: []SignatureInformation{signatureHelpInformation(result.proj, fun, sig, resolvedParams, displayedName)},
 	}
 	if activeParameter >= 0 {
 		help.ActiveParameter = uint32(activeParameter)
@@ -116,7 +118,7 @@ func overloadSignatureHelp(proj *xgo.Project, typeInfo *types.Info, callExpr *as
 		}
 		sig := overload.Signature()
 		params := sig.Params()
-		signature := signatureHelpInformation(overload, sig, params, displayedName)
+		signature := signatureHelpInformation(proj, overload, sig, params, displayedName)
 		if activeParameter < 0 {
 			activeParameter = overloadSignatureHelpActiveParameter(callExpr, pos, sig, resolvedArg, hasResolvedArg)
 		}
@@ -134,15 +136,16 @@ func overloadSignatureHelp(proj *xgo.Project, typeInfo *types.Info, callExpr *as
 }
 
 // signatureHelpInformation returns signature information for one function.
-func signatureHelpInformation(fun *gotypes.Func, sig *gotypes.Signature, params *gotypes.Tuple, displayedName string) SignatureInformation {
+func signatureHelpInformation(proj *xgo.Project, fun *gotypes.Func, sig *gotypes.Signature, params *gotypes.Tuple, displayedName string) SignatureInformation {
+	paramDocs := signatureHelpParameterDocs(proj, fun)
 	paramLabels := make([]string, 0, params.Len())
 	paramInfos := make([]ParameterInformation, 0, params.Len())
 	for i := range params.Len() {
 		paramLabel := signatureHelpParameterLabel(fun, sig, params, i)
 		paramLabels = append(paramLabels, paramLabel)
 		paramInfos = append(paramInfos, ParameterInformation{
-			Label: paramLabel,
-			// TODO: Add documentation.
+			Label:         paramLabel,
+			Documentation: paramDocs[xgoutil.SourceParamName(params.At(i))],
 		})
 	}
 
@@ -156,6 +159,32 @@ func signatureHelpInformation(fun *gotypes.Func, sig *gotypes.Signature, params
 	}
 }
 
+// signatureHelpParameterDocs returns the documentation of the parameters of
+// fun keyed by their source-facing names.
+func signatureHelpParameterDocs(proj *xgo.Project, fun *gotypes.Func) map[string]string {
+	if fun.Pkg() == nil {
+		return nil
+	}
+	var mainPkgDoc *pkgdoc.PkgDoc
+	if xgoutil.IsInMainPkg(fun) {
+		mainPkgDoc, _ = proj.PkgDoc()
+	}
+	pkgDoc := makePkgDocFor(mainPkgDoc)(fun.Pkg())
+	if pkgDoc == nil {
+		return nil
+	}
+
+	def := GetSpxDefinitionForFunc(fun, "", pkgDoc)
+	if len(def.ParameterDocs) == 0 {
+		return nil
+	}
+	paramDocs := make(map[string]string, len(def.ParameterDocs))
+	for _, paramDoc := range def.ParameterDocs {
+		paramDocs[paramDoc.Name] = paramDoc.Doc
+	}
+	return paramDocs
+}
+
 // signatureHelpResolvedArgAtPosition returns the resolved argument at pos.
 func signatureHelpResolvedArgAtPosition(typeInfo *types.Info, callExpr *ast.CallExpr, overloads []*gotypes.Func, pos token.Pos) (xgoutil.ResolvedCallExprArg, bool) {
 	for resolvedArg := range formatResolvedCallExprArgs(typeInfo, callExpr, overloads) {
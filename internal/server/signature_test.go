@@ -23,9 +23,9 @@ onStart => {
 			"assets/index.json":                  []byte(`{}`),
 			"assets/sprites/MySprite/index.json": []byte(`{}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		fmtHelp, err := s.textDocumentSignatureHelp(&SignatureHelpParams{
+		fmtHelp, err := s.textDocumentSignatureHelp(t.Context(), &SignatureHelpParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 2, Character: 10},
@@ -43,7 +43,7 @@ onStart => {
 			},
 		}, fmtHelp.Signatures[0])
 
-		turnHelp, err := s.textDocumentSignatureHelp(&SignatureHelpParams{
+		turnHelp, err := s.textDocumentSignatureHelp(t.Context(), &SignatureHelpParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 3, Character: 10},
@@ -72,9 +72,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		help, err := s.textDocumentSignatureHelp(&SignatureHelpParams{
+		help, err := s.textDocumentSignatureHelp(t.Context(), &SignatureHelpParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 4, Character: 4},
@@ -97,9 +97,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		help, err := s.textDocumentSignatureHelp(&SignatureHelpParams{
+		help, err := s.textDocumentSignatureHelp(t.Context(), &SignatureHelpParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 4, Character: 4},
@@ -121,9 +121,9 @@ onStart => {
 `),
 			"assets/index.json": []byte(`{"zorder":[{"name":"myWidget"}]}`),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		help, err := s.textDocumentSignatureHelp(&SignatureHelpParams{
+		help, err := s.textDocumentSignatureHelp(t.Context(), &SignatureHelpParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 2, Character: 1},
@@ -160,9 +160,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		help, err := s.textDocumentSignatureHelp(&SignatureHelpParams{
+		help, err := s.textDocumentSignatureHelp(t.Context(), &SignatureHelpParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 8, Character: 13},
@@ -210,9 +210,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		help, err := s.textDocumentSignatureHelp(&SignatureHelpParams{
+		help, err := s.textDocumentSignatureHelp(t.Context(), &SignatureHelpParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 22, Character: 18},
@@ -260,9 +260,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		help, err := s.textDocumentSignatureHelp(&SignatureHelpParams{
+		help, err := s.textDocumentSignatureHelp(t.Context(), &SignatureHelpParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 22, Character: 16},
@@ -286,9 +286,9 @@ onStart => {
 }
 `),
 		}
-		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{})
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
 
-		positionalHelp, err := s.textDocumentSignatureHelp(&SignatureHelpParams{
+		positionalHelp, err := s.textDocumentSignatureHelp(t.Context(), &SignatureHelpParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 4, Character: 12},
@@ -310,7 +310,7 @@ onStart => {
 			},
 		}, positionalHelp.Signatures[0])
 
-		kwargHelp, err := s.textDocumentSignatureHelp(&SignatureHelpParams{
+		kwargHelp, err := s.textDocumentSignatureHelp(t.Context(), &SignatureHelpParams{
 			TextDocumentPositionParams: TextDocumentPositionParams{
 				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
 				Position:     Position{Line: 4, Character: 23},
@@ -322,4 +322,44 @@ onStart => {
 		assert.Equal(t, uint32(0), kwargHelp.ActiveParameter)
 		assert.Equal(t, positionalHelp.Signatures[0], kwargHelp.Signatures[0])
 	})
+
+	t.Run("ParameterDocs", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.spx": []byte(`
+// moveBy moves by the given offsets.
+//
+//   dx: the horizontal offset
+//   dy: the vertical offset
+func moveBy(dx, dy int) {}
+
+onStart => {
+	moveBy 1, 2
+}
+`),
+		}
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+		help, err := s.textDocumentSignatureHelp(t.Context(), &SignatureHelpParams{
+			TextDocumentPositionParams: TextDocumentPositionParams{
+				TextDocument: TextDocumentIdentifier{URI: "file:///main.spx"},
+				Position:     Position{Line: 8, Character: 11},
+			},
+		})
+		require.NoError(t, err)
+		require.NotNil(t, help)
+		require.Len(t, help.Signatures, 1)
+		assert.Equal(t, SignatureInformation{
+			Label: "moveBy(dx int, dy int)",
+			Parameters: []ParameterInformation{
+				{
+					Label:         "dx int",
+					Documentation: "the horizontal offset",
+				},
+				{
+					Label:         "dy int",
+					Documentation: "the vertical offset",
+				},
+			},
+		}, help.Signatures[0])
+	})
 }
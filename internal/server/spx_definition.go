@@ -1,9 +1,11 @@
 package server
 
 import (
+	"encoding/json"
 	"fmt"
 	gotypes "go/types"
 	"html/template"
+	"regexp"
 	"slices"
 	"strings"
 	"sync"
@@ -24,12 +26,48 @@ type SpxDefinition struct {
 	Overview string
 	Detail   string
 
+	// Deprecated is the deprecation notice of the definition, or "" if the
+	// definition is not deprecated.
+	Deprecated string
+
+	// ParameterDocs holds the documentation of the parameters that are
+	// documented in Detail. It is only populated for functions.
+	ParameterDocs []SpxParameterDoc
+
+	// Signature is the structured signature of the definition. It is only
+	// populated for functions.
+	Signature *SpxFunctionSignature
+
 	CompletionItemLabel            string
 	CompletionItemKind             CompletionItemKind
 	CompletionItemInsertText       string
 	CompletionItemInsertTextFormat InsertTextFormat
 }
 
+// SpxParameterDoc represents the documentation of a function parameter.
+type SpxParameterDoc struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Doc  string `json:"doc"`
+}
+
+// SpxFunctionSignature represents the signature of a function in a form that
+// is suitable for generating code.
+type SpxFunctionSignature struct {
+	Params  []SpxParam `json:"params"`
+	Results []SpxParam `json:"results"`
+}
+
+// SpxParam represents a parameter or result of a function.
+type SpxParam struct {
+	Name       string `json:"name"`
+	TypeString string `json:"typeString"`
+
+	// IsVariadic reports whether the parameter is variadic. If so, TypeString
+	// is the type of each of its arguments.
+	IsVariadic bool `json:"isVariadic,omitempty"`
+}
+
 // HTML returns the HTML representation of the definition.
 func (def SpxDefinition) HTML() string {
 	return fmt.Sprintf("<pre is=\"definition-item\" def-id=%q overview=%q>\n%s</pre>\n", template.HTMLEscapeString(def.ID.String()), template.HTMLEscapeString(def.Overview), def.Detail)
@@ -43,12 +81,86 @@ func (def SpxDefinition) CompletionItem() CompletionItem {
 		Documentation:    &Or_CompletionItem_documentation{Value: MarkupContent{Kind: Markdown, Value: def.HTML()}},
 		InsertText:       def.CompletionItemInsertText,
 		InsertTextFormat: &def.CompletionItemInsertTextFormat,
+		Tags:             def.completionItemTags(),
 		Data: &CompletionItemData{
 			Definition: &def.ID,
 		},
 	}
 }
 
+// completionItemTags returns the [CompletionItemTag]s of the definition.
+func (def SpxDefinition) completionItemTags() []CompletionItemTag {
+	if def.Deprecated != "" {
+		return []CompletionItemTag{DeprecatedCompletion}
+	}
+	return nil
+}
+
+// spxDefinitionJSON is the JSON representation of [SpxDefinition].
+type spxDefinitionJSON struct {
+	ID       SpxDefinitionIdentifier `json:"id"`
+	Overview string                  `json:"overview"`
+	Detail   string                  `json:"detail"`
+
+	Deprecated string `json:"deprecated,omitempty"`
+
+	ParameterDocs []SpxParameterDoc `json:"parameterDocs,omitempty"`
+
+	Signature *SpxFunctionSignature `json:"signature,omitempty"`
+
+	CompletionItemLabel            string             `json:"completionItemLabel"`
+	CompletionItemKind             CompletionItemKind `json:"completionItemKind"`
+	CompletionItemInsertText       string             `json:"completionItemInsertText"`
+	CompletionItemInsertTextFormat InsertTextFormat   `json:"completionItemInsertTextFormat"`
+}
+
+// MarshalJSON implements [json.Marshaler]. The [SpxDefinition.TypeHint] field
+// is not serialized.
+func (def SpxDefinition) MarshalJSON() ([]byte, error) {
+	return json.Marshal(spxDefinitionJSON{
+		ID:       def.ID,
+		Overview: def.Overview,
+		Detail:   def.Detail,
+
+		Deprecated: def.Deprecated,
+
+		ParameterDocs: def.ParameterDocs,
+
+		Signature: def.Signature,
+
+		CompletionItemLabel:            def.CompletionItemLabel,
+		CompletionItemKind:             def.CompletionItemKind,
+		CompletionItemInsertText:       def.CompletionItemInsertText,
+		CompletionItemInsertTextFormat: def.CompletionItemInsertTextFormat,
+	})
+}
+
+// UnmarshalJSON implements [json.Unmarshaler]. The [SpxDefinition.TypeHint]
+// field is always left nil.
+func (def *SpxDefinition) UnmarshalJSON(data []byte) error {
+	var v spxDefinitionJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*def = SpxDefinition{
+		ID:       v.ID,
+		Overview: v.Overview,
+		Detail:   v.Detail,
+
+		Deprecated: v.Deprecated,
+
+		ParameterDocs: v.ParameterDocs,
+
+		Signature: v.Signature,
+
+		CompletionItemLabel:            v.CompletionItemLabel,
+		CompletionItemKind:             v.CompletionItemKind,
+		CompletionItemInsertText:       v.CompletionItemInsertText,
+		CompletionItemInsertTextFormat: v.CompletionItemInsertTextFormat,
+	}
+	return nil
+}
+
 var (
 	// GeneralSpxDefinitions are general spx definitions.
 	GeneralSpxDefinitions = []SpxDefinition{
@@ -92,6 +204,16 @@ var (
 			CompletionItemInsertText:       "for ${1:i} in ${2:1}:${3:5} {\n\t$0\n}",
 			CompletionItemInsertTextFormat: SnippetTextFormat,
 		},
+		{
+			ID:       SpxDefinitionIdentifier{Name: ToPtr("for_loop_with_counter")},
+			Overview: "for i := 0; i < n; i++ {}",
+			Detail:   "Loop with counter",
+
+			CompletionItemLabel:            "for",
+			CompletionItemKind:             KeywordCompletion,
+			CompletionItemInsertText:       "for ${1:i} := 0; ${1} < ${2:n}; ${1}++ {\n\t$0\n}",
+			CompletionItemInsertTextFormat: SnippetTextFormat,
+		},
 		{
 			ID:       SpxDefinitionIdentifier{Name: ToPtr("if_statement")},
 			Overview: "if condition {}",
@@ -252,19 +374,19 @@ func GetSpxDefinitionForBuiltinObj(obj gotypes.Object) SpxDefinition {
 
 	var detail string
 	if pkgDoc, err := pkgdata.GetPkgDoc(pkgPath); err == nil {
-		if doc, ok := pkgDoc.Vars[idName]; ok {
+		if doc, ok := pkgDoc.LookupVar(idName); ok {
 			detail = doc
-		} else if doc, ok := pkgDoc.Consts[idName]; ok {
+		} else if doc, ok := pkgDoc.LookupConst(idName); ok {
 			detail = doc
-		} else if typeDoc, ok := pkgDoc.Types[idName]; ok {
-			if doc, ok := typeDoc.Fields[idName]; ok {
+		} else if typeDoc, ok := pkgDoc.LookupType(idName); ok {
+			if doc, ok := pkgDoc.LookupField(idName, idName); ok {
 				detail = doc
-			} else if doc, ok := typeDoc.Methods[idName]; ok {
+			} else if doc, ok := pkgDoc.LookupMethod(idName, idName); ok {
 				detail = doc
 			} else {
-				detail = typeDoc.Doc
+				detail = typeDoc
 			}
-		} else if doc, ok := pkgDoc.Funcs[idName]; ok {
+		} else if doc, ok := pkgDoc.LookupFunc(idName); ok {
 			detail = doc
 		}
 	}
@@ -298,6 +420,8 @@ func GetSpxDefinitionForBuiltinObj(obj gotypes.Object) SpxDefinition {
 		Overview: overview,
 		Detail:   detail,
 
+		Deprecated: pkgdoc.DeprecationNotice(detail),
+
 		CompletionItemLabel:            obj.Name(),
 		CompletionItemKind:             completionItemKind,
 		CompletionItemInsertText:       obj.Name(),
@@ -467,6 +591,12 @@ var (
 		return spxPkg.Scope().Lookup("WidgetName").Type().(*gotypes.Alias)
 	})
 
+	// GetSpxWidgetType returns the [spx.Widget] type.
+	GetSpxWidgetType = sync.OnceValue(func() *gotypes.Named {
+		spxPkg := GetSpxPkg()
+		return spxPkg.Scope().Lookup("Widget").Type().(*gotypes.Named)
+	})
+
 	// GetSpxDirectionType returns the [spx.Direction] type.
 	GetSpxDirectionType = sync.OnceValue(func() *gotypes.Alias {
 		spxPkg := GetSpxPkg()
@@ -509,6 +639,31 @@ var (
 		return spxPkg.Scope().Lookup("RotationStyle").Type().(*gotypes.Named)
 	})
 
+	// GetSpxLayerActionValues returns the names of all [spx.layerAction] constants.
+	GetSpxLayerActionValues = sync.OnceValue(func() []string {
+		return spxConstNamesOfType(GetSpxLayerActionType())
+	})
+
+	// GetSpxDirActionValues returns the names of all [spx.dirAction] constants.
+	GetSpxDirActionValues = sync.OnceValue(func() []string {
+		return spxConstNamesOfType(GetSpxDirActionType())
+	})
+
+	// GetSpxEffectKindValues returns the names of all [spx.EffectKind] constants.
+	GetSpxEffectKindValues = sync.OnceValue(func() []string {
+		return spxConstNamesOfType(GetSpxEffectKindType())
+	})
+
+	// GetSpxKeyValues returns the names of all [spx.Key] constants.
+	GetSpxKeyValues = sync.OnceValue(func() []string {
+		return spxConstNamesOfType(GetSpxKeyType())
+	})
+
+	// GetSpxRotationStyleValues returns the names of all [spx.RotationStyle] constants.
+	GetSpxRotationStyleValues = sync.OnceValue(func() []string {
+		return spxConstNamesOfType(GetSpxRotationStyleType())
+	})
+
 	// GetSpxPropertyNameType returns the [spx.PropertyName] type.
 	GetSpxPropertyNameType = sync.OnceValue(func() *gotypes.Alias {
 		spxPkg := GetSpxPkg()
@@ -539,6 +694,42 @@ var (
 	})
 )
 
+// GetAllSpxDefinitions returns the combined spx definitions of the spx
+// package, the math package, and the builtins, together with the members of
+// [spx.Game] and [spx.Sprite].
+var GetAllSpxDefinitions = sync.OnceValue(func() []SpxDefinition {
+	spxPkgDoc, err := pkgdata.GetPkgDoc(SpxPkgPath)
+	if err != nil {
+		panic(fmt.Errorf("failed to get spx package doc: %w", err))
+	}
+
+	var defs []SpxDefinition
+	defs = append(defs, GetSpxPkgDefinitions()...)
+	defs = append(defs, GetMathPkgSpxDefinitions()...)
+	defs = append(defs, GetBuiltinSpxDefinitions()...)
+	for _, named := range []*gotypes.Named{GetSpxGameType(), GetSpxSpriteImplType()} {
+		for structMember := range xgoutil.StructMembers(named) {
+			selectorTypeName := structMember.Selector.Obj().Name()
+			switch member := structMember.Member.(type) {
+			case *gotypes.Var:
+				defs = append(defs, GetSpxDefinitionForVar(member, selectorTypeName, false, spxPkgDoc))
+			case *gotypes.Func:
+				if xgoutil.IsUnexpandableXGoOverloadableFunc(member) {
+					continue
+				}
+				if funcOverloads := xgoutil.ExpandXGoOverloadableFunc(member); funcOverloads != nil {
+					for _, funcOverload := range funcOverloads {
+						defs = append(defs, GetSpxDefinitionForFunc(funcOverload, selectorTypeName, spxPkgDoc))
+					}
+					continue
+				}
+				defs = append(defs, GetSpxDefinitionForFunc(member, selectorTypeName, spxPkgDoc))
+			}
+		}
+	}
+	return slices.Clip(defs)
+})
+
 // nonMainPkgSpxDefsCache is a cache of non-main package spx definitions.
 var nonMainPkgSpxDefsCache sync.Map // map[*types.Package][]SpxDefinition
 
@@ -635,12 +826,10 @@ func GetSpxDefinitionForVar(v *gotypes.Var, selectorTypeName string, forceVar bo
 	overview.WriteString(GetSimplifiedTypeString(v.Type()))
 
 	var detail string
-	if pkgDoc != nil {
-		if selectorTypeName == "" {
-			detail = pkgDoc.Vars[v.Name()]
-		} else if typeDoc, ok := pkgDoc.Types[selectorTypeName]; ok {
-			detail = typeDoc.Fields[v.Name()]
-		}
+	if selectorTypeName == "" {
+		detail, _ = pkgDoc.LookupVar(v.Name())
+	} else {
+		detail, _ = pkgDoc.LookupField(selectorTypeName, v.Name())
 	}
 
 	idName := v.Name()
@@ -665,6 +854,8 @@ func GetSpxDefinitionForVar(v *gotypes.Var, selectorTypeName string, forceVar bo
 		Overview: overview.String(),
 		Detail:   detail,
 
+		Deprecated: pkgdoc.DeprecationNotice(detail),
+
 		CompletionItemLabel:            v.Name(),
 		CompletionItemKind:             completionItemKind,
 		CompletionItemInsertText:       v.Name(),
@@ -694,10 +885,7 @@ func GetSpxDefinitionForConst(c *gotypes.Const, pkgDoc *pkgdoc.PkgDoc) (def SpxD
 	overview.WriteString(" = ")
 	overview.WriteString(c.Val().String())
 
-	var detail string
-	if pkgDoc != nil {
-		detail = pkgDoc.Consts[c.Name()]
-	}
+	detail, _ := pkgDoc.LookupConst(c.Name())
 
 	def = SpxDefinition{
 		TypeHint: c.Type(),
@@ -709,6 +897,8 @@ func GetSpxDefinitionForConst(c *gotypes.Const, pkgDoc *pkgdoc.PkgDoc) (def SpxD
 		Overview: overview.String(),
 		Detail:   detail,
 
+		Deprecated: pkgdoc.DeprecationNotice(detail),
+
 		CompletionItemLabel:            c.Name(),
 		CompletionItemKind:             ConstantCompletion,
 		CompletionItemInsertText:       c.Name(),
@@ -736,13 +926,8 @@ func GetSpxDefinitionForType(typeName *gotypes.TypeName, pkgDoc *pkgdoc.PkgDoc)
 	overview.WriteString("type ")
 	overview.WriteString(typeName.Name())
 
-	var detail string
-	if pkgDoc != nil {
-		typeDoc, ok := pkgDoc.Types[typeName.Name()]
-		if ok {
-			detail = typeDoc.Doc
-		}
-	}
+	detail, _ := pkgDoc.LookupType(typeName.Name())
+	example, _ := pkgDoc.LookupExample(typeName.Name(), "")
 
 	completionKind := ClassCompletion
 	if named := resolvedNamedType(typeName.Type()); named != nil {
@@ -762,7 +947,9 @@ func GetSpxDefinitionForType(typeName *gotypes.TypeName, pkgDoc *pkgdoc.PkgDoc)
 			Name:    ToPtr(typeName.Name()),
 		},
 		Overview: overview.String(),
-		Detail:   detail,
+		Detail:   appendSpxDefinitionExample(detail, example),
+
+		Deprecated: pkgdoc.DeprecationNotice(detail),
 
 		CompletionItemLabel:            typeName.Name(),
 		CompletionItemKind:             completionKind,
@@ -807,14 +994,13 @@ func GetSpxDefinitionForFunc(fun *gotypes.Func, recvTypeName string, pkgDoc *pkg
 		recvTypeName = parsedRecvTypeName
 	}
 
-	var detail string
-	if pkgDoc != nil {
-		funcName := fun.Name()
-		if recvTypeName == "" || xgoutil.IsXGotMethodName(funcName) {
-			detail = pkgDoc.Funcs[funcName]
-		} else if typeDoc, ok := pkgDoc.Types[recvTypeName]; ok {
-			detail = typeDoc.Methods[funcName]
-		}
+	var detail, example string
+	if funcName := fun.Name(); recvTypeName == "" || xgoutil.IsXGotMethodName(funcName) {
+		detail, _ = pkgDoc.LookupFunc(funcName)
+		example, _ = pkgDoc.LookupExample("", funcName)
+	} else {
+		detail, _ = pkgDoc.LookupMethod(recvTypeName, funcName)
+		example, _ = pkgDoc.LookupExample(recvTypeName, funcName)
 	}
 
 	idName := parsedName
@@ -834,16 +1020,185 @@ func GetSpxDefinitionForFunc(fun *gotypes.Func, recvTypeName string, pkgDoc *pkg
 			OverloadID: overloadID,
 		},
 		Overview: overview,
-		Detail:   detail,
+		Detail:   appendSpxDefinitionExample(detail, example),
+
+		Deprecated: pkgdoc.DeprecationNotice(detail),
+
+		ParameterDocs: parseSpxParameterDocs(detail, fun.Signature().Params()),
+
+		Signature: makeSpxFunctionSignature(fun),
 
 		CompletionItemLabel:            parsedName,
 		CompletionItemKind:             FunctionCompletion,
 		CompletionItemInsertText:       parsedName,
 		CompletionItemInsertTextFormat: PlainTextTextFormat,
 	}
+	if insertText, ok := spxEventHandlerInsertText(fun, parsedName); ok {
+		def.CompletionItemInsertText = insertText
+		def.CompletionItemInsertTextFormat = SnippetTextFormat
+	}
 	return
 }
 
+// spxEventHandlerInsertText returns the snippet that calls the spx event
+// handler fun named name with a lambda, e.g., "onKey ${1:key}, => {\n\t$0\n}"
+// for "onKey(key Key, onKey func())". Each argument before the lambda and each
+// parameter of the lambda gets a placeholder. It reports false if fun is not
+// an spx event handler taking a callback as its last parameter.
+func spxEventHandlerInsertText(fun *gotypes.Func, name string) (string, bool) {
+	if !IsInSpxPkg(fun) || !IsSpxEventHandlerFuncName(name) {
+		return "", false
+	}
+	_, _, _, isXGotMethod := displayedFuncName(fun)
+	params := fun.Signature().Params()
+	first := 0
+	if isXGotMethod {
+		first = 1
+	}
+	if params.Len() <= first {
+		return "", false
+	}
+	callback, ok := params.At(params.Len() - 1).Type().Underlying().(*gotypes.Signature)
+	if !ok {
+		return "", false
+	}
+
+	var (
+		sb           strings.Builder
+		placeholders int
+	)
+	sb.WriteString(name)
+	sb.WriteString(" ")
+	for i := first; i < params.Len()-1; i++ {
+		placeholders++
+		fmt.Fprintf(&sb, "${%d:%s}, ", placeholders, xgoutil.SourceParamName(params.At(i)))
+	}
+	callbackParams := callback.Params()
+	if callbackParams.Len() > 1 {
+		sb.WriteString("(")
+	}
+	for i := range callbackParams.Len() {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		placeholders++
+		fmt.Fprintf(&sb, "${%d:%s}", placeholders, spxEventHandlerCallbackParamName(callbackParams.At(i)))
+	}
+	if callbackParams.Len() > 1 {
+		sb.WriteString(")")
+	}
+	if callbackParams.Len() > 0 {
+		sb.WriteString(" ")
+	}
+	sb.WriteString("=> {\n\t$0\n}")
+	return sb.String(), true
+}
+
+// spxEventHandlerCallbackParamName returns the placeholder name for param of
+// an spx event handler callback. Unnamed parameters are named after their
+// types, e.g., "key" for an unnamed parameter of type Key.
+func spxEventHandlerCallbackParamName(param *gotypes.Var) string {
+	if name := param.Name(); name != "" && name != "_" {
+		return name
+	}
+	if typ, ok := xgoutil.DerefType(param.Type()).(interface{ Obj() *gotypes.TypeName }); ok {
+		return xgoutil.ToLowerCamelCase(typ.Obj().Name())
+	}
+	return "arg"
+}
+
+// spxParameterDocLineRE is the regular expression of an indented
+// "paramName: doc" line in a function doc comment.
+var spxParameterDocLineRE = regexp.MustCompile(`(?m)^[ \t]+(\w+):[ \t]*(\S.*?)[ \t]*$`)
+
+// appendSpxDefinitionExample appends the given example code to detail as a
+// fenced Go code block. It returns detail unchanged if example is "".
+func appendSpxDefinitionExample(detail, example string) string {
+	if example == "" {
+		return detail
+	}
+	var sb strings.Builder
+	if detail != "" {
+		sb.WriteString(strings.TrimRight(detail, "\n"))
+		sb.WriteString("\n\n")
+	}
+	sb.WriteString("Example:\n\n```go\n")
+	sb.WriteString(example)
+	sb.WriteString("\n```\n")
+	return sb.String()
+}
+
+// parseSpxParameterDocs parses the per-parameter documentation from the given
+// function doc. Only lines naming one of params are taken into account, and
+// the results follow the order of params.
+func parseSpxParameterDocs(doc string, params *gotypes.Tuple) []SpxParameterDoc {
+	if doc == "" || params == nil || params.Len() == 0 {
+		return nil
+	}
+	paramDocs := make(map[string]string)
+	for _, match := range spxParameterDocLineRE.FindAllStringSubmatch(doc, -1) {
+		if _, ok := paramDocs[match[1]]; !ok {
+			paramDocs[match[1]] = match[2]
+		}
+	}
+	if len(paramDocs) == 0 {
+		return nil
+	}
+
+	var docs []SpxParameterDoc
+	for param := range params.Variables() {
+		name := xgoutil.SourceParamName(param)
+		paramDoc, ok := paramDocs[name]
+		if !ok {
+			continue
+		}
+		docs = append(docs, SpxParameterDoc{
+			Name: name,
+			Type: GetSimplifiedTypeString(param.Type()),
+			Doc:  paramDoc,
+		})
+	}
+	return docs
+}
+
+// makeSpxFunctionSignature makes the [SpxFunctionSignature] of a function that
+// is used in [SpxDefinition].
+func makeSpxFunctionSignature(fun *gotypes.Func) *SpxFunctionSignature {
+	sig := fun.Signature()
+	_, _, _, isXGotMethod := displayedFuncName(fun)
+
+	params := make([]SpxParam, 0, sig.Params().Len())
+	for i := range sig.Params().Len() {
+		if isXGotMethod && i == 0 {
+			continue
+		}
+		param := sig.Params().At(i)
+		paramType := param.Type()
+		isVariadic := sig.Variadic() && i == sig.Params().Len()-1
+		if slice, ok := paramType.(*gotypes.Slice); ok && isVariadic {
+			paramType = slice.Elem()
+		}
+		params = append(params, SpxParam{
+			Name:       xgoutil.SourceParamName(param),
+			TypeString: GetSimplifiedTypeString(paramType),
+			IsVariadic: isVariadic,
+		})
+	}
+
+	results := make([]SpxParam, 0, sig.Results().Len())
+	for result := range sig.Results().Variables() {
+		results = append(results, SpxParam{
+			Name:       result.Name(),
+			TypeString: GetSimplifiedTypeString(result.Type()),
+		})
+	}
+
+	return &SpxFunctionSignature{
+		Params:  params,
+		Results: results,
+	}
+}
+
 // displayedFuncName resolves the source-facing function display name used by
 // spx UI surfaces.
 func displayedFuncName(fun *gotypes.Func) (parsedRecvTypeName, parsedName string, overloadID *string, isXGotMethod bool) {
@@ -857,8 +1212,13 @@ func displayedFuncName(fun *gotypes.Func) (parsedRecvTypeName, parsedName string
 			parsedRecvTypeName = named.Obj().Name()
 		}
 	} else if isXGoPkg && strings.HasPrefix(name, xgoutil.XGotPrefix) {
-		recvTypeName, methodName, ok := xgoutil.SplitXGotMethodName(name, true)
+		// Keep the overload suffix so that ParseXGoFuncName can extract the
+		// overload ID below.
+		recvTypeName, methodName, ok := xgoutil.SplitXGotMethodName(name, false)
 		if ok {
+			if funcName, ok := xgoutil.SplitXGoxFuncName(methodName); ok {
+				methodName = funcName
+			}
 			parsedRecvTypeName = recvTypeName
 			name = methodName
 			isXGotMethod = true
@@ -1102,3 +1462,37 @@ func IsSpxPropertyNameType(typ gotypes.Type) bool {
 	}
 	return false
 }
+
+// spxConstNamesOfType returns the names of all exported constants of the
+// given type in the spx package, in alphabetical order.
+func spxConstNamesOfType(typ gotypes.Type) []string {
+	scope := GetSpxPkg().Scope()
+	var names []string
+	for _, name := range scope.Names() {
+		cnst, ok := scope.Lookup(name).(*gotypes.Const)
+		if !ok || !cnst.Exported() || !gotypes.Identical(cnst.Type(), typ) {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// GetSpxEnumValues returns the names of all constants of the given spx enum
+// type, such as [spx.RotationStyle]. It returns nil if typ is not an spx enum
+// type.
+func GetSpxEnumValues(typ gotypes.Type) []string {
+	switch typ {
+	case GetSpxLayerActionType():
+		return GetSpxLayerActionValues()
+	case GetSpxDirActionType():
+		return GetSpxDirActionValues()
+	case GetSpxEffectKindType():
+		return GetSpxEffectKindValues()
+	case GetSpxKeyType():
+		return GetSpxKeyValues()
+	case GetSpxRotationStyleType():
+		return GetSpxRotationStyleValues()
+	}
+	return nil
+}
@@ -1,11 +1,14 @@
 package server
 
 import (
+	"encoding/json"
 	gotypes "go/types"
+	"slices"
 	"sync"
 	"testing"
 
 	"github.com/goplus/xgo/token"
+	"github.com/goplus/xgolsw/pkgdoc"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -295,3 +298,229 @@ func TestCanonicalSpxResourceNameType(t *testing.T) {
 		})
 	}
 }
+
+func TestGetAllSpxDefinitions(t *testing.T) {
+	defs := GetAllSpxDefinitions()
+	require.NotEmpty(t, defs)
+
+	hasDef := func(pkg, name string) bool {
+		return slices.ContainsFunc(defs, func(def SpxDefinition) bool {
+			return def.ID.Package != nil && *def.ID.Package == pkg &&
+				def.ID.Name != nil && *def.ID.Name == name
+		})
+	}
+	// The bundled spx package exposes its game entry point as
+	// XGot_Game_Main, so there is no Game.run to look for.
+	assert.True(t, hasDef(SpxPkgPath, "Game.main"))
+	assert.True(t, hasDef(SpxPkgPath, "Game.play"))
+	assert.True(t, hasDef(SpxPkgPath, "Sprite.move"))
+	assert.True(t, hasDef("builtin", "println"))
+}
+
+func TestSpxDefinitionJSON(t *testing.T) {
+	def := SpxDefinition{
+		TypeHint: gotypes.Typ[gotypes.Int],
+		ID: SpxDefinitionIdentifier{
+			Package:    ToPtr(SpxPkgPath),
+			Name:       ToPtr("Sprite.step"),
+			OverloadID: ToPtr("0"),
+		},
+		Overview: "step(step float64)",
+		Detail:   "Step moves the sprite forward.\n\nDeprecated: use move instead.",
+
+		Deprecated: "use move instead.",
+
+		Signature: &SpxFunctionSignature{
+			Params:  []SpxParam{{Name: "step", TypeString: "float64"}},
+			Results: []SpxParam{},
+		},
+
+		CompletionItemLabel:            "step",
+		CompletionItemKind:             FunctionCompletion,
+		CompletionItemInsertText:       "step ${1:step}",
+		CompletionItemInsertTextFormat: SnippetTextFormat,
+	}
+
+	data, err := json.Marshal(def)
+	require.NoError(t, err)
+
+	var raw map[string]any
+	require.NoError(t, json.Unmarshal(data, &raw))
+	assert.Equal(t, map[string]any{
+		"package":    SpxPkgPath,
+		"name":       "Sprite.step",
+		"overloadId": "0",
+	}, raw["id"])
+	assert.Equal(t, "step(step float64)", raw["overview"])
+	assert.Equal(t, "step", raw["completionItemLabel"])
+	assert.Equal(t, "use move instead.", raw["deprecated"])
+	assert.Equal(t, map[string]any{
+		"params":  []any{map[string]any{"name": "step", "typeString": "float64"}},
+		"results": []any{},
+	}, raw["signature"])
+	assert.NotContains(t, raw, "typeHint")
+	assert.NotContains(t, raw, "TypeHint")
+
+	var got SpxDefinition
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Nil(t, got.TypeHint)
+	def.TypeHint = nil
+	assert.Equal(t, def, got)
+}
+
+func TestSpxDefinitionSignature(t *testing.T) {
+	t.Run("SpxMethod", func(t *testing.T) {
+		defs := GetAllSpxDefinitions()
+		idx := slices.IndexFunc(defs, func(def SpxDefinition) bool {
+			return def.ID.Package != nil && *def.ID.Package == SpxPkgPath &&
+				def.ID.Name != nil && *def.ID.Name == "Sprite.changeXYpos"
+		})
+		require.NotEqual(t, -1, idx)
+		assert.Equal(t, &SpxFunctionSignature{
+			Params: []SpxParam{
+				{Name: "dx", TypeString: "float64"},
+				{Name: "dy", TypeString: "float64"},
+			},
+			Results: []SpxParam{},
+		}, defs[idx].Signature)
+	})
+
+	t.Run("VariadicFuncWithResults", func(t *testing.T) {
+		pkg := gotypes.NewPackage("example.com/foo", "foo")
+		sig := gotypes.NewSignatureType(
+			nil, nil, nil,
+			gotypes.NewTuple(
+				gotypes.NewParam(token.NoPos, pkg, "format", gotypes.Typ[gotypes.String]),
+				gotypes.NewParam(token.NoPos, pkg, "args", gotypes.NewSlice(gotypes.Universe.Lookup("any").Type())),
+			),
+			gotypes.NewTuple(
+				gotypes.NewParam(token.NoPos, pkg, "n", gotypes.Typ[gotypes.Int]),
+				gotypes.NewParam(token.NoPos, pkg, "", gotypes.Universe.Lookup("error").Type()),
+			),
+			true,
+		)
+		fun := gotypes.NewFunc(token.NoPos, pkg, "Printf", sig)
+		assert.Equal(t, &SpxFunctionSignature{
+			Params: []SpxParam{
+				{Name: "format", TypeString: "string"},
+				{Name: "args", TypeString: "any", IsVariadic: true},
+			},
+			Results: []SpxParam{
+				{Name: "n", TypeString: "int"},
+				{Name: "", TypeString: "error"},
+			},
+		}, GetSpxDefinitionForFunc(fun, "", nil).Signature)
+	})
+}
+
+func TestSpxDefinitionExample(t *testing.T) {
+	pkg := gotypes.NewPackage("example.com/foo", "foo")
+	pkgDoc := &pkgdoc.PkgDoc{
+		Path:  "example.com/foo",
+		Name:  "foo",
+		Funcs: map[string]string{"Println": "Println prints its arguments.\n", "Print": "Print prints.\n"},
+		Types: map[string]*pkgdoc.TypeDoc{
+			"Builder": {Doc: "Builder builds strings.\n", Examples: map[string]string{"": "var b foo.Builder"}},
+		},
+		Examples: map[string]string{"Println": `foo.Println("hi")`},
+	}
+
+	t.Run("Func", func(t *testing.T) {
+		fun := gotypes.NewFunc(token.NoPos, pkg, "Println", gotypes.NewSignatureType(nil, nil, nil, nil, nil, false))
+		def := GetSpxDefinitionForFunc(fun, "", pkgDoc)
+		assert.Equal(t, "Println prints its arguments.\n\nExample:\n\n```go\nfoo.Println(\"hi\")\n```\n", def.Detail)
+	})
+
+	t.Run("FuncWithoutExample", func(t *testing.T) {
+		fun := gotypes.NewFunc(token.NoPos, pkg, "Print", gotypes.NewSignatureType(nil, nil, nil, nil, nil, false))
+		def := GetSpxDefinitionForFunc(fun, "", pkgDoc)
+		assert.Equal(t, "Print prints.\n", def.Detail)
+	})
+
+	t.Run("Type", func(t *testing.T) {
+		typeName := gotypes.NewTypeName(token.NoPos, pkg, "Builder", nil)
+		gotypes.NewNamed(typeName, gotypes.NewStruct(nil, nil), nil)
+		def := GetSpxDefinitionForType(typeName, pkgDoc)
+		assert.Equal(t, "Builder builds strings.\n\nExample:\n\n```go\nvar b foo.Builder\n```\n", def.Detail)
+	})
+}
+
+func TestParseSpxParameterDocs(t *testing.T) {
+	params := gotypes.NewTuple(
+		gotypes.NewParam(token.NoPos, nil, "dx", gotypes.Typ[gotypes.Float64]),
+		gotypes.NewParam(token.NoPos, nil, "dy", gotypes.Typ[gotypes.Float64]),
+		gotypes.NewParam(token.NoPos, nil, "speed", gotypes.Typ[gotypes.Int]),
+	)
+
+	t.Run("Documented", func(t *testing.T) {
+		doc := "Move moves the sprite by the given offsets.\n\n  dy: the vertical offset\n  dx: the horizontal offset\n  other: not a parameter\n"
+		assert.Equal(t, []SpxParameterDoc{
+			{Name: "dx", Type: "float64", Doc: "the horizontal offset"},
+			{Name: "dy", Type: "float64", Doc: "the vertical offset"},
+		}, parseSpxParameterDocs(doc, params))
+	})
+
+	t.Run("UnindentedLinesIgnored", func(t *testing.T) {
+		doc := "dx: not an indented parameter doc\n"
+		assert.Nil(t, parseSpxParameterDocs(doc, params))
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		assert.Nil(t, parseSpxParameterDocs("", params))
+		assert.Nil(t, parseSpxParameterDocs("  dx: offset\n", gotypes.NewTuple()))
+	})
+}
+
+func TestGetSpxEnumValues(t *testing.T) {
+	t.Run("RotationStyle", func(t *testing.T) {
+		values := GetSpxRotationStyleValues()
+		assert.Contains(t, values, "None")
+		assert.Contains(t, values, "Normal")
+		assert.Contains(t, values, "LeftRight")
+		assert.Equal(t, values, GetSpxEnumValues(GetSpxRotationStyleType()))
+	})
+
+	t.Run("OtherEnumTypes", func(t *testing.T) {
+		for _, tt := range []struct {
+			name string
+			typ  gotypes.Type
+			want string
+		}{
+			{"LayerAction", GetSpxLayerActionType(), "Front"},
+			{"DirAction", GetSpxDirActionType(), "Forward"},
+			{"EffectKind", GetSpxEffectKindType(), "ColorEffect"},
+			{"Key", GetSpxKeyType(), "KeyA"},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				assert.Contains(t, GetSpxEnumValues(tt.typ), tt.want)
+			})
+		}
+	})
+
+	t.Run("NonEnumType", func(t *testing.T) {
+		assert.Nil(t, GetSpxEnumValues(gotypes.Typ[gotypes.Int]))
+	})
+
+	t.Run("PredefinedNames", func(t *testing.T) {
+		m := map[string][]byte{
+			"main.spx": []byte(`
+onStart => {
+	MySprite.setRotationStyle LeftRight
+}
+`),
+			"MySprite.spx":                       []byte(``),
+			"assets/index.json":                  []byte(`{}`),
+			"assets/sprites/MySprite/index.json": []byte(`{}`),
+		}
+		s := New(newProjectWithoutModTime(m), nil, fileMapGetter(m), &MockScheduler{}, nil)
+
+		result, err := s.compileAndGetASTFileForDocumentURI(t.Context(), "file:///main.spx")
+		require.NoError(t, err)
+		require.False(t, result.hasErrorSeverityDiagnostic)
+
+		slot := findInputSlot(findInputSlots(result.compileResult, result.astFile), "LeftRight", "", SpxInputTypeRotationStyle, SpxInputKindInPlace)
+		require.NotNil(t, slot)
+		assert.Contains(t, predefinedNameStrings(slot.PredefinedNames), "None")
+		assert.Contains(t, predefinedNameStrings(slot.PredefinedNames), "Normal")
+	})
+}
@@ -171,8 +171,9 @@ func NewSpxResourceSet(proj *xgo.Project) (*SpxResourceSet, error) {
 		sprite.Animations = make([]SpxSpriteAnimationResource, 0, len(sprite.FAnimations))
 		for animName, fAnim := range sprite.FAnimations {
 			animation := SpxSpriteAnimationResource{
-				ID:   SpxSpriteAnimationResourceID{SpriteName: spriteName, AnimationName: animName},
-				Name: animName,
+				ID:        SpxSpriteAnimationResourceID{SpriteName: spriteName, AnimationName: animName},
+				Name:      animName,
+				FrameRate: fAnim.FrameFps,
 			}
 			if fromIdx, ok := costumeIndexes[fAnim.FrameFrom]; ok {
 				animation.FromIndex = &fromIdx
@@ -324,8 +325,9 @@ func (id SpxSoundResourceID) ContextURI() SpxResourceContextURI {
 }
 
 type spxSpriteFAnimation struct {
-	FrameFrom string `json:"frameFrom"`
-	FrameTo   string `json:"frameTo"`
+	FrameFrom string  `json:"frameFrom"`
+	FrameTo   string  `json:"frameTo"`
+	FrameFps  float64 `json:"frameFps"`
 }
 
 // SpxSpriteResource represents an spx sprite resource.
@@ -426,6 +428,7 @@ type SpxSpriteAnimationResource struct {
 	Name      string                       `json:"name"`
 	FromIndex *int                         `json:"-"`
 	ToIndex   *int                         `json:"-"`
+	FrameRate float64                      `json:"-"`
 }
 
 // SpxSpriteAnimationResourceID is the ID of an spx sprite animation resource.
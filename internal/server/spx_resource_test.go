@@ -3,6 +3,7 @@ package server
 import (
 	"testing"
 
+	"github.com/goplus/xgolsw/xgo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -373,3 +374,54 @@ func TestParseSpxResourceURI(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestGetSpxResourceSet(t *testing.T) {
+	newServer := func(feats uint) (*Server, map[string][]byte) {
+		m := map[string][]byte{
+			"main.spx":                           []byte(`var MySprite Sprite`),
+			"MySprite.spx":                       []byte(``),
+			"assets/index.json":                  []byte(`{}`),
+			"assets/sprites/MySprite/index.json": []byte(`{}`),
+		}
+		files := make(map[string]*xgo.File, len(m))
+		for k, v := range m {
+			files[k] = &xgo.File{Content: v}
+		}
+		return New(xgo.NewProject(nil, files, feats), nil, fileMapGetter(m), &MockScheduler{}, nil), m
+	}
+	compile := func(t *testing.T, s *Server) *SpxResourceSet {
+		_, err := s.compileAndGetASTFileForDocumentURI(t.Context(), "file:///main.spx")
+		require.NoError(t, err)
+		spxResourceSet, err := getSpxResourceSet(s.getProj())
+		require.NoError(t, err)
+		return spxResourceSet
+	}
+
+	t.Run("Cached", func(t *testing.T) {
+		s, m := newServer(xgo.FeatAll)
+
+		spxResourceSet := compile(t, s)
+		require.NotNil(t, spxResourceSet.Sprite("MySprite"))
+		assert.Same(t, spxResourceSet, compile(t, s))
+
+		m["main.spx"] = []byte(`var MySprite Sprite; echo MySprite`)
+		assert.Same(t, spxResourceSet, compile(t, s))
+	})
+
+	t.Run("AssetFileChanged", func(t *testing.T) {
+		s, m := newServer(xgo.FeatAll)
+
+		spxResourceSet := compile(t, s)
+		m["assets/sprites/OtherSprite/index.json"] = []byte(`{}`)
+		newSpxResourceSet := compile(t, s)
+		assert.NotSame(t, spxResourceSet, newSpxResourceSet)
+		assert.NotNil(t, newSpxResourceSet.Sprite("OtherSprite"))
+		assert.Same(t, newSpxResourceSet, compile(t, s))
+	})
+
+	t.Run("FeatureDisabled", func(t *testing.T) {
+		s, _ := newServer(xgo.FeatAll &^ xgo.FeatSpxResourceCache)
+
+		assert.NotSame(t, compile(t, s), compile(t, s))
+	})
+}
@@ -7,7 +7,6 @@ import (
 
 	"github.com/goplus/gogen"
 	"github.com/goplus/xgo/scanner"
-	"github.com/goplus/xgo/x/typesutil"
 	"github.com/goplus/xgolsw/jsonrpc2"
 	"github.com/goplus/xgolsw/protocol"
 	"github.com/goplus/xgolsw/xgo"
@@ -86,38 +85,57 @@ func (s *Server) didClose(params *DidCloseTextDocumentParams) error {
 }
 
 // didModifyFile is a shared implementation for handling document modifications.
-// It updates the project with file changes and asynchronously publishes diagnostics.
+// It updates the project with file changes and schedules diagnostics for them.
 // The function:
 //  1. Updates the project's files with the provided changes
-//  2. Starts a goroutine to generate and publish diagnostics for each changed file
+//  2. Schedules the changed files with the diagnostic debouncer, which
+//     publishes their diagnostics in the background once changes settle
 //  3. Returns immediately after updating files for better responsiveness
 func (s *Server) didModifyFile(changes []FileChange) error {
 	// 1. Update files synchronously
 	s.ModifyFiles(changes)
 
-	// 2. Asynchronously generate and publish diagnostics
-	// This allows for quick response while diagnostics computation happens in background
-	go func() {
-		for _, change := range changes {
-			// Convert path to URI for diagnostics
-			uri := s.toDocumentURI(change.Path)
-
-			// Get diagnostics from AST and type checking
-			diagnostics, err := s.getDiagnostics(change.Path)
-			if err != nil {
-				// Log error but continue processing other files
-				continue
-			}
+	// 2. Schedule diagnostics, coalescing rapid successive changes into a
+	// single run
+	paths := make([]string, 0, len(changes))
+	for _, change := range changes {
+		paths = append(paths, change.Path)
+	}
+	s.diagnosticDebouncer.Schedule(paths...)
 
-			// Publish diagnostics
-			if err := s.publishDiagnostics(uri, diagnostics); err != nil {
-				// Log error but continue
-				continue
-			}
+	return nil
+}
+
+// publishFileDiagnostics generates and publishes diagnostics for each file at
+// the given paths.
+func (s *Server) publishFileDiagnostics(paths []string) {
+	for _, path := range paths {
+		// Convert path to URI for diagnostics
+		uri := s.toDocumentURI(path)
+
+		// Get diagnostics from AST and type checking
+		diagnostics, err := s.getDiagnostics(path)
+		if err != nil {
+			// Log error but continue processing other files
+			continue
 		}
-	}()
 
-	return nil
+		// Publish diagnostics
+		if err := s.publishDiagnostics(uri, diagnostics); err != nil {
+			// Log error but continue
+			continue
+		}
+	}
+}
+
+// SetDiagnosticDebounceDelay sets how long file changes must settle before
+// diagnostics are published for them. Zero means
+// [DefaultDiagnosticDebounceDelay], and a negative value means no delay.
+func (s *Server) SetDiagnosticDebounceDelay(delay time.Duration) {
+	if delay == 0 {
+		delay = DefaultDiagnosticDebounceDelay
+	}
+	s.diagnosticDebouncer.SetDelay(delay)
 }
 
 // changedText processes document content changes from the client.
@@ -237,31 +255,25 @@ func (s *Server) getDiagnostics(path string) ([]Diagnostic, error) {
 
 	astFilePos := proj.Fset.Position(astFile.Pos())
 
-	handleErr := func(err error) {
-		if typeErr, ok := err.(typesutil.Error); ok {
-			position := typeErr.Fset.Position(typeErr.Pos)
-			if position.Filename == astFilePos.Filename {
-				diagnostics = append(diagnostics, Diagnostic{
-					Severity: SeverityError,
-					Range:    RangeForPosEnd(proj, typeErr.Pos, typeErr.End),
-					Message:  typeErr.Msg,
-				})
-			}
-		}
-	}
-
 	// 2. Get type checking diagnostics
-	// Perform type checking on the file
-	_, err = proj.TypeInfo()
+	// Collect the cached type checking diagnostics of the project that are
+	// in the file
+	if typeInfo, _ := proj.TypeInfo(); typeInfo == nil {
+		// The project could not be type checked, e.g., because its AST
+		// package could not be built, so there are no type errors to report.
+		return diagnostics, nil
+	}
+	typeDiags, err := proj.Diagnostics()
 	if err != nil {
-		// Add type checking errors to diagnostics
-		switch err := err.(type) {
-		case errors.List:
-			for _, e := range err {
-				handleErr(e)
-			}
-		default:
-			handleErr(err)
+		return nil, fmt.Errorf("failed to get type checking diagnostics: %w", err)
+	}
+	for _, typeDiag := range typeDiags {
+		if proj.Fset.Position(typeDiag.Pos).Filename == astFilePos.Filename {
+			diagnostics = append(diagnostics, Diagnostic{
+				Severity: SeverityError,
+				Range:    RangeForPosEnd(proj, typeDiag.Pos, typeDiag.End),
+				Message:  typeDiag.Msg,
+			})
 		}
 	}
 
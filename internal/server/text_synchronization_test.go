@@ -230,15 +230,17 @@ func TestDidOpen(t *testing.T) {
 	} {
 		t.Run(tt.name, func(t *testing.T) {
 			// Setup test environment with real Project instead of MockProject
-			proj := xgo.NewProject(token.NewFileSet(), make(map[string]*xgo.File), 0)
-			proj.PutFile(tt.wantPath, file("mock content"))
+			proj := xgo.NewProject(token.NewFileSet(), map[string]*xgo.File{
+				tt.wantPath: file("mock content"),
+			}, 0)
 			mockReplier := &MockReplier{}
 
 			// Create a TestServer that extends the real Server
 			server := &Server{
-				workspaceRootFS:  proj,
-				replier:          mockReplier,
-				workspaceRootURI: "file://workspace/",
+				workspaceRootFS:     proj,
+				replier:             mockReplier,
+				workspaceRootURI:    "file://workspace/",
+				diagnosticDebouncer: NewDiagnosticDebouncer(0, func([]string) {}),
 			}
 
 			// Execute test
@@ -389,9 +391,10 @@ func TestDidChange(t *testing.T) {
 
 			// Create a TestServer that extends the real Server
 			server := &Server{
-				workspaceRootFS:  proj,
-				replier:          mockReplier,
-				workspaceRootURI: "file://workspace/",
+				workspaceRootFS:     proj,
+				replier:             mockReplier,
+				workspaceRootURI:    "file://workspace/",
+				diagnosticDebouncer: NewDiagnosticDebouncer(0, func([]string) {}),
 			}
 
 			// Execute test
@@ -480,9 +483,10 @@ func TestDidSave(t *testing.T) {
 
 			// Create a TestServer
 			server := &Server{
-				workspaceRootFS:  proj,
-				replier:          mockReplier,
-				workspaceRootURI: "file://workspace/",
+				workspaceRootFS:     proj,
+				replier:             mockReplier,
+				workspaceRootURI:    "file://workspace/",
+				diagnosticDebouncer: NewDiagnosticDebouncer(0, func([]string) {}),
 			}
 
 			// Execute test
@@ -918,4 +922,41 @@ func TestGetDiagnostics(t *testing.T) {
 			}
 		})
 	}
+
+	t.Run("TypeErrorInOtherFile", func(t *testing.T) {
+		files := map[string]*xgo.File{
+			"/a.xgo": {Content: []byte("package main\n\nfunc a() {\n\tvar x int = \"string\"\n\t_ = x\n}")},
+			"/b.xgo": {Content: []byte("package main\n\nfunc main() {\n\ta()\n}")},
+		}
+		proj := xgo.NewProject(token.NewFileSet(), files, xgo.FeatAll)
+		server := &Server{workspaceRootFS: proj}
+
+		typeDiags, err := proj.Diagnostics()
+		require.NoError(t, err)
+		require.Len(t, typeDiags, 1)
+
+		diagnostics, err := server.getDiagnostics("/a.xgo")
+		require.NoError(t, err)
+		require.Len(t, diagnostics, 1)
+		assert.Equal(t, typeDiags[0].Msg, diagnostics[0].Message)
+		assert.Equal(t, RangeForPosEnd(proj, typeDiags[0].Pos, typeDiags[0].End), diagnostics[0].Range)
+
+		diagnostics, err = server.getDiagnostics("/b.xgo")
+		require.NoError(t, err)
+		assert.Empty(t, diagnostics)
+	})
+
+	t.Run("TypeErrorWithoutDiagnosticsCache", func(t *testing.T) {
+		files := map[string]*xgo.File{
+			"/a.xgo": {Content: []byte("package main\n\nfunc a() {\n\tvar x int = \"string\"\n\t_ = x\n}")},
+		}
+		proj := xgo.NewProject(token.NewFileSet(), files, xgo.FeatAll&^xgo.FeatDiagnosticsCache)
+		server := &Server{workspaceRootFS: proj}
+
+		diagnostics, err := server.getDiagnostics("/a.xgo")
+		require.NoError(t, err)
+		require.Len(t, diagnostics, 1)
+		assert.Equal(t, SeverityError, diagnostics[0].Severity)
+		assert.Contains(t, diagnostics[0].Message, `cannot use "string"`)
+	})
 }
@@ -37,7 +37,7 @@ func newXGoUnitTestServer(source string) *Server {
 		"assets/index.json": []byte(`{}`),
 	}
 	proj := newProjectWithoutModTime(m)
-	s := New(proj, nil, fileMapGetter(m), &MockScheduler{})
+	s := New(proj, nil, fileMapGetter(m), &MockScheduler{}, nil)
 	s.workspaceRootFS.Importer = xgoUnitTestImporter{fallback: s.workspaceRootFS.Importer}
 	return s
 }
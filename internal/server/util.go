@@ -2,6 +2,8 @@ package server
 
 import (
 	"bytes"
+	"net/url"
+	"strings"
 	"unicode/utf16"
 	"unicode/utf8"
 
@@ -39,6 +41,28 @@ func DedupeLocations(locations []Location) []Location {
 	return result
 }
 
+// NormalizeURI returns the canonical form of the given file URI, so that
+// URIs sent by different clients for the same file compare equal. It decodes
+// percent-encoded characters, converts backslashes to slashes, and lowercases
+// Windows drive letters, e.g., `file:///C%3A/a%20b.spx` becomes
+// `file:///c:/a b.spx`. URIs of other schemes are returned unchanged.
+func NormalizeURI(uri DocumentURI) DocumentURI {
+	path, ok := strings.CutPrefix(string(uri), "file://")
+	if !ok {
+		return uri
+	}
+	if unescaped, err := url.PathUnescape(path); err == nil {
+		path = unescaped
+	}
+	path = strings.ReplaceAll(path, `\`, "/")
+	if len(path) >= 3 && path[0] == '/' && path[2] == ':' {
+		if drive := path[1]; 'A' <= drive && drive <= 'Z' {
+			path = "/" + string(drive+'a'-'A') + path[2:]
+		}
+	}
+	return DocumentURI("file://" + path)
+}
+
 // UTF16Len calculates the UTF-16 length of the given string.
 func UTF16Len(s string) int {
 	var length int
@@ -148,6 +172,32 @@ func FromPosition(proj *xgo.Project, astFile *ast.File, position token.Position)
 	}
 }
 
+// LSPPosition converts position, which must be in a file with the given
+// content, to a [Position] with a 0-based line and a UTF-16 character offset.
+func LSPPosition(content []byte, position token.Position) Position {
+	offset := min(max(position.Offset, 0), len(content))
+	lineStart := max(offset-max(position.Column-1, 0), 0)
+	lineContent := content[lineStart:offset]
+	if len(lineContent) > 0 && lineContent[len(lineContent)-1] == '\n' {
+		// The position is at the end of a file ending with a newline.
+		lineContent = lineContent[:len(lineContent)-1]
+	}
+	return Position{
+		Line:      uint32(max(position.Line-1, 0)),
+		Character: uint32(UTF16Len(string(lineContent))),
+	}
+}
+
+// LSPRange returns the [Range] of node, which must be in a file with the given
+// content, with 0-based lines and UTF-16 character offsets.
+func LSPRange(fset *token.FileSet, content []byte, node ast.Node) Range {
+	start, end := xgoutil.TokenRange(fset, node)
+	return Range{
+		Start: LSPPosition(content, start),
+		End:   LSPPosition(content, end),
+	}
+}
+
 // ToPosition converts a [Position] to a [token.Position].
 func ToPosition(proj *xgo.Project, astFile *ast.File, position Position) token.Position {
 	tokenFile := xgoutil.NodeTokenFile(proj.Fset, astFile)
@@ -188,11 +238,7 @@ func RangeForASTFilePosition(proj *xgo.Project, astFile *ast.File, position toke
 
 // RangeForASTFileNode returns the [Range] for the given node in the given AST file.
 func RangeForASTFileNode(proj *xgo.Project, astFile *ast.File, node ast.Node) Range {
-	fset := proj.Fset
-	return Range{
-		Start: FromPosition(proj, astFile, fset.Position(node.Pos())),
-		End:   FromPosition(proj, astFile, fset.Position(node.End())),
-	}
+	return LSPRange(proj.Fset, astFile.Code, node)
 }
 
 // RangeForPos returns the [Range] for the given position.
@@ -3,12 +3,67 @@ package server
 import (
 	"testing"
 
+	"github.com/goplus/xgo/ast"
+	"github.com/goplus/xgo/parser"
 	"github.com/goplus/xgo/token"
 	"github.com/goplus/xgolsw/xgo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+func TestNormalizeURI(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		uri  DocumentURI
+		want DocumentURI
+	}{
+		{
+			name: "UppercaseDriveLetter",
+			uri:  "file:///C:/Users/game/main.spx",
+			want: "file:///c:/Users/game/main.spx",
+		},
+		{
+			name: "LowercaseDriveLetter",
+			uri:  "file:///c:/Users/game/main.spx",
+			want: "file:///c:/Users/game/main.spx",
+		},
+		{
+			name: "EncodedDriveColon",
+			uri:  "file:///C%3A/Users/game/main.spx",
+			want: "file:///c:/Users/game/main.spx",
+		},
+		{
+			name: "Backslashes",
+			uri:  `file:///C:\Users\game\main.spx`,
+			want: "file:///c:/Users/game/main.spx",
+		},
+		{
+			name: "EncodedSpaces",
+			uri:  "file:///path%20with%20space/a.spx",
+			want: "file:///path with space/a.spx",
+		},
+		{
+			name: "InvalidEscape",
+			uri:  "file:///100%.spx",
+			want: "file:///100%.spx",
+		},
+		{
+			name: "Unchanged",
+			uri:  "file:///main.spx",
+			want: "file:///main.spx",
+		},
+		{
+			name: "NonFileScheme",
+			uri:  "spx://resources/sprites/My%20Sprite",
+			want: "spx://resources/sprites/My%20Sprite",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NormalizeURI(tt.uri))
+		})
+	}
+}
+
 func TestUTF16Len(t *testing.T) {
 	for _, tt := range []struct {
 		name string
@@ -512,6 +567,116 @@ func TestFromPosition(t *testing.T) {
 	}
 }
 
+func TestRangeForNode(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		code string
+		want Range
+	}{
+		{
+			name: "ASCII",
+			code: "var x = 1\n\nvar yy = 2",
+			want: Range{
+				Start: Position{Line: 2, Character: 4},
+				End:   Position{Line: 2, Character: 6},
+			},
+		},
+		{
+			name: "UTF16",
+			code: "var x = 1\n\nvar 𝑦𝑦 = 2",
+			want: Range{
+				Start: Position{Line: 2, Character: 4},
+				End:   Position{Line: 2, Character: 8}, // Each "𝑦" is 2 UTF-16 units.
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			fset := token.NewFileSet()
+			files := map[string]*xgo.File{
+				"test.gop": {Content: []byte(tt.code)},
+			}
+			proj := xgo.NewProject(fset, files, xgo.FeatAll)
+
+			astPkg, err := proj.ASTPackage()
+			require.NoError(t, err)
+			astFile, ok := astPkg.Files["test.gop"]
+			require.True(t, ok)
+
+			genDecl, ok := astFile.Decls[1].(*ast.GenDecl)
+			require.True(t, ok)
+			valueSpec, ok := genDecl.Specs[0].(*ast.ValueSpec)
+			require.True(t, ok)
+
+			got := RangeForNode(proj, valueSpec.Names[0])
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLSPRange(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		code string
+		want Range
+	}{
+		{
+			name: "ASCII",
+			code: "var x = 1\n\nvar yy = 2",
+			want: Range{
+				Start: Position{Line: 2, Character: 4},
+				End:   Position{Line: 2, Character: 6},
+			},
+		},
+		{
+			name: "UTF16",
+			code: "var x = 1\n\nvar 𝑦𝑦 = 2",
+			want: Range{
+				Start: Position{Line: 2, Character: 4},
+				End:   Position{Line: 2, Character: 8}, // Each "𝑦" is 2 UTF-16 units.
+			},
+		},
+		{
+			name: "UTF16BeforeNode",
+			code: "var x = 1\n\nvar 𝑦, yy = 2, 3",
+			want: Range{
+				Start: Position{Line: 2, Character: 8}, // "𝑦" is 2 UTF-16 units but 4 bytes.
+				End:   Position{Line: 2, Character: 10},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			fset := token.NewFileSet()
+			astFile, err := parser.ParseFile(fset, "test.xgo", tt.code, 0)
+			require.NoError(t, err)
+
+			genDecl, ok := astFile.Decls[1].(*ast.GenDecl)
+			require.True(t, ok)
+			valueSpec, ok := genDecl.Specs[0].(*ast.ValueSpec)
+			require.True(t, ok)
+			ident := valueSpec.Names[len(valueSpec.Names)-1]
+
+			// The identifier starts at line 3, column 5 (or 9 if preceded
+			// by "𝑦, ").
+			start := fset.Position(ident.Pos())
+			require.Equal(t, 3, start.Line)
+
+			got := LSPRange(fset, []byte(tt.code), ident)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("EndOfFileAfterNewline", func(t *testing.T) {
+		code := "var x = 1\n"
+		fset := token.NewFileSet()
+		astFile, err := parser.ParseFile(fset, "test.xgo", code, 0)
+		require.NoError(t, err)
+		tokenFile := fset.File(astFile.Pos())
+		eof := fset.Position(token.Pos(tokenFile.Base() + tokenFile.Size()))
+
+		assert.Equal(t, Position{Line: 0, Character: 9}, LSPPosition([]byte(code), eof))
+	})
+}
+
 func TestIsRangesOverlap(t *testing.T) {
 	for _, tt := range []struct {
 		name string
@@ -9,7 +9,9 @@ import (
 	"syscall/js"
 	"time"
 
+	"github.com/goplus/xgolsw/internal/buildinfo"
 	"github.com/goplus/xgolsw/internal/pkgdata"
+	"github.com/goplus/xgolsw/internal/scheduler"
 	"github.com/goplus/xgolsw/internal/server"
 	"github.com/goplus/xgolsw/jsonrpc2"
 	"github.com/goplus/xgolsw/xgo"
@@ -22,6 +24,9 @@ type Spxls struct {
 	server         *server.Server
 }
 
+// latestSpxls is the most recently created [Spxls] instance.
+var latestSpxls *Spxls
+
 // NewSpxls creates a new instance of [Spxls].
 func NewSpxls(this js.Value, args []js.Value) any {
 	if len(args) != 2 {
@@ -38,17 +43,51 @@ func NewSpxls(this js.Value, args []js.Value) any {
 		messageReplier: args[1],
 	}
 
-	fileMapGetter := func() map[string]*xgo.File {
-		files := filesProvider.Invoke()
-		return ConvertJSFilesToMap(files)
-	}
-	scheduler := &JSScheduler{}
-	s.server = server.New(xgo.NewProject(nil, fileMapGetter(), xgo.FeatAll), s, fileMapGetter, scheduler)
+	initialFiles, initialErrs := ConvertJSFilesToMapWithErrors(filesProvider.Invoke())
+	s.server = server.New(xgo.NewProject(nil, initialFiles, xgo.FeatAll), s, s.fileMapGetter(filesProvider), &JSScheduler{}, &server.ServerOptions{
+		DiagnosticDebounceDelay: diagnosticDebounceDelay,
+	})
+	s.server.SetProgressReporter(server.NewProgressNotifier(s))
+	s.logFileConversionErrors(initialErrs)
+	latestSpxls = s
 	return js.ValueOf(map[string]any{
 		"handleMessage": JSFuncOfWithError(s.HandleMessage),
 	})
 }
 
+// fileMapGetter returns a [server.FileMapGetter] that gets the files from the
+// given filesProvider.
+func (s *Spxls) fileMapGetter(filesProvider js.Value) server.FileMapGetter {
+	return func() map[string]*xgo.File {
+		files, errs := ConvertJSFilesToMapWithErrors(filesProvider.Invoke())
+		s.logFileConversionErrors(errs)
+		return files
+	}
+}
+
+// ReplaceProject replaces the project served by the most recently created
+// language server with a new one whose files are provided by the given
+// filesProvider.
+func ReplaceProject(this js.Value, args []js.Value) any {
+	if len(args) != 1 {
+		return errors.New("ReplaceProject: expected 1 argument")
+	}
+	if args[0].Type() != js.TypeFunction {
+		return errors.New("ReplaceProject: filesProvider argument must be a function")
+	}
+	if latestSpxls == nil {
+		return errors.New("ReplaceProject: no language server has been created")
+	}
+	s := latestSpxls
+	filesProvider := args[0]
+	initialFiles, initialErrs := ConvertJSFilesToMapWithErrors(filesProvider.Invoke())
+	if err := s.server.SetProject(xgo.NewProject(nil, initialFiles, xgo.FeatAll), s.fileMapGetter(filesProvider)); err != nil {
+		return fmt.Errorf("ReplaceProject: %w", err)
+	}
+	s.logFileConversionErrors(initialErrs)
+	return nil
+}
+
 // HandleMessage handles incoming LSP messages from the client.
 func (s *Spxls) HandleMessage(this js.Value, args []js.Value) any {
 	if len(args) != 1 {
@@ -68,6 +107,17 @@ func (s *Spxls) HandleMessage(this js.Value, args []js.Value) any {
 	return nil
 }
 
+// logFileConversionErrors reports errors returned by
+// [ConvertJSFilesToMapWithErrors] to the client as log messages.
+func (s *Spxls) logFileConversionErrors(errs []error) {
+	if s.server == nil {
+		return
+	}
+	for _, err := range errs {
+		s.server.LogMessage(server.MessageTypeWarning, fmt.Sprintf("failed to load file: %v", err))
+	}
+}
+
 // ReplyMessage sends a message back to the client via s.messageReplier.
 func (s *Spxls) ReplyMessage(m jsonrpc2.Message) (err error) {
 	rawMessage, err := json.Marshal(m)
@@ -91,7 +141,7 @@ func (s *Spxls) ReplyMessage(m jsonrpc2.Message) (err error) {
 	return nil
 }
 
-// JSScheduler implements [server.Scheduler]
+// JSScheduler implements [server.TimeoutScheduler]
 type JSScheduler struct{}
 
 // Sched yields the processor in browsers to allow JavaScript event loop to run.
@@ -106,6 +156,75 @@ func (s *JSScheduler) Sched() {
 	<-done
 }
 
+// schedulerPolicy is the policy used by [JSScheduler.SchedWithTimeout].
+var schedulerPolicy = scheduler.PolicyAuto
+
+// SchedWithTimeout yields the processor in browsers for at most about maxWait.
+// Depending on the scheduler policy, it uses either `requestAnimationFrame`,
+// which waits for the next frame, or `setTimeout` with maxWait.
+func (s *JSScheduler) SchedWithTimeout(maxWait time.Duration) {
+	done := make(chan bool, 1)
+	callback := js.FuncOf(func(this js.Value, p []js.Value) any {
+		done <- true
+		return nil
+	})
+	defer callback.Release()
+
+	requestAnimationFrame := js.Global().Get("requestAnimationFrame")
+	if schedulerPolicy.UseRequestAnimationFrame(maxWait, requestAnimationFrame.Type() == js.TypeFunction) {
+		requestAnimationFrame.Invoke(callback)
+	} else {
+		js.Global().Get("setTimeout").Invoke(callback, js.ValueOf(maxWait.Milliseconds()))
+	}
+	<-done
+}
+
+// SetSchedulerPolicy sets the policy used to yield the processor for bounded
+// durations. It accepts "setTimeout", "requestAnimationFrame", or "auto".
+func SetSchedulerPolicy(this js.Value, args []js.Value) any {
+	if len(args) != 1 {
+		return errors.New("SetSchedulerPolicy: expected 1 argument")
+	}
+	if args[0].Type() != js.TypeString {
+		return errors.New("SetSchedulerPolicy: argument must be a string")
+	}
+	policy, err := scheduler.ParsePolicy(args[0].String())
+	if err != nil {
+		return fmt.Errorf("SetSchedulerPolicy: %w", err)
+	}
+	schedulerPolicy = policy
+	return nil
+}
+
+// diagnosticDebounceDelay is the [server.ServerOptions.DiagnosticDebounceDelay]
+// of language servers created by [NewSpxls].
+var diagnosticDebounceDelay time.Duration
+
+// SetDiagnosticDebounceMs sets how many milliseconds file changes must settle
+// before diagnostics are published for them. Zero disables debouncing. It
+// applies to the most recently created language server and to those created
+// afterwards.
+func SetDiagnosticDebounceMs(this js.Value, args []js.Value) any {
+	if len(args) != 1 {
+		return errors.New("SetDiagnosticDebounceMs: expected 1 argument")
+	}
+	if args[0].Type() != js.TypeNumber {
+		return errors.New("SetDiagnosticDebounceMs: argument must be a number")
+	}
+	ms := args[0].Int()
+	if ms < 0 {
+		return fmt.Errorf("SetDiagnosticDebounceMs: invalid delay %d", ms)
+	}
+	diagnosticDebounceDelay = time.Duration(ms) * time.Millisecond
+	if ms == 0 {
+		diagnosticDebounceDelay = -1 // A negative delay means no debouncing.
+	}
+	if latestSpxls != nil {
+		latestSpxls.server.SetDiagnosticDebounceDelay(diagnosticDebounceDelay)
+	}
+	return nil
+}
+
 // SetCustomPkgdataZip sets custom package data that will be used with higher
 // priority than the embedded package data.
 func SetCustomPkgdataZip(this js.Value, args []js.Value) any {
@@ -146,10 +265,215 @@ func SetClassfileAutoImportedPackages(this js.Value, args []js.Value) any {
 		pkgs[key] = value.String()
 	}
 
-	xgo.SetClassfileAutoImportedPackages(id, pkgs)
+	if err := xgo.SetClassfileAutoImportedPackages(id, pkgs); err != nil {
+		return fmt.Errorf("SetClassfileAutoImportedPackages: %w", err)
+	}
+	return nil
+}
+
+// GetClassfileAutoImportedPackages gets the auto-imported packages for the
+// classfile specified by id. It returns null if no packages are set.
+func GetClassfileAutoImportedPackages(this js.Value, args []js.Value) any {
+	if len(args) != 1 {
+		return errors.New("GetClassfileAutoImportedPackages: expected 1 argument")
+	}
+	if args[0].Type() != js.TypeString {
+		return errors.New("GetClassfileAutoImportedPackages: argument must be a string")
+	}
+
+	pkgs, ok := xgo.GetClassfileAutoImportedPackages(args[0].String())
+	if !ok {
+		return js.Null()
+	}
+	result := js.Global().Get("Object").New()
+	for name, pkgPath := range pkgs {
+		result.Set(name, pkgPath)
+	}
+	return result
+}
+
+// RemoveClassfileAutoImportedPackages removes the auto-imported packages for
+// the classfile specified by id.
+func RemoveClassfileAutoImportedPackages(this js.Value, args []js.Value) any {
+	if len(args) != 1 {
+		return errors.New("RemoveClassfileAutoImportedPackages: expected 1 argument")
+	}
+	if args[0].Type() != js.TypeString {
+		return errors.New("RemoveClassfileAutoImportedPackages: argument must be a string")
+	}
+
+	xgo.RemoveClassfileAutoImportedPackages(args[0].String())
 	return nil
 }
 
+// ListClassfileAutoImportedPackageIDs lists the ids of the classfiles that have
+// auto-imported packages set.
+func ListClassfileAutoImportedPackageIDs(this js.Value, args []js.Value) any {
+	if len(args) != 0 {
+		return errors.New("ListClassfileAutoImportedPackageIDs: expected 0 arguments")
+	}
+
+	ids := xgo.ListClassfileAutoImportedPackageIDs()
+	result := make([]any, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, id)
+	}
+	return result
+}
+
+// ClearAllClassfileAutoImportedPackages removes the auto-imported packages for
+// all classfiles.
+func ClearAllClassfileAutoImportedPackages(this js.Value, args []js.Value) any {
+	if len(args) != 0 {
+		return errors.New("ClearAllClassfileAutoImportedPackages: expected 0 arguments")
+	}
+
+	xgo.ClearAllClassfileAutoImportedPackages()
+	return nil
+}
+
+// SetCompletionSortConfig sets the completion sort config used by all
+// subsequent completion requests. Its argument is an object of weights keyed
+// by the JSON names of the [server.CompletionSortConfig] fields. Kinds omitted
+// from it get their weights from [server.DefaultCompletionSortConfig], not
+// from a previously set config.
+func SetCompletionSortConfig(this js.Value, args []js.Value) any {
+	if len(args) != 1 {
+		return errors.New("SetCompletionSortConfig: expected 1 argument")
+	}
+	if args[0].Type() != js.TypeObject {
+		return errors.New("SetCompletionSortConfig: argument must be an object")
+	}
+	rawConfig := js.Global().Get("JSON").Call("stringify", args[0]).String()
+	cfg := server.DefaultCompletionSortConfig()
+	if err := json.Unmarshal([]byte(rawConfig), &cfg); err != nil {
+		return fmt.Errorf("SetCompletionSortConfig: %w", err)
+	}
+	server.SetCompletionSortConfig(cfg)
+	return nil
+}
+
+// PreloadPkgdata loads all package data into the cache in the background. It
+// returns a Promise that resolves once the package data has been loaded.
+func PreloadPkgdata(this js.Value, args []js.Value) any {
+	if len(args) != 0 {
+		return errors.New("PreloadPkgdata: expected 0 arguments")
+	}
+	executor := js.FuncOf(func(this js.Value, args []js.Value) any {
+		resolve, reject := args[0], args[1]
+		go func() {
+			if err := pkgdata.PreloadAll(); err != nil {
+				reject.Invoke(js.Global().Get("Error").New(fmt.Sprintf("PreloadPkgdata: %v", err)))
+				return
+			}
+			resolve.Invoke()
+		}()
+		return nil
+	})
+	defer executor.Release()
+	return js.Global().Get("Promise").New(executor)
+}
+
+// GetSpxDefinitions returns all spx definitions as an array of definition
+// objects.
+func GetSpxDefinitions(this js.Value, args []js.Value) any {
+	if len(args) != 0 {
+		return errors.New("GetSpxDefinitions: expected 0 arguments")
+	}
+	defsJSON, err := json.Marshal(server.GetAllSpxDefinitions())
+	if err != nil {
+		return fmt.Errorf("GetSpxDefinitions: %w", err)
+	}
+	return js.Global().Get("JSON").Call("parse", string(defsJSON))
+}
+
+// SearchSymbol returns the package-level symbols of all packages in the
+// package data whose names contain the given string, ignoring case.
+func SearchSymbol(this js.Value, args []js.Value) any {
+	if len(args) != 1 {
+		return errors.New("SearchSymbol: expected 1 argument")
+	}
+	if args[0].Type() != js.TypeString {
+		return errors.New("SearchSymbol: argument must be a string")
+	}
+	results := pkgdata.SearchPkgDoc(args[0].String())
+	if results == nil {
+		results = []pkgdata.PkgDocSearchResult{}
+	}
+	resultsJSON, err := json.Marshal(results)
+	if err != nil {
+		return fmt.Errorf("SearchSymbol: %w", err)
+	}
+	return js.Global().Get("JSON").Call("parse", string(resultsJSON))
+}
+
+// GetProjectMetrics returns the cache metrics of the project served by the
+// most recently created language server, or null if there is none.
+func GetProjectMetrics(this js.Value, args []js.Value) any {
+	if len(args) != 0 {
+		return errors.New("GetProjectMetrics: expected 0 arguments")
+	}
+	if latestSpxls == nil {
+		return js.Null()
+	}
+	metricsJSON, err := json.Marshal(latestSpxls.server.CacheMetrics())
+	if err != nil {
+		return fmt.Errorf("GetProjectMetrics: %w", err)
+	}
+	return js.Global().Get("JSON").Call("parse", string(metricsJSON))
+}
+
+// SaveProjectSnapshot returns a Uint8Array holding a snapshot of the project
+// served by the most recently created language server, including its
+// serializable caches, or null if there is none. The snapshot can be restored
+// with [LoadProjectSnapshot], e.g., after the WebAssembly module is reloaded.
+func SaveProjectSnapshot(this js.Value, args []js.Value) any {
+	if len(args) != 0 {
+		return errors.New("SaveProjectSnapshot: expected 0 arguments")
+	}
+	if latestSpxls == nil {
+		return js.Null()
+	}
+	data, err := latestSpxls.server.SaveProjectSnapshot()
+	if err != nil {
+		return fmt.Errorf("SaveProjectSnapshot: %w", err)
+	}
+	result := js.Global().Get("Uint8Array").New(len(data))
+	js.CopyBytesToJS(result, data)
+	return result
+}
+
+// LoadProjectSnapshot replaces the project served by the most recently created
+// language server with the one restored from a Uint8Array returned by
+// [SaveProjectSnapshot].
+func LoadProjectSnapshot(this js.Value, args []js.Value) any {
+	if len(args) != 1 {
+		return errors.New("LoadProjectSnapshot: expected 1 argument")
+	}
+	if args[0].Type() != js.TypeObject || !args[0].InstanceOf(js.Global().Get("Uint8Array")) {
+		return errors.New("LoadProjectSnapshot: argument must be a Uint8Array")
+	}
+	if latestSpxls == nil {
+		return errors.New("LoadProjectSnapshot: no language server has been created")
+	}
+	if err := latestSpxls.server.LoadProjectSnapshot(JSUint8ArrayToBytes(args[0])); err != nil {
+		return fmt.Errorf("LoadProjectSnapshot: %w", err)
+	}
+	return nil
+}
+
+// GetSpxlsVersion returns the build information of the language server.
+func GetSpxlsVersion(this js.Value, args []js.Value) any {
+	if len(args) != 0 {
+		return errors.New("GetSpxlsVersion: expected 0 arguments")
+	}
+	infoJSON, err := json.Marshal(buildinfo.Get())
+	if err != nil {
+		return fmt.Errorf("GetSpxlsVersion: %w", err)
+	}
+	return js.Global().Get("JSON").Call("parse", string(infoJSON))
+}
+
 // JSFuncOfWithError returns a function to be used by JavaScript that can return
 // an error.
 func JSFuncOfWithError(fn func(this js.Value, args []js.Value) any) js.Func {
@@ -170,23 +494,51 @@ func JSUint8ArrayToBytes(uint8Array js.Value) []byte {
 }
 
 // ConvertJSFilesToMap converts a JavaScript object of files to a map.
+// Malformed file objects are skipped silently. Use
+// [ConvertJSFilesToMapWithErrors] to find out about them.
 func ConvertJSFilesToMap(files js.Value) map[string]*xgo.File {
+	result, _ := ConvertJSFilesToMapWithErrors(files)
+	return result
+}
+
+// ConvertJSFilesToMapWithErrors converts a JavaScript object of files to a
+// map. Each file object must have a Uint8Array `content` and a number
+// `modTime`. Malformed file objects are left out of the map and reported as
+// errors.
+func ConvertJSFilesToMapWithErrors(files js.Value) (map[string]*xgo.File, []error) {
 	if files.Type() != js.TypeObject {
-		return nil
+		return nil, []error{fmt.Errorf("files must be an object, got %s", files.Type())}
 	}
-	keys := js.Global().Get("Object").Call("keys", files)
-	result := make(map[string]*xgo.File, keys.Length())
+	var (
+		keys           = js.Global().Get("Object").Call("keys", files)
+		objectCtor     = js.Global().Get("Object")
+		uint8ArrayCtor = js.Global().Get("Uint8Array")
+		result         = make(map[string]*xgo.File, keys.Length())
+		errs           []error
+	)
 	for i := range keys.Length() {
 		key := keys.Index(i).String()
 		value := files.Get(key)
-		if value.InstanceOf(js.Global().Get("Object")) {
-			result[key] = &xgo.File{
-				Content: JSUint8ArrayToBytes(value.Get("content")),
-				ModTime: time.UnixMilli(int64(value.Get("modTime").Int())),
-			}
+		if !value.InstanceOf(objectCtor) {
+			errs = append(errs, fmt.Errorf("file %q: must be an object, got %s", key, value.Type()))
+			continue
+		}
+		content := value.Get("content")
+		if !content.InstanceOf(uint8ArrayCtor) {
+			errs = append(errs, fmt.Errorf("file %q: content must be a Uint8Array, got %s", key, content.Type()))
+			continue
+		}
+		modTime := value.Get("modTime")
+		if modTime.Type() != js.TypeNumber {
+			errs = append(errs, fmt.Errorf("file %q: modTime must be a number, got %s", key, modTime.Type()))
+			continue
+		}
+		result[key] = &xgo.File{
+			Content: JSUint8ArrayToBytes(content),
+			ModTime: time.UnixMilli(int64(modTime.Int())),
 		}
 	}
-	return result
+	return result, errs
 }
 
 func main() {
@@ -194,5 +546,20 @@ func main() {
 	js.Global().Set("NewSpxls", JSFuncOfWithError(NewSpxls))
 	js.Global().Set("SetCustomPkgdataZip", JSFuncOfWithError(SetCustomPkgdataZip))
 	js.Global().Set("SetClassfileAutoImportedPackages", JSFuncOfWithError(SetClassfileAutoImportedPackages))
+	js.Global().Set("GetClassfileAutoImportedPackages", JSFuncOfWithError(GetClassfileAutoImportedPackages))
+	js.Global().Set("RemoveClassfileAutoImportedPackages", JSFuncOfWithError(RemoveClassfileAutoImportedPackages))
+	js.Global().Set("ListClassfileAutoImportedPackageIDs", JSFuncOfWithError(ListClassfileAutoImportedPackageIDs))
+	js.Global().Set("ClearAllClassfileAutoImportedPackages", JSFuncOfWithError(ClearAllClassfileAutoImportedPackages))
+	js.Global().Set("SetCompletionSortConfig", JSFuncOfWithError(SetCompletionSortConfig))
+	js.Global().Set("GetSpxDefinitions", JSFuncOfWithError(GetSpxDefinitions))
+	js.Global().Set("PreloadPkgdata", JSFuncOfWithError(PreloadPkgdata))
+	js.Global().Set("SearchSymbol", JSFuncOfWithError(SearchSymbol))
+	js.Global().Set("SetSchedulerPolicy", JSFuncOfWithError(SetSchedulerPolicy))
+	js.Global().Set("SetDiagnosticDebounceMs", JSFuncOfWithError(SetDiagnosticDebounceMs))
+	js.Global().Set("GetProjectMetrics", JSFuncOfWithError(GetProjectMetrics))
+	js.Global().Set("GetSpxlsVersion", JSFuncOfWithError(GetSpxlsVersion))
+	js.Global().Set("ReplaceProject", JSFuncOfWithError(ReplaceProject))
+	js.Global().Set("SaveProjectSnapshot", JSFuncOfWithError(SaveProjectSnapshot))
+	js.Global().Set("LoadProjectSnapshot", JSFuncOfWithError(LoadProjectSnapshot))
 	select {}
 }
@@ -0,0 +1,130 @@
+/*
+ * Copyright (c) 2025 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pkgdoc
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Equal reports whether p and other document the same symbols with the same
+// documentation. Differences in whitespace within doc strings are ignored.
+func (p *PkgDoc) Equal(other *PkgDoc) bool {
+	return p.Diff(other) == ""
+}
+
+// Diff returns a human-readable description of the differences from p to
+// other, one per line, or "" if they are [PkgDoc.Equal]. Each line starts with
+// "+" for a symbol only in other, "-" for a symbol only in p, or "~" for a
+// symbol whose documentation differs, followed by the symbol, e.g.,
+// `+Funcs["newFunc"]` or `~Types["T"].Methods["M"]`.
+func (p *PkgDoc) Diff(other *PkgDoc) string {
+	var d pkgDocDiff
+	switch {
+	case p == nil && other == nil:
+		return ""
+	case p == nil:
+		return "+<PkgDoc>\n"
+	case other == nil:
+		return "-<PkgDoc>\n"
+	}
+
+	d.doc("Doc", p.Doc, other.Doc)
+	d.doc("Path", p.Path, other.Path)
+	d.doc("Name", p.Name, other.Name)
+	d.docMap("Vars", p.Vars, other.Vars)
+	d.docMap("Consts", p.Consts, other.Consts)
+	for _, name := range sortedUnionKeys(p.Types, other.Types) {
+		symbol := fmt.Sprintf("Types[%q]", name)
+		oldType, newType := p.Types[name], other.Types[name]
+		switch {
+		case oldType == nil && newType == nil:
+		case oldType == nil:
+			d.add("+", symbol)
+		case newType == nil:
+			d.add("-", symbol)
+		default:
+			d.doc(symbol+".Doc", oldType.Doc, newType.Doc)
+			if !slices.Equal(oldType.TypeParams, newType.TypeParams) {
+				d.add("~", symbol+".TypeParams")
+			}
+			d.docMap(symbol+".Fields", oldType.Fields, newType.Fields)
+			d.docMap(symbol+".Methods", oldType.Methods, newType.Methods)
+			d.docMap(symbol+".Examples", oldType.Examples, newType.Examples)
+		}
+	}
+	d.docMap("Funcs", p.Funcs, other.Funcs)
+	d.docMap("Examples", p.Examples, other.Examples)
+	return d.String()
+}
+
+// pkgDocDiff accumulates the lines of a [PkgDoc.Diff] result.
+type pkgDocDiff struct {
+	strings.Builder
+}
+
+// add adds a line for symbol with the given change marker.
+func (d *pkgDocDiff) add(marker, symbol string) {
+	d.WriteString(marker)
+	d.WriteString(symbol)
+	d.WriteByte('\n')
+}
+
+// doc adds a changed line for symbol if oldDoc and newDoc differ in more than
+// whitespace.
+func (d *pkgDocDiff) doc(symbol, oldDoc, newDoc string) {
+	if normalizeDocSpace(oldDoc) != normalizeDocSpace(newDoc) {
+		d.add("~", symbol)
+	}
+}
+
+// docMap adds lines for all symbols that differ between oldDocs and newDocs.
+func (d *pkgDocDiff) docMap(field string, oldDocs, newDocs map[string]string) {
+	for _, name := range sortedUnionKeys(oldDocs, newDocs) {
+		symbol := fmt.Sprintf("%s[%q]", field, name)
+		oldDoc, inOld := oldDocs[name]
+		newDoc, inNew := newDocs[name]
+		switch {
+		case !inOld:
+			d.add("+", symbol)
+		case !inNew:
+			d.add("-", symbol)
+		default:
+			d.doc(symbol, oldDoc, newDoc)
+		}
+	}
+}
+
+// sortedUnionKeys returns the sorted keys present in either a or b.
+func sortedUnionKeys[V any](a, b map[string]V) []string {
+	keys := slices.Collect(maps.Keys(a))
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// normalizeDocSpace collapses every run of whitespace in doc into a single
+// space and trims leading and trailing whitespace.
+func normalizeDocSpace(doc string) string {
+	return strings.Join(strings.Fields(doc), " ")
+}
@@ -0,0 +1,114 @@
+/*
+ * Copyright (c) 2025 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pkgdoc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPkgDocDiff(t *testing.T) {
+	newPkgDoc := func() *PkgDoc {
+		return &PkgDoc{
+			Doc:    "Package foo does things.",
+			Path:   "example.com/foo",
+			Name:   "foo",
+			Vars:   map[string]string{"V": "V is a variable."},
+			Consts: map[string]string{"C": "C is a constant."},
+			Types: map[string]*TypeDoc{
+				"T": {
+					Doc:     "T is a type.",
+					Fields:  map[string]string{"F": "F is a field."},
+					Methods: map[string]string{"M": "M is a method."},
+				},
+			},
+			Funcs: map[string]string{"Fn": "Fn is a function."},
+		}
+	}
+
+	for _, tt := range []struct {
+		name   string
+		modify func(p *PkgDoc)
+		want   string
+	}{
+		{
+			name:   "EqualDocs",
+			modify: func(p *PkgDoc) {},
+			want:   "",
+		},
+		{
+			name: "WhitespaceOnlyChange",
+			modify: func(p *PkgDoc) {
+				p.Funcs["Fn"] = "Fn is\n\ta function.\n"
+			},
+			want: "",
+		},
+		{
+			name: "MissingFunc",
+			modify: func(p *PkgDoc) {
+				delete(p.Funcs, "Fn")
+			},
+			want: "-Funcs[\"Fn\"]\n",
+		},
+		{
+			name: "AddedFunc",
+			modify: func(p *PkgDoc) {
+				p.Funcs["New"] = "New is a new function."
+			},
+			want: "+Funcs[\"New\"]\n",
+		},
+		{
+			name: "ChangedMethodDoc",
+			modify: func(p *PkgDoc) {
+				p.Types["T"].Methods["M"] = "M does something else."
+			},
+			want: "~Types[\"T\"].Methods[\"M\"]\n",
+		},
+		{
+			name: "MissingType",
+			modify: func(p *PkgDoc) {
+				delete(p.Types, "T")
+			},
+			want: "-Types[\"T\"]\n",
+		},
+		{
+			name: "MultipleChanges",
+			modify: func(p *PkgDoc) {
+				p.Doc = "Package foo does other things."
+				p.Types["T"].TypeParams = []string{"E"}
+				delete(p.Vars, "V")
+			},
+			want: "~Doc\n-Vars[\"V\"]\n~Types[\"T\"].TypeParams\n",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			p, other := newPkgDoc(), newPkgDoc()
+			tt.modify(other)
+			assert.Equal(t, tt.want, p.Diff(other))
+			assert.Equal(t, tt.want == "", p.Equal(other))
+		})
+	}
+
+	t.Run("Nil", func(t *testing.T) {
+		var nilPkgDoc *PkgDoc
+		assert.Equal(t, "", nilPkgDoc.Diff(nil))
+		assert.True(t, nilPkgDoc.Equal(nil))
+		assert.Equal(t, "+<PkgDoc>\n", nilPkgDoc.Diff(newPkgDoc()))
+		assert.Equal(t, "-<PkgDoc>\n", newPkgDoc().Diff(nil))
+	})
+}
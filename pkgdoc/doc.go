@@ -17,12 +17,21 @@
 package pkgdoc
 
 import (
+	"encoding/json"
+	"fmt"
 	goast "go/ast"
 	godoc "go/doc"
+	goparser "go/parser"
+	gotoken "go/token"
+	"io/fs"
+	"maps"
+	"path"
+	"slices"
 	"strings"
 
 	"github.com/goplus/xgo/token"
 	"github.com/goplus/xgolsw/xgo/xgoutil"
+	"golang.org/x/mod/module"
 )
 
 // PkgDoc is the documentation for a package.
@@ -34,6 +43,27 @@ type PkgDoc struct {
 	Consts map[string]string
 	Types  map[string]*TypeDoc
 	Funcs  map[string]string
+
+	// Examples holds the source code of the package-level examples, keyed by
+	// the example function names without the "Example" prefix, e.g.,
+	// "Println" for `ExamplePrintln`. See [TypeDoc.Examples] for the examples
+	// of types and their methods.
+	Examples map[string]string `json:",omitempty"`
+}
+
+// String implements [fmt.Stringer]. It returns a compact JSON representation
+// of the package documentation.
+func (p *PkgDoc) String() string {
+	return jsonString(p)
+}
+
+// Summary returns a single-line summary of the package documentation, e.g.,
+// "package fmt: 12 types, 34 funcs, 5 vars, 2 consts".
+func (p *PkgDoc) Summary() string {
+	if p == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("package %s: %d types, %d funcs, %d vars, %d consts", p.Name, len(p.Types), len(p.Funcs), len(p.Vars), len(p.Consts))
 }
 
 // typeDoc returns the documentation for the given type name. It creates a new
@@ -53,6 +83,162 @@ type TypeDoc struct {
 	Doc     string
 	Fields  map[string]string
 	Methods map[string]string
+
+	// Examples holds the source code of the examples of the type and its
+	// methods, keyed by the example function names without the "Example"
+	// prefix and the type name, e.g., "" for `ExampleBuilder` and "Len" for
+	// `ExampleBuilder_Len`.
+	Examples map[string]string `json:",omitempty"`
+
+	// TypeParams holds the names of the type parameters of a generic type,
+	// e.g., ["K", "V"] for `type Map[K comparable, V any] struct{ ... }`.
+	TypeParams []string `json:",omitempty"`
+
+	// Deprecated is the deprecation notice of the type, or "" if the type is
+	// not deprecated. See [DeprecationNotice].
+	Deprecated string `json:",omitempty"`
+}
+
+// DeprecationNotice returns the deprecation notice in doc, or "" if there is
+// none. Following the Go convention, a deprecation notice is a paragraph that
+// begins with "Deprecated: ". The returned notice has the prefix removed and
+// its lines joined with single spaces, e.g., "use NewMove instead.".
+func DeprecationNotice(doc string) string {
+	const prefix = "Deprecated: "
+	for paragraph := range strings.SplitSeq(doc, "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if notice, ok := strings.CutPrefix(paragraph, prefix); ok {
+			return strings.Join(strings.Fields(notice), " ")
+		}
+	}
+	return ""
+}
+
+// LookupVar returns the documentation for the variable with the given name.
+func (p *PkgDoc) LookupVar(name string) (doc string, ok bool) {
+	if p == nil {
+		return "", false
+	}
+	doc, ok = p.Vars[name]
+	return
+}
+
+// LookupConst returns the documentation for the constant with the given name.
+func (p *PkgDoc) LookupConst(name string) (doc string, ok bool) {
+	if p == nil {
+		return "", false
+	}
+	doc, ok = p.Consts[name]
+	return
+}
+
+// LookupFunc returns the documentation for the function with the given name.
+func (p *PkgDoc) LookupFunc(name string) (doc string, ok bool) {
+	if p == nil {
+		return "", false
+	}
+	doc, ok = p.Funcs[name]
+	return
+}
+
+// LookupType returns the documentation for the type with the given name.
+func (p *PkgDoc) LookupType(name string) (doc string, ok bool) {
+	if p == nil {
+		return "", false
+	}
+	typeDoc, ok := p.Types[name]
+	if !ok || typeDoc == nil {
+		return "", false
+	}
+	return typeDoc.Doc, true
+}
+
+// LookupField returns the documentation for the field with the given name of
+// the given type.
+func (p *PkgDoc) LookupField(typeName, fieldName string) (doc string, ok bool) {
+	if p == nil {
+		return "", false
+	}
+	typeDoc, ok := p.Types[typeName]
+	if !ok || typeDoc == nil {
+		return "", false
+	}
+	doc, ok = typeDoc.Fields[fieldName]
+	return
+}
+
+// LookupMethod returns the documentation for the method with the given name
+// of the given type.
+func (p *PkgDoc) LookupMethod(typeName, methodName string) (doc string, ok bool) {
+	if p == nil {
+		return "", false
+	}
+	typeDoc, ok := p.Types[typeName]
+	if !ok || typeDoc == nil {
+		return "", false
+	}
+	doc, ok = typeDoc.Methods[methodName]
+	return
+}
+
+// String implements [fmt.Stringer]. It returns a compact JSON representation
+// of the type documentation.
+func (t *TypeDoc) String() string {
+	return jsonString(t)
+}
+
+// jsonString returns the compact JSON representation of v, or "<nil>" if v is
+// a nil pointer.
+func jsonString[T any](v *T) string {
+	if v == nil {
+		return "<nil>"
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("<%T: %v>", v, err)
+	}
+	return string(b)
+}
+
+// NewGoFromDir creates a new [PkgDoc] from the Go source files in dir. Only
+// files accepted by fileFilter are parsed (all files if it is nil), and only
+// the package named after the last non-version element of pkgPath is used. It
+// returns (nil, nil) if dir contains no such package.
+//
+// Test files of the package, including those of its external test package, are
+// only used for their examples. See [PkgDoc.Examples].
+func NewGoFromDir(pkgPath, dir string, fileFilter func(fs.FileInfo) bool) (*PkgDoc, error) {
+	fset := gotoken.NewFileSet()
+	astPkgs, err := goparser.ParseDir(fset, dir, fileFilter, goparser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse package directory %q: %w", dir, err)
+	}
+
+	pkgName := path.Base(pkgPath)
+	if prefix, _, ok := module.SplitPathVersion(pkgPath); ok {
+		pkgName = path.Base(prefix)
+	}
+	astPkg, ok := astPkgs[pkgName]
+	if !ok {
+		return nil, nil
+	}
+
+	var testFiles []*goast.File
+	for _, name := range slices.Sorted(maps.Keys(astPkg.Files)) {
+		if strings.HasSuffix(name, "_test.go") {
+			testFiles = append(testFiles, astPkg.Files[name])
+			delete(astPkg.Files, name)
+		}
+	}
+	if xtestPkg, ok := astPkgs[pkgName+"_test"]; ok {
+		for _, name := range slices.Sorted(maps.Keys(xtestPkg.Files)) {
+			testFiles = append(testFiles, xtestPkg.Files[name])
+		}
+	}
+
+	pkgDoc := NewGo(pkgPath, astPkg)
+	pkgDoc.addExamples(fset, testFiles)
+	return pkgDoc, nil
 }
 
 // NewGo creates a new [PkgDoc] from the given Go [ast.Package].
@@ -110,11 +296,13 @@ func NewGo(pkgPath string, pkg *goast.Package) *PkgDoc {
 
 		typeDoc := pkgDoc.typeDoc(t.Name)
 		typeDoc.Doc = t.Doc
+		typeDoc.Deprecated = DeprecationNotice(t.Doc)
 		for _, spec := range t.Decl.Specs {
 			typeSpec, ok := spec.(*goast.TypeSpec)
-			if !ok {
+			if !ok || typeSpec.Name.Name != t.Name {
 				continue
 			}
+			typeDoc.TypeParams = goTypeParamNames(typeSpec.TypeParams)
 			structType, ok := typeSpec.Type.(*goast.StructType)
 			if !ok {
 				continue
@@ -160,3 +348,27 @@ func NewGo(pkgPath string, pkg *goast.Package) *PkgDoc {
 
 	return pkgDoc
 }
+
+// goTypeParamNames returns the names of the type parameters in the given Go
+// type parameter list, or nil if there are none.
+func goTypeParamNames(typeParams *goast.FieldList) []string {
+	if typeParams == nil {
+		return nil
+	}
+	return typeParamNames(typeParams.List, func(field *goast.Field) []*goast.Ident {
+		return field.Names
+	})
+}
+
+// typeParamNames returns the names of the type parameters declared by fields,
+// or nil if there are none. It is shared by the Go and XGo syntax trees, whose
+// identifiers both implement [fmt.Stringer] by returning their names.
+func typeParamNames[Field any, Ident fmt.Stringer](fields []Field, names func(Field) []Ident) []string {
+	var typeParams []string
+	for _, field := range fields {
+		for _, name := range names(field) {
+			typeParams = append(typeParams, name.String())
+		}
+	}
+	return typeParams
+}
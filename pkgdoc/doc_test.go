@@ -0,0 +1,259 @@
+/*
+ * Copyright (c) 2025 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pkgdoc
+
+import (
+	goast "go/ast"
+	goparser "go/parser"
+	gotoken "go/token"
+	"io/fs"
+	"testing"
+
+	"github.com/goplus/xgo/ast"
+	"github.com/goplus/xgo/token"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newGoPkgDoc creates a new [PkgDoc] from the Go source src.
+func newGoPkgDoc(t *testing.T, pkgPath, src string) *PkgDoc {
+	t.Helper()
+	fset := gotoken.NewFileSet()
+	file, err := goparser.ParseFile(fset, "pkg.go", src, goparser.ParseComments)
+	require.NoError(t, err)
+	return NewGo(pkgPath, &goast.Package{
+		Name:  file.Name.Name,
+		Files: map[string]*goast.File{"pkg.go": file},
+	})
+}
+
+func TestTypeParams(t *testing.T) {
+	const src = `package foo
+
+// Stack is a generic stack.
+type Stack[T any] struct {
+	Items []T
+}
+
+// Push pushes v onto the stack.
+func (s *Stack[T]) Push(v T) {}
+
+// Map is a generic map.
+type Map[K comparable, V any] map[K]V
+
+// Plain is not generic.
+type Plain struct{}
+`
+
+	t.Run("Go", func(t *testing.T) {
+		pkgDoc := newGoPkgDoc(t, "example.com/foo", src)
+		require.Contains(t, pkgDoc.Types, "Stack")
+		assert.Equal(t, []string{"T"}, pkgDoc.Types["Stack"].TypeParams)
+		assert.Contains(t, pkgDoc.Types["Stack"].Fields, "Items")
+		assert.Contains(t, pkgDoc.Types["Stack"].Methods, "Push")
+		assert.Equal(t, []string{"K", "V"}, pkgDoc.Types["Map"].TypeParams)
+		assert.Nil(t, pkgDoc.Types["Plain"].TypeParams)
+	})
+
+	t.Run("GoTypes", func(t *testing.T) {
+		pkgDoc := newGoFromTypes("example.com/foo", checkGoPackage(t, "example.com/foo", src))
+		require.Contains(t, pkgDoc.Types, "Stack")
+		assert.Equal(t, []string{"T"}, pkgDoc.Types["Stack"].TypeParams)
+		assert.Contains(t, pkgDoc.Types["Stack"].Methods, "Push")
+		assert.Equal(t, []string{"K", "V"}, pkgDoc.Types["Map"].TypeParams)
+		assert.Nil(t, pkgDoc.Types["Plain"].TypeParams)
+	})
+
+	t.Run("XGo", func(t *testing.T) {
+		// The XGo parser does not parse type parameters yet, so the AST is
+		// built by hand.
+		file := &ast.File{
+			Name: ast.NewIdent("foo"),
+			Decls: []ast.Decl{&ast.GenDecl{
+				Tok: token.TYPE,
+				Specs: []ast.Spec{&ast.TypeSpec{
+					Name: ast.NewIdent("Stack"),
+					TypeParams: &ast.FieldList{List: []*ast.Field{{
+						Names: []*ast.Ident{ast.NewIdent("T")},
+						Type:  ast.NewIdent("any"),
+					}}},
+					Type: &ast.StructType{Fields: &ast.FieldList{}},
+				}},
+			}},
+		}
+		pkgDoc := NewXGo("example.com/foo", &ast.Package{
+			Name:  "foo",
+			Files: map[string]*ast.File{"foo.xgo": file},
+		})
+		require.Contains(t, pkgDoc.Types, "Stack")
+		assert.Equal(t, []string{"T"}, pkgDoc.Types["Stack"].TypeParams)
+	})
+}
+
+func TestPkgDocLookup(t *testing.T) {
+	pkgDoc := &PkgDoc{
+		Vars:   map[string]string{"V": "V doc"},
+		Consts: map[string]string{"C": "C doc"},
+		Types: map[string]*TypeDoc{
+			"T": {
+				Doc:     "T doc",
+				Fields:  map[string]string{"F": "F doc"},
+				Methods: map[string]string{"M": "M doc"},
+			},
+			"Nil": nil,
+		},
+		Funcs: map[string]string{"Fn": "Fn doc"},
+	}
+
+	for _, tt := range []struct {
+		name   string
+		lookup func(p *PkgDoc) (string, bool)
+		want   string
+	}{
+		{"Var", func(p *PkgDoc) (string, bool) { return p.LookupVar("V") }, "V doc"},
+		{"Const", func(p *PkgDoc) (string, bool) { return p.LookupConst("C") }, "C doc"},
+		{"Type", func(p *PkgDoc) (string, bool) { return p.LookupType("T") }, "T doc"},
+		{"Func", func(p *PkgDoc) (string, bool) { return p.LookupFunc("Fn") }, "Fn doc"},
+		{"Field", func(p *PkgDoc) (string, bool) { return p.LookupField("T", "F") }, "F doc"},
+		{"Method", func(p *PkgDoc) (string, bool) { return p.LookupMethod("T", "M") }, "M doc"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, ok := tt.lookup(pkgDoc)
+			assert.True(t, ok)
+			assert.Equal(t, tt.want, doc)
+
+			doc, ok = tt.lookup(nil)
+			assert.False(t, ok)
+			assert.Empty(t, doc)
+		})
+	}
+
+	for _, tt := range []struct {
+		name   string
+		lookup func() (string, bool)
+	}{
+		{"UnknownVar", func() (string, bool) { return pkgDoc.LookupVar("C") }},
+		{"UnknownConst", func() (string, bool) { return pkgDoc.LookupConst("V") }},
+		{"UnknownType", func() (string, bool) { return pkgDoc.LookupType("U") }},
+		{"NilType", func() (string, bool) { return pkgDoc.LookupType("Nil") }},
+		{"UnknownFunc", func() (string, bool) { return pkgDoc.LookupFunc("T") }},
+		{"UnknownField", func() (string, bool) { return pkgDoc.LookupField("T", "M") }},
+		{"FieldOfUnknownType", func() (string, bool) { return pkgDoc.LookupField("U", "F") }},
+		{"FieldOfNilType", func() (string, bool) { return pkgDoc.LookupField("Nil", "F") }},
+		{"UnknownMethod", func() (string, bool) { return pkgDoc.LookupMethod("T", "F") }},
+		{"MethodOfUnknownType", func() (string, bool) { return pkgDoc.LookupMethod("U", "M") }},
+		{"MethodOfNilType", func() (string, bool) { return pkgDoc.LookupMethod("Nil", "M") }},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, ok := tt.lookup()
+			assert.False(t, ok)
+			assert.Empty(t, doc)
+		})
+	}
+}
+
+func TestNewGoFromDir(t *testing.T) {
+	t.Run("AllFiles", func(t *testing.T) {
+		pkgDoc, err := NewGoFromDir("example.com/greet", "testdata/greet", nil)
+		require.NoError(t, err)
+		require.NotNil(t, pkgDoc)
+		assert.Equal(t, "Package greet prints greetings.\n", pkgDoc.Doc)
+		assert.Equal(t, "example.com/greet", pkgDoc.Path)
+		assert.Equal(t, "greet", pkgDoc.Name)
+		assert.Equal(t, map[string]string{
+			"Alert":   "Alert shows a greeting for name in a browser alert.\n",
+			"Println": "Println prints a greeting for name.\n",
+		}, pkgDoc.Funcs)
+		require.Contains(t, pkgDoc.Types, "Greeter")
+		assert.Equal(t, "Greeter prints greetings with a prefix.\n", pkgDoc.Types["Greeter"].Doc)
+		assert.Equal(t, map[string]string{"Prefix": "Prefix is printed before each name.\n"}, pkgDoc.Types["Greeter"].Fields)
+		assert.Equal(t, map[string]string{"Greet": "Greet prints a greeting for name.\n"}, pkgDoc.Types["Greeter"].Methods)
+		assert.NotContains(t, pkgDoc.Funcs, "ExamplePrintln")
+	})
+
+	t.Run("FileFilter", func(t *testing.T) {
+		pkgDoc, err := NewGoFromDir("example.com/greet", "testdata/greet", func(fi fs.FileInfo) bool {
+			return fi.Name() != "greet_js.go" && fi.Name() != "example_test.go"
+		})
+		require.NoError(t, err)
+		require.NotNil(t, pkgDoc)
+		assert.NotContains(t, pkgDoc.Funcs, "Alert")
+		assert.Contains(t, pkgDoc.Funcs, "Println")
+		assert.Nil(t, pkgDoc.Examples)
+	})
+
+	t.Run("MajorVersionSuffix", func(t *testing.T) {
+		pkgDoc, err := NewGoFromDir("example.com/greet/v2", "testdata/greet", nil)
+		require.NoError(t, err)
+		require.NotNil(t, pkgDoc)
+		assert.Equal(t, "example.com/greet/v2", pkgDoc.Path)
+		assert.Equal(t, "greet", pkgDoc.Name)
+	})
+
+	t.Run("NoMatchingPackage", func(t *testing.T) {
+		pkgDoc, err := NewGoFromDir("example.com/other", "testdata/greet", nil)
+		require.NoError(t, err)
+		assert.Nil(t, pkgDoc)
+	})
+
+	t.Run("NonExistentDir", func(t *testing.T) {
+		_, err := NewGoFromDir("example.com/greet", "testdata/nonexistent", nil)
+		assert.ErrorContains(t, err, `failed to parse package directory "testdata/nonexistent"`)
+	})
+}
+
+func TestPkgDocString(t *testing.T) {
+	t.Run("PkgDoc", func(t *testing.T) {
+		pkgDoc := &PkgDoc{
+			Path:   "example.com/foo",
+			Name:   "foo",
+			Vars:   map[string]string{},
+			Consts: map[string]string{},
+			Types:  map[string]*TypeDoc{},
+			Funcs:  map[string]string{"F": "F does things."},
+		}
+		assert.Equal(t, `{"Doc":"","Path":"example.com/foo","Name":"foo","Vars":{},"Consts":{},"Types":{},"Funcs":{"F":"F does things."}}`, pkgDoc.String())
+	})
+
+	t.Run("TypeDoc", func(t *testing.T) {
+		typeDoc := &TypeDoc{
+			Doc:        "T is a type.",
+			Fields:     map[string]string{"F": ""},
+			Methods:    map[string]string{},
+			TypeParams: []string{"E"},
+		}
+		assert.Equal(t, `{"Doc":"T is a type.","Fields":{"F":""},"Methods":{},"TypeParams":["E"]}`, typeDoc.String())
+	})
+
+	t.Run("Nil", func(t *testing.T) {
+		assert.Equal(t, "<nil>", (*PkgDoc)(nil).String())
+		assert.Equal(t, "<nil>", (*TypeDoc)(nil).String())
+	})
+}
+
+func TestPkgDocSummary(t *testing.T) {
+	pkgDoc := &PkgDoc{
+		Name:   "foo",
+		Vars:   map[string]string{"V": ""},
+		Consts: map[string]string{"C1": "", "C2": ""},
+		Types:  map[string]*TypeDoc{"T": {}},
+		Funcs:  map[string]string{"F1": "", "F2": "", "F3": ""},
+	}
+	assert.Equal(t, "package foo: 1 types, 3 funcs, 1 vars, 2 consts", pkgDoc.Summary())
+	assert.Equal(t, "package bar: 0 types, 0 funcs, 0 vars, 0 consts", (&PkgDoc{Name: "bar"}).Summary())
+	assert.Equal(t, "<nil>", (*PkgDoc)(nil).Summary())
+}
@@ -0,0 +1,111 @@
+/*
+ * Copyright (c) 2025 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pkgdoc
+
+import (
+	"bytes"
+	goast "go/ast"
+	godoc "go/doc"
+	"go/format"
+	"go/printer"
+	gotoken "go/token"
+	"strings"
+)
+
+// addExamples adds the examples found in the given test files to p. An example
+// named after a documented type, e.g., `ExampleBuilder` or
+// `ExampleBuilder_Len`, is added to the [TypeDoc.Examples] of that type under
+// the rest of its name, i.e., "" or "Len". Any other example, e.g.,
+// `ExamplePrintln`, is added to [PkgDoc.Examples] under its name without the
+// "Example" prefix, i.e., "Println".
+func (p *PkgDoc) addExamples(fset *gotoken.FileSet, testFiles []*goast.File) {
+	for _, ex := range godoc.Examples(testFiles...) {
+		code := exampleCode(fset, ex)
+		if code == "" {
+			continue
+		}
+		if typeName, rest, _ := strings.Cut(ex.Name, "_"); typeName != "" {
+			if typeDoc, ok := p.Types[typeName]; ok && typeDoc != nil {
+				if typeDoc.Examples == nil {
+					typeDoc.Examples = make(map[string]string)
+				}
+				typeDoc.Examples[rest] = code
+				continue
+			}
+		}
+		if p.Examples == nil {
+			p.Examples = make(map[string]string)
+		}
+		p.Examples[ex.Name] = code
+	}
+}
+
+// exampleCode returns the formatted source code of ex. For an example function,
+// it is the body of the function without the enclosing braces and the output
+// comment. It returns "" if the code cannot be formatted.
+func exampleCode(fset *gotoken.FileSet, ex *godoc.Example) string {
+	body, ok := ex.Code.(*goast.BlockStmt)
+	if !ok {
+		var buf bytes.Buffer
+		if err := format.Node(&buf, fset, ex.Code); err != nil {
+			return ""
+		}
+		return strings.TrimSpace(buf.String())
+	}
+
+	var comments []*goast.CommentGroup
+	for _, cg := range ex.Comments {
+		if body.Lbrace < cg.Pos() && cg.End() < body.Rbrace {
+			comments = append(comments, cg)
+		}
+	}
+	if (ex.Output != "" || ex.EmptyOutput) && len(comments) > 0 {
+		comments = comments[:len(comments)-1] // The output comment.
+	}
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, &printer.CommentedNode{Node: body, Comments: comments}); err != nil {
+		return ""
+	}
+	code := strings.TrimSpace(buf.String())
+	code = strings.TrimPrefix(code, "{")
+	code = strings.TrimSuffix(code, "}")
+	lines := strings.Split(strings.Trim(code, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimPrefix(line, "\t")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// LookupExample returns the source code of the example for the symbol with
+// the given name of the given type, or of the package-level symbol with the
+// given name if typeName is "". For a type itself, name is "".
+func (p *PkgDoc) LookupExample(typeName, name string) (code string, ok bool) {
+	if p == nil {
+		return "", false
+	}
+	if typeName == "" {
+		code, ok = p.Examples[name]
+		return
+	}
+	typeDoc, ok := p.Types[typeName]
+	if !ok || typeDoc == nil {
+		return "", false
+	}
+	code, ok = typeDoc.Examples[name]
+	return
+}
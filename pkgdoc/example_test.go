@@ -0,0 +1,51 @@
+/*
+ * Copyright (c) 2025 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pkgdoc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExamples(t *testing.T) {
+	pkgDoc, err := NewGoFromDir("example.com/greet", "testdata/greet", nil)
+	require.NoError(t, err)
+	require.NotNil(t, pkgDoc)
+
+	t.Run("PackageLevel", func(t *testing.T) {
+		code, ok := pkgDoc.LookupExample("", "Println")
+		require.True(t, ok)
+		assert.Equal(t, "// Greet the world.\ngreet.Println(\"world\")", code)
+		assert.Equal(t, code, pkgDoc.Examples["Println"])
+	})
+
+	t.Run("Method", func(t *testing.T) {
+		code, ok := pkgDoc.LookupExample("Greeter", "Greet")
+		require.True(t, ok)
+		assert.Equal(t, "g := greet.Greeter{Prefix: \"Hi\"}\ng.Greet(\"world\")", code)
+		assert.NotContains(t, pkgDoc.Examples, "Greeter_Greet")
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		_, ok := pkgDoc.LookupExample("", "Greet")
+		assert.False(t, ok)
+		_, ok = pkgDoc.LookupExample("Unknown", "")
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,124 @@
+/*
+ * Copyright (c) 2025 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pkgdoc
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	gotoken "go/token"
+	gotypes "go/types"
+	"io"
+	"strings"
+
+	"github.com/goplus/xgo/token"
+	"github.com/goplus/xgolsw/xgo/xgoutil"
+	"golang.org/x/tools/go/gcexportdata"
+)
+
+// NewGoFromExportData creates a new [PkgDoc] from the export data of the Go
+// package pkgPath. The export data may be either an object or archive file
+// produced by the Go toolchain (e.g., by `go list -export`), or raw export
+// data as written by [gcexportdata.Write].
+//
+// Since export data carries no comments, all doc strings of the returned
+// [PkgDoc] are empty. It is a fallback for when the package source is not
+// available.
+func NewGoFromExportData(pkgPath string, exportData io.Reader) (*PkgDoc, error) {
+	br := bufio.NewReader(exportData)
+	var r io.Reader = br
+	if head, _ := br.Peek(8); bytes.HasPrefix(head, []byte("!<arch>\n")) || bytes.HasPrefix(head, []byte("go objec")) {
+		er, err := gcexportdata.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create package export reader: %w", err)
+		}
+		r = er
+	}
+	pkg, err := gcexportdata.Read(r, gotoken.NewFileSet(), make(map[string]*gotypes.Package), pkgPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read package export data: %w", err)
+	}
+	return newGoFromTypes(pkgPath, pkg), nil
+}
+
+// newGoFromTypes creates a new [PkgDoc] with empty doc strings from the
+// exported members of the given [gotypes.Package].
+func newGoFromTypes(pkgPath string, pkg *gotypes.Package) *PkgDoc {
+	pkgDoc := &PkgDoc{
+		Path:   pkgPath,
+		Name:   pkg.Name(),
+		Vars:   make(map[string]string),
+		Consts: make(map[string]string),
+		Types:  make(map[string]*TypeDoc),
+		Funcs:  make(map[string]string),
+	}
+
+	scope := pkg.Scope()
+	isXGoPackage := false
+	for _, name := range scope.Names() {
+		if token.IsExported(name) && xgoutil.IsXGoPackageMarkerName(name) {
+			if _, ok := scope.Lookup(name).(*gotypes.Const); ok {
+				isXGoPackage = true
+				break
+			}
+		}
+	}
+
+	for _, name := range scope.Names() {
+		if !token.IsExported(name) {
+			continue
+		}
+		switch obj := scope.Lookup(name).(type) {
+		case *gotypes.Var:
+			pkgDoc.Vars[name] = ""
+		case *gotypes.Const:
+			pkgDoc.Consts[name] = ""
+		case *gotypes.TypeName:
+			typeDoc := pkgDoc.typeDoc(name)
+			named, ok := obj.Type().(*gotypes.Named)
+			if !ok {
+				continue
+			}
+			if typeParams := named.TypeParams(); typeParams.Len() > 0 {
+				typeDoc.TypeParams = make([]string, typeParams.Len())
+				for i := range typeParams.Len() {
+					typeDoc.TypeParams[i] = typeParams.At(i).Obj().Name()
+				}
+			}
+			if structType, ok := named.Underlying().(*gotypes.Struct); ok {
+				for field := range structType.Fields() {
+					if field.Exported() {
+						typeDoc.Fields[field.Name()] = ""
+					}
+				}
+			}
+			for method := range named.Methods() {
+				if method.Exported() {
+					typeDoc.Methods[method.Name()] = ""
+				}
+			}
+		case *gotypes.Func:
+			pkgDoc.Funcs[name] = ""
+			if isXGoPackage && strings.HasPrefix(name, xgoutil.XGotPrefix) {
+				if recvTypeName, methodName, ok := xgoutil.SplitXGotMethodName(name, true); ok {
+					pkgDoc.typeDoc(recvTypeName).Methods[methodName] = ""
+				}
+			}
+		}
+	}
+	return pkgDoc
+}
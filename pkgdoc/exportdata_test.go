@@ -0,0 +1,104 @@
+/*
+ * Copyright (c) 2025 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pkgdoc
+
+import (
+	"bytes"
+	gotoken "go/token"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/gcexportdata"
+)
+
+func TestNewGoFromExportData(t *testing.T) {
+	t.Run("Normal", func(t *testing.T) {
+		pkg := checkGoPackage(t, "example.com/foo", `package foo
+
+// V is a variable.
+var V int
+
+var unexported int
+
+// C is a constant.
+const C = 1
+
+// T is a type.
+type T struct {
+	Field int
+	hidden int
+}
+
+// M is a method.
+func (T) M() {}
+
+func (T) m() {}
+
+// F is a function.
+func F() {}
+`)
+		var buf bytes.Buffer
+		require.NoError(t, gcexportdata.Write(&buf, gotoken.NewFileSet(), pkg))
+
+		pkgDoc, err := NewGoFromExportData("example.com/foo", &buf)
+		require.NoError(t, err)
+		assert.Equal(t, &PkgDoc{
+			Path:   "example.com/foo",
+			Name:   "foo",
+			Vars:   map[string]string{"V": ""},
+			Consts: map[string]string{"C": ""},
+			Types: map[string]*TypeDoc{
+				"T": {
+					Fields:  map[string]string{"Field": ""},
+					Methods: map[string]string{"M": ""},
+				},
+			},
+			Funcs: map[string]string{"F": ""},
+		}, pkgDoc)
+	})
+
+	t.Run("XGoPackage", func(t *testing.T) {
+		pkg := checkGoPackage(t, "example.com/bar", `package bar
+
+const XGoPackage = true
+
+type Game struct{}
+
+func XGot_Game_Run(g *Game) {}
+`)
+		var buf bytes.Buffer
+		require.NoError(t, gcexportdata.Write(&buf, gotoken.NewFileSet(), pkg))
+
+		pkgDoc, err := NewGoFromExportData("example.com/bar", &buf)
+		require.NoError(t, err)
+		assert.Contains(t, pkgDoc.Funcs, "XGot_Game_Run")
+		require.Contains(t, pkgDoc.Types, "Game")
+		assert.Contains(t, pkgDoc.Types["Game"].Methods, "Run")
+	})
+
+	t.Run("InvalidExportData", func(t *testing.T) {
+		_, err := NewGoFromExportData("example.com/foo", strings.NewReader("invalid export data"))
+		assert.ErrorContains(t, err, "failed to read package export data")
+	})
+
+	t.Run("InvalidArchive", func(t *testing.T) {
+		_, err := NewGoFromExportData("example.com/foo", strings.NewReader("!<arch>\ninvalid"))
+		assert.ErrorContains(t, err, "failed to create package export reader")
+	})
+}
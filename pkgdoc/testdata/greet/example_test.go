@@ -0,0 +1,15 @@
+package greet_test
+
+import "example.com/greet"
+
+func ExamplePrintln() {
+	// Greet the world.
+	greet.Println("world")
+	// Output: Hello, world
+}
+
+func ExampleGreeter_Greet() {
+	g := greet.Greeter{Prefix: "Hi"}
+	g.Greet("world")
+	// Output: Hi world
+}
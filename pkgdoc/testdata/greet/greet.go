@@ -0,0 +1,20 @@
+// Package greet prints greetings.
+package greet
+
+import "fmt"
+
+// Greeter prints greetings with a prefix.
+type Greeter struct {
+	// Prefix is printed before each name.
+	Prefix string
+}
+
+// Greet prints a greeting for name.
+func (g Greeter) Greet(name string) {
+	fmt.Println(g.Prefix, name)
+}
+
+// Println prints a greeting for name.
+func Println(name string) {
+	fmt.Println("Hello,", name)
+}
@@ -0,0 +1,6 @@
+//go:build ignore
+
+// This file belongs to another package and is ignored by NewGoFromDir.
+package main
+
+func main() {}
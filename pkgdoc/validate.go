@@ -0,0 +1,185 @@
+/*
+ * Copyright (c) 2025 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pkgdoc
+
+import (
+	"fmt"
+	gotypes "go/types"
+	"maps"
+	"slices"
+
+	"github.com/goplus/xgolsw/xgo/xgoutil"
+)
+
+// ValidationErrorKind is the kind of a [ValidationError].
+type ValidationErrorKind string
+
+// ValidationErrorKind constants.
+const (
+	// ValidationErrorMissing means that a documented symbol does not exist in
+	// the package.
+	ValidationErrorMissing ValidationErrorKind = "missing"
+
+	// ValidationErrorWrongKind means that a documented symbol exists in the
+	// package but is of a different kind, e.g., a function documented as a
+	// type.
+	ValidationErrorWrongKind ValidationErrorKind = "wrongKind"
+)
+
+// ValidationError describes a discrepancy found by [Validate].
+type ValidationError struct {
+	Kind ValidationErrorKind
+
+	// Name is the documented symbol in the notation of [PkgDoc.Diff], e.g.,
+	// `Funcs["F"]` or `Types["T"].Methods["M"]`.
+	Name string
+
+	Message string
+}
+
+// Error implements [error].
+func (e ValidationError) Error() string {
+	return e.Name + ": " + e.Message
+}
+
+// Validate cross-references the names in doc against the scope of pkg and
+// returns the discrepancies. It checks the names in [PkgDoc.Vars],
+// [PkgDoc.Consts], [PkgDoc.Funcs], and [PkgDoc.Types], in that order and by
+// name within each, as well as the fields and methods of every documented
+// type. Methods of XGo packages that are implemented as XGo
+// template methods, e.g., `XGot_Game_Foo`, are accepted as methods of their
+// receiver types.
+func Validate(doc *PkgDoc, pkg *gotypes.Package) []ValidationError {
+	if doc == nil || pkg == nil {
+		return nil
+	}
+	v := &pkgDocValidator{
+		pkg:         pkg,
+		xgotMethods: make(map[[2]string]bool),
+	}
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		if recvTypeName, methodName, ok := xgoutil.SplitXGotMethodName(name, true); ok {
+			v.xgotMethods[[2]string{recvTypeName, methodName}] = true
+		}
+	}
+
+	v.objects("Vars", doc.Vars, "variable", isVarObject)
+	v.objects("Consts", doc.Consts, "constant", isConstObject)
+	v.objects("Funcs", doc.Funcs, "function", isFuncObject)
+	for _, typeName := range slices.Sorted(maps.Keys(doc.Types)) {
+		symbol := fmt.Sprintf("Types[%q]", typeName)
+		obj := scope.Lookup(typeName)
+		switch {
+		case obj == nil:
+			v.add(ValidationErrorMissing, symbol, fmt.Sprintf("type %s not found in package %s", typeName, pkg.Path()))
+			continue
+		case !isTypeObject(obj):
+			v.add(ValidationErrorWrongKind, symbol, fmt.Sprintf("%s is not a type", typeName))
+			continue
+		}
+		if typeDoc := doc.Types[typeName]; typeDoc != nil {
+			v.typeMembers(symbol, obj.(*gotypes.TypeName), typeDoc)
+		}
+	}
+	return v.errs
+}
+
+// pkgDocValidator accumulates the results of [Validate].
+type pkgDocValidator struct {
+	pkg         *gotypes.Package
+	xgotMethods map[[2]string]bool // Keys are {recvTypeName, methodName}.
+	errs        []ValidationError
+}
+
+// add adds a validation error for symbol.
+func (v *pkgDocValidator) add(kind ValidationErrorKind, symbol, msg string) {
+	v.errs = append(v.errs, ValidationError{Kind: kind, Name: symbol, Message: msg})
+}
+
+// objects validates the package-level names in docs, each of which must refer
+// to an object accepted by isKind.
+func (v *pkgDocValidator) objects(field string, docs map[string]string, kindName string, isKind func(gotypes.Object) bool) {
+	for _, name := range slices.Sorted(maps.Keys(docs)) {
+		symbol := fmt.Sprintf("%s[%q]", field, name)
+		obj := v.pkg.Scope().Lookup(name)
+		switch {
+		case obj == nil:
+			v.add(ValidationErrorMissing, symbol, fmt.Sprintf("%s %s not found in package %s", kindName, name, v.pkg.Path()))
+		case !isKind(obj):
+			v.add(ValidationErrorWrongKind, symbol, fmt.Sprintf("%s is not a %s", name, kindName))
+		}
+	}
+}
+
+// typeMembers validates the fields and methods documented in typeDoc against
+// the type named by typeName.
+func (v *pkgDocValidator) typeMembers(symbol string, typeName *gotypes.TypeName, typeDoc *TypeDoc) {
+	for _, name := range slices.Sorted(maps.Keys(typeDoc.Fields)) {
+		fieldSymbol := fmt.Sprintf("%s.Fields[%q]", symbol, name)
+		obj, _, _ := gotypes.LookupFieldOrMethod(typeName.Type(), true, v.pkg, name)
+		switch {
+		case obj == nil:
+			v.add(ValidationErrorMissing, fieldSymbol, fmt.Sprintf("field %s.%s not found", typeName.Name(), name))
+		case !isFieldObject(obj):
+			v.add(ValidationErrorWrongKind, fieldSymbol, fmt.Sprintf("%s.%s is not a field", typeName.Name(), name))
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(typeDoc.Methods)) {
+		if v.xgotMethods[[2]string{typeName.Name(), name}] {
+			continue
+		}
+		methodSymbol := fmt.Sprintf("%s.Methods[%q]", symbol, name)
+		obj, _, _ := gotypes.LookupFieldOrMethod(typeName.Type(), true, v.pkg, name)
+		switch {
+		case obj == nil:
+			v.add(ValidationErrorMissing, methodSymbol, fmt.Sprintf("method %s.%s not found", typeName.Name(), name))
+		case !isFuncObject(obj):
+			v.add(ValidationErrorWrongKind, methodSymbol, fmt.Sprintf("%s.%s is not a method", typeName.Name(), name))
+		}
+	}
+}
+
+// isVarObject reports whether obj is a package-level variable.
+func isVarObject(obj gotypes.Object) bool {
+	_, ok := obj.(*gotypes.Var)
+	return ok
+}
+
+// isConstObject reports whether obj is a constant.
+func isConstObject(obj gotypes.Object) bool {
+	_, ok := obj.(*gotypes.Const)
+	return ok
+}
+
+// isFuncObject reports whether obj is a function or method.
+func isFuncObject(obj gotypes.Object) bool {
+	_, ok := obj.(*gotypes.Func)
+	return ok
+}
+
+// isTypeObject reports whether obj is a type name.
+func isTypeObject(obj gotypes.Object) bool {
+	_, ok := obj.(*gotypes.TypeName)
+	return ok
+}
+
+// isFieldObject reports whether obj is a struct field.
+func isFieldObject(obj gotypes.Object) bool {
+	field, ok := obj.(*gotypes.Var)
+	return ok && field.IsField()
+}
@@ -0,0 +1,124 @@
+/*
+ * Copyright (c) 2025 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pkgdoc
+
+import (
+	goast "go/ast"
+	goparser "go/parser"
+	gotoken "go/token"
+	gotypes "go/types"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// checkGoPackage type-checks the Go source src as a package with the given
+// path.
+func checkGoPackage(t *testing.T, pkgPath, src string) *gotypes.Package {
+	t.Helper()
+	fset := gotoken.NewFileSet()
+	file, err := goparser.ParseFile(fset, "pkg.go", src, 0)
+	require.NoError(t, err)
+	pkg, err := new(gotypes.Config).Check(pkgPath, fset, []*goast.File{file}, nil)
+	require.NoError(t, err)
+	return pkg
+}
+
+func TestValidate(t *testing.T) {
+	pkg := checkGoPackage(t, "example.com/foo", `package foo
+
+var V int
+
+const C = 1
+
+func F() {}
+
+type T struct{ Field int }
+
+func (T) M() {}
+`)
+
+	t.Run("Valid", func(t *testing.T) {
+		doc := &PkgDoc{
+			Vars:   map[string]string{"V": ""},
+			Consts: map[string]string{"C": ""},
+			Funcs:  map[string]string{"F": ""},
+			Types: map[string]*TypeDoc{
+				"T": {
+					Fields:  map[string]string{"Field": ""},
+					Methods: map[string]string{"M": ""},
+				},
+			},
+		}
+		assert.Empty(t, Validate(doc, pkg))
+	})
+
+	t.Run("MissingFunc", func(t *testing.T) {
+		doc := &PkgDoc{
+			Funcs: map[string]string{"F": "", "nonExistent": ""},
+		}
+		errs := Validate(doc, pkg)
+		require.Len(t, errs, 1)
+		assert.Equal(t, ValidationErrorMissing, errs[0].Kind)
+		assert.Equal(t, `Funcs["nonExistent"]`, errs[0].Name)
+		assert.Equal(t, `Funcs["nonExistent"]: function nonExistent not found in package example.com/foo`, errs[0].Error())
+	})
+
+	t.Run("WrongKind", func(t *testing.T) {
+		doc := &PkgDoc{
+			Funcs: map[string]string{"V": ""},
+			Types: map[string]*TypeDoc{
+				"T": {Methods: map[string]string{"Field": ""}},
+			},
+		}
+		errs := Validate(doc, pkg)
+		require.Len(t, errs, 2)
+		assert.Equal(t, ValidationError{
+			Kind:    ValidationErrorWrongKind,
+			Name:    `Funcs["V"]`,
+			Message: "V is not a function",
+		}, errs[0])
+		assert.Equal(t, ValidationError{
+			Kind:    ValidationErrorWrongKind,
+			Name:    `Types["T"].Methods["Field"]`,
+			Message: "T.Field is not a method",
+		}, errs[1])
+	})
+
+	t.Run("XGotMethod", func(t *testing.T) {
+		pkg := checkGoPackage(t, "example.com/bar", `package bar
+
+const XGoPackage = true
+
+type Game struct{}
+
+func XGot_Game_Run(g *Game) {}
+`)
+		doc := &PkgDoc{
+			Types: map[string]*TypeDoc{
+				"Game": {Methods: map[string]string{"Run": ""}},
+			},
+		}
+		assert.Empty(t, Validate(doc, pkg))
+	})
+
+	t.Run("Nil", func(t *testing.T) {
+		assert.Nil(t, Validate(nil, pkg))
+		assert.Nil(t, Validate(&PkgDoc{}, nil))
+	})
+}
@@ -94,9 +94,30 @@ func NewXGo(pkgPath string, pkg *ast.Package) *PkgDoc {
 							}
 						}
 					case *ast.TypeSpec:
+						if interfaceType, ok := spec.Type.(*ast.InterfaceType); ok {
+							typeDoc := pkgDoc.typeDoc(spec.Name.Name)
+							typeDoc.Doc = doc
+							typeDoc.Deprecated = DeprecationNotice(doc)
+							typeDoc.TypeParams = xgoTypeParamNames(spec.TypeParams)
+							for _, method := range interfaceType.Methods.List {
+								if len(method.Names) == 0 {
+									continue
+								}
+
+								methodDoc := ""
+								if method.Doc != nil {
+									methodDoc = method.Doc.Text()
+								} else if method.Comment != nil {
+									methodDoc = method.Comment.Text()
+								}
+								typeDoc.Methods[method.Names[0].Name] = methodDoc
+							}
+						}
 						if structType, ok := spec.Type.(*ast.StructType); ok {
 							typeDoc := pkgDoc.typeDoc(spec.Name.Name)
 							typeDoc.Doc = doc
+							typeDoc.Deprecated = DeprecationNotice(doc)
+							typeDoc.TypeParams = xgoTypeParamNames(spec.TypeParams)
 							for _, field := range structType.Fields.List {
 								fieldDoc := ""
 								if field.Doc != nil {
@@ -150,3 +171,14 @@ func NewXGo(pkgPath string, pkg *ast.Package) *PkgDoc {
 
 	return pkgDoc
 }
+
+// xgoTypeParamNames returns the names of the type parameters in the given XGo
+// type parameter list, or nil if there are none.
+func xgoTypeParamNames(typeParams *ast.FieldList) []string {
+	if typeParams == nil {
+		return nil
+	}
+	return typeParamNames(typeParams.List, func(field *ast.Field) []*ast.Ident {
+		return field.Names
+	})
+}
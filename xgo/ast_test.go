@@ -155,6 +155,40 @@ func Test() {
 		assert.Error(t, err)
 		assert.Nil(t, astFile)
 	})
+
+	t.Run("ParsesOnlyRequestedFile", func(t *testing.T) {
+		proj := NewProject(nil, map[string]*File{
+			"main.spx":   file(`var x int`),
+			"Sprite.spx": file(`var y int`),
+		}, FeatAll)
+
+		astFile, err := proj.ASTFile("main.spx")
+		require.NoError(t, err)
+		require.NotNil(t, astFile)
+		assert.Equal(t, uint64(1), proj.CacheMetrics().File.Builds)
+	})
+
+	t.Run("InvalidatesOnlyModifiedFile", func(t *testing.T) {
+		proj := NewProject(nil, map[string]*File{
+			"main.spx":   file(`var x int`),
+			"Sprite.spx": file(`var y int`),
+		}, FeatAll)
+
+		mainASTFile1, err := proj.ASTFile("main.spx")
+		require.NoError(t, err)
+		spriteASTFile1, err := proj.ASTFile("Sprite.spx")
+		require.NoError(t, err)
+
+		proj.PutFile("Sprite.spx", file(`var z int`))
+
+		mainASTFile2, err := proj.ASTFile("main.spx")
+		require.NoError(t, err)
+		spriteASTFile2, err := proj.ASTFile("Sprite.spx")
+		require.NoError(t, err)
+
+		assert.Same(t, mainASTFile1, mainASTFile2)
+		assert.NotSame(t, spriteASTFile1, spriteASTFile2)
+	})
 }
 
 func TestBuildASTPackageCache(t *testing.T) {
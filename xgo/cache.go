@@ -20,25 +20,132 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
+	"maps"
+	"slices"
+	"sync/atomic"
 )
 
 // ErrUnknownCacheKind represents an error of unknown cache kind.
 var ErrUnknownCacheKind = errors.New("unknown cache kind")
 
+// ErrCyclicCacheDep represents an error of a cache builder that directly or
+// indirectly depends on the cache it is building.
+var ErrCyclicCacheDep = errors.New("cyclic cache dependency")
+
 // CacheBuilder represents a project level cache builder.
+//
+// The project passed to a builder shares all state with the project the cache
+// is looked up from. Looking up the cache being built through it, directly or
+// through other builders, fails with [ErrCyclicCacheDep].
 type CacheBuilder = func(proj *Project) (any, error)
 
-// FileCacheBuilder represents a file level cache builder.
+// FileCacheBuilder represents a file level cache builder. The project passed
+// to it behaves as described for [CacheBuilder].
 type FileCacheBuilder = func(proj *Project, path string, file *File) (any, error)
 
 // CacheKind represents a kind of cache.
 type CacheKind = any
 
 // fileCacheKey represents a key for file-level cache entries.
-// It combines a cache kind with a file path to uniquely identify cached data.
+// It combines a cache kind with a file path, mod time, version and content
+// hash to uniquely identify cached data for each file content.
 type fileCacheKey struct {
-	kind CacheKind
-	path string
+	kind    CacheKind
+	path    string
+	modTime int64
+	version int
+	hash    [16]byte
+}
+
+// newFileCacheKey creates a [fileCacheKey] for the given file.
+func newFileCacheKey(kind CacheKind, path string, file *File) fileCacheKey {
+	return fileCacheKey{
+		kind:    kind,
+		path:    path,
+		modTime: file.ModTime.UnixNano(),
+		version: file.Version,
+		hash:    file.Hash(),
+	}
+}
+
+// CacheCounters holds the counters of cache lookups of a single cache level.
+type CacheCounters struct {
+	// Hits is the number of lookups served from an existing cache entry.
+	Hits uint64 `json:"hits"`
+
+	// Misses is the number of lookups that found no cache entry and did not
+	// build it themselves, e.g., because another concurrent lookup was
+	// already building it.
+	Misses uint64 `json:"misses"`
+
+	// Builds is the number of times a cache builder was run.
+	Builds uint64 `json:"builds"`
+
+	// Errors is the number of lookups that returned an error, including
+	// errors served from the cache.
+	Errors uint64 `json:"errors"`
+}
+
+// CacheMetrics holds the cache counters of a [Project].
+type CacheMetrics struct {
+	// Project holds the counters of the project level caches.
+	Project CacheCounters `json:"project"`
+
+	// File holds the counters of the file level caches.
+	File CacheCounters `json:"file"`
+}
+
+// cacheCounters is the atomic counterpart of [CacheCounters].
+type cacheCounters struct {
+	hits   atomic.Uint64
+	misses atomic.Uint64
+	builds atomic.Uint64
+	errors atomic.Uint64
+}
+
+// load returns the current values of the counters.
+func (c *cacheCounters) load() CacheCounters {
+	return CacheCounters{
+		Hits:   c.hits.Load(),
+		Misses: c.misses.Load(),
+		Builds: c.builds.Load(),
+		Errors: c.errors.Load(),
+	}
+}
+
+// reset resets all counters to zero.
+func (c *cacheCounters) reset() {
+	c.hits.Store(0)
+	c.misses.Store(0)
+	c.builds.Store(0)
+	c.errors.Store(0)
+}
+
+// record records the result of a cache lookup that was not a hit.
+func (c *cacheCounters) record(built bool, err error) {
+	if !built {
+		c.misses.Add(1)
+	}
+	if err != nil {
+		c.errors.Add(1)
+	}
+}
+
+// CacheMetrics returns the cache counters accumulated by [Project.Cache] and
+// [Project.FileCache] since the project was created or
+// [Project.ResetCacheMetrics] was last called. Snapshots and clones of the
+// project start with zero counters.
+func (p *Project) CacheMetrics() CacheMetrics {
+	return CacheMetrics{
+		Project: p.cacheCounters.load(),
+		File:    p.fileCacheCounters.load(),
+	}
+}
+
+// ResetCacheMetrics resets all counters returned by [Project.CacheMetrics].
+func (p *Project) ResetCacheMetrics() {
+	p.cacheCounters.reset()
+	p.fileCacheCounters.reset()
 }
 
 // RegisterCacheBuilder registers a project level cache builder.
@@ -53,6 +160,18 @@ func (p *Project) RegisterCacheBuilder(kind CacheKind, builder func(root *Projec
 	p.mu.Lock()
 	defer p.mu.Unlock()
 	p.cacheBuilders[kind] = builder
+	delete(p.assetCacheKinds, kind)
+}
+
+// RegisterAssetCacheBuilder registers a project level cache builder like
+// [Project.RegisterCacheBuilder], except that the cache depends only on asset
+// files (see [Project.AssetFiles]). It is therefore kept when other files
+// change, and rebuilt only after an asset file changes.
+func (p *Project) RegisterAssetCacheBuilder(kind CacheKind, builder func(root *Project) (any, error)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cacheBuilders[kind] = builder
+	p.assetCacheKinds[kind] = struct{}{}
 }
 
 // RegisterFileCacheBuilder registers a file level cache builder.
@@ -77,10 +196,22 @@ func (p *Project) Cache(kind CacheKind) (any, error) {
 	v, ok := p.caches[kind]
 	p.mu.RUnlock()
 	if ok {
-		return decodeDataOrErr(v)
+		p.cacheCounters.hits.Add(1)
+		data, err := decodeDataOrErr(v)
+		if err != nil {
+			p.cacheCounters.errors.Add(1)
+		}
+		return data, err
+	}
+
+	sfgKey := fmt.Sprintf("%T-%v", kind, kind)
+	if slices.Contains(p.cacheBuildKeys, sfgKey) {
+		p.cacheCounters.errors.Add(1)
+		return nil, ErrCyclicCacheDep
 	}
 
-	data, err, _ := p.cacheSFG.Do(fmt.Sprintf("%T-%v", kind, kind), func() (any, error) {
+	var built bool
+	data, err, _ := p.cacheSFG.Do(sfgKey, func() (any, error) {
 		p.mu.RLock()
 		builder, ok := p.cacheBuilders[kind]
 		p.mu.RUnlock()
@@ -88,7 +219,9 @@ func (p *Project) Cache(kind CacheKind) (any, error) {
 			return nil, ErrUnknownCacheKind
 		}
 
-		data, err := builder(p)
+		built = true
+		p.cacheCounters.builds.Add(1)
+		data, err := builder(p.cacheBuildView(sfgKey))
 
 		p.mu.Lock()
 		p.caches[kind] = encodeDataOrErr(data, err)
@@ -96,6 +229,7 @@ func (p *Project) Cache(kind CacheKind) (any, error) {
 
 		return data, err
 	})
+	p.cacheCounters.record(built, err)
 	return data, err
 }
 
@@ -103,28 +237,43 @@ func (p *Project) Cache(kind CacheKind) (any, error) {
 //
 // The kind must be the same comparable value that was used with [Project.RegisterFileCacheBuilder].
 func (p *Project) FileCache(kind CacheKind, path string) (any, error) {
-	key := fileCacheKey{kind, path}
+	p.mu.RLock()
+	builder, ok := p.fileCacheBuilders[kind]
+	file, fileExists := p.files[path]
+	p.mu.RUnlock()
+	if !ok {
+		p.fileCacheCounters.record(false, ErrUnknownCacheKind)
+		return nil, ErrUnknownCacheKind
+	}
+	if !fileExists {
+		p.fileCacheCounters.record(false, fs.ErrNotExist)
+		return nil, fs.ErrNotExist
+	}
+	key := newFileCacheKey(kind, path, file)
 
 	p.mu.RLock()
 	v, ok := p.fileCaches[key]
 	p.mu.RUnlock()
 	if ok {
-		return decodeDataOrErr(v)
+		p.fileCacheCounters.hits.Add(1)
+		data, err := decodeDataOrErr(v)
+		if err != nil {
+			p.fileCacheCounters.errors.Add(1)
+		}
+		return data, err
 	}
 
-	data, err, _ := p.fileCacheSFG.Do(fmt.Sprintf("%T-%v-%s", kind, kind, path), func() (any, error) {
-		p.mu.RLock()
-		builder, ok := p.fileCacheBuilders[kind]
-		file, fileExists := p.files[path]
-		p.mu.RUnlock()
-		if !ok {
-			return nil, ErrUnknownCacheKind
-		}
-		if !fileExists {
-			return nil, fs.ErrNotExist
-		}
+	sfgKey := fmt.Sprintf("%T-%v-%s-%d-%d-%x", kind, kind, path, key.modTime, key.version, key.hash)
+	if slices.Contains(p.cacheBuildKeys, sfgKey) {
+		p.fileCacheCounters.errors.Add(1)
+		return nil, ErrCyclicCacheDep
+	}
 
-		data, err := builder(p, path, file)
+	var built bool
+	data, err, _ := p.fileCacheSFG.Do(sfgKey, func() (any, error) {
+		built = true
+		p.fileCacheCounters.builds.Add(1)
+		data, err := builder(p.cacheBuildView(sfgKey), path, file)
 
 		p.mu.Lock()
 		p.fileCaches[key] = encodeDataOrErr(data, err)
@@ -132,16 +281,42 @@ func (p *Project) FileCache(kind CacheKind, path string) (any, error) {
 
 		return data, err
 	})
+	p.fileCacheCounters.record(built, err)
 	return data, err
 }
 
+// cacheBuildView returns a view of the project to pass to the builder of the
+// cache identified by key. The view shares all state with p, and additionally
+// records key so that lookups of the same cache made through the view, i.e.,
+// from within its own builder, fail with [ErrCyclicCacheDep] instead of
+// deadlocking.
+func (p *Project) cacheBuildView(key string) *Project {
+	return &Project{
+		PkgPath:        p.PkgPath,
+		Mod:            p.Mod,
+		Importer:       p.Importer,
+		Fset:           p.Fset,
+		feats:          p.feats,
+		projectState:   p.projectState,
+		cacheBuildKeys: append(slices.Clip(p.cacheBuildKeys), key),
+	}
+}
+
 // deleteFileCache deletes file-specific caches for the given path. It also
-// clears project-level caches implicitly if necessary.
+// clears project-level caches implicitly if necessary. Caches registered with
+// [Project.RegisterAssetCacheBuilder] are kept unless path is an asset file.
 func (p *Project) deleteFileCache(path string) {
-	clear(p.caches)
-	for kind := range p.fileCacheBuilders {
-		delete(p.fileCaches, fileCacheKey{kind, path})
+	if isAssetFile(path) {
+		clear(p.caches)
+	} else {
+		maps.DeleteFunc(p.caches, func(kind CacheKind, _ dataOrErr) bool {
+			_, ok := p.assetCacheKinds[kind]
+			return !ok
+		})
 	}
+	maps.DeleteFunc(p.fileCaches, func(key fileCacheKey, _ dataOrErr) bool {
+		return key.path == path
+	})
 }
 
 // dataOrErr represents a data or an error.
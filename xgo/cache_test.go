@@ -159,6 +159,65 @@ func TestProjectRegisterCacheBuilder(t *testing.T) {
 	})
 }
 
+func TestProjectRegisterAssetCacheBuilder(t *testing.T) {
+	proj := NewProject(nil, map[string]*File{
+		"main.spx":          file(""),
+		"assets/index.json": file("{}"),
+	}, 0)
+
+	type testAssetCacheKind struct{}
+	type testCacheKind struct{}
+
+	var assetBuildCount, buildCount int
+	proj.RegisterAssetCacheBuilder(testAssetCacheKind{}, func(p *Project) (any, error) {
+		assetBuildCount++
+		return assetBuildCount, nil
+	})
+	proj.RegisterCacheBuilder(testCacheKind{}, func(p *Project) (any, error) {
+		buildCount++
+		return buildCount, nil
+	})
+	buildCaches := func() {
+		_, err := proj.Cache(testAssetCacheKind{})
+		require.NoError(t, err)
+		_, err = proj.Cache(testCacheKind{})
+		require.NoError(t, err)
+	}
+
+	buildCaches()
+	buildCaches()
+	assert.Equal(t, 1, assetBuildCount)
+	assert.Equal(t, 1, buildCount)
+
+	// Changing a non-asset file keeps the asset cache.
+	proj.PutFile("main.spx", file("echo 1"))
+	buildCaches()
+	assert.Equal(t, 1, assetBuildCount)
+	assert.Equal(t, 2, buildCount)
+
+	// Changing an asset file rebuilds both caches.
+	proj.PutFile("assets/index.json", file(`{"zorder":[]}`))
+	buildCaches()
+	assert.Equal(t, 2, assetBuildCount)
+	assert.Equal(t, 3, buildCount)
+
+	// Asset caches are carried over to snapshots.
+	snapshot := proj.Snapshot()
+	snapshot.PutFile("main.spx", file("echo 2"))
+	data, err := snapshot.Cache(testAssetCacheKind{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, data)
+
+	// Re-registering the kind with RegisterCacheBuilder makes it a normal cache.
+	proj.RegisterCacheBuilder(testAssetCacheKind{}, func(p *Project) (any, error) {
+		return "normal", nil
+	})
+	proj.PutFile("main.spx", file("echo 3"))
+	data, err = proj.Cache(testAssetCacheKind{})
+	require.NoError(t, err)
+	assert.Equal(t, "normal", data)
+}
+
 func TestProjectRegisterFileCacheBuilder(t *testing.T) {
 	t.Run("RegisterNewFileCacheBuilder", func(t *testing.T) {
 		proj := NewProject(nil, nil, 0)
@@ -545,6 +604,123 @@ func TestProjectCache(t *testing.T) {
 		require.NoError(t, err2)
 		assert.Equal(t, "types-data", data2)
 	})
+
+	t.Run("CyclicDependency", func(t *testing.T) {
+		proj := NewProject(nil, nil, 0)
+
+		type testCacheKind struct{}
+
+		// Register cache builder that depends on its own cache.
+		var innerErr error
+		proj.RegisterCacheBuilder(testCacheKind{}, func(p *Project) (any, error) {
+			_, innerErr = p.Cache(testCacheKind{})
+			return nil, innerErr
+		})
+
+		done := make(chan struct{})
+		var data any
+		var err error
+		go func() {
+			defer close(done)
+			data, err = proj.Cache(testCacheKind{})
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("cache lookup deadlocked")
+		}
+		assert.ErrorIs(t, innerErr, ErrCyclicCacheDep)
+		assert.ErrorIs(t, err, ErrCyclicCacheDep)
+		assert.Nil(t, data)
+	})
+
+	t.Run("IndirectCyclicDependency", func(t *testing.T) {
+		proj := NewProject(nil, nil, 0)
+
+		type testCacheKind1 struct{}
+		type testCacheKind2 struct{}
+
+		// Register cache builders that depend on each other.
+		proj.RegisterCacheBuilder(testCacheKind1{}, func(p *Project) (any, error) {
+			return p.Cache(testCacheKind2{})
+		})
+		proj.RegisterCacheBuilder(testCacheKind2{}, func(p *Project) (any, error) {
+			return p.Cache(testCacheKind1{})
+		})
+
+		_, err := proj.Cache(testCacheKind1{})
+		assert.ErrorIs(t, err, ErrCyclicCacheDep)
+	})
+
+	t.Run("ConcurrentLookupsAreNotCyclic", func(t *testing.T) {
+		proj := NewProject(nil, nil, 0)
+
+		type testCacheKind struct{}
+
+		// Register cache builder that blocks until released.
+		release := make(chan struct{})
+		proj.RegisterCacheBuilder(testCacheKind{}, func(p *Project) (any, error) {
+			<-release
+			return "cached-data", nil
+		})
+
+		var wg sync.WaitGroup
+		errs := make([]error, 2)
+		for i := range errs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, errs[i] = proj.Cache(testCacheKind{})
+			}()
+		}
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+		wg.Wait()
+		for _, err := range errs {
+			assert.NoError(t, err)
+		}
+	})
+
+	t.Run("ConcurrentSharedDependencyIsNotCyclic", func(t *testing.T) {
+		proj := NewProject(nil, nil, 0)
+
+		type sharedCacheKind struct{}
+		type testCacheKind1 struct{}
+		type testCacheKind2 struct{}
+
+		// Register cache builders that both depend on a shared cache, which
+		// blocks until released.
+		release := make(chan struct{})
+		proj.RegisterCacheBuilder(sharedCacheKind{}, func(p *Project) (any, error) {
+			<-release
+			return "shared", nil
+		})
+		proj.RegisterCacheBuilder(testCacheKind1{}, func(p *Project) (any, error) {
+			return p.Cache(sharedCacheKind{})
+		})
+		proj.RegisterCacheBuilder(testCacheKind2{}, func(p *Project) (any, error) {
+			return p.Cache(sharedCacheKind{})
+		})
+
+		var wg sync.WaitGroup
+		results := make([]any, 2)
+		errs := make([]error, 2)
+		for i, kind := range []CacheKind{testCacheKind1{}, testCacheKind2{}} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i], errs[i] = proj.Cache(kind)
+			}()
+		}
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+		wg.Wait()
+		for i := range errs {
+			require.NoError(t, errs[i])
+			assert.Equal(t, "shared", results[i])
+		}
+		assert.Equal(t, uint64(3), proj.CacheMetrics().Project.Builds)
+	})
 }
 
 func TestProjectFileCache(t *testing.T) {
@@ -597,6 +773,43 @@ func TestProjectFileCache(t *testing.T) {
 		assert.Nil(t, data)
 	})
 
+	t.Run("FileCacheKeyedByContent", func(t *testing.T) {
+		proj := NewProject(nil, nil, 0)
+
+		type testCacheKind struct{}
+
+		var buildCount int
+		proj.RegisterFileCacheBuilder(testCacheKind{}, func(p *Project, path string, f *File) (any, error) {
+			buildCount++
+			return string(f.Content), nil
+		})
+
+		proj.PutFile("test.go", file("package test"))
+		data, err := proj.FileCache(testCacheKind{}, "test.go")
+		require.NoError(t, err)
+		assert.Equal(t, "package test", data)
+
+		// Replace the file without invalidating caches explicitly.
+		proj.mu.Lock()
+		proj.files["test.go"] = file("package test2")
+		proj.mu.Unlock()
+
+		data, err = proj.FileCache(testCacheKind{}, "test.go")
+		require.NoError(t, err)
+		assert.Equal(t, "package test2", data)
+		assert.Equal(t, 2, buildCount)
+
+		// The same content with a zero mod time reuses the cache.
+		proj.mu.Lock()
+		proj.files["test.go"] = file("package test2")
+		proj.mu.Unlock()
+
+		data, err = proj.FileCache(testCacheKind{}, "test.go")
+		require.NoError(t, err)
+		assert.Equal(t, "package test2", data)
+		assert.Equal(t, 2, buildCount)
+	})
+
 	t.Run("FileCacheIsReused", func(t *testing.T) {
 		proj := NewProject(nil, nil, 0)
 
@@ -840,6 +1053,22 @@ func TestProjectFileCache(t *testing.T) {
 		require.NoError(t, err2)
 		assert.Equal(t, "filedata2", data2)
 	})
+
+	t.Run("CyclicDependency", func(t *testing.T) {
+		proj := NewProject(nil, nil, 0)
+
+		type testCacheKind struct{}
+
+		// Register file cache builder that depends on its own cache.
+		proj.RegisterFileCacheBuilder(testCacheKind{}, func(p *Project, path string, f *File) (any, error) {
+			return p.FileCache(testCacheKind{}, path)
+		})
+		proj.PutFile("test.go", file("package test"))
+
+		data, err := proj.FileCache(testCacheKind{}, "test.go")
+		assert.ErrorIs(t, err, ErrCyclicCacheDep)
+		assert.Nil(t, data)
+	})
 }
 
 func TestProjectDeleteFileCache(t *testing.T) {
@@ -1098,3 +1327,87 @@ func TestDataOrErr(t *testing.T) {
 		}
 	})
 }
+
+func TestProjectCacheMetrics(t *testing.T) {
+	t.Run("Cache", func(t *testing.T) {
+		proj := NewProject(nil, nil, 0)
+
+		type testCacheKind struct{}
+		proj.RegisterCacheBuilder(testCacheKind{}, func(p *Project) (any, error) {
+			return "test-data", nil
+		})
+
+		for range 2 {
+			_, err := proj.Cache(testCacheKind{})
+			require.NoError(t, err)
+		}
+		assert.Equal(t, CacheMetrics{
+			Project: CacheCounters{Hits: 1, Misses: 0, Builds: 1},
+		}, proj.CacheMetrics())
+	})
+
+	t.Run("CacheErrors", func(t *testing.T) {
+		proj := NewProject(nil, nil, 0)
+
+		type testCacheKind struct{}
+		proj.RegisterCacheBuilder(testCacheKind{}, func(p *Project) (any, error) {
+			return nil, fs.ErrInvalid
+		})
+
+		for range 2 {
+			_, err := proj.Cache(testCacheKind{})
+			require.ErrorIs(t, err, fs.ErrInvalid)
+		}
+		_, err := proj.Cache("unknown")
+		require.ErrorIs(t, err, ErrUnknownCacheKind)
+		assert.Equal(t, CacheMetrics{
+			Project: CacheCounters{Hits: 1, Misses: 1, Builds: 1, Errors: 3},
+		}, proj.CacheMetrics())
+	})
+
+	t.Run("FileCache", func(t *testing.T) {
+		proj := NewProject(nil, map[string]*File{"main.spx": file("echo 1")}, 0)
+
+		type testCacheKind struct{}
+		proj.RegisterFileCacheBuilder(testCacheKind{}, func(p *Project, path string, file *File) (any, error) {
+			return string(file.Content), nil
+		})
+
+		for range 2 {
+			_, err := proj.FileCache(testCacheKind{}, "main.spx")
+			require.NoError(t, err)
+		}
+		_, err := proj.FileCache(testCacheKind{}, "missing.spx")
+		require.ErrorIs(t, err, fs.ErrNotExist)
+		assert.Equal(t, CacheMetrics{
+			File: CacheCounters{Hits: 1, Misses: 1, Builds: 1, Errors: 1},
+		}, proj.CacheMetrics())
+
+		// Changing the file invalidates its cache entry.
+		proj.PutFile("main.spx", file("echo 2"))
+		_, err = proj.FileCache(testCacheKind{}, "main.spx")
+		require.NoError(t, err)
+		assert.Equal(t, uint64(2), proj.CacheMetrics().File.Builds)
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		proj := NewProject(nil, nil, 0)
+
+		type testCacheKind struct{}
+		proj.RegisterCacheBuilder(testCacheKind{}, func(p *Project) (any, error) {
+			return "test-data", nil
+		})
+		_, err := proj.Cache(testCacheKind{})
+		require.NoError(t, err)
+		require.NotZero(t, proj.CacheMetrics())
+
+		proj.ResetCacheMetrics()
+		assert.Zero(t, proj.CacheMetrics())
+
+		_, err = proj.Cache(testCacheKind{})
+		require.NoError(t, err)
+		assert.Equal(t, CacheMetrics{
+			Project: CacheCounters{Hits: 1},
+		}, proj.CacheMetrics())
+	})
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright (c) 2025 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package xgo
+
+import (
+	"errors"
+
+	"github.com/goplus/xgo/token"
+	"github.com/goplus/xgo/x/typesutil"
+	xerrors "github.com/qiniu/x/errors"
+)
+
+// Diagnostic is a type checking diagnostic of an XGo project.
+type Diagnostic struct {
+	Pos, End token.Pos // Diagnostic position.
+	Msg      string    // Diagnostic message.
+	Soft     bool      // Whether the diagnostic is a "soft" error.
+}
+
+// diagnosticsCacheKind is a cache kind type for diagnostics.
+type diagnosticsCacheKind struct{}
+
+// diagnosticsCache is a cache for diagnostics.
+type diagnosticsCache struct {
+	diagnostics []Diagnostic
+}
+
+// buildDiagnosticsCache implements [CacheBuilder] to build a
+// [diagnosticsCache] for the provided XGo project.
+func buildDiagnosticsCache(proj *Project) (any, error) {
+	diags, err := typeCheckDiagnostics(proj)
+	if err != nil {
+		return nil, err
+	}
+	return &diagnosticsCache{diags}, nil
+}
+
+// typeCheckDiagnostics collects the [typesutil.Error] values reported while
+// type checking the provided XGo project.
+func typeCheckDiagnostics(proj *Project) ([]Diagnostic, error) {
+	typeInfo, checkerErr := proj.TypeInfo()
+	if typeInfo == nil {
+		return nil, checkerErr
+	}
+
+	var errs []error
+	if errList, ok := checkerErr.(xerrors.List); ok {
+		errs = errList
+	} else if checkerErr != nil {
+		errs = []error{checkerErr}
+	}
+
+	var diags []Diagnostic
+	for _, err := range errs {
+		var typeErr typesutil.Error
+		if !errors.As(err, &typeErr) {
+			continue
+		}
+		diags = append(diags, Diagnostic{
+			Pos:  typeErr.Pos,
+			End:  typeErr.End,
+			Msg:  typeErr.Msg,
+			Soft: typeErr.Soft,
+		})
+	}
+	return diags, nil
+}
+
+// Diagnostics retrieves the type checking diagnostics from the project. If
+// the project was created without [FeatDiagnosticsCache], the diagnostics are
+// collected from [Project.TypeInfo] on each call.
+func (p *Project) Diagnostics() ([]Diagnostic, error) {
+	cacheIface, err := p.Cache(diagnosticsCacheKind{})
+	if errors.Is(err, ErrUnknownCacheKind) {
+		return typeCheckDiagnostics(p)
+	}
+	if err != nil {
+		return nil, err
+	}
+	cache := cacheIface.(*diagnosticsCache)
+	return cache.diagnostics, nil
+}
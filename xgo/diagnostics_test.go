@@ -0,0 +1,109 @@
+/*
+ * Copyright (c) 2025 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package xgo
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildDiagnosticsCache(t *testing.T) {
+	t.Run("TypeErrors", func(t *testing.T) {
+		proj := NewProject(nil, map[string]*File{
+			"main.spx": file(`var x int = "hello"`),
+		}, FeatAll)
+
+		cache, err := buildDiagnosticsCache(proj)
+		require.NoError(t, err)
+		require.IsType(t, &diagnosticsCache{}, cache)
+
+		diags := cache.(*diagnosticsCache).diagnostics
+		require.Len(t, diags, 1)
+		assert.Equal(t, "main.spx", proj.Fset.Position(diags[0].Pos).Filename)
+		assert.True(t, diags[0].End > diags[0].Pos)
+		assert.Contains(t, diags[0].Msg, `cannot use "hello"`)
+	})
+
+	t.Run("NoErrors", func(t *testing.T) {
+		proj := NewProject(nil, map[string]*File{
+			"main.spx": file(`var x int = 1`),
+		}, FeatAll)
+
+		cache, err := buildDiagnosticsCache(proj)
+		require.NoError(t, err)
+		assert.Empty(t, cache.(*diagnosticsCache).diagnostics)
+	})
+}
+
+func TestProjectDiagnostics(t *testing.T) {
+	t.Run("Cache", func(t *testing.T) {
+		proj := NewProject(nil, map[string]*File{
+			"main.spx": file(`echo undefinedVar`),
+		}, FeatAll)
+
+		var buildCount atomic.Int32
+		proj.RegisterCacheBuilder(diagnosticsCacheKind{}, func(proj *Project) (any, error) {
+			buildCount.Add(1)
+			return buildDiagnosticsCache(proj)
+		})
+
+		diags1, err := proj.Diagnostics()
+		require.NoError(t, err)
+		require.Len(t, diags1, 1)
+
+		diags2, err := proj.Diagnostics()
+		require.NoError(t, err)
+		assert.Equal(t, diags1, diags2)
+		assert.Equal(t, int32(1), buildCount.Load())
+
+		proj.PutFile("main.spx", file(`echo "hello"`))
+		diags3, err := proj.Diagnostics()
+		require.NoError(t, err)
+		assert.Empty(t, diags3)
+		assert.Equal(t, int32(2), buildCount.Load())
+	})
+
+	t.Run("WithoutDiagnosticsCache", func(t *testing.T) {
+		proj := NewProject(nil, map[string]*File{
+			"main.spx": file(`echo undefinedVar`),
+		}, FeatAll&^FeatDiagnosticsCache)
+
+		diags, err := proj.Diagnostics()
+		require.NoError(t, err)
+		require.Len(t, diags, 1)
+		assert.Contains(t, diags[0].Msg, "undefinedVar")
+	})
+
+	t.Run("CacheError", func(t *testing.T) {
+		proj := NewProject(nil, map[string]*File{
+			"main.spx": file(`var x int`),
+		}, FeatAll)
+
+		buildErr := errors.New("build failed")
+		proj.RegisterCacheBuilder(diagnosticsCacheKind{}, func(proj *Project) (any, error) {
+			return nil, buildErr
+		})
+
+		diags, err := proj.Diagnostics()
+		assert.ErrorIs(t, err, buildErr)
+		assert.Nil(t, diags)
+	})
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright (c) 2025 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package xgo
+
+import "slices"
+
+// ChangeKind is the kind of change made to a file in a [Project].
+type ChangeKind int
+
+const (
+	// Added means the file has been added.
+	Added ChangeKind = iota + 1
+
+	// Modified means the file content has been replaced.
+	Modified
+
+	// Removed means the file has been removed.
+	Removed
+)
+
+// String implements [fmt.Stringer].
+func (k ChangeKind) String() string {
+	switch k {
+	case Added:
+		return "Added"
+	case Modified:
+		return "Modified"
+	case Removed:
+		return "Removed"
+	}
+	return "Unknown"
+}
+
+// OnFilesChangedToken identifies a callback registered with
+// [Project.RegisterOnFilesChanged].
+type OnFilesChangedToken uint64
+
+// onFilesChangedCallback is a callback registered with
+// [Project.RegisterOnFilesChanged].
+type onFilesChangedCallback struct {
+	token OnFilesChangedToken
+	fn    func(changed map[string]ChangeKind)
+}
+
+// RegisterOnFilesChanged registers fn to be called whenever [Project.PutFile],
+// [Project.DeleteFile], [Project.RenameFile], or [Project.UpdateFiles] changes
+// any files, with the changed paths mapped to their kinds of change. A
+// renamed file is reported as its old path being removed and its new path
+// being added.
+//
+// Callbacks are called synchronously in registration order after the change
+// is committed, so they may safely read the project. Callbacks are not carried
+// over to snapshots or clones of the project.
+//
+// The returned token can be passed to [Project.UnregisterOnFilesChanged].
+func (p *Project) RegisterOnFilesChanged(fn func(changed map[string]ChangeKind)) OnFilesChangedToken {
+	p.onFilesChangedMu.Lock()
+	defer p.onFilesChangedMu.Unlock()
+	p.onFilesChangedSeq++
+	token := OnFilesChangedToken(p.onFilesChangedSeq)
+	p.onFilesChanged = append(p.onFilesChanged, onFilesChangedCallback{token: token, fn: fn})
+	return token
+}
+
+// UnregisterOnFilesChanged unregisters the callback registered with
+// [Project.RegisterOnFilesChanged] that returned token. It is a no-op if the
+// callback has already been unregistered.
+func (p *Project) UnregisterOnFilesChanged(token OnFilesChangedToken) {
+	p.onFilesChangedMu.Lock()
+	defer p.onFilesChangedMu.Unlock()
+	p.onFilesChanged = slices.DeleteFunc(p.onFilesChanged, func(cb onFilesChangedCallback) bool {
+		return cb.token == token
+	})
+}
+
+// notifyFilesChanged calls all callbacks registered with
+// [Project.RegisterOnFilesChanged] if changed is not empty. It must not be
+// called with p.mu held.
+func (p *Project) notifyFilesChanged(changed map[string]ChangeKind) {
+	if len(changed) == 0 {
+		return
+	}
+	p.onFilesChangedMu.Lock()
+	callbacks := slices.Clone(p.onFilesChanged)
+	p.onFilesChangedMu.Unlock()
+	for _, cb := range callbacks {
+		cb.fn(changed)
+	}
+}
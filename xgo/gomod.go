@@ -0,0 +1,79 @@
+/*
+ * Copyright (c) 2025 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package xgo
+
+import (
+	"fmt"
+
+	"golang.org/x/mod/modfile"
+)
+
+// goModCacheKind is a cache kind type for [modfile.File].
+type goModCacheKind struct{}
+
+// goModCache is a cache for [modfile.File].
+type goModCache struct {
+	goMod *modfile.File
+}
+
+// buildGoModCache implements [CacheBuilder] to build a [goModCache] for the
+// provided XGo project.
+func buildGoModCache(proj *Project) (any, error) {
+	goModPath, goModFile := proj.goModFile()
+	if goModFile == nil {
+		return &goModCache{}, nil
+	}
+	goMod, err := modfile.Parse(goModPath, goModFile.Content, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", goModPath, err)
+	}
+	return &goModCache{goMod}, nil
+}
+
+// GoMod retrieves the parsed go.mod file at the project root. It returns nil
+// if the project has no such file.
+func (p *Project) GoMod() (*modfile.File, error) {
+	cacheIface, err := p.Cache(goModCacheKind{})
+	if err != nil {
+		return nil, err
+	}
+	cache := cacheIface.(*goModCache)
+	return cache.goMod, nil
+}
+
+// goModFile returns the go.mod file at the project root, or a nil file if
+// there is none. go.mod files in subdirectories belong to other modules and
+// are ignored.
+func (p *Project) goModFile() (goModPath string, goModFile *File) {
+	for _, goModPath := range []string{"go.mod", "/go.mod"} {
+		if goModFile, ok := p.File(goModPath); ok {
+			return goModPath, goModFile
+		}
+	}
+	return "", nil
+}
+
+// goVersion returns the Go version declared by the go.mod file of the
+// project in the form expected by [go/types.Config.GoVersion], e.g.,
+// "go1.21". It returns "" if there is no such declaration.
+func (p *Project) goVersion() string {
+	goMod, _ := p.GoMod()
+	if goMod == nil || goMod.Go == nil {
+		return ""
+	}
+	return "go" + goMod.Go.Version
+}
@@ -0,0 +1,108 @@
+/*
+ * Copyright (c) 2025 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package xgo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectGoMod(t *testing.T) {
+	t.Run("Normal", func(t *testing.T) {
+		proj := NewProject(nil, map[string]*File{
+			"go.mod":   file("module example.com/foo\n\ngo 1.21\n"),
+			"main.xgo": file(`echo "Hello"`),
+		}, FeatAll)
+
+		goMod, err := proj.GoMod()
+		require.NoError(t, err)
+		require.NotNil(t, goMod)
+		require.NotNil(t, goMod.Module)
+		assert.Equal(t, "example.com/foo", goMod.Module.Mod.Path)
+		require.NotNil(t, goMod.Go)
+		assert.Equal(t, "1.21", goMod.Go.Version)
+		assert.Equal(t, "go1.21", proj.goVersion())
+	})
+
+	t.Run("RootOnly", func(t *testing.T) {
+		proj := NewProject(nil, map[string]*File{
+			"go.mod":     file("module example.com/foo\n\ngo 1.21\n"),
+			"sub/go.mod": file("module example.com/foo/sub\n\ngo 1.22\n"),
+		}, FeatAll)
+
+		goMod, err := proj.GoMod()
+		require.NoError(t, err)
+		require.NotNil(t, goMod)
+		assert.Equal(t, "example.com/foo", goMod.Module.Mod.Path)
+	})
+
+	t.Run("NestedGoModOnly", func(t *testing.T) {
+		proj := NewProject(nil, map[string]*File{
+			"assets/go.mod": file("module example.com/assets\n"),
+			"main.xgo":      file(`echo "Hello"`),
+		}, FeatAll)
+
+		goMod, err := proj.GoMod()
+		require.NoError(t, err)
+		assert.Nil(t, goMod)
+	})
+
+	t.Run("AbsoluteRootPath", func(t *testing.T) {
+		proj := NewProject(nil, map[string]*File{
+			"/go.mod": file("module example.com/foo\n"),
+		}, FeatAll)
+
+		goMod, err := proj.GoMod()
+		require.NoError(t, err)
+		require.NotNil(t, goMod)
+		assert.Equal(t, "example.com/foo", goMod.Module.Mod.Path)
+	})
+
+	t.Run("NoGoMod", func(t *testing.T) {
+		proj := NewProject(nil, map[string]*File{
+			"main.xgo": file(`echo "Hello"`),
+		}, FeatAll)
+
+		goMod, err := proj.GoMod()
+		require.NoError(t, err)
+		assert.Nil(t, goMod)
+		assert.Empty(t, proj.goVersion())
+	})
+
+	t.Run("InvalidGoMod", func(t *testing.T) {
+		proj := NewProject(nil, map[string]*File{
+			"go.mod": file("module example.com/foo\n\ngo invalid version\n"),
+		}, FeatAll)
+
+		goMod, err := proj.GoMod()
+		require.Error(t, err)
+		assert.Nil(t, goMod)
+		assert.Empty(t, proj.goVersion())
+	})
+
+	t.Run("UpdatedGoMod", func(t *testing.T) {
+		proj := NewProject(nil, map[string]*File{
+			"go.mod": file("module example.com/foo\n\ngo 1.21\n"),
+		}, FeatAll)
+		assert.Equal(t, "go1.21", proj.goVersion())
+
+		proj.PutFile("go.mod", file("module example.com/foo\n\ngo 1.22\n"))
+		assert.Equal(t, "go1.22", proj.goVersion())
+	})
+}
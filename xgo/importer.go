@@ -0,0 +1,50 @@
+/*
+ * Copyright (c) 2025 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package xgo
+
+import (
+	"errors"
+	gotypes "go/types"
+	"io/fs"
+
+	"github.com/goplus/gogen/packages"
+	"github.com/goplus/xgo/token"
+	"github.com/goplus/xgolsw/internal"
+)
+
+// defaultImporter is the importer used when [Project.Importer] is nil. It
+// imports packages from the embedded package data, and falls back to the
+// importer of the XGo type checker, which loads packages with the go command,
+// for packages missing from the package data.
+type defaultImporter struct {
+	fallback gotypes.Importer
+}
+
+// newDefaultImporter creates a new [defaultImporter] that loads packages
+// missing from the package data into fset.
+func newDefaultImporter(fset *token.FileSet) *defaultImporter {
+	return &defaultImporter{fallback: packages.NewImporter(fset)}
+}
+
+// Import implements [gotypes.Importer].
+func (imp *defaultImporter) Import(path string) (*gotypes.Package, error) {
+	pkg, err := internal.Importer.Import(path)
+	if err != nil && errors.Is(err, fs.ErrNotExist) {
+		return imp.fallback.Import(path)
+	}
+	return pkg, err
+}
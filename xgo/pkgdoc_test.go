@@ -113,6 +113,36 @@ func TestFunc() {
 		assert.Contains(t, gameType.Methods, "TestFunc")
 	})
 
+	t.Run("InterfaceMethods", func(t *testing.T) {
+		proj := NewProject(nil, map[string]*File{
+			"main.spx": file(`
+import "fmt"
+
+// Runner runs things.
+type Runner interface {
+	Run() // Run starts the game
+
+	// Stop stops the game.
+	Stop()
+
+	fmt.Stringer
+}
+`),
+		}, FeatAll)
+
+		pkgDoc, err := proj.PkgDoc()
+		require.NoError(t, err)
+		require.NotNil(t, pkgDoc)
+
+		runnerType, exists := pkgDoc.Types["Runner"]
+		require.True(t, exists)
+		assert.Equal(t, "Runner runs things.\n", runnerType.Doc)
+		assert.Equal(t, map[string]string{
+			"Run":  "Run starts the game\n",
+			"Stop": "Stop stops the game.\n",
+		}, runnerType.Methods)
+	})
+
 	t.Run("Cache", func(t *testing.T) {
 		proj := NewProject(nil, map[string]*File{
 			"main.spx": file(`var x int
@@ -17,10 +17,14 @@
 package xgo
 
 import (
+	"crypto/md5"
 	gotypes "go/types"
 	"io/fs"
 	"iter"
 	"maps"
+	"path"
+	"slices"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -40,8 +44,16 @@ const (
 	// FeatPkgDocCache enables PkgDoc cache building.
 	FeatPkgDocCache
 
+	// FeatDiagnosticsCache enables Diagnostics cache building.
+	FeatDiagnosticsCache
+
+	// FeatSpxResourceCache enables caching of the spx resource set parsed from
+	// the asset index files. Its builder is registered by the consumer with
+	// [Project.RegisterAssetCacheBuilder].
+	FeatSpxResourceCache
+
 	// FeatAll enables all features.
-	FeatAll = FeatASTCache | FeatTypeInfoCache | FeatPkgDocCache
+	FeatAll = FeatASTCache | FeatTypeInfoCache | FeatPkgDocCache | FeatDiagnosticsCache | FeatSpxResourceCache
 )
 
 // cacheFeature represents a cache feature configuration that maps feature
@@ -56,8 +68,10 @@ type cacheFeature struct {
 var builtinCacheFeatures = []cacheFeature{
 	{FeatASTCache, astFileCacheKind{}, buildASTFileCache},
 	{FeatASTCache, astPackageCacheKind{}, buildASTPackageCache},
+	{FeatASTCache, goModCacheKind{}, buildGoModCache},
 	{FeatTypeInfoCache, typeInfoCacheKind{}, buildTypeInfoCache},
 	{FeatPkgDocCache, pkgDocCacheKind{}, buildPkgDocCache},
+	{FeatDiagnosticsCache, diagnosticsCacheKind{}, buildDiagnosticsCache},
 }
 
 // File represents a file in an XGo project.
@@ -66,26 +80,72 @@ type File struct {
 	// Deprecated: ModTime is no longer supported due to lsp text sync specification. Use Version instead.
 	ModTime time.Time
 	Version int
+
+	hashOnce sync.Once
+	hash     [16]byte
+}
+
+// Hash returns the MD5 hash of the file content. It is computed on first use
+// and cached, so Content must not be modified afterwards.
+func (f *File) Hash() [16]byte {
+	f.hashOnce.Do(func() {
+		f.hash = md5.Sum(f.Content)
+	})
+	return f.hash
+}
+
+// isModified reports whether newFile differs from f. It compares mod times,
+// falling back to content hashes when both mod times are zero.
+func (f *File) isModified(newFile *File) bool {
+	if f.ModTime.IsZero() && newFile.ModTime.IsZero() {
+		return f.Hash() != newFile.Hash()
+	}
+	return !f.ModTime.Equal(newFile.ModTime)
 }
 
 // Project represents an XGo project.
 type Project struct {
-	PkgPath  string
-	Mod      *xgomod.Module
+	PkgPath string
+	Mod     *xgomod.Module
+
+	// Importer imports the packages used by the project. If nil, packages
+	// are imported from the embedded package data, falling back to the go
+	// command for packages missing from it.
 	Importer gotypes.Importer
-	Fset     *token.FileSet
 
+	Fset *token.FileSet
+
+	feats uint
+
+	*projectState
+
+	// cacheBuildKeys holds the keys of the caches being built when the
+	// project is passed to a cache builder. See [Project.cacheBuildView].
+	cacheBuildKeys []string
+}
+
+// projectState is the mutable state of a [Project]. It is shared between the
+// project and the views of it passed to cache builders.
+type projectState struct {
 	mu            sync.RWMutex
 	files         map[string]*File
 	filesSnapshot atomic.Pointer[map[string]*File] // Immutable snapshot for lock-free file reads.
 
-	cacheBuilders map[CacheKind]CacheBuilder
-	caches        map[CacheKind]dataOrErr
-	cacheSFG      singleflight.Group
+	cacheBuilders   map[CacheKind]CacheBuilder
+	caches          map[CacheKind]dataOrErr
+	cacheSFG        singleflight.Group
+	assetCacheKinds map[CacheKind]struct{}
 
 	fileCacheBuilders map[CacheKind]FileCacheBuilder
 	fileCaches        map[fileCacheKey]dataOrErr
 	fileCacheSFG      singleflight.Group
+
+	cacheCounters     cacheCounters
+	fileCacheCounters cacheCounters
+
+	onFilesChangedMu  sync.Mutex
+	onFilesChanged    []onFilesChangedCallback
+	onFilesChangedSeq uint64
 }
 
 // NewProject creates a new project with optional static files and features.
@@ -94,13 +154,17 @@ func NewProject(fset *token.FileSet, files map[string]*File, feats uint) *Projec
 		fset = token.NewFileSet()
 	}
 	proj := &Project{
-		Mod:               xgomod.Default,
-		Fset:              fset,
-		files:             make(map[string]*File),
-		cacheBuilders:     make(map[CacheKind]CacheBuilder),
-		caches:            make(map[CacheKind]dataOrErr),
-		fileCacheBuilders: make(map[CacheKind]FileCacheBuilder),
-		fileCaches:        make(map[fileCacheKey]dataOrErr),
+		Mod:   xgomod.Default,
+		Fset:  fset,
+		feats: feats,
+		projectState: &projectState{
+			files:             make(map[string]*File),
+			cacheBuilders:     make(map[CacheKind]CacheBuilder),
+			caches:            make(map[CacheKind]dataOrErr),
+			assetCacheKinds:   make(map[CacheKind]struct{}),
+			fileCacheBuilders: make(map[CacheKind]FileCacheBuilder),
+			fileCaches:        make(map[fileCacheKey]dataOrErr),
+		},
 	}
 	if files != nil {
 		maps.Copy(proj.files, files)
@@ -125,15 +189,55 @@ func (p *Project) Snapshot() *Project {
 	defer p.mu.RUnlock()
 
 	proj := &Project{
-		PkgPath:           p.PkgPath,
-		Mod:               p.Mod,
-		Importer:          p.Importer,
-		Fset:              p.Fset,
-		files:             maps.Clone(p.files),
-		cacheBuilders:     maps.Clone(p.cacheBuilders),
-		caches:            maps.Clone(p.caches),
-		fileCacheBuilders: maps.Clone(p.fileCacheBuilders),
-		fileCaches:        maps.Clone(p.fileCaches),
+		PkgPath:  p.PkgPath,
+		Mod:      p.Mod,
+		Importer: p.Importer,
+		Fset:     p.Fset,
+		feats:    p.feats,
+		projectState: &projectState{
+			files:             maps.Clone(p.files),
+			cacheBuilders:     maps.Clone(p.cacheBuilders),
+			caches:            maps.Clone(p.caches),
+			assetCacheKinds:   maps.Clone(p.assetCacheKinds),
+			fileCacheBuilders: maps.Clone(p.fileCacheBuilders),
+			fileCaches:        maps.Clone(p.fileCaches),
+		},
+	}
+	proj.updateFilesSnapshot()
+	return proj
+}
+
+// Clone creates a fully independent copy of the project. Unlike
+// [Project.Snapshot], the clone has its own [token.FileSet], its own copies of
+// all files, and empty caches, so every cache is rebuilt from the file
+// contents on demand. This makes it slower than [Project.Snapshot], but safe
+// to analyze in parallel with the original project.
+func (p *Project) Clone() *Project {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	files := make(map[string]*File, len(p.files))
+	for path, file := range p.files {
+		files[path] = &File{
+			Content: slices.Clone(file.Content),
+			ModTime: file.ModTime,
+			Version: file.Version,
+		}
+	}
+	proj := &Project{
+		PkgPath:  p.PkgPath,
+		Mod:      p.Mod,
+		Importer: p.Importer,
+		Fset:     token.NewFileSet(),
+		feats:    p.feats,
+		projectState: &projectState{
+			files:             files,
+			cacheBuilders:     maps.Clone(p.cacheBuilders),
+			caches:            make(map[CacheKind]dataOrErr),
+			assetCacheKinds:   maps.Clone(p.assetCacheKinds),
+			fileCacheBuilders: maps.Clone(p.fileCacheBuilders),
+			fileCaches:        make(map[fileCacheKey]dataOrErr),
+		},
 	}
 	proj.updateFilesSnapshot()
 	return proj
@@ -148,12 +252,73 @@ func (p *Project) SnapshotWithOverlay(overlay map[string]*File) *Project {
 	return snapshot
 }
 
+// Features returns the feature flags the project was created with.
+func (p *Project) Features() uint {
+	return p.feats
+}
+
+// FileVersion returns the version of the file at path. See [Project.PutFile]
+// for how versions change.
+func (p *Project) FileVersion(path string) (int, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	file, ok := p.files[path]
+	if !ok {
+		return 0, false
+	}
+	return file.Version, true
+}
+
 // Files returns an iterator over all file path-content pairs in the project.
 func (p *Project) Files() iter.Seq2[string, *File] {
 	snapshot := p.filesSnapshot.Load()
 	return maps.All(*snapshot)
 }
 
+// SpxFiles returns an iterator over all spx source file path-content pairs
+// in the project.
+func (p *Project) SpxFiles() iter.Seq2[string, *File] {
+	return p.filesWithExt(".spx")
+}
+
+// GoFiles returns an iterator over all Go source file path-content pairs in
+// the project.
+func (p *Project) GoFiles() iter.Seq2[string, *File] {
+	return p.filesWithExt(".go")
+}
+
+// AssetFiles returns an iterator over all asset file path-content pairs in
+// the project, i.e., files under the "assets/" directory.
+func (p *Project) AssetFiles() iter.Seq2[string, *File] {
+	return p.filesWhere(isAssetFile)
+}
+
+// isAssetFile reports whether path is an asset file, i.e., a file under the
+// "assets/" directory.
+func isAssetFile(path string) bool {
+	return strings.HasPrefix(path, "assets/")
+}
+
+// filesWithExt returns an iterator over all file path-content pairs in the
+// project whose path has the given extension.
+func (p *Project) filesWithExt(ext string) iter.Seq2[string, *File] {
+	return p.filesWhere(func(file string) bool {
+		return path.Ext(file) == ext
+	})
+}
+
+// filesWhere returns an iterator over all file path-content pairs in the
+// project whose path satisfies match.
+func (p *Project) filesWhere(match func(path string) bool) iter.Seq2[string, *File] {
+	return func(yield func(string, *File) bool) {
+		for path, file := range p.Files() {
+			if match(path) && !yield(path, file) {
+				return
+			}
+		}
+	}
+}
+
 // File gets a file from the project.
 func (p *Project) File(path string) (file *File, ok bool) {
 	p.mu.RLock()
@@ -163,59 +328,129 @@ func (p *Project) File(path string) (file *File, ok bool) {
 }
 
 // PutFile puts a file into the project.
+//
+// If the content of file differs from the content of the existing file at
+// path, or there is no existing file, the version of the put file is at least
+// one greater than the version of the existing file (or 1 for a new file).
+// Otherwise, it is at least the version of the existing file. If file.Version
+// is lower than that, a copy of file with the raised version is put instead,
+// so file itself is never modified.
 func (p *Project) PutFile(path string, file *File) {
+	p.notifyFilesChanged(p.putFile(path, file))
+}
+
+// putFile implements [Project.PutFile] and returns the changed files.
+func (p *Project) putFile(path string, file *File) map[string]ChangeKind {
 	p.mu.Lock()
 	defer p.mu.Unlock()
-	p.files[path] = file
+	kind := Added
+	oldFile, ok := p.files[path]
+	if ok {
+		kind = Modified
+	}
+	p.files[path] = versionedFile(oldFile, file)
 	p.updateFilesSnapshot()
 	p.deleteFileCache(path)
+	return map[string]ChangeKind{path: kind}
+}
+
+// versionedFile returns file with its version raised as described in
+// [Project.PutFile] for replacing oldFile, which is nil for a new file.
+func versionedFile(oldFile, file *File) *File {
+	if file == nil {
+		return nil
+	}
+	version := 1
+	if oldFile != nil {
+		version = oldFile.Version
+		if oldFile.Hash() != file.Hash() {
+			version++
+		}
+	}
+	if file.Version >= version {
+		return file
+	}
+	return &File{
+		Content: file.Content,
+		ModTime: file.ModTime,
+		Version: version,
+	}
 }
 
 // DeleteFile deletes a file from the project.
 func (p *Project) DeleteFile(path string) error {
+	changed, err := p.deleteFile(path)
+	if err != nil {
+		return err
+	}
+	p.notifyFilesChanged(changed)
+	return nil
+}
+
+// deleteFile implements [Project.DeleteFile] and returns the changed files.
+func (p *Project) deleteFile(path string) (map[string]ChangeKind, error) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 	if _, ok := p.files[path]; ok {
 		delete(p.files, path)
 		p.updateFilesSnapshot()
 		p.deleteFileCache(path)
-		return nil
+		return map[string]ChangeKind{path: Removed}, nil
 	}
-	return fs.ErrNotExist
+	return nil, fs.ErrNotExist
 }
 
 // RenameFile renames a file in the project.
 func (p *Project) RenameFile(oldPath, newPath string) error {
+	changed, err := p.renameFile(oldPath, newPath)
+	if err != nil {
+		return err
+	}
+	p.notifyFilesChanged(changed)
+	return nil
+}
+
+// renameFile implements [Project.RenameFile] and returns the changed files.
+func (p *Project) renameFile(oldPath, newPath string) (map[string]ChangeKind, error) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
 	file, ok := p.files[oldPath]
 	if !ok {
-		return fs.ErrNotExist
+		return nil, fs.ErrNotExist
 	}
 	if _, ok := p.files[newPath]; ok {
-		return fs.ErrExist
+		return nil, fs.ErrExist
 	}
 
 	p.files[newPath] = file
 	delete(p.files, oldPath)
 	p.updateFilesSnapshot()
 	p.deleteFileCache(oldPath)
-	return nil
+	return map[string]ChangeKind{oldPath: Removed, newPath: Added}, nil
 }
 
 // UpdateFiles updates all files in the project with the provided map of files.
 // It removes existing files not present in the new map and updates files from
-// the new map.
+// the new map. Versions of the added and updated files are raised as described in
+// [Project.PutFile].
 func (p *Project) UpdateFiles(newFiles map[string]*File) {
+	p.notifyFilesChanged(p.updateFiles(newFiles))
+}
+
+// updateFiles implements [Project.UpdateFiles] and returns the changed files.
+func (p *Project) updateFiles(newFiles map[string]*File) map[string]ChangeKind {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
+	changed := make(map[string]ChangeKind)
+
 	// Delete files that are not in the new map.
 	maps.DeleteFunc(p.files, func(path string, _ *File) bool {
 		_, ok := newFiles[path]
 		if !ok {
 			p.deleteFileCache(path)
+			changed[path] = Removed
 		}
 		return !ok
 	})
@@ -223,19 +458,42 @@ func (p *Project) UpdateFiles(newFiles map[string]*File) {
 	// Add or update files from the new map.
 	for path, newFile := range newFiles {
 		if oldFile, ok := p.files[path]; ok {
-			// Only update if ModTime changed.
-			if !oldFile.ModTime.Equal(newFile.ModTime) {
-				p.files[path] = newFile
+			// Only update if the file changed.
+			if oldFile.isModified(newFile) {
+				p.files[path] = versionedFile(oldFile, newFile)
 				p.deleteFileCache(path)
+				changed[path] = Modified
 			}
 		} else {
 			// New file, always add.
-			p.files[path] = newFile
+			p.files[path] = versionedFile(nil, newFile)
 			p.deleteFileCache(path)
+			changed[path] = Added
 		}
 	}
 
 	p.updateFilesSnapshot()
+	return changed
+}
+
+// AutoDetectPkgPath detects the package path of the project from the module
+// directive of its go.mod file (see [Project.GoMod]).
+func (p *Project) AutoDetectPkgPath() (string, bool) {
+	goMod, _ := p.GoMod()
+	if goMod == nil || goMod.Module == nil {
+		return "", false
+	}
+	return goMod.Module.Mod.Path, true
+}
+
+// pkgPath returns [Project.PkgPath], or the result of
+// [Project.AutoDetectPkgPath] if it is empty. It does not modify the project.
+func (p *Project) pkgPath() string {
+	if p.PkgPath != "" {
+		return p.PkgPath
+	}
+	pkgPath, _ := p.AutoDetectPkgPath()
+	return pkgPath
 }
 
 // updateFilesSnapshot updates the atomic snapshot of files.
@@ -19,6 +19,8 @@ package xgo
 import (
 	"fmt"
 	"io/fs"
+	"iter"
+	"slices"
 	"sync"
 	"testing"
 	"time"
@@ -104,6 +106,13 @@ func TestNewProject(t *testing.T) {
 		assert.GreaterOrEqual(t, total3, 0)
 	})
 
+	t.Run("Features", func(t *testing.T) {
+		proj := NewProject(nil, nil, FeatASTCache|FeatSpxResourceCache)
+		assert.Equal(t, uint(FeatASTCache|FeatSpxResourceCache), proj.Features())
+		assert.Equal(t, proj.Features(), proj.Snapshot().Features())
+		assert.Equal(t, proj.Features(), proj.Clone().Features())
+	})
+
 	t.Run("FilesSnapshotIsCreated", func(t *testing.T) {
 		files := map[string]*File{
 			"test.go": file("package test"),
@@ -234,6 +243,95 @@ func TestProjectSnapshot(t *testing.T) {
 	})
 }
 
+func TestProjectClone(t *testing.T) {
+	t.Run("BasicClone", func(t *testing.T) {
+		files := map[string]*File{
+			"main.go": {Content: []byte("package main"), Version: 2},
+		}
+		proj := NewProject(nil, files, FeatAll)
+		proj.PkgPath = "test/pkg"
+
+		clone := proj.Clone()
+		require.NotNil(t, clone)
+		assert.Equal(t, proj.PkgPath, clone.PkgPath)
+		assert.Equal(t, proj.Mod, clone.Mod)
+		assert.Equal(t, proj.Importer, clone.Importer)
+		assert.NotSame(t, proj.Fset, clone.Fset)
+		assert.Len(t, clone.cacheBuilders, len(proj.cacheBuilders))
+		assert.Len(t, clone.fileCacheBuilders, len(proj.fileCacheBuilders))
+
+		mainFile, ok := clone.File("main.go")
+		require.True(t, ok)
+		assert.NotSame(t, files["main.go"], mainFile)
+		assert.Equal(t, []byte("package main"), mainFile.Content)
+		assert.Equal(t, 2, mainFile.Version)
+	})
+
+	t.Run("FilesAreIndependent", func(t *testing.T) {
+		files := map[string]*File{
+			"main.go": file("package main"),
+		}
+		proj := NewProject(nil, files, 0)
+
+		clone := proj.Clone()
+		clone.PutFile("new.go", file("package new"))
+		cloneMainFile, ok := clone.File("main.go")
+		require.True(t, ok)
+		cloneMainFile.Content[0] = 'P'
+
+		_, ok = proj.File("new.go")
+		assert.False(t, ok)
+		mainFile, ok := proj.File("main.go")
+		require.True(t, ok)
+		assert.Equal(t, []byte("package main"), mainFile.Content)
+	})
+
+	t.Run("CachesAreRebuiltIndependently", func(t *testing.T) {
+		proj := NewProject(nil, map[string]*File{
+			"main.go": file("package main"),
+		}, 0)
+
+		type testCacheKind struct{}
+
+		var buildCount int
+		proj.RegisterCacheBuilder(testCacheKind{}, func(p *Project) (any, error) {
+			buildCount++
+			return buildCount, nil
+		})
+		data, err := proj.Cache(testCacheKind{})
+		require.NoError(t, err)
+		assert.Equal(t, 1, data)
+
+		clone := proj.Clone()
+		assert.Empty(t, clone.caches)
+		assert.Empty(t, clone.fileCaches)
+
+		data, err = clone.Cache(testCacheKind{})
+		require.NoError(t, err)
+		assert.Equal(t, 2, data)
+
+		data, err = proj.Cache(testCacheKind{})
+		require.NoError(t, err)
+		assert.Equal(t, 1, data)
+	})
+
+	t.Run("ASTUsesOwnFileSet", func(t *testing.T) {
+		proj := NewProject(nil, map[string]*File{
+			"main.spx": file("echo \"Hello\""),
+		}, FeatAll)
+		astFile, err := proj.ASTFile("main.spx")
+		require.NoError(t, err)
+
+		clone := proj.Clone()
+		cloneASTFile, err := clone.ASTFile("main.spx")
+		require.NoError(t, err)
+		assert.NotSame(t, astFile, cloneASTFile)
+		cloneTokenFile := clone.Fset.File(cloneASTFile.Pos())
+		require.NotNil(t, cloneTokenFile)
+		assert.NotSame(t, proj.Fset.File(astFile.Pos()), cloneTokenFile)
+	})
+}
+
 func TestProjectSnapshotWithOverlay(t *testing.T) {
 	t.Run("BasicOverlay", func(t *testing.T) {
 		files := map[string]*File{
@@ -462,6 +560,49 @@ func TestProjectFiles(t *testing.T) {
 	})
 }
 
+func TestProjectFilesByKind(t *testing.T) {
+	proj := NewProject(nil, map[string]*File{
+		"main.spx":          file("echo 1"),
+		"Sprite.spx":        file("echo 2"),
+		"helper.go":         file("package main"),
+		"assets/index.json": file("{}"),
+	}, 0)
+
+	collectPaths := func(seq iter.Seq2[string, *File]) []string {
+		var paths []string
+		for path := range seq {
+			paths = append(paths, path)
+		}
+		slices.Sort(paths)
+		return paths
+	}
+
+	t.Run("SpxFiles", func(t *testing.T) {
+		assert.Equal(t, []string{"Sprite.spx", "main.spx"}, collectPaths(proj.SpxFiles()))
+	})
+
+	t.Run("GoFiles", func(t *testing.T) {
+		assert.Equal(t, []string{"helper.go"}, collectPaths(proj.GoFiles()))
+	})
+
+	t.Run("AssetFiles", func(t *testing.T) {
+		assert.Equal(t, []string{"assets/index.json"}, collectPaths(proj.AssetFiles()))
+	})
+
+	t.Run("EarlyBreak", func(t *testing.T) {
+		var count int
+		for range proj.SpxFiles() {
+			count++
+			break
+		}
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("EmptyProject", func(t *testing.T) {
+		assert.Empty(t, collectPaths(NewProject(nil, nil, 0).SpxFiles()))
+	})
+}
+
 func TestProjectFile(t *testing.T) {
 	t.Run("ExistingFile", func(t *testing.T) {
 		files := map[string]*File{
@@ -632,6 +773,66 @@ func TestProjectPutFile(t *testing.T) {
 		assert.Nil(t, nilFile)
 	})
 
+	t.Run("Version", func(t *testing.T) {
+		proj := NewProject(nil, nil, 0)
+
+		proj.PutFile("main.go", file("package main"))
+		version, ok := proj.FileVersion("main.go")
+		require.True(t, ok)
+		assert.Equal(t, 1, version)
+
+		proj.PutFile("main.go", file("package main"))
+		version, ok = proj.FileVersion("main.go")
+		require.True(t, ok)
+		assert.Equal(t, 1, version)
+
+		proj.PutFile("main.go", file("package main\n\nfunc main() {}"))
+		version, ok = proj.FileVersion("main.go")
+		require.True(t, ok)
+		assert.Equal(t, 2, version)
+
+		proj.PutFile("main.go", &File{Content: []byte("package main"), Version: 10})
+		version, ok = proj.FileVersion("main.go")
+		require.True(t, ok)
+		assert.Equal(t, 10, version)
+
+		_, ok = proj.FileVersion("missing.go")
+		assert.False(t, ok)
+	})
+
+	t.Run("VersionDoesNotModifyFile", func(t *testing.T) {
+		proj := NewProject(nil, nil, 0)
+
+		f := file("package main")
+		proj.PutFile("main.go", f)
+		assert.Equal(t, 0, f.Version)
+
+		putFile, ok := proj.File("main.go")
+		require.True(t, ok)
+		assert.NotSame(t, f, putFile)
+		assert.Equal(t, 1, putFile.Version)
+
+		// A file that already has a high enough version is put as is.
+		f = &File{Content: []byte("package main"), Version: 5}
+		proj.PutFile("main.go", f)
+		putFile, ok = proj.File("main.go")
+		require.True(t, ok)
+		assert.Same(t, f, putFile)
+
+		// Reusing the same file in another project does not see versions
+		// stamped by the first one.
+		f = file("package other")
+		proj.PutFile("main.go", f)
+		other := NewProject(nil, nil, 0)
+		other.PutFile("main.go", f)
+		version, ok := other.FileVersion("main.go")
+		require.True(t, ok)
+		assert.Equal(t, 1, version)
+		version, ok = proj.FileVersion("main.go")
+		require.True(t, ok)
+		assert.Equal(t, 6, version)
+	})
+
 	t.Run("FilesSnapshotUpdated", func(t *testing.T) {
 		proj := NewProject(nil, nil, 0)
 
@@ -991,6 +1192,43 @@ func TestProjectUpdateFiles(t *testing.T) {
 		assert.Equal(t, sameTime, mainFile.ModTime)
 	})
 
+	t.Run("UpdateFilesWithZeroModTimeAndDifferentContent", func(t *testing.T) {
+		files := map[string]*File{
+			"main.go": file("package main"),
+		}
+		proj := NewProject(nil, files, 0)
+
+		newFiles := map[string]*File{
+			"main.go": file("package main\n\nfunc main() {}"),
+		}
+
+		proj.UpdateFiles(newFiles)
+
+		// Verify file was updated due to different content hash.
+		mainFile, ok := proj.File("main.go")
+		require.True(t, ok)
+		assert.Equal(t, newFiles["main.go"].Content, mainFile.Content)
+		assert.Equal(t, 1, mainFile.Version)
+	})
+
+	t.Run("UpdateFilesWithZeroModTimeAndSameContent", func(t *testing.T) {
+		files := map[string]*File{
+			"main.go": file("package main"),
+		}
+		proj := NewProject(nil, files, 0)
+
+		newFiles := map[string]*File{
+			"main.go": file("package main"),
+		}
+
+		proj.UpdateFiles(newFiles)
+
+		// Verify file was not updated due to same content hash.
+		mainFile, ok := proj.File("main.go")
+		require.True(t, ok)
+		assert.Same(t, files["main.go"], mainFile)
+	})
+
 	t.Run("UpdateFilesRemovesMissingFiles", func(t *testing.T) {
 		files := map[string]*File{
 			"main.go": file("package main"),
@@ -1092,6 +1330,159 @@ func TestProjectUpdateFiles(t *testing.T) {
 		require.True(t, ok)
 		assert.Equal(t, []byte("package main"), mainFile.Content)
 	})
+
+	t.Run("Version", func(t *testing.T) {
+		proj := NewProject(nil, nil, 0)
+
+		mainFile := file("package main")
+		proj.UpdateFiles(map[string]*File{"main.go": mainFile})
+		version, ok := proj.FileVersion("main.go")
+		require.True(t, ok)
+		assert.Equal(t, 1, version)
+		assert.Equal(t, 0, mainFile.Version)
+
+		proj.UpdateFiles(map[string]*File{"main.go": file("package main")})
+		version, ok = proj.FileVersion("main.go")
+		require.True(t, ok)
+		assert.Equal(t, 1, version)
+
+		proj.UpdateFiles(map[string]*File{"main.go": file("package main\n\nfunc main() {}")})
+		version, ok = proj.FileVersion("main.go")
+		require.True(t, ok)
+		assert.Equal(t, 2, version)
+
+		proj.UpdateFiles(map[string]*File{"main.go": {Content: []byte("package main"), Version: 10}})
+		version, ok = proj.FileVersion("main.go")
+		require.True(t, ok)
+		assert.Equal(t, 10, version)
+	})
+}
+
+func TestProjectRegisterOnFilesChanged(t *testing.T) {
+	newRecordingProject := func(files map[string]*File) (*Project, *[]map[string]ChangeKind, OnFilesChangedToken) {
+		proj := NewProject(nil, files, 0)
+		var calls []map[string]ChangeKind
+		token := proj.RegisterOnFilesChanged(func(changed map[string]ChangeKind) {
+			calls = append(calls, changed)
+		})
+		return proj, &calls, token
+	}
+
+	t.Run("PutFile", func(t *testing.T) {
+		proj, calls, _ := newRecordingProject(nil)
+
+		proj.PutFile("main.spx", file("echo 1"))
+		proj.PutFile("main.spx", file("echo 2"))
+		assert.Equal(t, []map[string]ChangeKind{
+			{"main.spx": Added},
+			{"main.spx": Modified},
+		}, *calls)
+	})
+
+	t.Run("DeleteFile", func(t *testing.T) {
+		proj, calls, _ := newRecordingProject(map[string]*File{"main.spx": file("echo 1")})
+
+		require.NoError(t, proj.DeleteFile("main.spx"))
+		assert.ErrorIs(t, proj.DeleteFile("main.spx"), fs.ErrNotExist)
+		assert.Equal(t, []map[string]ChangeKind{
+			{"main.spx": Removed},
+		}, *calls)
+	})
+
+	t.Run("RenameFile", func(t *testing.T) {
+		proj, calls, _ := newRecordingProject(map[string]*File{"A.spx": file("echo 1")})
+
+		require.NoError(t, proj.RenameFile("A.spx", "B.spx"))
+		assert.ErrorIs(t, proj.RenameFile("A.spx", "C.spx"), fs.ErrNotExist)
+		assert.Equal(t, []map[string]ChangeKind{
+			{"A.spx": Removed, "B.spx": Added},
+		}, *calls)
+	})
+
+	t.Run("UpdateFiles", func(t *testing.T) {
+		proj, calls, _ := newRecordingProject(map[string]*File{
+			"main.spx":   file("echo 1"),
+			"A.spx":      file("echo 2"),
+			"Stable.spx": file("echo 3"),
+		})
+
+		proj.UpdateFiles(map[string]*File{
+			"main.spx":   file("echo 4"),
+			"B.spx":      file("echo 5"),
+			"Stable.spx": file("echo 3"),
+		})
+		assert.Equal(t, []map[string]ChangeKind{
+			{"main.spx": Modified, "A.spx": Removed, "B.spx": Added},
+		}, *calls)
+
+		// No changes, no calls.
+		proj.UpdateFiles(map[string]*File{
+			"main.spx":   file("echo 4"),
+			"B.spx":      file("echo 5"),
+			"Stable.spx": file("echo 3"),
+		})
+		assert.Len(t, *calls, 1)
+	})
+
+	t.Run("CallbackCanReadProject", func(t *testing.T) {
+		proj := NewProject(nil, nil, 0)
+		var content []byte
+		proj.RegisterOnFilesChanged(func(changed map[string]ChangeKind) {
+			f, ok := proj.File("main.spx")
+			require.True(t, ok)
+			content = f.Content
+		})
+
+		proj.PutFile("main.spx", file("echo 1"))
+		assert.Equal(t, []byte("echo 1"), content)
+	})
+
+	t.Run("Unregister", func(t *testing.T) {
+		proj, calls, token := newRecordingProject(nil)
+		var otherCalls int
+		proj.RegisterOnFilesChanged(func(changed map[string]ChangeKind) {
+			otherCalls++
+		})
+
+		proj.PutFile("main.spx", file("echo 1"))
+		proj.UnregisterOnFilesChanged(token)
+		proj.UnregisterOnFilesChanged(token)
+		proj.PutFile("main.spx", file("echo 2"))
+		assert.Len(t, *calls, 1)
+		assert.Equal(t, 2, otherCalls)
+	})
+}
+
+func TestFileHash(t *testing.T) {
+	t.Run("SameContent", func(t *testing.T) {
+		f1 := file("package main")
+		f2 := file("package main")
+		assert.Equal(t, f1.Hash(), f2.Hash())
+	})
+
+	t.Run("DifferentContent", func(t *testing.T) {
+		f1 := file("package main")
+		f2 := file("package test")
+		assert.NotEqual(t, f1.Hash(), f2.Hash())
+	})
+
+	t.Run("IndependentOfModTime", func(t *testing.T) {
+		f1 := &File{Content: []byte("package main"), ModTime: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)}
+		f2 := &File{Content: []byte("package main"), ModTime: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+		assert.Equal(t, f1.Hash(), f2.Hash())
+	})
+
+	t.Run("EmptyContent", func(t *testing.T) {
+		f := &File{}
+		assert.Equal(t, [16]byte{0xd4, 0x1d, 0x8c, 0xd9, 0x8f, 0x00, 0xb2, 0x04, 0xe9, 0x80, 0x09, 0x98, 0xec, 0xf8, 0x42, 0x7e}, f.Hash())
+	})
+
+	t.Run("Cached", func(t *testing.T) {
+		f := file("package main")
+		hash := f.Hash()
+		f.Content = []byte("package test")
+		assert.Equal(t, hash, f.Hash())
+	})
 }
 
 func TestProjectUpdateFilesSnapshot(t *testing.T) {
@@ -1238,3 +1629,132 @@ func TestProjectUpdateFilesSnapshot(t *testing.T) {
 		wg.Wait()
 	})
 }
+
+func TestProjectAutoDetectPkgPath(t *testing.T) {
+	t.Run("GoMod", func(t *testing.T) {
+		proj := NewProject(nil, map[string]*File{
+			"go.mod":   file("// Game module.\nmodule example.com/game\n\ngo 1.25\n"),
+			"main.spx": file(`var x int`),
+		}, FeatAll)
+
+		pkgPath, ok := proj.AutoDetectPkgPath()
+		require.True(t, ok)
+		assert.Equal(t, "example.com/game", pkgPath)
+	})
+
+	t.Run("QuotedModulePath", func(t *testing.T) {
+		proj := NewProject(nil, map[string]*File{
+			"go.mod": file("module \"example.com/game\"\n"),
+		}, FeatAll)
+
+		pkgPath, ok := proj.AutoDetectPkgPath()
+		require.True(t, ok)
+		assert.Equal(t, "example.com/game", pkgPath)
+	})
+
+	t.Run("RootOnly", func(t *testing.T) {
+		proj := NewProject(nil, map[string]*File{
+			"go.mod":       file("module example.com/game\n"),
+			"tools/go.mod": file("module example.com/game/tools\n"),
+		}, FeatAll)
+
+		pkgPath, ok := proj.AutoDetectPkgPath()
+		require.True(t, ok)
+		assert.Equal(t, "example.com/game", pkgPath)
+	})
+
+	t.Run("ModuleBlock", func(t *testing.T) {
+		proj := NewProject(nil, map[string]*File{
+			"go.mod": file("module (\n\texample.com/game // The game.\n)\n"),
+		}, FeatAll)
+
+		pkgPath, ok := proj.AutoDetectPkgPath()
+		require.True(t, ok)
+		assert.Equal(t, "example.com/game", pkgPath)
+	})
+
+	t.Run("InvalidGoMod", func(t *testing.T) {
+		proj := NewProject(nil, map[string]*File{
+			"go.mod": file("module example.com/game\nrequire (\n"),
+		}, FeatAll)
+
+		_, ok := proj.AutoDetectPkgPath()
+		assert.False(t, ok)
+	})
+
+	t.Run("NestedGoModOnly", func(t *testing.T) {
+		proj := NewProject(nil, map[string]*File{
+			"tools/go.mod": file("module example.com/game/tools\n"),
+			"main.spx":     file(`var x int`),
+		}, FeatAll)
+
+		_, ok := proj.AutoDetectPkgPath()
+		assert.False(t, ok)
+	})
+
+	t.Run("NoGoMod", func(t *testing.T) {
+		proj := NewProject(nil, map[string]*File{
+			"main.spx": file(`var x int`),
+		}, FeatAll)
+
+		pkgPath, ok := proj.AutoDetectPkgPath()
+		assert.False(t, ok)
+		assert.Empty(t, pkgPath)
+	})
+
+	t.Run("NoModuleDirective", func(t *testing.T) {
+		proj := NewProject(nil, map[string]*File{
+			"go.mod": file("go 1.25\n"),
+		}, FeatAll)
+
+		_, ok := proj.AutoDetectPkgPath()
+		assert.False(t, ok)
+	})
+
+	t.Run("UsedWithoutPkgPath", func(t *testing.T) {
+		proj := NewProject(nil, map[string]*File{
+			"go.mod":   file("module example.com/game\n"),
+			"main.spx": file(`var x int`),
+		}, FeatAll)
+
+		typeInfo, err := proj.TypeInfo()
+		require.NoError(t, err)
+		assert.Equal(t, "example.com/game", typeInfo.Pkg.Path())
+
+		pkgDoc, err := proj.PkgDoc()
+		require.NoError(t, err)
+		assert.Equal(t, "example.com/game", pkgDoc.Path)
+
+		assert.Empty(t, proj.PkgPath)
+	})
+
+	t.Run("GoModChanged", func(t *testing.T) {
+		proj := NewProject(nil, map[string]*File{
+			"go.mod":   file("module example.com/game\n"),
+			"main.spx": file(`var x int`),
+		}, FeatAll)
+
+		typeInfo, err := proj.TypeInfo()
+		require.NoError(t, err)
+		assert.Equal(t, "example.com/game", typeInfo.Pkg.Path())
+
+		proj.PutFile("go.mod", file("module example.com/other\n"))
+
+		typeInfo, err = proj.TypeInfo()
+		require.NoError(t, err)
+		assert.Equal(t, "example.com/other", typeInfo.Pkg.Path())
+	})
+
+	t.Run("ExplicitPkgPathKept", func(t *testing.T) {
+		proj := NewProject(nil, map[string]*File{
+			"go.mod":   file("module example.com/game\n"),
+			"main.spx": file(`var x int`),
+		}, FeatAll)
+		proj.PkgPath = "main"
+
+		typeInfo, err := proj.TypeInfo()
+		require.NoError(t, err)
+		assert.Equal(t, "main", typeInfo.Pkg.Path())
+		assert.Equal(t, "main", proj.PkgPath)
+	})
+}
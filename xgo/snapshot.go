@@ -0,0 +1,142 @@
+/*
+ * Copyright (c) 2025 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package xgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/goplus/xgo/token"
+	"github.com/goplus/xgolsw/pkgdoc"
+)
+
+// snapshotFormatVersion is the version of the format produced by
+// [Project.MarshalSnapshot]. It must be increased whenever the format changes
+// incompatibly.
+const snapshotFormatVersion = 1
+
+// projectSnapshot is the serialized form of a [Project].
+type projectSnapshot struct {
+	Version int                     `json:"version"`
+	PkgPath string                  `json:"pkgPath"`
+	Feats   uint                    `json:"feats"`
+	Files   map[string]snapshotFile `json:"files"`
+	PkgDoc  *pkgdoc.PkgDoc          `json:"pkgDoc,omitempty"`
+}
+
+// snapshotFile is the serialized form of a [File].
+type snapshotFile struct {
+	Content []byte    `json:"content"`
+	ModTime time.Time `json:"modTime"`
+	Version int       `json:"version"`
+}
+
+// MarshalSnapshot serializes the files of the project along with its
+// serializable caches, so that the project can be restored with
+// [UnmarshalSnapshot], e.g., after the process is restarted.
+//
+// Only caches that have already been built are included, and only the
+// [pkgdoc.PkgDoc] cache is currently serializable. Other caches, such as the
+// AST and type info caches, are rebuilt lazily after restoring.
+func (p *Project) MarshalSnapshot() ([]byte, error) {
+	p.mu.RLock()
+	snapshot := projectSnapshot{
+		Version: snapshotFormatVersion,
+		PkgPath: p.PkgPath,
+		Feats:   p.feats,
+		Files:   make(map[string]snapshotFile, len(p.files)),
+	}
+	for path, file := range p.files {
+		snapshot.Files[path] = snapshotFile{
+			Content: file.Content,
+			ModTime: file.ModTime,
+			Version: file.Version,
+		}
+	}
+	if cache, ok := p.caches[pkgDocCacheKind{}].(*pkgDocCache); ok {
+		snapshot.PkgDoc = cache.pkgDoc
+	}
+	p.mu.RUnlock()
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal project snapshot: %w", err)
+	}
+	return data, nil
+}
+
+// Option configures a [Project] restored by [UnmarshalSnapshot].
+type Option func(*snapshotOptions)
+
+// snapshotOptions holds the options of [UnmarshalSnapshot].
+type snapshotOptions struct {
+	fset  *token.FileSet
+	feats uint
+}
+
+// WithFileSet sets the file set of the restored project. A new file set is
+// created by default.
+func WithFileSet(fset *token.FileSet) Option {
+	return func(opts *snapshotOptions) {
+		opts.fset = fset
+	}
+}
+
+// WithFeatures sets the features of the restored project. The features of the
+// marshaled project are used by default.
+func WithFeatures(feats uint) Option {
+	return func(opts *snapshotOptions) {
+		opts.feats = feats
+	}
+}
+
+// UnmarshalSnapshot restores a project from data produced by
+// [Project.MarshalSnapshot]. Persisted caches are restored only if the
+// corresponding features are enabled.
+//
+// Fields of [Project] that are not serializable, such as [Project.Mod] and
+// [Project.Importer], must be set by the caller as with [NewProject].
+func UnmarshalSnapshot(data []byte, opts ...Option) (*Project, error) {
+	var snapshot projectSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal project snapshot: %w", err)
+	}
+	if snapshot.Version != snapshotFormatVersion {
+		return nil, fmt.Errorf("unsupported project snapshot version %d", snapshot.Version)
+	}
+
+	options := snapshotOptions{feats: snapshot.Feats}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	files := make(map[string]*File, len(snapshot.Files))
+	for path, file := range snapshot.Files {
+		files[path] = &File{
+			Content: file.Content,
+			ModTime: file.ModTime,
+			Version: file.Version,
+		}
+	}
+	proj := NewProject(options.fset, files, options.feats)
+	proj.PkgPath = snapshot.PkgPath
+	if snapshot.PkgDoc != nil && options.feats&FeatPkgDocCache != 0 {
+		proj.caches[pkgDocCacheKind{}] = &pkgDocCache{snapshot.PkgDoc}
+	}
+	return proj, nil
+}
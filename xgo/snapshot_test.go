@@ -0,0 +1,109 @@
+/*
+ * Copyright (c) 2025 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package xgo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goplus/xgo/token"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectMarshalSnapshot(t *testing.T) {
+	newProject := func() *Project {
+		proj := NewProject(nil, map[string]*File{
+			"main.spx": file(`
+// Test documentation
+func Test() {}
+`),
+			"assets/index.json": {Content: []byte(`{}`), ModTime: time.UnixMilli(1700000000000)},
+		}, FeatAll)
+		proj.PkgPath = "main"
+		return proj
+	}
+
+	t.Run("WarmPkgDocCache", func(t *testing.T) {
+		proj := newProject()
+		pkgDoc, err := proj.PkgDoc()
+		require.NoError(t, err)
+
+		data, err := proj.MarshalSnapshot()
+		require.NoError(t, err)
+		restored, err := UnmarshalSnapshot(data)
+		require.NoError(t, err)
+
+		assert.Equal(t, "main", restored.PkgPath)
+		assert.Equal(t, proj.Features(), restored.Features())
+		for path, file := range proj.Files() {
+			restoredFile, ok := restored.File(path)
+			require.True(t, ok)
+			assert.Equal(t, file.Content, restoredFile.Content)
+			assert.True(t, file.ModTime.Equal(restoredFile.ModTime))
+			assert.Equal(t, file.Version, restoredFile.Version)
+		}
+
+		restored.ResetCacheMetrics()
+		restoredPkgDoc, err := restored.PkgDoc()
+		require.NoError(t, err)
+		assert.Equal(t, pkgDoc, restoredPkgDoc)
+		metrics := restored.CacheMetrics()
+		assert.Equal(t, uint64(1), metrics.Project.Hits)
+		assert.Zero(t, metrics.Project.Builds)
+
+		// The restored cache is invalidated like any other cache.
+		restored.PutFile("main.spx", file(`func Other() {}`))
+		restoredPkgDoc, err = restored.PkgDoc()
+		require.NoError(t, err)
+		assert.NotEqual(t, pkgDoc, restoredPkgDoc)
+	})
+
+	t.Run("ColdPkgDocCache", func(t *testing.T) {
+		data, err := newProject().MarshalSnapshot()
+		require.NoError(t, err)
+		restored, err := UnmarshalSnapshot(data)
+		require.NoError(t, err)
+
+		_, err = restored.PkgDoc()
+		require.NoError(t, err)
+		assert.NotZero(t, restored.CacheMetrics().Project.Builds)
+	})
+
+	t.Run("WithOptions", func(t *testing.T) {
+		proj := newProject()
+		_, err := proj.PkgDoc()
+		require.NoError(t, err)
+		data, err := proj.MarshalSnapshot()
+		require.NoError(t, err)
+
+		fset := token.NewFileSet()
+		restored, err := UnmarshalSnapshot(data, WithFileSet(fset), WithFeatures(FeatASTCache))
+		require.NoError(t, err)
+		assert.Same(t, fset, restored.Fset)
+		assert.Equal(t, uint(FeatASTCache), restored.Features())
+		assert.Empty(t, restored.caches)
+	})
+
+	t.Run("InvalidData", func(t *testing.T) {
+		_, err := UnmarshalSnapshot([]byte("not json"))
+		assert.Error(t, err)
+
+		_, err = UnmarshalSnapshot([]byte(`{"version":0}`))
+		assert.EqualError(t, err, "unsupported project snapshot version 0")
+	})
+}
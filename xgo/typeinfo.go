@@ -54,15 +54,12 @@ func buildTypeInfoCache(proj *Project) (any, error) {
 			Implicits:  make(map[ast.Node]gotypes.Object),
 			Scopes:     make(map[ast.Node]*gotypes.Scope),
 		},
-		Pkg: gotypes.NewPackage(proj.PkgPath, astPkg.Name),
+		Pkg: gotypes.NewPackage(proj.pkgPath(), astPkg.Name),
 	}
 
 	var checkerErrs errors.List
 	if err := typesutil.NewChecker(
-		&gotypes.Config{
-			Error:    func(err error) { checkerErrs.Add(err) },
-			Importer: proj.Importer,
-		},
+		proj.typesConfig(func(err error) { checkerErrs.Add(err) }),
 		&typesutil.Config{
 			Types: typeInfo.Pkg,
 			Fset:  proj.Fset,
@@ -87,6 +84,29 @@ func buildTypeInfoCache(proj *Project) (any, error) {
 	return &typeInfoCache{typeInfo, checkerErrs.ToError()}, nil
 }
 
+// typesConfig returns the [gotypes.Config] used to type-check the project,
+// reporting errors to onErr.
+func (p *Project) typesConfig(onErr func(err error)) *gotypes.Config {
+	importer := p.Importer
+	if importer == nil {
+		importer = newDefaultImporter(p.Fset)
+	}
+	return &gotypes.Config{
+		Error:     onErr,
+		Importer:  importer,
+		GoVersion: p.goVersion(),
+	}
+}
+
+// HasTypeInfo reports whether the [types.Info] of the project has already been
+// built, in which case [Project.TypeInfo] returns it without type checking.
+func (p *Project) HasTypeInfo() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	_, ok := p.caches[typeInfoCacheKind{}]
+	return ok
+}
+
 // TypeInfo retrieves the [types.Info] from the project. The returned [types.Info]
 // is nil only if building failed.
 //
@@ -20,6 +20,7 @@ import (
 	gotypes "go/types"
 	"testing"
 
+	"github.com/goplus/xgolsw/internal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -62,6 +63,54 @@ func main() {
 		assert.NotEmpty(t, typeInfo.Uses)
 	})
 
+	t.Run("DefaultImporter", func(t *testing.T) {
+		proj := NewProject(nil, map[string]*File{
+			"main.xgo": {
+				Content: []byte(`
+import "fmt"
+
+fmt.Println("hello")
+`),
+			},
+		}, FeatAll)
+		require.Nil(t, proj.Importer)
+
+		cache, err := buildTypeInfoCache(proj)
+		require.NoError(t, err)
+		typeInfoCache := cache.(*typeInfoCache)
+		require.NoError(t, typeInfoCache.checkerErr)
+
+		fmtPkg, err := internal.Importer.Import("fmt")
+		require.NoError(t, err)
+		var printlnObj gotypes.Object
+		for ident, obj := range typeInfoCache.typeInfo.Uses {
+			if ident.Name == "Println" {
+				printlnObj = obj
+			}
+		}
+		require.NotNil(t, printlnObj)
+		assert.Same(t, fmtPkg.Scope().Lookup("Println"), printlnObj)
+	})
+
+	t.Run("DefaultImporterFallback", func(t *testing.T) {
+		proj := NewProject(nil, map[string]*File{
+			"main.xgo": {
+				Content: []byte(`
+import "testing"
+
+echo testing.Verbose()
+`),
+			},
+		}, FeatAll)
+		_, err := internal.Importer.Import("testing")
+		require.Error(t, err, "testing should be missing from the package data")
+
+		cache, err := buildTypeInfoCache(proj)
+		require.NoError(t, err)
+		typeInfoCache := cache.(*typeInfoCache)
+		require.NoError(t, typeInfoCache.checkerErr)
+	})
+
 	t.Run("ASTPackageError", func(t *testing.T) {
 		proj := NewProject(nil, map[string]*File{
 			"invalid.xgo": {
@@ -185,6 +234,22 @@ func getCounter() int {
 		assert.Same(t, typeInfo1, typeInfo2)
 	})
 
+	t.Run("HasTypeInfo", func(t *testing.T) {
+		proj := NewProject(nil, map[string]*File{
+			"main.xgo": {
+				Content: []byte(`var x int`),
+			},
+		}, FeatAll)
+		assert.False(t, proj.HasTypeInfo())
+
+		_, err := proj.TypeInfo()
+		require.NoError(t, err)
+		assert.True(t, proj.HasTypeInfo())
+
+		proj.PutFile("main.xgo", file(`var y int`))
+		assert.False(t, proj.HasTypeInfo())
+	})
+
 	t.Run("CacheError", func(t *testing.T) {
 		// Create a project without the TypeInfoCache feature enabled.
 		// This will cause Cache() to return ErrUnknownCacheKind.
@@ -200,3 +265,27 @@ func getCounter() int {
 		assert.Nil(t, typeInfo)
 	})
 }
+
+func TestProjectTypesConfig(t *testing.T) {
+	t.Run("GoVersionFromGoMod", func(t *testing.T) {
+		proj := NewProject(nil, map[string]*File{
+			"go.mod":   file("module example.com/foo\n\ngo 1.21\n"),
+			"main.xgo": file(`echo "Hello"`),
+		}, FeatAll)
+
+		conf := proj.typesConfig(nil)
+		assert.Equal(t, "go1.21", conf.GoVersion)
+
+		_, err := proj.TypeInfo()
+		assert.NoError(t, err)
+	})
+
+	t.Run("NoGoMod", func(t *testing.T) {
+		proj := NewProject(nil, map[string]*File{
+			"main.xgo": file(`echo "Hello"`),
+		}, FeatAll)
+
+		conf := proj.typesConfig(nil)
+		assert.Empty(t, conf.GoVersion)
+	})
+}
@@ -17,7 +17,10 @@
 package xgo
 
 import (
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
 
 	"github.com/goplus/mod/modfile"
 	"github.com/goplus/mod/modload"
@@ -32,6 +35,11 @@ var spxProject = &modfile.Project{
 	Works:    []*modfile.Class{{Ext: ".spx", Class: "SpriteImpl", Embedded: true}},
 }
 
+// classfileProjects maps classfile ids to their project configurations.
+var classfileProjects = map[string]*modfile.Project{
+	"spx": spxProject,
+}
+
 func init() {
 	modload.Default.Opt.Projects = append(modload.Default.Opt.Projects, spxProject)
 	if err := xgomod.Default.ImportClasses(); err != nil {
@@ -39,11 +47,62 @@ func init() {
 	}
 }
 
+// RegisterClassfile registers the classfile project proj with the given id,
+// so that its files are recognized by all projects and its auto-imported
+// packages can be managed with id. It returns an error if id or the file
+// extension of proj is already registered.
+func RegisterClassfile(id string, proj *modfile.Project) error {
+	if id == "" {
+		return errors.New("empty classfile id")
+	}
+	if proj == nil || proj.Ext == "" {
+		return fmt.Errorf("invalid classfile project for id: %s", id)
+	}
+	if _, ok := classfileProjects[id]; ok {
+		return fmt.Errorf("classfile id already registered: %s", id)
+	}
+	for _, registered := range modload.Default.Opt.Projects {
+		if registered.Ext == proj.Ext {
+			return fmt.Errorf("classfile extension already registered: %s", proj.Ext)
+		}
+	}
+
+	modload.Default.Opt.Projects = append(modload.Default.Opt.Projects, proj)
+	if err := xgomod.Default.ImportClasses(); err != nil {
+		modload.Default.Opt.Projects = slices.DeleteFunc(modload.Default.Opt.Projects, func(p *modfile.Project) bool {
+			return p == proj
+		})
+		return fmt.Errorf("failed to import classfile %s: %w", id, err)
+	}
+	classfileProjects[id] = proj
+	return nil
+}
+
+// UnregisterClassfile unregisters the classfile registered with
+// [RegisterClassfile] under id. It returns an error if id is unknown or is
+// the built-in "spx" classfile.
+func UnregisterClassfile(id string) error {
+	proj, ok := classfileProjects[id]
+	if !ok {
+		return fmt.Errorf("unknown classfile id: %s", id)
+	}
+	if proj == spxProject {
+		return errors.New("cannot unregister the built-in spx classfile")
+	}
+
+	delete(classfileProjects, id)
+	modload.Default.Opt.Projects = slices.DeleteFunc(modload.Default.Opt.Projects, func(p *modfile.Project) bool {
+		return p == proj
+	})
+	return xgomod.Default.ImportClasses()
+}
+
 // SetClassfileAutoImportedPackages sets the auto-imported packages for the
-// classfile specified by id.
-func SetClassfileAutoImportedPackages(id string, pkgs map[string]string) {
-	if id != "spx" {
-		panic(fmt.Sprintf("unknown classfile id: %s", id))
+// classfile specified by id. It returns an error if id is unknown.
+func SetClassfileAutoImportedPackages(id string, pkgs map[string]string) error {
+	proj, ok := classfileProjects[id]
+	if !ok {
+		return fmt.Errorf("unknown classfile id: %s", id)
 	}
 
 	imports := make([]*modfile.Import, 0, len(pkgs))
@@ -51,5 +110,49 @@ func SetClassfileAutoImportedPackages(id string, pkgs map[string]string) {
 		imports = append(imports, &modfile.Import{Name: name, Path: pkgs[name]})
 	}
 
-	spxProject.Import = imports
+	proj.Import = imports
+	return nil
+}
+
+// GetClassfileAutoImportedPackages returns the auto-imported packages for the
+// classfile specified by id. It returns false if no packages are set for it.
+func GetClassfileAutoImportedPackages(id string) (map[string]string, bool) {
+	proj, ok := classfileProjects[id]
+	if !ok || len(proj.Import) == 0 {
+		return nil, false
+	}
+
+	pkgs := make(map[string]string, len(proj.Import))
+	for _, imp := range proj.Import {
+		pkgs[imp.Name] = imp.Path
+	}
+	return pkgs, true
+}
+
+// RemoveClassfileAutoImportedPackages removes the auto-imported packages for
+// the classfile specified by id. It does nothing if id is unknown.
+func RemoveClassfileAutoImportedPackages(id string) {
+	if proj, ok := classfileProjects[id]; ok {
+		proj.Import = nil
+	}
+}
+
+// ListClassfileAutoImportedPackageIDs returns the sorted ids of the classfiles
+// that have auto-imported packages set.
+func ListClassfileAutoImportedPackageIDs() []string {
+	var ids []string
+	for _, id := range slices.Sorted(maps.Keys(classfileProjects)) {
+		if len(classfileProjects[id].Import) > 0 {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// ClearAllClassfileAutoImportedPackages removes the auto-imported packages for
+// all classfiles.
+func ClearAllClassfileAutoImportedPackages() {
+	for _, proj := range classfileProjects {
+		proj.Import = nil
+	}
 }
@@ -19,35 +19,151 @@ package xgo
 import (
 	"testing"
 
+	"github.com/goplus/mod/modfile"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+// registerTestClassfile registers a classfile with the given id for the
+// duration of the test.
+func registerTestClassfile(t *testing.T, id string) {
+	t.Helper()
+	require.NoError(t, RegisterClassfile(id, &modfile.Project{Ext: "." + id, Class: "App"}))
+	t.Cleanup(func() {
+		assert.NoError(t, UnregisterClassfile(id))
+	})
+}
+
+// keepClassfileAutoImportedPackages restores the auto-imported packages for
+// the classfile specified by id at the end of the test.
+func keepClassfileAutoImportedPackages(t *testing.T, id string) {
+	t.Helper()
+	pkgs, ok := GetClassfileAutoImportedPackages(id)
+	t.Cleanup(func() {
+		if ok {
+			assert.NoError(t, SetClassfileAutoImportedPackages(id, pkgs))
+		} else {
+			RemoveClassfileAutoImportedPackages(id)
+		}
+	})
+}
+
 func TestSetClassfileAutoImportedPackages(t *testing.T) {
 	t.Run("Spx", func(t *testing.T) {
-		originalImports := spxProject.Import
-		t.Cleanup(func() {
-			spxProject.Import = originalImports
-		})
+		keepClassfileAutoImportedPackages(t, "spx")
 
 		pkgs := map[string]string{
 			"fmt":    "fmt",
 			"foobar": "example.com/foobar",
 			"math":   "math",
 		}
-		SetClassfileAutoImportedPackages("spx", pkgs)
+		require.NoError(t, SetClassfileAutoImportedPackages("spx", pkgs))
 
-		assert.Len(t, spxProject.Import, 3)
-
-		got := make(map[string]string)
-		for _, imp := range spxProject.Import {
-			got[imp.Name] = imp.Path
-		}
+		got, ok := GetClassfileAutoImportedPackages("spx")
+		require.True(t, ok)
 		assert.Equal(t, pkgs, got)
 	})
 
 	t.Run("UnknownClassfileID", func(t *testing.T) {
-		assert.Panics(t, func() {
-			SetClassfileAutoImportedPackages("unknown", nil)
-		})
+		err := SetClassfileAutoImportedPackages("unknown", nil)
+		assert.EqualError(t, err, "unknown classfile id: unknown")
+	})
+}
+
+func TestClassfileAutoImportedPackages(t *testing.T) {
+	keepClassfileAutoImportedPackages(t, "spx")
+	registerTestClassfile(t, "yap")
+
+	ClearAllClassfileAutoImportedPackages()
+	assert.Empty(t, ListClassfileAutoImportedPackageIDs())
+	_, ok := GetClassfileAutoImportedPackages("spx")
+	assert.False(t, ok)
+
+	spxPkgs := map[string]string{
+		"fmt":    "fmt",
+		"foobar": "example.com/foobar",
+	}
+	yapPkgs := map[string]string{
+		"http": "net/http",
+	}
+	require.NoError(t, SetClassfileAutoImportedPackages("spx", spxPkgs))
+	require.NoError(t, SetClassfileAutoImportedPackages("yap", yapPkgs))
+	assert.Equal(t, []string{"spx", "yap"}, ListClassfileAutoImportedPackageIDs())
+	got, ok := GetClassfileAutoImportedPackages("spx")
+	require.True(t, ok)
+	assert.Equal(t, spxPkgs, got)
+	got, ok = GetClassfileAutoImportedPackages("yap")
+	require.True(t, ok)
+	assert.Equal(t, yapPkgs, got)
+
+	_, ok = GetClassfileAutoImportedPackages("unknown")
+	assert.False(t, ok)
+	assert.NotPanics(t, func() {
+		RemoveClassfileAutoImportedPackages("unknown")
+	})
+	assert.Equal(t, []string{"spx", "yap"}, ListClassfileAutoImportedPackageIDs())
+
+	RemoveClassfileAutoImportedPackages("spx")
+	assert.Equal(t, []string{"yap"}, ListClassfileAutoImportedPackageIDs())
+	_, ok = GetClassfileAutoImportedPackages("spx")
+	assert.False(t, ok)
+	got, ok = GetClassfileAutoImportedPackages("yap")
+	require.True(t, ok)
+	assert.Equal(t, yapPkgs, got)
+
+	require.NoError(t, SetClassfileAutoImportedPackages("spx", spxPkgs))
+	ClearAllClassfileAutoImportedPackages()
+	assert.Empty(t, ListClassfileAutoImportedPackageIDs())
+}
+
+func TestRegisterClassfile(t *testing.T) {
+	t.Run("Normal", func(t *testing.T) {
+		registerTestClassfile(t, "yap")
+
+		require.NoError(t, SetClassfileAutoImportedPackages("yap", map[string]string{"http": "net/http"}))
+		got, ok := GetClassfileAutoImportedPackages("yap")
+		require.True(t, ok)
+		assert.Equal(t, map[string]string{"http": "net/http"}, got)
+		assert.Contains(t, ListClassfileAutoImportedPackageIDs(), "yap")
+	})
+
+	t.Run("DuplicateID", func(t *testing.T) {
+		err := RegisterClassfile("spx", &modfile.Project{Ext: ".other", Class: "App"})
+		assert.EqualError(t, err, "classfile id already registered: spx")
+	})
+
+	t.Run("DuplicateExt", func(t *testing.T) {
+		err := RegisterClassfile("other", &modfile.Project{Ext: ".spx", Class: "App"})
+		assert.EqualError(t, err, "classfile extension already registered: .spx")
+	})
+
+	t.Run("InvalidArguments", func(t *testing.T) {
+		assert.EqualError(t, RegisterClassfile("", &modfile.Project{Ext: ".yap"}), "empty classfile id")
+		assert.EqualError(t, RegisterClassfile("yap", nil), "invalid classfile project for id: yap")
+		assert.EqualError(t, RegisterClassfile("yap", &modfile.Project{}), "invalid classfile project for id: yap")
+	})
+
+	t.Run("Unregister", func(t *testing.T) {
+		require.NoError(t, RegisterClassfile("yap", &modfile.Project{Ext: ".yap", Class: "App"}))
+		require.NoError(t, SetClassfileAutoImportedPackages("yap", map[string]string{"http": "net/http"}))
+		require.NoError(t, UnregisterClassfile("yap"))
+
+		_, ok := GetClassfileAutoImportedPackages("yap")
+		assert.False(t, ok)
+		assert.EqualError(t, SetClassfileAutoImportedPackages("yap", nil), "unknown classfile id: yap")
+
+		// The id and extension can be registered again.
+		registerTestClassfile(t, "yap")
+	})
+
+	t.Run("UnregisterUnknown", func(t *testing.T) {
+		assert.EqualError(t, UnregisterClassfile("unknown"), "unknown classfile id: unknown")
+	})
+
+	t.Run("UnregisterSpx", func(t *testing.T) {
+		keepClassfileAutoImportedPackages(t, "spx")
+
+		assert.EqualError(t, UnregisterClassfile("spx"), "cannot unregister the built-in spx classfile")
+		assert.NoError(t, SetClassfileAutoImportedPackages("spx", map[string]string{"fmt": "fmt"}))
 	})
 }
@@ -271,8 +271,8 @@ func SourceParamName(param *gotypes.Var) string {
 	return name
 }
 
-// isOptionalParam reports whether param is an XGo optional parameter.
-func isOptionalParam(typeInfo *types.Info, param *gotypes.Var) bool {
+// IsOptionalParam reports whether param is an XGo optional parameter.
+func IsOptionalParam(typeInfo *types.Info, param *gotypes.Var) bool {
 	if _, ok := trimOptionalParamPrefix(param.Name()); ok {
 		return true
 	}
@@ -539,7 +539,7 @@ func resolveCallExprKwarg(typeInfo *types.Info, expr *ast.CallExpr, sig *gotypes
 	}
 
 	param := params.At(paramIndex)
-	if len(expr.Kwargs) == 0 && !isOptionalParam(typeInfo, param) {
+	if len(expr.Kwargs) == 0 && !IsOptionalParam(typeInfo, param) {
 		return nil
 	}
 
@@ -444,6 +444,23 @@ func TestSourceParamName(t *testing.T) {
 	}
 }
 
+func TestIsOptionalParam(t *testing.T) {
+	pkg := gotypes.NewPackage("main", "main")
+	for _, tt := range []struct {
+		name string
+		want bool
+	}{
+		{name: "__xgo_optional_opts", want: true},
+		{name: "__gop_optional_opts", want: true},
+		{name: "opts", want: false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			param := gotypes.NewParam(token.NoPos, pkg, tt.name, gotypes.Typ[gotypes.Int])
+			assert.Equal(t, tt.want, IsOptionalParam(nil, param))
+		})
+	}
+}
+
 func TestResolvedCallExprArgs(t *testing.T) {
 	t.Run("NilCallExpr", func(t *testing.T) {
 		resolved := slices.Collect(ResolvedCallExprArgs(nil, nil))
@@ -68,3 +68,11 @@ func NodeASTFile(fset *token.FileSet, astPkg *ast.Package, node ast.Node) *ast.F
 	}
 	return PosASTFile(fset, astPkg, node.Pos())
 }
+
+// TokenRange returns the start and end positions of the given node.
+func TokenRange(fset *token.FileSet, node ast.Node) (start, end token.Position) {
+	if fset == nil || node == nil {
+		return
+	}
+	return fset.Position(node.Pos()), fset.Position(node.End())
+}
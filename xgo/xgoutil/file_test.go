@@ -176,3 +176,30 @@ func TestNodeASTFile(t *testing.T) {
 		assert.Nil(t, file)
 	})
 }
+
+func TestTokenRange(t *testing.T) {
+	t.Run("Normal", func(t *testing.T) {
+		fset, astFile, err := newTestFile("main.xgo", "var x = 1\n\nvar yy = 2")
+		require.NoError(t, err)
+
+		yyDecl := requireValueSpec(t, requireGenDecl(t, astFile.Decls[1]).Specs[0]).Names[0]
+		start, end := TokenRange(fset, yyDecl)
+		assert.Equal(t, "main.xgo", start.Filename)
+		assert.Equal(t, 3, start.Line)
+		assert.Equal(t, 5, start.Column)
+		assert.Equal(t, 3, end.Line)
+		assert.Equal(t, 7, end.Column)
+	})
+
+	t.Run("NilFileSet", func(t *testing.T) {
+		start, end := TokenRange(nil, &ast.Ident{Name: "test"})
+		assert.False(t, start.IsValid())
+		assert.False(t, end.IsValid())
+	})
+
+	t.Run("NilNode", func(t *testing.T) {
+		start, end := TokenRange(token.NewFileSet(), nil)
+		assert.False(t, start.IsValid())
+		assert.False(t, end.IsValid())
+	})
+}
@@ -36,8 +36,20 @@ func IsXGotMethodName(name string) bool {
 	return ok
 }
 
+// IsXGotName reports whether the given name has the XGo template method
+// prefix.
+func IsXGotName(name string) bool {
+	return strings.HasPrefix(name, XGotPrefix)
+}
+
+// IsXGooName reports whether the given name has the XGo overload prefix.
+func IsXGooName(name string) bool {
+	return strings.HasPrefix(name, XGooPrefix)
+}
+
 // SplitXGotMethodName splits an XGo template method name into receiver type
-// name and method name.
+// name and method name. If trimXGox is true, the XGox prefix and the overload
+// suffix are also trimmed from the method name.
 func SplitXGotMethodName(name string, trimXGox bool) (recvTypeName string, methodName string, ok bool) {
 	if !strings.HasPrefix(name, XGotPrefix) {
 		return "", "", false
@@ -50,10 +62,29 @@ func SplitXGotMethodName(name string, trimXGox bool) (recvTypeName string, metho
 		if funcName, ok := SplitXGoxFuncName(methodName); ok {
 			methodName = funcName
 		}
+		if base, _, ok := SplitXGooOverloadSuffix(methodName); ok {
+			methodName = base
+		}
 	}
 	return
 }
 
+// SplitXGooOverloadSuffix splits an XGo overloaded function name like
+// "Play__2" into its base name and overload index. Overload suffixes "a"
+// through "z" map to indexes 10 through 35.
+func SplitXGooOverloadSuffix(name string) (base string, index int, ok bool) {
+	matches := xgoOverloadFuncNameRE.FindStringSubmatch(name)
+	if len(matches) != 3 {
+		return "", 0, false
+	}
+	if c := matches[2][0]; c >= '0' && c <= '9' {
+		index = int(c - '0')
+	} else {
+		index = int(c-'a') + 10
+	}
+	return matches[1], index, true
+}
+
 // SplitXGoxFuncName splits an XGo type as parameters function name into the
 // function name.
 func SplitXGoxFuncName(name string) (funcName string, ok bool) {
@@ -82,6 +82,17 @@ func TestSplitXGotMethodName(t *testing.T) {
 		assert.Equal(t, "Method", methodName)
 	})
 
+	t.Run("ValidXGotMethodNameTrimOverloadSuffix", func(t *testing.T) {
+		recvTypeName, methodName, ok := SplitXGotMethodName("XGot_Game_Run__1", true)
+		require.True(t, ok)
+		assert.Equal(t, "Game", recvTypeName)
+		assert.Equal(t, "Run", methodName)
+
+		_, methodName, ok = SplitXGotMethodName("XGot_Game_Run__1", false)
+		require.True(t, ok)
+		assert.Equal(t, "Run__1", methodName)
+	})
+
 	t.Run("InvalidPrefix", func(t *testing.T) {
 		_, _, ok := SplitXGotMethodName("Type_Method", false)
 		assert.False(t, ok)
@@ -115,6 +126,48 @@ func TestSplitXGotMethodName(t *testing.T) {
 	})
 }
 
+func TestIsXGotName(t *testing.T) {
+	assert.True(t, IsXGotName("XGot_Game_Run"))
+	assert.False(t, IsXGotName("XGoo_Game_Run"))
+	assert.False(t, IsXGotName("Run"))
+	// The legacy Gop prefixes are not XGo prefixes.
+	assert.False(t, IsXGotName("Gopt_Game_Run__1"))
+}
+
+func TestIsXGooName(t *testing.T) {
+	assert.True(t, IsXGooName("XGoo_Sprite_Play"))
+	assert.False(t, IsXGooName("XGot_Sprite_Play"))
+	assert.False(t, IsXGooName("Play"))
+	// The legacy Gop prefixes are not XGo prefixes.
+	assert.False(t, IsXGooName("Gopo_Sprite_Play__2"))
+}
+
+func TestSplitXGooOverloadSuffix(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		in        string
+		wantBase  string
+		wantIndex int
+		wantOK    bool
+	}{
+		{name: "GoptMethod", in: "Gopt_Game_Run__1", wantBase: "Gopt_Game_Run", wantIndex: 1, wantOK: true},
+		{name: "GopoFunc", in: "Gopo_Sprite_Play__2", wantBase: "Gopo_Sprite_Play", wantIndex: 2, wantOK: true},
+		{name: "XGotMethod", in: "XGot_Game_Run__1", wantBase: "XGot_Game_Run", wantIndex: 1, wantOK: true},
+		{name: "XGooFunc", in: "XGoo_Sprite_Play__2", wantBase: "XGoo_Sprite_Play", wantIndex: 2, wantOK: true},
+		{name: "LetterSuffix", in: "Play__b", wantBase: "Play", wantIndex: 11, wantOK: true},
+		{name: "NoSuffix", in: "Println", wantBase: "", wantIndex: 0, wantOK: false},
+		{name: "EmptySuffix", in: "Play__", wantBase: "", wantIndex: 0, wantOK: false},
+		{name: "UpperCaseSuffix", in: "Play__A", wantBase: "", wantIndex: 0, wantOK: false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			base, index, ok := SplitXGooOverloadSuffix(tt.in)
+			assert.Equal(t, tt.wantBase, base)
+			assert.Equal(t, tt.wantIndex, index)
+			assert.Equal(t, tt.wantOK, ok)
+		})
+	}
+}
+
 func TestSplitXGoxFuncName(t *testing.T) {
 	t.Run("ValidXGoxFuncName", func(t *testing.T) {
 		funcName, ok := SplitXGoxFuncName("XGox_Method")
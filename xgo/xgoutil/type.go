@@ -66,10 +66,18 @@ func IsTypesCompatible(got, want gotypes.Type) bool {
 		if want.Results().Len() != gotSig.Results().Len() {
 			return false
 		}
-		if want.Results().Len() == 0 {
-			return true
+		// Results of want with the invalid type (e.g., for blank identifiers
+		// in `_, err = f()`) are compatible with any type.
+		for i := range want.Results().Len() {
+			wantResult := want.Results().At(i).Type()
+			if !IsValidType(wantResult) {
+				continue
+			}
+			if !IsTypesCompatible(gotSig.Results().At(i).Type(), wantResult) {
+				return false
+			}
 		}
-		return IsTypesCompatible(gotSig.Results().At(0).Type(), want.Results().At(0).Type())
+		return true
 	}
 
 	if gotSig, ok := got.(*gotypes.Signature); ok {
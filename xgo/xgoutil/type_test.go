@@ -224,6 +224,24 @@ func TestIsTypesCompatible(t *testing.T) {
 		), false)
 		assert.False(t, IsTypesCompatible(twoResultsSig, intResultSig))
 
+		errorType := gotypes.Universe.Lookup("error").Type()
+		intErrorSig := gotypes.NewSignatureType(nil, nil, nil, nil, gotypes.NewTuple(
+			gotypes.NewVar(0, nil, "", gotypes.Typ[gotypes.Int]),
+			gotypes.NewVar(0, nil, "", errorType),
+		), false)
+		stringErrorSig := gotypes.NewSignatureType(nil, nil, nil, nil, gotypes.NewTuple(
+			gotypes.NewVar(0, nil, "", gotypes.Typ[gotypes.String]),
+			gotypes.NewVar(0, nil, "", errorType),
+		), false)
+		blankErrorWantSig := gotypes.NewSignatureType(nil, nil, nil, nil, gotypes.NewTuple(
+			gotypes.NewVar(0, nil, "", gotypes.Typ[gotypes.Invalid]),
+			gotypes.NewVar(0, nil, "", errorType),
+		), false)
+		assert.True(t, IsTypesCompatible(intErrorSig, blankErrorWantSig))
+		assert.True(t, IsTypesCompatible(stringErrorSig, blankErrorWantSig))
+		assert.False(t, IsTypesCompatible(twoResultsSig, blankErrorWantSig))
+		assert.False(t, IsTypesCompatible(intErrorSig, twoResultsSig))
+
 		ptrToInt := gotypes.NewPointer(gotypes.Typ[gotypes.Int])
 		ptrResultSig := gotypes.NewSignatureType(nil, nil, nil, nil, gotypes.NewTuple(gotypes.NewVar(0, nil, "", ptrToInt)), false)
 		ptrWantSig := gotypes.NewSignatureType(nil, nil, nil, nil, gotypes.NewTuple(gotypes.NewVar(0, nil, "", ptrToInt)), false)
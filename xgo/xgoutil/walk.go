@@ -0,0 +1,69 @@
+/*
+ * Copyright (c) 2025 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package xgoutil
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/goplus/xgo/ast"
+)
+
+// ASTPackageProvider provides a cached XGo AST package, such as
+// [github.com/goplus/xgolsw/xgo.Project].
+type ASTPackageProvider interface {
+	ASTPackage() (*ast.Package, error)
+}
+
+// WalkAllFiles calls fn for each AST file in the AST package of proj,
+// in the lexical order of file paths. Files that failed to parse completely are
+// still delivered with their partial ASTs.
+//
+// It stops walking and returns the error if fn returns a non-nil error.
+func WalkAllFiles(proj ASTPackageProvider, fn func(spxFile string, astFile *ast.File) error) error {
+	astPkg, _ := proj.ASTPackage()
+	if astPkg == nil {
+		return nil
+	}
+	for _, spxFile := range slices.Sorted(maps.Keys(astPkg.Files)) {
+		if err := fn(spxFile, astPkg.Files[spxFile]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WalkAllNodes calls fn for each node in the AST files of proj in depth-first
+// order. See [WalkAllFiles] for the order of files.
+//
+// It stops walking and returns the error if fn returns a non-nil error.
+func WalkAllNodes(proj ASTPackageProvider, fn func(spxFile string, node ast.Node) error) error {
+	return WalkAllFiles(proj, func(spxFile string, astFile *ast.File) error {
+		var err error
+		ast.Inspect(astFile, func(node ast.Node) bool {
+			if err != nil {
+				return false
+			}
+			if node == nil {
+				return true
+			}
+			err = fn(spxFile, node)
+			return err == nil
+		})
+		return err
+	})
+}
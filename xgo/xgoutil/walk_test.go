@@ -0,0 +1,125 @@
+/*
+ * Copyright (c) 2025 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package xgoutil
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/goplus/xgo/ast"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testASTPackageProvider struct {
+	astPkg *ast.Package
+}
+
+func (p testASTPackageProvider) ASTPackage() (*ast.Package, error) {
+	return p.astPkg, nil
+}
+
+func newWalkTestProject(t *testing.T) testASTPackageProvider {
+	files := make(map[string]*ast.File)
+	for filename, source := range map[string]string{
+		"main.gox": `
+func onStart() {
+	echo "main"
+}
+`,
+		"MySprite.gox": `
+func onClick() {
+	echo "sprite"
+}
+`,
+	} {
+		_, astFile, err := newTestFile(filename, source)
+		require.NoError(t, err)
+		files[filename] = astFile
+	}
+	return testASTPackageProvider{astPkg: newTestPackage(files)}
+}
+
+func TestWalkAllFiles(t *testing.T) {
+	t.Run("VisitsAllFiles", func(t *testing.T) {
+		proj := newWalkTestProject(t)
+
+		var spxFiles []string
+		err := WalkAllFiles(proj, func(spxFile string, astFile *ast.File) error {
+			require.NotNil(t, astFile)
+			spxFiles = append(spxFiles, spxFile)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"MySprite.gox", "main.gox"}, spxFiles)
+	})
+
+	t.Run("StopsOnError", func(t *testing.T) {
+		proj := newWalkTestProject(t)
+		wantErr := errors.New("stop")
+
+		var count int
+		err := WalkAllFiles(proj, func(spxFile string, astFile *ast.File) error {
+			count++
+			return wantErr
+		})
+		assert.ErrorIs(t, err, wantErr)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("EmptyProject", func(t *testing.T) {
+		proj := testASTPackageProvider{astPkg: newTestPackage(nil)}
+
+		err := WalkAllFiles(proj, func(spxFile string, astFile *ast.File) error {
+			t.Fatalf("unexpected file %q", spxFile)
+			return nil
+		})
+		assert.NoError(t, err)
+	})
+}
+
+func TestWalkAllNodes(t *testing.T) {
+	t.Run("VisitsAllFiles", func(t *testing.T) {
+		proj := newWalkTestProject(t)
+
+		basicLits := make(map[string]string)
+		err := WalkAllNodes(proj, func(spxFile string, node ast.Node) error {
+			if lit, ok := node.(*ast.BasicLit); ok {
+				basicLits[spxFile] = lit.Value
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"main.gox":     `"main"`,
+			"MySprite.gox": `"sprite"`,
+		}, basicLits)
+	})
+
+	t.Run("StopsOnError", func(t *testing.T) {
+		proj := newWalkTestProject(t)
+		wantErr := errors.New("stop")
+
+		var count int
+		err := WalkAllNodes(proj, func(spxFile string, node ast.Node) error {
+			count++
+			return wantErr
+		})
+		assert.ErrorIs(t, err, wantErr)
+		assert.Equal(t, 1, count)
+	})
+}
@@ -135,6 +135,64 @@ func EnclosingNode[T ast.Node](path []ast.Node) T {
 	return zero
 }
 
+// FindEnclosingFuncDecl returns the function declaration enclosing pos in
+// file.
+func FindEnclosingFuncDecl(file *ast.File, pos token.Pos) (*ast.FuncDecl, bool) {
+	return findEnclosingNode[*ast.FuncDecl](file, pos)
+}
+
+// FindEnclosingFuncLit returns the innermost function literal enclosing pos in
+// file.
+func FindEnclosingFuncLit(file *ast.File, pos token.Pos) (*ast.FuncLit, bool) {
+	return findEnclosingNode[*ast.FuncLit](file, pos)
+}
+
+// FindEnclosingStmt returns the innermost statement enclosing pos in file.
+func FindEnclosingStmt(file *ast.File, pos token.Pos) (ast.Stmt, bool) {
+	return findEnclosingNode[ast.Stmt](file, pos)
+}
+
+// IdentAt returns the innermost identifier containing pos in file, i.e., with
+// ident.Pos() <= pos < ident.End(). It returns nil if not found. Like
+// [PathEnclosingInterval], it binary searches the children of each node on the
+// path, so it does not visit the whole file.
+func IdentAt(file *ast.File, pos token.Pos) *ast.Ident {
+	return nodeAt[*ast.Ident](file, pos)
+}
+
+// ExprAt returns the innermost expression containing pos in file, i.e., with
+// expr.Pos() <= pos < expr.End(). It returns nil if not found.
+func ExprAt(file *ast.File, pos token.Pos) ast.Expr {
+	return nodeAt[ast.Expr](file, pos)
+}
+
+// nodeAt returns the innermost node of type T containing pos in file, or the
+// zero value of T if not found.
+func nodeAt[T ast.Node](file *ast.File, pos token.Pos) T {
+	var zero T
+	if file == nil || !pos.IsValid() {
+		return zero
+	}
+	for node := range PathEnclosingIntervalNodes(file, pos, pos, false) {
+		if node, ok := node.(T); ok && node.Pos() <= pos && pos < node.End() {
+			return node
+		}
+	}
+	return zero
+}
+
+// findEnclosingNode returns the innermost node of type T enclosing pos in
+// file.
+func findEnclosingNode[T ast.Node](file *ast.File, pos token.Pos) (T, bool) {
+	for node := range PathEnclosingIntervalNodes(file, pos, pos, false) {
+		if node, ok := node.(T); ok {
+			return node, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
 // EnclosingReturnStmt returns the nearest enclosing return statement in the
 // given AST path. It returns nil if not found.
 func EnclosingReturnStmt(path []ast.Node) *ast.ReturnStmt {
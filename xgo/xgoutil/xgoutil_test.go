@@ -883,6 +883,102 @@ func test(x int) {
 	})
 }
 
+func TestFindEnclosingFuncDecl(t *testing.T) {
+	_, astFile, err := newTestFile("main.xgo", `
+func outer() {
+	inner := func() {
+		println("inner")
+	}
+	inner()
+}
+
+func other() {}
+`)
+	require.NoError(t, err)
+
+	var lit *ast.BasicLit
+	ast.Inspect(astFile, func(n ast.Node) bool {
+		if bl, ok := n.(*ast.BasicLit); ok && bl.Value == `"inner"` {
+			lit = bl
+			return false
+		}
+		return true
+	})
+	require.NotNil(t, lit)
+
+	t.Run("FuncDecl", func(t *testing.T) {
+		funcDecl, ok := FindEnclosingFuncDecl(astFile, lit.Pos())
+		require.True(t, ok)
+		assert.Equal(t, "outer", funcDecl.Name.Name)
+
+		_, ok = FindEnclosingFuncDecl(astFile, astFile.Pos())
+		assert.False(t, ok)
+	})
+
+	t.Run("FuncLit", func(t *testing.T) {
+		funcLit, ok := FindEnclosingFuncLit(astFile, lit.Pos())
+		require.True(t, ok)
+		assert.True(t, funcLit.Pos() < lit.Pos() && lit.End() < funcLit.End())
+
+		funcDecl, ok := FindEnclosingFuncDecl(astFile, lit.Pos())
+		require.True(t, ok)
+		_, ok = FindEnclosingFuncLit(astFile, funcDecl.Name.Pos())
+		assert.False(t, ok)
+	})
+
+	t.Run("Stmt", func(t *testing.T) {
+		stmt, ok := FindEnclosingStmt(astFile, lit.Pos())
+		require.True(t, ok)
+		exprStmt, ok := stmt.(*ast.ExprStmt)
+		require.True(t, ok)
+		call, ok := exprStmt.X.(*ast.CallExpr)
+		require.True(t, ok)
+		assert.Equal(t, "println", call.Fun.(*ast.Ident).Name)
+	})
+}
+
+func TestIdentAt(t *testing.T) {
+	_, astFile, err := newTestFile("main.xgo", `
+var x = 42
+println x + 1
+`)
+	require.NoError(t, err)
+
+	valueSpec := astFile.Decls[0].(*ast.GenDecl).Specs[0].(*ast.ValueSpec)
+	name := valueSpec.Names[0]
+	value := valueSpec.Values[0].(*ast.BasicLit)
+
+	t.Run("Ident", func(t *testing.T) {
+		assert.Same(t, name, IdentAt(astFile, name.Pos()))
+		assert.Nil(t, IdentAt(astFile, name.End()))
+		assert.Nil(t, IdentAt(astFile, value.Pos()))
+		assert.Nil(t, IdentAt(astFile, token.NoPos))
+		assert.Nil(t, IdentAt(nil, name.Pos()))
+	})
+
+	t.Run("Expr", func(t *testing.T) {
+		assert.Same(t, name, ExprAt(astFile, name.Pos()))
+		assert.Same(t, value, ExprAt(astFile, value.Pos()+1))
+		assert.Nil(t, ExprAt(astFile, astFile.Decls[0].Pos()))
+	})
+
+	t.Run("NestedExpr", func(t *testing.T) {
+		var binaryExpr *ast.BinaryExpr
+		ast.Inspect(astFile, func(n ast.Node) bool {
+			if expr, ok := n.(*ast.BinaryExpr); ok {
+				binaryExpr = expr
+				return false
+			}
+			return true
+		})
+		require.NotNil(t, binaryExpr)
+
+		assert.Same(t, binaryExpr, ExprAt(astFile, binaryExpr.OpPos))
+		assert.Same(t, binaryExpr.X, ExprAt(astFile, binaryExpr.X.Pos()))
+		assert.Same(t, binaryExpr.X, IdentAt(astFile, binaryExpr.X.Pos()))
+	})
+}
+
 func TestEnclosingReturnStmt(t *testing.T) {
 	_, astFile, err := newTestFile("main.xgo", `
 func foo() string {